@@ -1,25 +1,41 @@
 package AuthCore
 
 import (
+	cache "CourseEnrollment/internal/cache/AuthCore"
 	db "CourseEnrollment/internal/database/AuthCore"
+	"CourseEnrollment/internal/oidc"
+	"CourseEnrollment/internal/password"
 	pb "CourseEnrollment/pkg/proto"
-	"crypto/rand"
+	"sync/atomic"
 )
 
 // API contains the data needed to operate the auth core endpoints
 type API struct {
 	// The database to authorize users
-	Database db.Database
-	// The key to sign stuff with it
-	jwtKey []byte
+	Database db.Interface
+	// The allow list of tokens. Tokens which are not in it are rejected.
+	Cache cache.Interface
+	// The identity provider which users can login with. Nil disables single sign-on.
+	OIDC *oidc.Provider
+	// The policy which the passwords of new students are hashed with
+	PasswordPolicy password.Policy
+	// The throttling of login attempts
+	LoginLimits LoginLimits
+	// The IPs and CIDRs of the reverse proxies whose X-Forwarded-For header is trusted. Nil trusts no
+	// proxy, so the client IP is the address of the connection.
+	TrustedProxies []string
+	// The keys to sign and verify the tokens with. They can be replaced while serving.
+	keys atomic.Pointer[KeySet]
 	// The gRPC client for connection to main core
 	CoreClient pb.CourseEnrollmentServerServiceClient
 }
 
 // GenerateJWTKey generates a random JWT key
 func (a *API) GenerateJWTKey() {
-	const keyLen = 32
-	key := make([]byte, keyLen)
-	_, _ = rand.Read(key)
-	a.jwtKey = key
+	a.SetKeys(NewRandomKeySet())
+}
+
+// SetKeys replaces the keys which tokens are signed and verified with
+func (a *API) SetKeys(keys *KeySet) {
+	a.keys.Store(keys)
 }
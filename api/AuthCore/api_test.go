@@ -0,0 +1,123 @@
+package AuthCore
+
+import (
+	cache "CourseEnrollment/internal/cache/AuthCore"
+	"CourseEnrollment/internal/database"
+	"CourseEnrollment/internal/password"
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/course"
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	testPassword   = "password"
+	testDepartment = 1
+	// A super admin
+	testAdmin = 100
+	// A staff of testDepartment
+	testStaff = 101
+)
+
+// testPasswordPolicy uses the minimum bcrypt cost to keep the tests fast
+var testPasswordPolicy = password.Policy{Algorithm: password.Bcrypt, BcryptCost: bcrypt.MinCost}
+
+// testAPI is an auth core on in-memory storage. It has no enrollment server, so only the endpoints
+// which do not need it can be tested with it.
+type testAPI struct {
+	*API
+	db     *database.MemoryDatabase
+	router *gin.Engine
+}
+
+// newTestAPI creates an auth core with a super admin, a staff of testDepartment and students 1 and
+// 2 of testDepartment. Everyone's password is testPassword.
+func newTestAPI(t *testing.T) *testAPI {
+	gin.SetMode(gin.TestMode)
+	db := database.NewMemoryDatabase()
+	db.SetPasswordPolicy(testPasswordPolicy)
+	hash, err := testPasswordPolicy.Hash(testPassword)
+	require.NoError(t, err)
+	db.AddDepartment(testDepartment, "Computer Engineering")
+	db.AddStaff(database.MemoryStaff{ID: testAdmin, Password: hash, Department: testDepartment, Role: shared.StaffRoleSuperAdmin})
+	db.AddStaff(database.MemoryStaff{ID: testStaff, Password: hash, Department: testDepartment})
+	for _, id := range []course.StudentID{1, 2} {
+		db.AddStudent(database.MemoryStudent{ID: id, Password: hash, MaxUnits: 20, Department: testDepartment, EntryYear: 1400, EnrollmentStartTime: time.Now()})
+	}
+	api := &API{
+		Database:       db,
+		Cache:          cache.NewMemoryAuth(),
+		PasswordPolicy: testPasswordPolicy,
+		LoginLimits:    DefaultLoginLimits,
+	}
+	api.GenerateJWTKey()
+	return &testAPI{API: api, db: db, router: api.Router()}
+}
+
+// request sends a request to the router and returns the response. If body is not nil, it's sent as
+// JSON. If result is not nil, a successful response is parsed into it.
+func (a *testAPI) request(t *testing.T, token, method, path string, header http.Header, body, result any) *httptest.ResponseRecorder {
+	t.Helper()
+	var requestBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		requestBody = bytes.NewReader(data)
+	}
+	request := httptest.NewRequest(method, path, requestBody)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response := httptest.NewRecorder()
+	a.router.ServeHTTP(response, request)
+	if result != nil && response.Code < 300 {
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), result), "cannot parse response: %s", response.Body)
+	}
+	return response
+}
+
+// status sends a request without headers and returns the status code
+func (a *testAPI) status(t *testing.T, token, method, path string, body, result any) int {
+	t.Helper()
+	return a.request(t, token, method, path, nil, body, result).Code
+}
+
+// attemptLogin tries to login and returns the response
+func (a *testAPI) attemptLogin(t *testing.T, user uint64, password string, isStaff bool) *httptest.ResponseRecorder {
+	t.Helper()
+	return a.request(t, "", http.MethodPost, "/login", nil, LoginRequest{User: user, Password: password, IsStaff: isStaff}, nil)
+}
+
+// login logs in a user and returns its token. The test fails if the login fails.
+func (a *testAPI) login(t *testing.T, user uint64, password string, isStaff bool) string {
+	t.Helper()
+	var result TokenResult
+	require.Equal(t, http.StatusOK, a.status(t, "", http.MethodPost, "/login", LoginRequest{User: user, Password: password, IsStaff: isStaff}, &result), "cannot login")
+	return result.Token
+}
+
+// isValid checks if a token is accepted by an endpoint which does not need the enrollment server
+func (a *testAPI) isValid(t *testing.T, token string, isStaff bool) bool {
+	t.Helper()
+	path := "/student/notifications"
+	if isStaff {
+		path = "/staff/audit?student_id=1"
+	}
+	code := a.status(t, token, http.MethodGet, path, nil, nil)
+	require.Contains(t, []int{http.StatusOK, http.StatusUnauthorized}, code)
+	return code == http.StatusOK
+}
@@ -1,6 +1,7 @@
 package AuthCore
 
 import (
+	cache "CourseEnrollment/internal/cache/AuthCore"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"net/http"
@@ -14,8 +15,23 @@ func (a *API) LoginUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
 		return
 	}
+	// Throttle before checking the password, because bcrypt is slow
+	userKey, ipKey := cache.UserLimitKey(request.User, request.IsStaff), cache.IPLimitKey(clientIP(c))
+	if a.checkLocked(c, userKey, ipKey) {
+		return
+	}
+	lockout, err := a.addLoginAttempt(c, ipKey, a.LoginLimits.IPAttempts)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot add login attempt")
+		return
+	}
+	if lockout > 0 {
+		abortLocked(c, lockout)
+		return
+	}
 	// Check them
-	userOk, departmentID, err := a.Database.AuthUser(c.Request.Context(), request.User, request.Password, request.IsStaff)
+	userOk, user, err := a.Database.AuthUser(c.Request.Context(), request.User, request.Password, request.IsStaff)
 	if err != nil {
 		c.Status(http.StatusInternalServerError)
 		log.WithError(err).WithField("request", request).Error("cannot check user credentials")
@@ -23,31 +39,94 @@ func (a *API) LoginUser(c *gin.Context) {
 	}
 	// Check auth info
 	if !userOk {
+		if _, err = a.addLoginAttempt(c, userKey, a.LoginLimits.UserFailures); err != nil {
+			log.WithError(err).Error("cannot add failed login attempt")
+		}
 		c.Status(http.StatusUnauthorized)
 		return
 	}
+	// Forget the failed attempts of the user
+	if err = a.Cache.Unlock(c.Request.Context(), userKey); err != nil {
+		log.WithError(err).Error("cannot reset login attempts")
+	}
+	a.issueToken(c, AuthData{
+		User:       request.User,
+		Department: user.Department,
+		IsStaff:    request.IsStaff,
+		Role:       user.Role,
+	})
+}
+
+// issueToken creates and allows a token for a user who has just logged in and sends it back
+func (a *API) issueToken(c *gin.Context, user AuthData) {
 	// Create the JWT
-	token, err := createJWTToken(a.jwtKey, request.User, departmentID, request.IsStaff)
+	claims := newJWTToken(user)
+	token, err := a.signJWTToken(claims)
 	if err != nil {
 		c.Status(http.StatusInternalServerError)
 		log.WithError(err).Error("cannot sign the jwt")
 		return
 	}
+	// Allow it
+	if err = a.Cache.Set(c.Request.Context(), claims.session(user.User)); err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot store the token")
+		return
+	}
 	// Send back the result
 	c.JSON(http.StatusOK, TokenResult{token})
 }
 
-// RefreshJWTToken refreshes the JWT token of a user
+// RefreshJWTToken refreshes the JWT token of a user. The old token is revoked. The department and
+// the role are read again, so the changes of staff take effect on refresh.
 func (a *API) RefreshJWTToken(c *gin.Context) {
 	// Get auth data
 	auth := c.MustGet(authInfoKey).(AuthData)
+	exists, user, err := a.Database.GetUser(c.Request.Context(), auth.User, auth.IsStaff)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot get user")
+		return
+	}
+	if !exists {
+		if err = a.Cache.Delete(c.Request.Context(), auth.TokenID); err != nil {
+			log.WithError(err).Error("cannot revoke the token")
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{reasonKey: "unknown user"})
+		return
+	}
+	auth.Department, auth.Role = user.Department, user.Role
 	// Sign again
-	token, err := createJWTToken(a.jwtKey, auth.User, auth.Department, auth.IsStaff)
+	claims := newJWTToken(auth)
+	token, err := a.signJWTToken(claims)
 	if err != nil {
 		c.Status(http.StatusInternalServerError)
 		log.WithError(err).Error("cannot sign the jwt")
 		return
 	}
+	// Replace the old one
+	if err = a.Cache.Replace(c.Request.Context(), auth.TokenID, claims.session(auth.User)); err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot replace the token")
+		return
+	}
 	// Send back the result
 	c.JSON(http.StatusOK, TokenResult{token})
 }
+
+// Logout revokes the token which the request is sent with
+func (a *API) Logout(c *gin.Context) {
+	auth := c.MustGet(authInfoKey).(AuthData)
+	if err := a.Cache.Delete(c.Request.Context(), auth.TokenID); err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot revoke the token")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PublicKeys sends the public keys which tokens are signed with. Tokens which are signed with
+// HS256 keys cannot be verified by anyone else, so those keys are never sent.
+func (a *API) PublicKeys(c *gin.Context) {
+	c.JSON(http.StatusOK, a.keys.Load().JWKS())
+}
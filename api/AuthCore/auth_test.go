@@ -0,0 +1,63 @@
+package AuthCore
+
+import (
+	"CourseEnrollment/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestSessions(t *testing.T) {
+	api := newTestAPI(t)
+	adminToken := api.login(t, testAdmin, testPassword, true)
+	staffToken := api.login(t, testStaff, testPassword, true)
+	assert.Equal(t, http.StatusUnauthorized, api.attemptLogin(t, 1, "wrong", false).Code)
+	// Refreshing revokes the old token
+	first := api.login(t, 1, testPassword, false)
+	var refreshed TokenResult
+	require.Equal(t, http.StatusOK, api.status(t, first, http.MethodPost, "/refresh", nil, &refreshed))
+	assert.False(t, api.isValid(t, first, false))
+	assert.True(t, api.isValid(t, refreshed.Token, false))
+	// Logging out revokes only that token
+	second := api.login(t, 1, testPassword, false)
+	assert.Equal(t, http.StatusNoContent, api.status(t, second, http.MethodPost, "/logout", nil, nil))
+	assert.False(t, api.isValid(t, second, false))
+	assert.Equal(t, http.StatusUnauthorized, api.status(t, second, http.MethodPost, "/logout", nil, nil))
+	assert.True(t, api.isValid(t, refreshed.Token, false))
+	// Staff can revoke every session of a student
+	third := api.login(t, 1, testPassword, false)
+	assert.Equal(t, http.StatusBadRequest, api.status(t, staffToken, http.MethodDelete, "/staff/sessions", nil, nil))
+	assert.Equal(t, http.StatusNoContent, api.status(t, staffToken, http.MethodDelete, "/staff/sessions?user_id=1", nil, nil))
+	assert.False(t, api.isValid(t, refreshed.Token, false))
+	assert.False(t, api.isValid(t, third, false))
+	assert.True(t, api.isValid(t, api.login(t, 1, testPassword, false), false))
+	// Only super admins can revoke the sessions of staff
+	assert.Equal(t, http.StatusForbidden, api.status(t, staffToken, http.MethodDelete, "/staff/sessions?user_id=100&staff=true", nil, nil))
+	assert.Equal(t, http.StatusNoContent, api.status(t, adminToken, http.MethodDelete, "/staff/sessions?user_id=101&staff=true", nil, nil))
+	assert.False(t, api.isValid(t, staffToken, true))
+	assert.True(t, api.isValid(t, adminToken, true))
+}
+
+func TestRefreshJWTToken(t *testing.T) {
+	api := newTestAPI(t)
+	adminToken := api.login(t, testAdmin, testPassword, true)
+	staffToken := api.login(t, testStaff, testPassword, true)
+	// The role is kept in refreshed tokens
+	var refreshed TokenResult
+	require.Equal(t, http.StatusOK, api.status(t, staffToken, http.MethodPost, "/refresh", nil, &refreshed))
+	staffToken = refreshed.Token
+	assert.Equal(t, http.StatusForbidden, api.status(t, staffToken, http.MethodDelete, "/staff/sessions?user_id=100&staff=true", nil, nil))
+	// Refreshed tokens get the current role and department
+	const otherDepartment = testDepartment + 1
+	api.db.AddDepartment(otherDepartment, "Mathematics")
+	api.db.AddStaff(database.MemoryStaff{ID: testAdmin, Department: otherDepartment})
+	require.Equal(t, http.StatusOK, api.status(t, adminToken, http.MethodPost, "/refresh", nil, &refreshed))
+	assert.Equal(t, http.StatusForbidden, api.status(t, refreshed.Token, http.MethodDelete, "/staff/sessions?user_id=101&staff=true", nil, nil))
+	assert.Equal(t, http.StatusForbidden, api.status(t, refreshed.Token, http.MethodPost, "/staff/password-reset",
+		CreatePasswordResetRequest{User: 1}, nil))
+	// Removed users cannot refresh
+	api.db.DeleteStaff(testStaff)
+	assert.Equal(t, http.StatusUnauthorized, api.status(t, staffToken, http.MethodPost, "/refresh", nil, nil))
+	assert.False(t, api.isValid(t, staffToken, true))
+}
@@ -1,19 +1,28 @@
 package AuthCore
 
 import (
-	"github.com/golang-jwt/jwt/v4"
+	pb "CourseEnrollment/pkg/proto"
 	"time"
 )
 
-// signingMethod is the method which we sign all jwt tokens with
-var signingMethod = jwt.SigningMethodHS256
-
 // jwtTTL is the time interval which jwt token is valid
 const jwtTTL = time.Hour
 
 // jwtIssuer which is "course enrollment auth"
 const jwtIssuer = "cea"
 
+// oidcLoginIssuer is the issuer of the state of single sign-ons, so it cannot be confused with tokens
+const oidcLoginIssuer = "cea-sso"
+
+// oidcLoginTTL is the time which users have to login in the identity provider
+const oidcLoginTTL = 10 * time.Minute
+
+// oidcCookie is the cookie which the state of single sign-on is kept in
+const oidcCookie = "cea_sso"
+
+// passwordResetTTL is the time which the reset tokens of passwords are valid
+const passwordResetTTL = 24 * time.Hour
+
 // authInfoKey is the key name which is in gin.Context keys map
 const authInfoKey = "auth"
 
@@ -22,3 +31,34 @@ const reasonKey = "reason"
 
 // requestKey is the key which maps to request data
 const requestKey = "request-data"
+
+// auditReasonHeader is the header which staff can send the reason of their changes in
+const auditReasonHeader = "X-Audit-Reason"
+
+// maxAuditReasonLength is the maximum number of characters in the reason of a change
+const maxAuditReasonLength = 500
+
+// auditPageSize is the maximum number of audit log entries which are returned at once
+const auditPageSize = 100
+
+// notificationsPageSize is the maximum number of notifications which are returned at once
+const notificationsPageSize = 100
+
+// studentChangeActions maps the actions of StudentChange to their proto values
+var studentChangeActions = map[string]pb.StudentChange_Action{
+	"enroll":       pb.StudentChange_ENROLL,
+	"disenroll":    pb.StudentChange_DISENROLL,
+	"change_group": pb.StudentChange_CHANGE_GROUP,
+}
+
+// lotteryPriorities maps the priorities of SchedulePhase to their proto values
+var lotteryPriorities = map[string]pb.EnrollmentPhase_Priority{
+	"seniority": pb.EnrollmentPhase_SENIORITY,
+	"gpa_band":  pb.EnrollmentPhase_GPA_BAND,
+}
+
+// lotteryPriorityNames maps the proto values of priorities to their names in SchedulePhase
+var lotteryPriorityNames = map[pb.EnrollmentPhase_Priority]string{
+	pb.EnrollmentPhase_SENIORITY: "seniority",
+	pb.EnrollmentPhase_GPA_BAND:  "gpa_band",
+}
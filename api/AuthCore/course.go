@@ -4,8 +4,12 @@ import (
 	"CourseEnrollment/pkg/proto"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // EnrolledCoursesOfStudent will return the enrolled courses of a student.
@@ -37,3 +41,58 @@ func (a *API) CoursesOfDepartment(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, courses)
 }
+
+// WatchCourses streams the seats of course groups as server-sent events. The groups are given in
+// "course" query parameters in the form of "course_id-group_id", or only "course_id" to watch every
+// group of a course. Each event is a CourseSeatsUpdate; the first one has the seats of every group.
+func (a *API) WatchCourses(c *gin.Context) {
+	// Parse the groups
+	request := new(proto.WatchCoursesRequest)
+	for _, value := range c.QueryArray("course") {
+		courseID, groupID, hasGroup := strings.Cut(value, "-")
+		group := new(proto.CourseGroup)
+		id, err := strconv.ParseInt(courseID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{reasonKey: "cannot parse course id"})
+			return
+		}
+		group.CourseId = int32(id)
+		if hasGroup {
+			id, err := strconv.ParseUint(groupID, 10, 8)
+			if err != nil || id == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{reasonKey: "cannot parse group id"})
+				return
+			}
+			group.GroupId = uint32(id)
+		}
+		request.Groups = append(request.Groups, group)
+	}
+	// The first update is received before sending the headers to report the errors properly
+	stream, err := a.CoreClient.WatchCourses(c.Request.Context(), request)
+	var update *proto.CourseSeatsUpdate
+	if err == nil {
+		update, err = stream.Recv()
+	}
+	if err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			c.JSON(http.StatusBadRequest, gin.H{reasonKey: status.Convert(err).Message()})
+			return
+		}
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot watch courses")
+		return
+	}
+	c.Stream(func(io.Writer) bool {
+		c.SSEvent("seats", update)
+		// Stream only flushes after this function returns, but the next update might take a while
+		c.Writer.Flush()
+		update, err = stream.Recv()
+		if err != nil {
+			if c.Request.Context().Err() == nil {
+				log.WithError(err).Error("cannot watch courses")
+			}
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,176 @@
+package AuthCore
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/go-faster/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"math/big"
+	"time"
+)
+
+// minHMACKeyLength is the minimum length of the secrets of HS256 keys in bytes
+const minHMACKeyLength = 32
+
+// signingKey is a key which tokens are signed and verified with
+type signingKey struct {
+	id     string
+	method jwt.SigningMethod
+	// []byte for HS256, *rsa.PrivateKey for RS256 and ed25519.PrivateKey for EdDSA
+	private any
+	// []byte for HS256, *rsa.PublicKey for RS256 and ed25519.PublicKey for EdDSA
+	public any
+	// The tokens which are signed with this key are rejected after this time. Zero means never.
+	expiresAt time.Time
+}
+
+// KeySet is the set of keys of the JWT tokens. New tokens are signed with the current key and
+// have its ID in their kid header. The tokens which are signed with the other keys are accepted
+// until the keys expire, so the keys can be rotated without logging everyone out.
+type KeySet struct {
+	current *signingKey
+	keys    map[string]*signingKey
+}
+
+// NewRandomKeySet creates a key set with a random HS256 key. The tokens which are signed with it
+// cannot be verified by other instances or after a restart.
+func NewRandomKeySet() *KeySet {
+	secret := make([]byte, minHMACKeyLength)
+	_, _ = rand.Read(secret)
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	key := &signingKey{id: hex.EncodeToString(id), method: jwt.SigningMethodHS256, private: secret, public: secret}
+	return &KeySet{current: key, keys: map[string]*signingKey{key.id: key}}
+}
+
+// keySetFile is the format of key set files
+type keySetFile struct {
+	// The ID of the key which new tokens are signed with
+	Current string `json:"current"`
+	Keys    []struct {
+		ID string `json:"kid"`
+		// HS256, RS256 or EdDSA
+		Algorithm string `json:"alg"`
+		// The base64 encoded secret of HS256 keys
+		Secret string `json:"secret"`
+		// The PEM encoded private key of RS256 and EdDSA keys
+		PrivateKey string     `json:"private_key"`
+		ExpiresAt  *time.Time `json:"expires_at"`
+	} `json:"keys"`
+}
+
+// ParseKeySet parses a key set from its JSON file
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var file keySetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "cannot parse keys")
+	}
+	result := &KeySet{keys: make(map[string]*signingKey, len(file.Keys))}
+	for _, k := range file.Keys {
+		if k.ID == "" {
+			return nil, errors.New("empty kid")
+		}
+		if _, exists := result.keys[k.ID]; exists {
+			return nil, errors.Errorf("duplicate kid: %s", k.ID)
+		}
+		key := &signingKey{id: k.ID}
+		if k.ExpiresAt != nil {
+			key.expiresAt = *k.ExpiresAt
+		}
+		var err error
+		switch k.Algorithm {
+		case jwt.SigningMethodHS256.Alg():
+			key.method = jwt.SigningMethodHS256
+			var secret []byte
+			secret, err = base64.StdEncoding.DecodeString(k.Secret)
+			if err == nil && len(secret) < minHMACKeyLength {
+				err = errors.Errorf("secret is shorter than %d bytes", minHMACKeyLength)
+			}
+			key.private, key.public = secret, secret
+		case jwt.SigningMethodRS256.Alg():
+			key.method = jwt.SigningMethodRS256
+			var private *rsa.PrivateKey
+			private, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(k.PrivateKey))
+			if err == nil {
+				key.private, key.public = private, &private.PublicKey
+			}
+		case jwt.SigningMethodEdDSA.Alg():
+			key.method = jwt.SigningMethodEdDSA
+			var private crypto.PrivateKey
+			private, err = jwt.ParseEdPrivateKeyFromPEM([]byte(k.PrivateKey))
+			if err == nil {
+				key.private, key.public = private, private.(ed25519.PrivateKey).Public()
+			}
+		default:
+			err = errors.Errorf("unknown alg: %s", k.Algorithm)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %s", k.ID)
+		}
+		result.keys[k.ID] = key
+	}
+	result.current = result.keys[file.Current]
+	if result.current == nil {
+		return nil, errors.Errorf("current key %q does not exist", file.Current)
+	}
+	if !result.current.expiresAt.IsZero() {
+		return nil, errors.New("current key cannot expire")
+	}
+	return result, nil
+}
+
+// sign signs the claims with the current key
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.current.method, claims)
+	token.Header["kid"] = s.current.id
+	return token.SignedString(s.current.private)
+}
+
+// verificationKey returns the key which a token must be verified with. It's meant to be used as
+// the jwt.Keyfunc of parsing tokens.
+func (s *KeySet) verificationKey(token *jwt.Token) (any, error) {
+	id, _ := token.Header["kid"].(string)
+	key, exists := s.keys[id]
+	if !exists {
+		return nil, fmt.Errorf("unknown kid: %q", id)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	if !key.expiresAt.IsZero() && time.Now().After(key.expiresAt) {
+		return nil, fmt.Errorf("expired kid: %q", id)
+	}
+	return key.public, nil
+}
+
+// JWKS returns the public keys which have not expired. HS256 keys are never published.
+func (s *KeySet) JWKS() JWKS {
+	result := JWKS{Keys: make([]JWK, 0, len(s.keys))}
+	now := time.Now()
+	for _, key := range s.keys {
+		if !key.expiresAt.IsZero() && now.After(key.expiresAt) {
+			continue
+		}
+		jwk := JWK{KeyID: key.id, Use: "sig", Algorithm: key.method.Alg()}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		result.Keys = append(result.Keys, jwk)
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package AuthCore
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testKeySet builds the JSON of a key set which signs with current
+func testKeySet(t *testing.T, current string, keys ...map[string]any) *KeySet {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"current": current, "keys": keys})
+	require.NoError(t, err)
+	result, err := ParseKeySet(data)
+	require.NoError(t, err)
+	return result
+}
+
+func TestParseKeySet(t *testing.T) {
+	_, err := ParseKeySet([]byte(`{"current":"none","keys":[]}`))
+	assert.Error(t, err)
+	_, err = ParseKeySet([]byte(`{"current":"short","keys":[{"kid":"short","alg":"HS256","secret":"c2hvcnQ="}]}`))
+	assert.Error(t, err)
+}
+
+func TestKeyRotation(t *testing.T) {
+	api := newTestAPI(t)
+	secret := map[string]any{"kid": "old", "alg": "HS256", "secret": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("s", 32)))}
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	edKey := map[string]any{"kid": "new", "alg": "EdDSA", "private_key": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))}
+	// Tokens of the old key are accepted while it has not expired
+	api.SetKeys(testKeySet(t, "old", secret))
+	oldToken := api.login(t, 1, testPassword, false)
+	secret["expires_at"] = time.Now().Add(time.Hour)
+	api.SetKeys(testKeySet(t, "new", secret, edKey))
+	assert.True(t, api.isValid(t, oldToken, false))
+	newToken := api.login(t, 1, testPassword, false)
+	assert.True(t, api.isValid(t, newToken, false))
+	// Only the public keys are published and the new tokens can be verified with them
+	var jwks JWKS
+	require.Equal(t, http.StatusOK, api.status(t, "", http.MethodGet, "/.well-known/jwks.json", nil, &jwks))
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, JWK{KeyType: "OKP", Use: "sig", Algorithm: "EdDSA", KeyID: "new", Curve: "Ed25519",
+		X: base64.RawURLEncoding.EncodeToString(public)}, jwks.Keys[0])
+	parts := strings.Split(newToken, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(public, []byte(parts[0]+"."+parts[1]), signature))
+	// Tokens of the old key are rejected after it has expired
+	secret["expires_at"] = time.Now().Add(-time.Minute)
+	api.SetKeys(testKeySet(t, "new", secret, edKey))
+	assert.False(t, api.isValid(t, oldToken, false))
+	assert.True(t, api.isValid(t, newToken, false))
+	// Tokens are rejected after their key is removed
+	api.SetKeys(testKeySet(t, "old", map[string]any{"kid": "old", "alg": "HS256", "secret": secret["secret"]}))
+	assert.False(t, api.isValid(t, newToken, false))
+}
@@ -1,12 +1,16 @@
 package AuthCore
 
 import (
-	"fmt"
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/course"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
 	"net/http"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // JWTAuthMiddleware is a middleware which authenticates the JWT token of user
@@ -22,12 +26,7 @@ func (a *API) JWTAuthMiddleware() gin.HandlerFunc {
 		}
 		header = header[len(prefix):]
 		// Parse the JWT
-		token, err := jwt.ParseWithClaims(header, new(JWTToken), func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return a.jwtKey, nil
-		})
+		token, err := jwt.ParseWithClaims(header, new(JWTToken), a.keys.Load().verificationKey)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{reasonKey: "invalid auth"})
 			return
@@ -41,17 +40,41 @@ func (a *API) JWTAuthMiddleware() gin.HandlerFunc {
 		authData := AuthData{
 			Department: claims.Department,
 			IsStaff:    claims.IsStaff,
+			Role:       claims.Role,
+			TokenID:    claims.ID,
 		}
 		authData.User, err = strconv.ParseUint(claims.Subject, 10, 64)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{reasonKey: "invalid auth"})
 			return
 		}
+		// Check if it's revoked
+		valid, err := a.Cache.IsValid(c.Request.Context(), claims.ID)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			log.WithError(err).Error("cannot check the token")
+			return
+		}
+		if !valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{reasonKey: "revoked auth"})
+			return
+		}
 		// Set in map
 		c.Set(authInfoKey, authData)
 	}
 }
 
+// TokenFromQuery uses the "token" query parameter as the JWT token if the request does not have
+// an Authorization header. It must be called before JWTAuthMiddleware. It's only meant for the
+// endpoints which browsers cannot set headers for, like server-sent events.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("token"); token != "" && c.Request.Header.Get("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+}
+
 // StudentOnly will only allow students to access this endpoint.
 // It must be called after JWTAuthMiddleware
 func StudentOnly() gin.HandlerFunc {
@@ -74,6 +97,48 @@ func StaffOnly() gin.HandlerFunc {
 	}
 }
 
+// SuperAdminOnly will only allow super admins to access this endpoint.
+// It must be called after StaffOnly
+func SuperAdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.MustGet(authInfoKey).(AuthData).Role != shared.StaffRoleSuperAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{reasonKey: "super admins only!"})
+			return
+		}
+	}
+}
+
+// ForwardActor sends the user to the enrollment server alongside every request, so the changes
+// which the user makes are audited. Staff can also send the reason of their changes in the
+// X-Audit-Reason header. The department and the role of staff are also sent, so the enrollment
+// server can limit them to their department. It must be called after JWTAuthMiddleware.
+func ForwardActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet(authInfoKey).(AuthData)
+		kind := course.ActorStudent
+		if user.IsStaff {
+			kind = course.ActorStaff
+		}
+		pairs := []string{
+			shared.AuditActorKindMetadataKey, strconv.FormatUint(uint64(kind), 10),
+			shared.AuditActorIDMetadataKey, strconv.FormatUint(user.User, 10),
+		}
+		if user.IsStaff {
+			pairs = append(pairs,
+				shared.StaffDepartmentMetadataKey, strconv.FormatUint(uint64(user.Department), 10),
+				shared.StaffRoleMetadataKey, strconv.FormatUint(uint64(user.Role), 10))
+		}
+		if reason := c.GetHeader(auditReasonHeader); reason != "" && user.IsStaff {
+			if utf8.RuneCountInString(reason) > maxAuditReasonLength {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{reasonKey: "audit reason is too long"})
+				return
+			}
+			pairs = append(pairs, shared.AuditReasonMetadataKey, reason)
+		}
+		c.Request = c.Request.WithContext(metadata.AppendToOutgoingContext(c.Request.Context(), pairs...))
+	}
+}
+
 // ParseEnrollmentBody will parse the body of a request into CourseEnrollmentRequest
 func ParseEnrollmentBody() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -85,3 +150,13 @@ func ParseEnrollmentBody() gin.HandlerFunc {
 		c.Set(requestKey, request)
 	}
 }
+
+// SSOEnabled is a middleware which rejects the requests of single sign-on if there is no identity
+// provider
+func (a *API) SSOEnabled() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.OIDC == nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{reasonKey: "sso is disabled"})
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package AuthCore
+
+import (
+	"CourseEnrollment/internal/oidc"
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// oidcLogin is the state of a single sign-on between redirecting the user to the identity provider
+// and its callback. It's signed with the keys of tokens and kept in a cookie of the user, so any
+// instance of auth core can handle the callback.
+type oidcLogin struct {
+	jwt.RegisteredClaims
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	IsStaff  bool   `json:"staff"`
+}
+
+// StartSSO redirects the user to the identity provider to login
+func (a *API) StartSSO(c *gin.Context) {
+	var request SSORequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	now := time.Now()
+	login := oidcLogin{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    oidcLoginIssuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcLoginTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		State:    oidc.NewRandom(),
+		Nonce:    oidc.NewRandom(),
+		Verifier: oidc.NewRandom(),
+		IsStaff:  request.IsStaff,
+	}
+	cookie, err := a.keys.Load().sign(login)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot sign the sso state")
+		return
+	}
+	// Lax, because the callback is a top level redirect from the provider
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcCookie, cookie, int(oidcLoginTTL.Seconds()), "/sso", "", a.ssoSecureCookie(), true)
+	c.Redirect(http.StatusFound, a.OIDC.AuthCodeURL(login.State, login.Nonce, oidc.Challenge(login.Verifier)))
+}
+
+// FinishSSO is the callback which the identity provider redirects the user to. The identity of the
+// user is exchanged with the provider and the token of their linked student or staff is sent back.
+func (a *API) FinishSSO(c *gin.Context) {
+	var request SSOCallbackRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if request.Error != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{reasonKey: "sso failed: " + request.Error})
+		return
+	}
+	if request.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: "empty code"})
+		return
+	}
+	// Check the state, which must be from the cookie of this browser
+	cookie, err := c.Cookie(oidcCookie)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: "sso is not started"})
+		return
+	}
+	c.SetCookie(oidcCookie, "", -1, "/sso", "", a.ssoSecureCookie(), true)
+	var login oidcLogin
+	_, err = jwt.ParseWithClaims(cookie, &login, a.keys.Load().verificationKey)
+	if err != nil || login.Issuer != oidcLoginIssuer ||
+		subtle.ConstantTimeCompare([]byte(login.State), []byte(request.State)) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: "invalid sso state"})
+		return
+	}
+	// Get the identity
+	identity, err := a.OIDC.Exchange(c.Request.Context(), request.Code, login.Verifier, login.Nonce)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{reasonKey: "invalid sso"})
+		log.WithError(err).Warn("cannot exchange sso code")
+		return
+	}
+	ok, id, user, err := a.Database.AuthExternalUser(c.Request.Context(), identity.Issuer, identity.Subject, login.IsStaff)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("subject", identity.Subject).Error("cannot find the user of identity")
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{reasonKey: "identity is not linked"})
+		return
+	}
+	a.issueToken(c, AuthData{
+		User:       id,
+		Department: user.Department,
+		IsStaff:    login.IsStaff,
+		Role:       user.Role,
+	})
+}
+
+// ssoSecureCookie checks if the cookie of sso must only be sent over HTTPS, which is when the
+// callback is on HTTPS
+func (a *API) ssoSecureCookie() bool {
+	return strings.HasPrefix(a.OIDC.Config().RedirectURL, "https://")
+}
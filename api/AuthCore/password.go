@@ -0,0 +1,155 @@
+package AuthCore
+
+import (
+	cache "CourseEnrollment/internal/cache/AuthCore"
+	db "CourseEnrollment/internal/database/AuthCore"
+	"CourseEnrollment/internal/password"
+	"CourseEnrollment/internal/shared"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// ChangePassword changes the password of the user. Every session of the user is revoked and a new
+// token is sent back.
+func (a *API) ChangePassword(c *gin.Context) {
+	auth := c.MustGet(authInfoKey).(AuthData)
+	// Parse request
+	var request ChangePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if err := password.Check(request.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	// Check the old password like logins, so a stolen token cannot guess it
+	userKey := cache.UserLimitKey(auth.User, auth.IsStaff)
+	if a.checkLocked(c, userKey) {
+		return
+	}
+	ok, _, err := a.Database.AuthUser(c.Request.Context(), auth.User, request.OldPassword, auth.IsStaff)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot check user credentials")
+		return
+	}
+	if !ok {
+		if _, err = a.addLoginAttempt(c, userKey, a.LoginLimits.UserFailures); err != nil {
+			log.WithError(err).Error("cannot add failed login attempt")
+		}
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "wrong password"})
+		return
+	}
+	// Change it
+	ok, err = a.Database.SetPassword(c.Request.Context(), auth.User, auth.IsStaff, request.NewPassword)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot change password")
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{reasonKey: "unknown user"})
+		return
+	}
+	if err = a.Cache.DeleteUser(c.Request.Context(), auth.User, auth.IsStaff); err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot revoke sessions")
+		return
+	}
+	a.issueToken(c, auth)
+}
+
+// CreatePasswordReset creates a one-time token which resets the password of a user. Staff give it
+// to the user, who sends it with their new password to ResetPassword. Staff can only reset the
+// students of their department and only super admins can reset staff.
+func (a *API) CreatePasswordReset(c *gin.Context) {
+	auth := c.MustGet(authInfoKey).(AuthData)
+	// Parse request
+	var request CreatePasswordResetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if request.IsStaff && auth.Role != shared.StaffRoleSuperAdmin {
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "super admins only!"})
+		return
+	}
+	exists, user, err := a.Database.GetUser(c.Request.Context(), request.User, request.IsStaff)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("request", request).Error("cannot get user")
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{reasonKey: "user not found"})
+		return
+	}
+	if !request.IsStaff && !auth.ManagesDepartment(user.Department) {
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "other department"})
+		return
+	}
+	// Create the token
+	token := make([]byte, 32)
+	_, _ = rand.Read(token)
+	result := PasswordResetResult{
+		Token:     hex.EncodeToString(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	err = a.Database.AddPasswordReset(c.Request.Context(), hashResetToken(result.Token),
+		db.PasswordReset{User: request.User, IsStaff: request.IsStaff}, result.ExpiresAt)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("request", request).Error("cannot add password reset")
+		return
+	}
+	log.WithField("staff", auth.User).WithField("request", request).Info("password reset is created")
+	c.JSON(http.StatusOK, result)
+}
+
+// ResetPassword sets the password of a user with a reset token. Every session of the user is
+// revoked and the user is unlocked.
+func (a *API) ResetPassword(c *gin.Context) {
+	// Parse request
+	var request ResetPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if err := password.Check(request.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	// Reset
+	ok, reset, err := a.Database.ResetPassword(c.Request.Context(), hashResetToken(request.Token), request.NewPassword)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot reset password")
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: "invalid reset token"})
+		return
+	}
+	if err = a.Cache.DeleteUser(c.Request.Context(), reset.User, reset.IsStaff); err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot revoke sessions")
+		return
+	}
+	if err = a.Cache.Unlock(c.Request.Context(), cache.UserLimitKey(reset.User, reset.IsStaff)); err != nil {
+		log.WithError(err).Error("cannot unlock user")
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// hashResetToken returns the hash of a reset token which is stored in the database, so the tokens
+// cannot be used by anyone who reads the database
+func hashResetToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
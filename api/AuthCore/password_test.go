@@ -0,0 +1,54 @@
+package AuthCore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestChangePassword(t *testing.T) {
+	api := newTestAPI(t)
+	// Changing the password needs the old one and revokes the other sessions
+	token := api.login(t, 1, testPassword, false)
+	other := api.login(t, 1, testPassword, false)
+	change := ChangePasswordRequest{OldPassword: "wrong", NewPassword: "new password"}
+	assert.Equal(t, http.StatusForbidden, api.status(t, token, http.MethodPut, "/password", change, nil))
+	change = ChangePasswordRequest{OldPassword: testPassword, NewPassword: "short"}
+	assert.Equal(t, http.StatusBadRequest, api.status(t, token, http.MethodPut, "/password", change, nil))
+	change.NewPassword = "new password"
+	var changed TokenResult
+	require.Equal(t, http.StatusOK, api.status(t, token, http.MethodPut, "/password", change, &changed))
+	assert.False(t, api.isValid(t, token, false))
+	assert.False(t, api.isValid(t, other, false))
+	assert.True(t, api.isValid(t, changed.Token, false))
+	assert.Equal(t, http.StatusUnauthorized, api.attemptLogin(t, 1, testPassword, false).Code)
+	api.login(t, 1, "new password", false)
+}
+
+func TestPasswordReset(t *testing.T) {
+	api := newTestAPI(t)
+	adminToken := api.login(t, testAdmin, testPassword, true)
+	staffToken := api.login(t, testStaff, testPassword, true)
+	// Staff reset the passwords of their students and super admins of staff
+	var reset PasswordResetResult
+	assert.Equal(t, http.StatusNotFound, api.status(t, staffToken, http.MethodPost, "/staff/password-reset",
+		CreatePasswordResetRequest{User: 99}, nil))
+	assert.Equal(t, http.StatusForbidden, api.status(t, staffToken, http.MethodPost, "/staff/password-reset",
+		CreatePasswordResetRequest{User: testAdmin, IsStaff: true}, nil))
+	require.Equal(t, http.StatusOK, api.status(t, adminToken, http.MethodPost, "/staff/password-reset",
+		CreatePasswordResetRequest{User: testStaff, IsStaff: true}, &reset))
+	require.Equal(t, http.StatusOK, api.status(t, staffToken, http.MethodPost, "/staff/password-reset",
+		CreatePasswordResetRequest{User: 2}, &reset))
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), reset.ExpiresAt, time.Minute)
+	// The token can be used once and revokes the sessions of the user
+	studentToken := api.login(t, 2, testPassword, false)
+	request := ResetPasswordRequest{Token: "wrong", NewPassword: "reset password"}
+	assert.Equal(t, http.StatusBadRequest, api.status(t, "", http.MethodPost, "/password/reset", request, nil))
+	request.Token = reset.Token
+	assert.Equal(t, http.StatusNoContent, api.status(t, "", http.MethodPost, "/password/reset", request, nil))
+	assert.Equal(t, http.StatusBadRequest, api.status(t, "", http.MethodPost, "/password/reset", request, nil))
+	assert.False(t, api.isValid(t, studentToken, false))
+	api.login(t, 2, "reset password", false)
+}
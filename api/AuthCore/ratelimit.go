@@ -0,0 +1,87 @@
+package AuthCore
+
+import (
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginLimits is the throttling of login attempts. A user is locked after too many failed
+// attempts, and an IP after too many attempts at all, so neither passwords can be guessed nor
+// bcrypt can be used to overload the auth core.
+type LoginLimits struct {
+	// The attempts are counted in a sliding window of this duration
+	Window time.Duration
+	// The number of failed attempts of a user in the window which locks it
+	UserFailures int
+	// The number of attempts from an IP in the window which locks it
+	IPAttempts int
+	// The duration of the first lock. Each next lock is twice the previous one, at most MaxLockout.
+	Lockout    time.Duration
+	MaxLockout time.Duration
+}
+
+// DefaultLoginLimits are the limits of logins in production. The IP limit is high because the
+// students of a university are usually behind a few NATs.
+var DefaultLoginLimits = LoginLimits{
+	Window:       15 * time.Minute,
+	UserFailures: 5,
+	IPAttempts:   300,
+	Lockout:      time.Minute,
+	MaxLockout:   time.Hour,
+}
+
+// clientIP returns the IP which the attempts of a request are counted for. X-Forwarded-For is only
+// used for the connections of TrustedProxies, so it cannot be spoofed. Connections over a unix
+// socket have no IP and can only be from a local reverse proxy, so the address which the proxy has
+// appended to X-Forwarded-For is used for them.
+func clientIP(c *gin.Context) string {
+	if ip := c.ClientIP(); ip != "" {
+		return ip
+	}
+	forwarded := c.Request.Header.Values("X-Forwarded-For")
+	if len(forwarded) == 0 {
+		return ""
+	}
+	last := forwarded[len(forwarded)-1]
+	return strings.TrimSpace(last[strings.LastIndexByte(last, ',')+1:])
+}
+
+// checkLocked aborts the request with 429 if any of the keys is locked. It returns true if the
+// request is aborted.
+func (a *API) checkLocked(c *gin.Context, keys ...string) bool {
+	for _, key := range keys {
+		duration, err := a.Cache.LockedFor(c.Request.Context(), key)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			log.WithError(err).WithField("key", key).Error("cannot check the lock")
+			return true
+		}
+		if duration > 0 {
+			abortLocked(c, duration)
+			return true
+		}
+	}
+	return false
+}
+
+// addLoginAttempt records an attempt of a key and locks it if it has reached limit. It returns
+// the duration of the lock, which is zero if the key is not locked.
+func (a *API) addLoginAttempt(c *gin.Context, key string, limit int) (time.Duration, error) {
+	attempts, err := a.Cache.AddLoginAttempt(c.Request.Context(), key, a.LoginLimits.Window)
+	if err != nil || attempts < limit {
+		return 0, err
+	}
+	log.WithField("key", key).Warn("too many login attempts")
+	return a.Cache.Lock(c.Request.Context(), key, a.LoginLimits.Lockout, a.LoginLimits.MaxLockout)
+}
+
+// abortLocked aborts a request which is locked for duration
+func abortLocked(c *gin.Context, duration time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(duration.Seconds()))))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{reasonKey: "too many login attempts"})
+}
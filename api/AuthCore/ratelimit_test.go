@@ -0,0 +1,113 @@
+package AuthCore
+
+import (
+	cache "CourseEnrollment/internal/cache/AuthCore"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   []string
+		expected       string
+	}{
+		{
+			name:       "no header",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:         "untrusted proxy",
+			remoteAddr:   "192.0.2.1:1234",
+			forwardedFor: []string{"198.51.100.1"},
+			expected:     "192.0.2.1",
+		},
+		{
+			name:           "trusted proxy",
+			trustedProxies: []string{"192.0.2.0/24"},
+			remoteAddr:     "192.0.2.1:1234",
+			forwardedFor:   []string{"198.51.100.1"},
+			expected:       "198.51.100.1",
+		},
+		{
+			name:         "unix socket",
+			remoteAddr:   "@",
+			forwardedFor: []string{"203.0.113.1", "198.51.100.2, 198.51.100.1"},
+			expected:     "198.51.100.1",
+		},
+		{
+			name:       "unix socket without header",
+			remoteAddr: "@",
+			expected:   "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := &API{TrustedProxies: test.trustedProxies}
+			r := api.Router()
+			var result string
+			r.GET("/ip", func(c *gin.Context) {
+				result = clientIP(c)
+			})
+			request := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			request.RemoteAddr = test.remoteAddr
+			for _, value := range test.forwardedFor {
+				request.Header.Add("X-Forwarded-For", value)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), request)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	api := newTestAPI(t)
+	adminToken := api.login(t, testAdmin, testPassword, true)
+	staffToken := api.login(t, testStaff, testPassword, true)
+	// A successful login forgets the failed attempts
+	for i := 0; i < DefaultLoginLimits.UserFailures-1; i++ {
+		assert.Equal(t, http.StatusUnauthorized, api.attemptLogin(t, 1, "wrong", false).Code)
+	}
+	api.login(t, 1, testPassword, false)
+	// Too many failed attempts lock the user, even with the right password
+	for i := 0; i < DefaultLoginLimits.UserFailures; i++ {
+		assert.Equal(t, http.StatusUnauthorized, api.attemptLogin(t, 1, "wrong", false).Code)
+	}
+	response := api.attemptLogin(t, 1, testPassword, false)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.Equal(t, "60", response.Header().Get("Retry-After"))
+	// Others can still login
+	api.login(t, 2, testPassword, false)
+	// Staff can unlock students but only super admins can unlock staff
+	assert.Equal(t, http.StatusBadRequest, api.status(t, staffToken, http.MethodDelete, "/staff/lockout", nil, nil))
+	assert.Equal(t, http.StatusForbidden, api.status(t, staffToken, http.MethodDelete, "/staff/lockout?user_id=100&staff=true", nil, nil))
+	assert.Equal(t, http.StatusNoContent, api.status(t, staffToken, http.MethodDelete, "/staff/lockout?user_id=1", nil, nil))
+	api.login(t, 1, testPassword, false)
+	assert.Equal(t, http.StatusNoContent, api.status(t, adminToken, http.MethodDelete, "/staff/lockout?user_id=101&staff=true", nil, nil))
+	// Too many attempts from an IP lock it, whether or not they are right
+	api.LoginLimits.IPAttempts = 3
+	require.NoError(t, api.Cache.Unlock(context.Background(), cache.IPLimitKey("192.0.2.1")))
+	for i := 0; i < api.LoginLimits.IPAttempts-1; i++ {
+		api.login(t, 1, testPassword, false)
+	}
+	response = api.attemptLogin(t, 1, testPassword, false)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.Equal(t, "60", response.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, api.attemptLogin(t, testAdmin, testPassword, true).Code)
+	// X-Forwarded-For is not trusted without a trusted proxy, so it cannot be spoofed to get a new IP
+	for i := 0; i < api.LoginLimits.IPAttempts; i++ {
+		response = api.request(t, "", http.MethodPost, "/login", http.Header{"X-Forwarded-For": {"198.51.100." + strconv.Itoa(i)}},
+			LoginRequest{User: 1, Password: testPassword}, nil)
+		assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	}
+}
@@ -0,0 +1,62 @@
+package AuthCore
+
+import (
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// Router creates the router of all endpoints of the auth core
+func (a *API) Router() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	// The client IP is the key of login throttling, so it must not be spoofable
+	if err := r.SetTrustedProxies(a.TrustedProxies); err != nil {
+		log.WithError(err).Error("invalid trusted proxies, trusting none")
+		_ = r.SetTrustedProxies(nil)
+	}
+	// Login and token refresh
+	r.POST("/login", a.LoginUser)
+	r.POST("/refresh", a.JWTAuthMiddleware(), a.RefreshJWTToken)
+	r.POST("/logout", a.JWTAuthMiddleware(), a.Logout)
+	r.GET("/.well-known/jwks.json", a.PublicKeys)
+	// Passwords
+	r.PUT("/password", a.JWTAuthMiddleware(), a.ChangePassword)
+	r.POST("/password/reset", a.ResetPassword)
+	// Single sign-on, if there is an identity provider
+	r.GET("/sso/login", a.SSOEnabled(), a.StartSSO)
+	r.GET("/sso/callback", a.SSOEnabled(), a.FinishSSO)
+	// Seats of courses for both students and staff
+	r.GET("/courses/watch", TokenFromQuery(), a.JWTAuthMiddleware(), a.WatchCourses)
+	// Student endpoints
+	studentRouter := r.Group("/student", a.JWTAuthMiddleware(), StudentOnly(), ForwardActor())
+	studentRouter.PUT("/course", ParseEnrollmentBody(), a.EnrollStudent)
+	studentRouter.PATCH("/course", ParseEnrollmentBody(), a.ChangeGroupOfStudent)
+	studentRouter.DELETE("/course", a.DisenrollStudent)
+	studentRouter.POST("/confirm", a.ConfirmPromotion)
+	studentRouter.GET("/course", a.EnrolledCoursesOfStudent)
+	studentRouter.GET("/courses", a.CoursesOfDepartment)
+	studentRouter.POST("/changes", a.ApplyStudentChanges)
+	studentRouter.PUT("/intent", ParseEnrollmentBody(), a.AddGroupChangeIntent)
+	studentRouter.DELETE("/intent", a.CancelGroupChangeIntent)
+	studentRouter.GET("/intents", a.GroupChangeIntentsOfStudent)
+	studentRouter.PUT("/wishlist", a.PutWishlist)
+	studentRouter.GET("/wishlist", a.WishlistOfStudent)
+	studentRouter.GET("/notifications", a.NotificationsOfStudent)
+	// Admin endpoints
+	staffRouter := r.Group("/staff", a.JWTAuthMiddleware(), StaffOnly(), ForwardActor())
+	staffRouter.PUT("/force-std", a.ForceEnroll)
+	staffRouter.DELETE("/force-std", a.ForceDisenroll)
+	staffRouter.GET("/student-courses", a.CoursesOfStudent)
+	staffRouter.GET("/course-students", a.StudentsOfCourse)
+	staffRouter.PATCH("/capacity", a.UpdateCourseCapacity)
+	staffRouter.PUT("/student", a.PutStudent)
+	staffRouter.PUT("/course", a.PutCourse)
+	staffRouter.GET("/schedule", a.GetSchedule)
+	staffRouter.PUT("/schedule", SuperAdminOnly(), a.PutSchedule)
+	staffRouter.POST("/lottery", SuperAdminOnly(), a.RunLottery)
+	staffRouter.GET("/audit", a.AuditLog)
+	staffRouter.DELETE("/sessions", a.RevokeSessions)
+	staffRouter.DELETE("/lockout", a.UnlockUser)
+	staffRouter.POST("/password-reset", a.CreatePasswordReset)
+	return r
+}
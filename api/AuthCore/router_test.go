@@ -0,0 +1,85 @@
+package AuthCore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestRequestValidation checks that invalid requests are rejected before they are sent to the
+// enrollment server. The test API has no enrollment server, so they would panic otherwise.
+func TestRequestValidation(t *testing.T) {
+	api := newTestAPI(t)
+	studentToken := api.login(t, 1, testPassword, false)
+	staffToken := api.login(t, testStaff, testPassword, true)
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		header http.Header
+		body   any
+	}{
+		{
+			name:   "invalid change action",
+			token:  studentToken,
+			method: http.MethodPost,
+			path:   "/student/changes",
+			body:   map[string]any{"changes": []map[string]any{{"action": "swap", "course_id": 40101}}},
+		},
+		{
+			name:   "invalid notifications page",
+			token:  studentToken,
+			method: http.MethodGet,
+			path:   "/student/notifications?after=abc",
+		},
+		{
+			name:   "invalid watched course",
+			token:  studentToken,
+			method: http.MethodGet,
+			path:   "/courses/watch?course=abc",
+		},
+		{
+			name:   "invalid watched group",
+			token:  studentToken,
+			method: http.MethodGet,
+			path:   "/courses/watch?course=40101-0",
+		},
+		{
+			name:   "negative confirmation window",
+			token:  staffToken,
+			method: http.MethodPut,
+			path:   "/staff/course",
+			body: map[string]any{"course_id": 40101, "group_id": 1, "department": testDepartment, "name": "Compilers",
+				"units": 3, "confirmation_window": -1},
+		},
+		{
+			name:   "audit log without filter",
+			token:  staffToken,
+			method: http.MethodGet,
+			path:   "/staff/audit",
+		},
+		{
+			name:   "invalid audit student",
+			token:  staffToken,
+			method: http.MethodGet,
+			path:   "/staff/audit?student_id=abc",
+		},
+		{
+			name:   "long audit reason",
+			token:  staffToken,
+			method: http.MethodDelete,
+			path:   "/staff/force-std?course_id=40101&group_id=1&std_id=2",
+			header: http.Header{"X-Audit-Reason": {strings.Repeat("a", maxAuditReasonLength+1)}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := api.request(t, test.token, test.method, test.path, test.header, test.body, nil)
+			assert.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+		})
+	}
+	// Students cannot use the endpoints of staff
+	assert.Equal(t, http.StatusUnauthorized, api.status(t, studentToken, http.MethodGet, "/staff/audit?student_id=1", nil, nil))
+}
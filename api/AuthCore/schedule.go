@@ -0,0 +1,108 @@
+package AuthCore
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"net/http"
+	"time"
+)
+
+// GetSchedule returns the enrollment schedule
+func (a *API) GetSchedule(c *gin.Context) {
+	schedule, err := a.CoreClient.GetSchedule(c.Request.Context(), new(emptypb.Empty))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).Error("cannot get schedule")
+		return
+	}
+	result := Schedule{Phases: make([]SchedulePhase, len(schedule.Phases))}
+	for i, phase := range schedule.Phases {
+		result.Phases[i] = SchedulePhase{
+			Name:             phase.Name,
+			AllowEnroll:      phase.AllowEnroll,
+			AllowDisenroll:   phase.AllowDisenroll,
+			AllowChangeGroup: phase.AllowChangeGroup,
+			StudentDuration:  phase.StudentDuration / int64(time.Second/time.Millisecond),
+			Windows:          make([]ScheduleWindow, len(phase.Windows)),
+			Lottery:          phase.Lottery,
+			Seed:             phase.Seed,
+		}
+		for _, priority := range phase.Priorities {
+			result.Phases[i].Priorities = append(result.Phases[i].Priorities, lotteryPriorityNames[priority])
+		}
+		for j, window := range phase.Windows {
+			result.Phases[i].Windows[j] = ScheduleWindow{
+				Department: course.DepartmentID(window.DepartmentId),
+				EntryYear:  int16(window.EntryYear),
+				Start:      time.UnixMilli(window.Start),
+				End:        time.UnixMilli(window.End),
+			}
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// PutSchedule replaces the enrollment schedule
+func (a *API) PutSchedule(c *gin.Context) {
+	// Parse request
+	var request Schedule
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	schedule := &proto.EnrollmentSchedule{Phases: make([]*proto.EnrollmentPhase, len(request.Phases))}
+	for i, phase := range request.Phases {
+		schedule.Phases[i] = &proto.EnrollmentPhase{
+			Name:             phase.Name,
+			AllowEnroll:      phase.AllowEnroll,
+			AllowDisenroll:   phase.AllowDisenroll,
+			AllowChangeGroup: phase.AllowChangeGroup,
+			StudentDuration:  (time.Duration(phase.StudentDuration) * time.Second).Milliseconds(),
+			Windows:          make([]*proto.EnrollmentWindow, len(phase.Windows)),
+			Lottery:          phase.Lottery,
+			Seed:             phase.Seed,
+		}
+		for _, priority := range phase.Priorities {
+			schedule.Phases[i].Priorities = append(schedule.Phases[i].Priorities, lotteryPriorities[priority])
+		}
+		for j, window := range phase.Windows {
+			schedule.Phases[i].Windows[j] = &proto.EnrollmentWindow{
+				DepartmentId: uint32(window.Department),
+				EntryYear:    int32(window.EntryYear),
+				Start:        window.Start.UnixMilli(),
+				End:          window.End.UnixMilli(),
+			}
+		}
+	}
+	// Do the request
+	_, err := a.CoreClient.PutSchedule(c.Request.Context(), schedule)
+	handleEnrollmentRPCError(c, err)
+}
+
+// RunLottery allocates the seats of a closed lottery phase based on the wishlists of students
+func (a *API) RunLottery(c *gin.Context) {
+	// Parse request
+	var request RunLotteryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	// Do the request
+	result, err := a.CoreClient.RunLottery(c.Request.Context(), &proto.RunLotteryRequest{Phase: request.Phase})
+	if err != nil {
+		handleEnrollmentRPCError(c, err)
+		return
+	}
+	response := LotteryResult{
+		Order:    make([]course.StudentID, len(result.Order)),
+		Enrolled: result.Enrolled,
+		Reserved: result.Reserved,
+	}
+	for i, id := range result.Order {
+		response.Order[i] = course.StudentID(id)
+	}
+	c.JSON(http.StatusOK, response)
+}
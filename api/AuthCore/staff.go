@@ -1,11 +1,17 @@
 package AuthCore
 
 import (
+	cache "CourseEnrollment/internal/cache/AuthCore"
+	"CourseEnrollment/internal/password"
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/course"
 	"CourseEnrollment/pkg/proto"
+	"context"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // ForceEnroll will forcibly enroll a student in a course.
@@ -98,3 +104,264 @@ func (a *API) UpdateCourseCapacity(c *gin.Context) {
 	})
 	handleEnrollmentRPCError(c, err)
 }
+
+// PutStudent creates a student or replaces the data of an existing one.
+// The enrolled courses of an existing student are kept.
+func (a *API) PutStudent(c *gin.Context) {
+	// Parse request
+	var request PutStudentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if !c.MustGet(authInfoKey).(AuthData).ManagesDepartment(request.Department) {
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "other department"})
+		return
+	}
+	// Hash the password
+	var passwordHash string
+	if request.Password != "" {
+		if err := password.Check(request.Password); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+			return
+		}
+		var err error
+		passwordHash, err = a.PasswordPolicy.Hash(request.Password)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			log.WithError(err).Error("cannot hash the password")
+			return
+		}
+	}
+	// Do the request
+	_, err := a.CoreClient.PutStudent(c.Request.Context(), &proto.PutStudentRequest{
+		StudentId:           uint64(request.StudentID),
+		PasswordHash:        passwordHash,
+		EnrollmentStartTime: request.EnrollmentStartTime.UnixMilli(),
+		MaxUnits:            uint32(request.MaxUnits),
+		RemainingActions:    uint32(request.RemainingActions),
+		DepartmentId:        uint32(request.Department),
+		EntryYear:           int32(request.EntryYear),
+		Female:              request.Sex == "female",
+		GpaBand:             uint32(request.GPABand),
+	})
+	handleEnrollmentRPCError(c, err)
+}
+
+// PutCourse creates a course group or replaces the data of an existing one.
+// The students of an existing group are kept.
+func (a *API) PutCourse(c *gin.Context) {
+	// Parse request
+	var request PutCourseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if !c.MustGet(authInfoKey).(AuthData).ManagesDepartment(request.Department) {
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "other department"})
+		return
+	}
+	var examTime int64
+	if request.ExamTime != nil {
+		examTime = request.ExamTime.Unix()
+	}
+	eligibility := &proto.CourseEligibility{
+		Departments:     make([]uint32, len(request.Departments)),
+		MinEntryYear:    int32(request.MinEntryYear),
+		MaxEntryYear:    int32(request.MaxEntryYear),
+		AllowedStudents: make([]uint64, len(request.AllowedStudents)),
+		DeniedStudents:  make([]uint64, len(request.DeniedStudents)),
+	}
+	for i, department := range request.Departments {
+		eligibility.Departments[i] = uint32(department)
+	}
+	for i, student := range request.AllowedStudents {
+		eligibility.AllowedStudents[i] = uint64(student)
+	}
+	for i, student := range request.DeniedStudents {
+		eligibility.DeniedStudents[i] = uint64(student)
+	}
+	var sexLock course.SexLock
+	switch request.SexLock {
+	case "male":
+		sexLock = course.SexLockMaleOnly
+	case "female":
+		sexLock = course.SexLockFemaleOnly
+	}
+	// Do the request
+	_, err := a.CoreClient.PutCourse(c.Request.Context(), &proto.PutCourseRequest{
+		CourseId:           int32(request.CourseID),
+		GroupId:            uint32(request.GroupID),
+		DepartmentId:       uint32(request.Department),
+		Name:               request.Name,
+		Lecturer:           request.Lecturer,
+		Units:              uint32(request.Units),
+		Capacity:           int32(request.Capacity),
+		ReserveCapacity:    int32(request.ReserveCapacity),
+		ExamTime:           examTime,
+		ClassDays:          request.ClassDays,
+		ClassStartMinute:   request.ClassStart,
+		ClassEndMinute:     request.ClassEnd,
+		SexLock:            uint32(sexLock),
+		Notes:              request.Notes,
+		Eligibility:        eligibility,
+		ConfirmationWindow: request.ConfirmationWindow,
+	})
+	handleEnrollmentRPCError(c, err)
+}
+
+// RevokeSessions revokes every token of a user, so they must login again. Staff can only revoke the
+// sessions of the students of their department and only super admins can revoke the sessions of staff.
+func (a *API) RevokeSessions(c *gin.Context) {
+	auth := c.MustGet(authInfoKey).(AuthData)
+	// Parse request
+	var request RevokeSessionsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if request.IsStaff && auth.Role != shared.StaffRoleSuperAdmin {
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "super admins only!"})
+		return
+	}
+	if !request.IsStaff {
+		exists, user, err := a.Database.GetUser(c.Request.Context(), request.User, false)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			log.WithError(err).WithField("request", request).Error("cannot get user")
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{reasonKey: "user not found"})
+			return
+		}
+		if !auth.ManagesDepartment(user.Department) {
+			c.JSON(http.StatusForbidden, gin.H{reasonKey: "other department"})
+			return
+		}
+	}
+	// Revoke
+	if err := a.Cache.DeleteUser(c.Request.Context(), request.User, request.IsStaff); err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("request", request).Error("cannot revoke sessions")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AuditLog gets the history of enrollment changes of a student, a course or the student in the
+// course. The after query parameter is the ID of the last seen entry. Staff can only get the history
+// of the students or the courses of their department.
+func (a *API) AuditLog(c *gin.Context) {
+	// Parse the filters
+	var studentID, after uint64
+	var courseID int64
+	var err error
+	if value := c.Query("student_id"); value != "" {
+		if studentID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{reasonKey: "invalid student_id"})
+			return
+		}
+	}
+	if value := c.Query("course_id"); value != "" {
+		if courseID, err = strconv.ParseInt(value, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{reasonKey: "invalid course_id"})
+			return
+		}
+	}
+	if studentID == 0 && courseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: "student_id or course_id is needed"})
+		return
+	}
+	if value := c.Query("after"); value != "" {
+		if after, err = strconv.ParseUint(value, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{reasonKey: "cannot parse after: " + err.Error()})
+			return
+		}
+	}
+	// Check the department
+	allowed, err := a.managesAuditTarget(c.Request.Context(), c.MustGet(authInfoKey).(AuthData), studentID, int32(courseID))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithFields(log.Fields{"student_id": studentID, "course_id": courseID}).Error("cannot get department")
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "other department"})
+		return
+	}
+	// Get the log
+	entries, err := a.Database.GetAuditLog(c.Request.Context(), studentID, int32(courseID), after, auditPageSize)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithFields(log.Fields{"student_id": studentID, "course_id": courseID}).Error("cannot get audit log")
+		return
+	}
+	result := AuditResult{Entries: make([]AuditEntry, len(entries))}
+	for i, entry := range entries {
+		result.Entries[i] = AuditEntry{
+			ID:        entry.ID,
+			Action:    entry.Action.String(),
+			StudentID: entry.StudentID,
+			CourseID:  entry.CourseID,
+			GroupID:   entry.GroupID,
+			Capacity:  entry.Capacity,
+			ActorKind: entry.ActorKind.String(),
+			ActorID:   entry.ActorID,
+			Origin:    entry.Origin,
+			Reason:    entry.Reason,
+			Time:      time.UnixMilli(entry.Time).UTC(),
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// managesAuditTarget checks if a staff can read the audit log of a student or a course. Every entry
+// of the log is about the student or the course; so it's enough that one of them is in a department
+// which the staff manages. Zero student or course ID is ignored.
+func (a *API) managesAuditTarget(ctx context.Context, auth AuthData, studentID uint64, courseID int32) (bool, error) {
+	if auth.Role == shared.StaffRoleSuperAdmin {
+		return true, nil
+	}
+	if studentID != 0 {
+		exists, user, err := a.Database.GetUser(ctx, studentID, false)
+		if err != nil {
+			return false, err
+		}
+		if exists && auth.ManagesDepartment(user.Department) {
+			return true, nil
+		}
+	}
+	if courseID != 0 {
+		exists, department, err := a.Database.GetCourseDepartment(ctx, courseID)
+		if err != nil {
+			return false, err
+		}
+		if exists && auth.ManagesDepartment(department) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UnlockUser unlocks a user which is locked because of failed logins. Only super admins can
+// unlock staff.
+func (a *API) UnlockUser(c *gin.Context) {
+	// Parse request
+	var request UnlockUserRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	if request.IsStaff && c.MustGet(authInfoKey).(AuthData).Role != shared.StaffRoleSuperAdmin {
+		c.JSON(http.StatusForbidden, gin.H{reasonKey: "super admins only!"})
+		return
+	}
+	// Unlock
+	if err := a.Cache.Unlock(c.Request.Context(), cache.UserLimitKey(request.User, request.IsStaff)); err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("request", request).Error("cannot unlock user")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,46 @@
+package AuthCore
+
+import (
+	"CourseEnrollment/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStaffDepartmentScope(t *testing.T) {
+	api := newTestAPI(t)
+	// Student 3 and course 20 are in another department
+	const otherDepartment = testDepartment + 1
+	api.db.AddDepartment(otherDepartment, "Mathematics")
+	hash, err := testPasswordPolicy.Hash(testPassword)
+	require.NoError(t, err)
+	api.db.AddStudent(database.MemoryStudent{ID: 3, Password: hash, MaxUnits: 20, Department: otherDepartment, EntryYear: 1400, EnrollmentStartTime: time.Now()})
+	api.db.AddCourse(database.MemoryCourse{ID: 10, GroupID: 1, Department: testDepartment, Name: "Programming", Units: 3, Capacity: 10})
+	api.db.AddCourse(database.MemoryCourse{ID: 20, GroupID: 1, Department: otherDepartment, Name: "Calculus", Units: 3, Capacity: 10})
+	adminToken := api.login(t, testAdmin, testPassword, true)
+	staffToken := api.login(t, testStaff, testPassword, true)
+	studentToken := api.login(t, 3, testPassword, false)
+	// Staff cannot revoke the sessions of the students of other departments
+	assert.Equal(t, http.StatusForbidden, api.status(t, staffToken, http.MethodDelete, "/staff/sessions?user_id=3", nil, nil))
+	assert.True(t, api.isValid(t, studentToken, false))
+	assert.Equal(t, http.StatusNotFound, api.status(t, staffToken, http.MethodDelete, "/staff/sessions?user_id=99", nil, nil))
+	// Staff can only read the audit log of the students and the courses of their department
+	for path, code := range map[string]int{
+		"/staff/audit?student_id=1":              http.StatusOK,
+		"/staff/audit?course_id=10":              http.StatusOK,
+		"/staff/audit?student_id=3&course_id=10": http.StatusOK,
+		"/staff/audit?student_id=3":              http.StatusForbidden,
+		"/staff/audit?course_id=20":              http.StatusForbidden,
+		"/staff/audit?student_id=3&course_id=20": http.StatusForbidden,
+		"/staff/audit?student_id=99":             http.StatusForbidden,
+	} {
+		assert.Equal(t, code, api.status(t, staffToken, http.MethodGet, path, nil, nil), path)
+	}
+	// Super admins manage every department
+	assert.Equal(t, http.StatusOK, api.status(t, adminToken, http.MethodGet, "/staff/audit?student_id=3", nil, nil))
+	assert.Equal(t, http.StatusOK, api.status(t, adminToken, http.MethodGet, "/staff/audit?course_id=20", nil, nil))
+	assert.Equal(t, http.StatusNoContent, api.status(t, adminToken, http.MethodDelete, "/staff/sessions?user_id=3", nil, nil))
+	assert.False(t, api.isValid(t, studentToken, false))
+}
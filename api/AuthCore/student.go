@@ -1,6 +1,8 @@
 package AuthCore
 
 import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/notification"
 	pb "CourseEnrollment/pkg/proto"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
@@ -8,6 +10,7 @@ import (
 	"google.golang.org/grpc/status"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // EnrollStudent will enroll the student in a course
@@ -53,17 +56,150 @@ func (a *API) DisenrollStudent(c *gin.Context) {
 	handleEnrollmentRPCError(c, err)
 }
 
+// ConfirmPromotion will confirm the seat of the student in a course which they are promoted to from
+// its reserve queue
+func (a *API) ConfirmPromotion(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	// Get the course ID from query
+	courseID, err := strconv.ParseInt(c.Query("course_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: "cannot parse course_id: " + err.Error()})
+		return
+	}
+	// Send data to enrollment core
+	_, err = a.CoreClient.StudentConfirmPromotion(c.Request.Context(), &pb.StudentDisenrollRequest{
+		StudentId: std.User,
+		CourseId:  int32(courseID),
+	})
+	handleEnrollmentRPCError(c, err)
+}
+
+// ApplyStudentChanges will apply several enroll, disenroll and change group actions of the student
+// at once. Either all of them are applied or none of them. All changed courses must be in departments
+// of the same queue shard; the changes of other shards must be sent in other requests.
+func (a *API) ApplyStudentChanges(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	// Parse request
+	var request StudentChangesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	changes := make([]*pb.StudentChange, len(request.Changes))
+	for i, change := range request.Changes {
+		changes[i] = &pb.StudentChange{
+			Action:   studentChangeActions[change.Action],
+			CourseId: int32(change.CourseID),
+			GroupId:  uint32(change.GroupID),
+		}
+	}
+	// Send data to enrollment core
+	_, err := a.CoreClient.StudentApplyChanges(c.Request.Context(), &pb.StudentApplyChangesRequest{
+		StudentId: std.User,
+		Changes:   changes,
+	})
+	handleEnrollmentRPCError(c, err)
+}
+
+// AddGroupChangeIntent will change the group of the student in a course if the destination group has
+// a free seat. Otherwise, the group is changed when a seat frees.
+func (a *API) AddGroupChangeIntent(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	request := c.MustGet(requestKey).(CourseEnrollmentRequest)
+	// Send data to enrollment core
+	result, err := a.CoreClient.AddGroupChangeIntent(c.Request.Context(), &pb.StudentChangeGroupRequest{
+		StudentId:  std.User,
+		CourseId:   int32(request.CourseID),
+		NewGroupId: uint32(request.GroupID),
+	})
+	if err != nil {
+		handleEnrollmentRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"changed": result.Changed})
+}
+
+// CancelGroupChangeIntent will cancel the intent of the student to change their group in a course
+func (a *API) CancelGroupChangeIntent(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	// Get the course ID from query
+	courseID, err := strconv.ParseInt(c.Query("course_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: "cannot parse course_id: " + err.Error()})
+		return
+	}
+	// Send data to enrollment core
+	_, err = a.CoreClient.CancelGroupChangeIntent(c.Request.Context(), &pb.StudentDisenrollRequest{
+		StudentId: std.User,
+		CourseId:  int32(courseID),
+	})
+	handleEnrollmentRPCError(c, err)
+}
+
+// GroupChangeIntentsOfStudent will return the pending group change intents of the student
+func (a *API) GroupChangeIntentsOfStudent(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	intents, err := a.CoreClient.GetGroupChangeIntents(c.Request.Context(), &pb.GetStudentCoursesRequest{StudentId: std.User})
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("user id", std.User).Error("cannot get intents")
+		return
+	}
+	c.JSON(http.StatusOK, intents)
+}
+
+// PutWishlist will replace the wishlist of the student in the open lottery phase
+func (a *API) PutWishlist(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	// Parse request
+	var request Wishlist
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{reasonKey: err.Error()})
+		return
+	}
+	entries := make([]*pb.WishlistEntry, len(request.Wishes))
+	for i, wish := range request.Wishes {
+		entries[i] = &pb.WishlistEntry{CourseId: int32(wish.CourseID), GroupId: uint32(wish.GroupID)}
+	}
+	// Send data to enrollment core
+	_, err := a.CoreClient.PutWishlist(c.Request.Context(), &pb.PutWishlistRequest{
+		StudentId: std.User,
+		Entries:   entries,
+	})
+	handleEnrollmentRPCError(c, err)
+}
+
+// WishlistOfStudent will return the wishlist of the student
+func (a *API) WishlistOfStudent(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	wishlist, err := a.CoreClient.GetWishlist(c.Request.Context(), &pb.GetStudentCoursesRequest{StudentId: std.User})
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("user id", std.User).Error("cannot get wishlist")
+		return
+	}
+	result := Wishlist{Phase: wishlist.Phase, Wishes: make([]CourseEnrollmentRequest, len(wishlist.Entries))}
+	for i, entry := range wishlist.Entries {
+		result.Wishes[i] = CourseEnrollmentRequest{CourseID: course.CourseID(entry.CourseId), GroupID: course.GroupID(entry.GroupId)}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // handleEnrollmentRPCError will handle the error returned from a gRPC request which corresponds to
 // an action which a student does.
 func handleEnrollmentRPCError(c *gin.Context, err error) {
 	if err != nil {
 		if statusError, ok := status.FromError(err); ok {
 			switch statusError.Code() {
-			case codes.NotFound, codes.FailedPrecondition:
+			case codes.NotFound, codes.FailedPrecondition, codes.InvalidArgument:
 				// Some errors like "not your enrollment time or etc."
 				// We directly send the error message
 				c.JSON(http.StatusBadRequest, gin.H{reasonKey: statusError.Message()})
 				return
+			case codes.PermissionDenied:
+				// A staff which has acted on another department
+				c.JSON(http.StatusForbidden, gin.H{reasonKey: statusError.Message()})
+				return
 			}
 		}
 		c.Status(http.StatusInternalServerError)
@@ -73,3 +209,44 @@ func handleEnrollmentRPCError(c *gin.Context, err error) {
 	// Done
 	c.Status(http.StatusNoContent)
 }
+
+// NotificationsOfStudent will get the inbox of the student. The after query parameter is the ID
+// of the last seen notification.
+func (a *API) NotificationsOfStudent(c *gin.Context) {
+	std := c.MustGet(authInfoKey).(AuthData)
+	var after uint64
+	if afterString := c.Query("after"); afterString != "" {
+		var err error
+		after, err = strconv.ParseUint(afterString, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{reasonKey: "cannot parse after: " + err.Error()})
+			return
+		}
+	}
+	notifications, err := a.Database.GetNotifications(c.Request.Context(), std.User, after, notificationsPageSize)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		log.WithError(err).WithField("user id", std.User).Error("cannot get notifications")
+		return
+	}
+	result := NotificationsResult{Notifications: make([]Notification, len(notifications))}
+	for i, n := range notifications {
+		subject, message := notification.Message(n.Event)
+		result.Notifications[i] = Notification{
+			ID:              n.ID,
+			Kind:            n.Kind.String(),
+			CourseID:        n.CourseID,
+			GroupID:         n.GroupID,
+			Capacity:        n.Capacity,
+			ReservePosition: n.ReservePosition,
+			Time:            time.UnixMilli(n.Time).UTC(),
+			Subject:         subject,
+			Message:         message,
+		}
+		if n.ConfirmationDeadline != 0 {
+			deadline := time.UnixMilli(n.ConfirmationDeadline).UTC()
+			result.Notifications[i].ConfirmationDeadline = &deadline
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
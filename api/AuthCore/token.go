@@ -1,24 +1,46 @@
 package AuthCore
 
 import (
-	"CourseEnrollment/pkg/course"
+	cache "CourseEnrollment/internal/cache/AuthCore"
+	"crypto/rand"
+	"encoding/hex"
 	"github.com/golang-jwt/jwt/v4"
 	"strconv"
 	"time"
 )
 
-// createJWTToken will create a JWT token for user authorization
-func createJWTToken(key []byte, userID uint64, department course.DepartmentID, isStaff bool) (string, error) {
+// newJWTToken will create the claims of a new JWT token for user authorization. Each token has
+// a random ID, so it can be revoked.
+func newJWTToken(user AuthData) JWTToken {
 	now := time.Now()
-	return jwt.NewWithClaims(signingMethod, JWTToken{
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return JWTToken{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(id),
 			Issuer:    jwtIssuer,
-			Subject:   strconv.FormatUint(userID, 10),
+			Subject:   strconv.FormatUint(user.User, 10),
 			ExpiresAt: jwt.NewNumericDate(now.Add(jwtTTL)),
 			NotBefore: jwt.NewNumericDate(now),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
-		Department: department,
-		IsStaff:    isStaff,
-	}).SignedString(key)
+		Department: user.Department,
+		IsStaff:    user.IsStaff,
+		Role:       user.Role,
+	}
+}
+
+// signJWTToken will sign the claims of a token with the current key
+func (a *API) signJWTToken(claims JWTToken) (string, error) {
+	return a.keys.Load().sign(claims)
+}
+
+// session returns the session of the token which is stored in the cache
+func (t JWTToken) session(user uint64) cache.Session {
+	return cache.Session{
+		ID:        t.ID,
+		User:      user,
+		IsStaff:   t.IsStaff,
+		ExpiresAt: t.ExpiresAt.Time,
+	}
 }
@@ -1,8 +1,11 @@
 package AuthCore
 
 import (
+	"CourseEnrollment/internal/shared"
 	"CourseEnrollment/pkg/course"
+	pb "CourseEnrollment/pkg/proto"
 	"github.com/golang-jwt/jwt/v4"
+	"time"
 )
 
 // LoginRequest is the login request which user sends to us
@@ -17,6 +20,8 @@ type JWTToken struct {
 	jwt.RegisteredClaims
 	Department course.DepartmentID `json:"department"`
 	IsStaff    bool                `json:"staff"`
+	// Only set for staff
+	Role shared.StaffRole `json:"role,omitempty"`
 }
 
 // AuthData is the struct which is passed to endpoints and contains the
@@ -25,6 +30,14 @@ type AuthData struct {
 	User       uint64
 	Department course.DepartmentID
 	IsStaff    bool
+	Role       shared.StaffRole
+	// The jti of the token
+	TokenID string
+}
+
+// ManagesDepartment reports whether a staff can change the courses and the students of a department
+func (a AuthData) ManagesDepartment(department course.DepartmentID) bool {
+	return a.Role == shared.StaffRoleSuperAdmin || a.Department == department
 }
 
 // TokenResult contains a JWT token only
@@ -41,6 +54,19 @@ type CourseEnrollmentRequest struct {
 	GroupID course.GroupID `form:"group_id" json:"group_id" binding:"required"`
 }
 
+// StudentChangesRequest is sent when a student wants to apply several changes to their courses at once
+type StudentChangesRequest struct {
+	Changes []StudentChange `json:"changes" binding:"required,min=1,dive"`
+}
+
+// StudentChange is a single change of StudentChangesRequest
+type StudentChange struct {
+	Action   string          `json:"action" binding:"required,oneof=enroll disenroll change_group"`
+	CourseID course.CourseID `json:"course_id" binding:"required"`
+	// The group to enroll in or change to. It's ignored when disenrolling.
+	GroupID course.GroupID `json:"group_id"`
+}
+
 // StaffCourseEnrollmentRequest is sent when a staff want's to do something with
 // courses of a student. For example force enroll or force disenroll.
 type StaffCourseEnrollmentRequest struct {
@@ -57,3 +83,219 @@ type ChangeCapacityStudent struct {
 	// The new capacity
 	NewCapacity int `form:"capacity" json:"capacity" binding:"required"`
 }
+
+// PutStudentRequest is sent to create a student or replace the data of an existing one
+type PutStudentRequest struct {
+	StudentID course.StudentID `json:"std_id" binding:"required"`
+	// The password is required for new students. If it's empty for an existing student,
+	// its password is not changed.
+	Password            string              `json:"password"`
+	EnrollmentStartTime time.Time           `json:"enrollment_start_time" binding:"required"`
+	MaxUnits            uint8               `json:"max_units" binding:"required"`
+	RemainingActions    uint8               `json:"remaining_actions"`
+	Department          course.DepartmentID `json:"department" binding:"required"`
+	EntryYear           int16               `json:"entry_year" binding:"required"`
+	Sex                 string              `json:"sex" binding:"required,oneof=male female"`
+	// Higher bands get seats earlier in the lottery phases which are ordered by GPA band
+	GPABand uint8 `json:"gpa_band"`
+}
+
+// PutCourseRequest is sent to create a course group or replace the data of an existing one
+type PutCourseRequest struct {
+	// The typical fields are available
+	CourseEnrollmentRequest
+	Department      course.DepartmentID `json:"department" binding:"required"`
+	Name            string              `json:"name" binding:"required"`
+	Lecturer        string              `json:"lecturer"`
+	Units           uint8               `json:"units" binding:"required"`
+	Capacity        int                 `json:"capacity"`
+	ReserveCapacity int                 `json:"reserve_capacity"`
+	// Nil means that this course has no exam
+	ExamTime *time.Time `json:"exam_time"`
+	// The days which class is held on, alongside the start and the end minute from 00:00
+	ClassDays  []pb.Weekday `json:"class_days"`
+	ClassStart uint32       `json:"class_start"`
+	ClassEnd   uint32       `json:"class_end"`
+	// Empty means no sex lock
+	SexLock string `json:"sex_lock" binding:"omitempty,oneof=male female"`
+	Notes   string `json:"notes"`
+	// Who can take this group. Empty fields do not restrict anyone.
+	Departments     []course.DepartmentID `json:"departments"`
+	MinEntryYear    int16                 `json:"min_entry_year"`
+	MaxEntryYear    int16                 `json:"max_entry_year"`
+	AllowedStudents []course.StudentID    `json:"allowed_students"`
+	DeniedStudents  []course.StudentID    `json:"denied_students"`
+	// How many seconds the students which are promoted from the reserve queue have to confirm their
+	// seat. Zero means that no confirmation is needed.
+	ConfirmationWindow int64 `json:"confirmation_window" binding:"gte=0"`
+}
+
+// Schedule is the enrollment schedule which staff get and put. Empty phases means that each
+// student can act in the hour after their enrollment start time.
+type Schedule struct {
+	Phases []SchedulePhase `json:"phases" binding:"dive"`
+}
+
+// SchedulePhase is a single phase of the enrollment schedule like add/drop
+type SchedulePhase struct {
+	Name             string `json:"name" binding:"required"`
+	AllowEnroll      bool   `json:"allow_enroll"`
+	AllowDisenroll   bool   `json:"allow_disenroll"`
+	AllowChangeGroup bool   `json:"allow_change_group"`
+	// If not zero, each student can only act for this many seconds after their enrollment start time
+	StudentDuration int64            `json:"student_duration" binding:"gte=0"`
+	Windows         []ScheduleWindow `json:"windows" binding:"dive"`
+	// In a lottery phase, students submit wishlists instead of acting directly and the seats are
+	// allocated when the phase is closed. The students are ordered by the priorities and the
+	// ties are broken randomly based on the seed.
+	Lottery    bool     `json:"lottery"`
+	Priorities []string `json:"priorities" binding:"dive,oneof=seniority gpa_band"`
+	Seed       int64    `json:"seed"`
+}
+
+// ScheduleWindow is the time range which a cohort can act in a phase
+type ScheduleWindow struct {
+	// Zero means every department
+	Department course.DepartmentID `json:"department"`
+	// Zero means every entry year
+	EntryYear int16     `json:"entry_year"`
+	Start     time.Time `json:"start" binding:"required"`
+	End       time.Time `json:"end" binding:"required"`
+}
+
+// Wishlist is the ranked list of groups which a student wants in a lottery phase. The phase is
+// ignored when a student puts their wishlist; it's always the open lottery phase.
+type Wishlist struct {
+	Phase string `json:"phase"`
+	// The first wish is the most wanted one. Empty wishes removes the wishlist.
+	Wishes []CourseEnrollmentRequest `json:"wishes" binding:"dive"`
+}
+
+// RunLotteryRequest is sent to allocate the seats of a closed lottery phase
+type RunLotteryRequest struct {
+	Phase string `json:"phase" binding:"required"`
+}
+
+// LotteryResult is the result of allocating the seats of a lottery phase
+type LotteryResult struct {
+	// The students which had a wishlist, in the order which they were given seats
+	Order []course.StudentID `json:"order"`
+	// How many wishes got a seat
+	Enrolled uint32 `json:"enrolled"`
+	// How many wishes got a place in a reserve queue
+	Reserved uint32 `json:"reserved"`
+}
+
+// Notification is a notification in the inbox of a student
+type Notification struct {
+	ID       uint64          `json:"id"`
+	Kind     string          `json:"kind"`
+	CourseID course.CourseID `json:"course_id"`
+	GroupID  course.GroupID  `json:"group_id"`
+	Capacity int             `json:"capacity"`
+	// The position of the student in the reserve queue. Zero means not in the queue.
+	ReservePosition int       `json:"reserve_position"`
+	Time            time.Time `json:"time"`
+	// The deadline of confirming the seat. Nil if the seat does not need confirmation.
+	ConfirmationDeadline *time.Time `json:"confirmation_deadline,omitempty"`
+	Subject              string     `json:"subject"`
+	Message              string     `json:"message"`
+}
+
+// NotificationsResult is the page of notifications which is returned to students. The next page
+// is the notifications after the ID of the last one.
+type NotificationsResult struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+// RevokeSessionsRequest is the user which their sessions are revoked
+type RevokeSessionsRequest struct {
+	User    uint64 `form:"user_id" binding:"required"`
+	IsStaff bool   `form:"staff"`
+}
+
+// SSORequest is the kind of user who is going to login with single sign-on
+type SSORequest struct {
+	IsStaff bool `form:"staff"`
+}
+
+// SSOCallbackRequest is the result of login which the identity provider redirects the user with
+type SSOCallbackRequest struct {
+	Code  string `form:"code"`
+	State string `form:"state"`
+	// Set instead of the code if the login has failed
+	Error string `form:"error"`
+}
+
+// ChangePasswordRequest is the new password of the user
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// CreatePasswordResetRequest is the user whose password is reset
+type CreatePasswordResetRequest struct {
+	User    uint64 `json:"user_id" binding:"required"`
+	IsStaff bool   `json:"staff"`
+}
+
+// PasswordResetResult is the one-time token which resets the password of a user
+type PasswordResetResult struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResetPasswordRequest is the new password of a user with their reset token
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// UnlockUserRequest is the user which is unlocked
+type UnlockUserRequest struct {
+	User    uint64 `form:"user_id" binding:"required"`
+	IsStaff bool   `form:"staff"`
+}
+
+// AuditEntry is an enrollment change in the audit log
+type AuditEntry struct {
+	ID     uint64 `json:"id"`
+	Action string `json:"action"`
+	// Zero if the change is not about a single student
+	StudentID course.StudentID `json:"student_id"`
+	CourseID  course.CourseID  `json:"course_id"`
+	// Zero if unknown
+	GroupID course.GroupID `json:"group_id"`
+	// The capacity of the group after a capacity change
+	Capacity  int       `json:"capacity,omitempty"`
+	ActorKind string    `json:"actor_kind"`
+	ActorID   uint64    `json:"actor_id"`
+	Origin    string    `json:"origin"`
+	Reason    string    `json:"reason,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// AuditResult is the page of audit log entries which is returned to staff. The next page is the
+// entries after the ID of the last one.
+type AuditResult struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+// JWK is a public key which tokens are signed with, in the format of RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	// The modulus and the exponent of RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// The curve and the public key of Ed25519 keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is the set of public keys which other services can verify the tokens with
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
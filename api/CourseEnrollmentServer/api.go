@@ -1,8 +1,10 @@
 package CourseEnrollmentServer
 
 import (
+	"CourseEnrollment/internal/reconcile"
 	"CourseEnrollment/pkg/course"
 	"CourseEnrollment/pkg/proto"
+	"sync"
 )
 
 // API is the server API which is used in course enrollment server
@@ -14,4 +16,17 @@ type API struct {
 	Students map[course.StudentID]*course.Student
 	// List of all courses
 	Courses *course.Courses
+	// Reconciler compares the state with the database. Reconciliation is disabled if it's nil.
+	Reconciler *reconcile.Reconciler
+	// Requests which change the state hold this for reading. Freeze holds it for writing
+	// to stop every change. Requests which add students or courses also hold it for writing.
+	stateLock sync.RWMutex
+}
+
+// Freeze calls f while no request can change the courses and students. Every message which
+// is batched before f is called is applied to the state, and nothing is batched while f is running.
+func (api *API) Freeze(f func()) {
+	api.stateLock.Lock()
+	defer api.stateLock.Unlock()
+	f()
 }
@@ -0,0 +1,37 @@
+package CourseEnrollmentServer
+
+import (
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/course"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"path"
+	"strconv"
+)
+
+// AuditInterceptor puts the audit of each request in its context, so the changes which the
+// request makes are audited. The actor is read from the metadata which AuthCore sends and the
+// origin is the name of the RPC. Requests without an actor are made by the system.
+func AuditInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	audit := course.Audit{Origin: path.Base(info.FullMethod)}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(shared.AuditActorKindMetadataKey); len(values) != 0 {
+			kind, _ := strconv.ParseUint(values[0], 10, 8)
+			audit.ActorKind = course.ActorKind(kind)
+		}
+		if values := md.Get(shared.AuditActorIDMetadataKey); len(values) != 0 {
+			audit.ActorID, _ = strconv.ParseUint(values[0], 10, 64)
+		}
+		if values := md.Get(shared.AuditReasonMetadataKey); len(values) != 0 {
+			audit.Reason = values[0]
+		}
+	}
+	return handler(course.WithAudit(ctx, audit), req)
+}
+
+// systemContext returns a context which the changes made with are audited as the changes of
+// the system in a job
+func systemContext(ctx context.Context, job string) context.Context {
+	return course.WithAudit(ctx, course.Audit{ActorKind: course.ActorSystem, Origin: job})
+}
@@ -0,0 +1,67 @@
+package CourseEnrollmentServer
+
+import (
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/course"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strconv"
+)
+
+// staffScope is the department and the role of the staff which has sent a request
+type staffScope struct {
+	department course.DepartmentID
+	role       shared.StaffRole
+}
+
+// staffScopeContextKey is the key of the staff scope in contexts
+type staffScopeContextKey struct{}
+
+// StaffScopeInterceptor puts the department and the role of the staff which has sent each request
+// in its context, so the handlers can limit them to their department. They are read from the
+// metadata which AuthCore sends. Requests which are not sent for a staff, like the ones of internal
+// tools, are not limited.
+func StaffScopeInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(shared.StaffRoleMetadataKey); len(values) != 0 {
+			var scope staffScope
+			role, err := strconv.ParseUint(values[0], 10, 8)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, "invalid staff role")
+			}
+			scope.role = shared.StaffRole(role)
+			if values = md.Get(shared.StaffDepartmentMetadataKey); len(values) != 0 {
+				department, err := strconv.ParseUint(values[0], 10, 8)
+				if err != nil {
+					return nil, status.Error(codes.InvalidArgument, "invalid staff department")
+				}
+				scope.department = course.DepartmentID(department)
+			}
+			ctx = context.WithValue(ctx, staffScopeContextKey{}, scope)
+		}
+	}
+	return handler(ctx, req)
+}
+
+// authorizeDepartment returns a PermissionDenied error if the request is sent for a staff which
+// cannot change the given department
+func authorizeDepartment(ctx context.Context, department course.DepartmentID) error {
+	scope, ok := ctx.Value(staffScopeContextKey{}).(staffScope)
+	if ok && scope.role != shared.StaffRoleSuperAdmin && scope.department != department {
+		return status.Error(codes.PermissionDenied, "other department")
+	}
+	return nil
+}
+
+// authorizeSuperAdmin returns a PermissionDenied error if the request is sent for a staff which is
+// not a super admin
+func authorizeSuperAdmin(ctx context.Context) error {
+	scope, ok := ctx.Value(staffScopeContextKey{}).(staffScope)
+	if ok && scope.role != shared.StaffRoleSuperAdmin {
+		return status.Error(codes.PermissionDenied, "super admins only")
+	}
+	return nil
+}
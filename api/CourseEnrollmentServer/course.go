@@ -10,6 +10,9 @@ import (
 
 // GetStudentEnrolledCourses will get the enrolled courses of a student
 func (api *API) GetStudentEnrolledCourses(_ context.Context, req *proto.GetStudentCoursesRequest) (*proto.StudentCourseDataArray, error) {
+	// Students might be added meanwhile
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
 	// Get student
 	std, exists := api.Students[course.StudentID(req.GetStudentId())]
 	if !exists {
@@ -23,3 +26,33 @@ func (api *API) GetStudentEnrolledCourses(_ context.Context, req *proto.GetStude
 func (api *API) GetCoursesOfDepartment(_ context.Context, req *proto.GetDepartmentCoursesRequest) (*proto.DepartmentCourses, error) {
 	return api.Courses.GetDepartmentCoursesProto(course.DepartmentID(req.GetDepartmentId())), nil
 }
+
+// WatchCourses streams the seats of some course groups until the client cancels the stream.
+// The state lock is only held while the watcher is created; so the stream does not stop any change.
+func (api *API) WatchCourses(req *proto.WatchCoursesRequest, stream proto.CourseEnrollmentServerService_WatchCoursesServer) error {
+	groups := make([]course.WatchedGroup, len(req.Groups))
+	for i, group := range req.Groups {
+		groups[i] = course.WatchedGroup{CourseID: course.CourseID(group.CourseId), GroupID: course.GroupID(group.GroupId)}
+	}
+	api.stateLock.RLock()
+	watcher, err := api.Courses.WatchSeats(groups)
+	api.stateLock.RUnlock()
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	defer watcher.Close()
+	for {
+		seats, err := watcher.Next(stream.Context())
+		if err != nil {
+			// The client has gone
+			return nil
+		}
+		update := &proto.CourseSeatsUpdate{Courses: make([]*proto.CourseSeats, len(seats))}
+		for i := range seats {
+			update.Courses[i] = seats[i].ToProto()
+		}
+		if err := stream.Send(update); err != nil {
+			return err
+		}
+	}
+}
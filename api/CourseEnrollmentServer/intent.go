@@ -0,0 +1,88 @@
+package CourseEnrollmentServer
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// AddGroupChangeIntent changes the group of a student if the destination group has a free seat.
+// Otherwise, it registers an intent to change the group when a seat frees.
+func (api *API) AddGroupChangeIntent(ctx context.Context, r *proto.StudentChangeGroupRequest) (*proto.AddGroupChangeIntentResponse, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	// Get student
+	std, ok := api.Students[course.StudentID(r.StudentId)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "student_id")
+	}
+	// Add the intent
+	changed, err := std.AddIntent(ctx, api.Courses, course.CourseID(r.CourseId), course.GroupID(r.NewGroupId), api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	// The old group of student has a free seat now
+	if changed {
+		api.fulfillIntents(ctx, course.CourseID(r.CourseId))
+	}
+	return &proto.AddGroupChangeIntentResponse{Changed: changed}, nil
+}
+
+// CancelGroupChangeIntent removes the intent of a student to change their group in a course
+func (api *API) CancelGroupChangeIntent(ctx context.Context, r *proto.StudentDisenrollRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	// Get student
+	std, ok := api.Students[course.StudentID(r.StudentId)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "student_id")
+	}
+	// Cancel it
+	err := std.CancelIntent(ctx, api.Courses, course.CourseID(r.CourseId), api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	// Done
+	return new(emptypb.Empty), nil
+}
+
+// GetGroupChangeIntents lists the pending intents of a student
+func (api *API) GetGroupChangeIntents(_ context.Context, r *proto.GetStudentCoursesRequest) (*proto.GroupChangeIntents, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	// Get student
+	std, ok := api.Students[course.StudentID(r.StudentId)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "student_id")
+	}
+	return std.GetIntentsProto(api.Courses), nil
+}
+
+// fulfillIntents fulfills the intents of a course after a seat is freed in one of its groups.
+// The seat is already freed, so the errors are only logged. The moves are audited as the changes
+// of the system.
+func (api *API) fulfillIntents(ctx context.Context, courseID course.CourseID) {
+	err := course.FulfillIntents(systemContext(ctx, "FulfillIntents"), api.Courses, api.Students, courseID, api.Broker)
+	if err != nil {
+		log.WithError(err).WithField("course_id", courseID).Error("cannot fulfill intents")
+	}
+}
@@ -0,0 +1,105 @@
+package CourseEnrollmentServer
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// PutWishlist replaces the wishlist of a student in the lottery phase which is open for them
+func (api *API) PutWishlist(ctx context.Context, r *proto.PutWishlistRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	// Get student
+	std, ok := api.Students[course.StudentID(r.StudentId)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "student_id")
+	}
+	wishes := make([]course.Wish, len(r.Entries))
+	for i, entry := range r.Entries {
+		wishes[i] = course.Wish{CourseID: course.CourseID(entry.CourseId), GroupID: course.GroupID(entry.GroupId)}
+	}
+	// Put it
+	err := std.PutWishlist(ctx, api.Courses, wishes, api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	// Done
+	return new(emptypb.Empty), nil
+}
+
+// GetWishlist returns the wishlist of a student
+func (api *API) GetWishlist(_ context.Context, r *proto.GetStudentCoursesRequest) (*proto.Wishlist, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	// Get student
+	std, ok := api.Students[course.StudentID(r.StudentId)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "student_id")
+	}
+	return std.GetWishlistProto(), nil
+}
+
+// RunLottery allocates the seats of a closed lottery phase. Nothing else can change the state
+// while the lottery runs; so the result only depends on the wishlists and the seed of the phase.
+func (api *API) RunLottery(ctx context.Context, r *proto.RunLotteryRequest) (*proto.RunLotteryResponse, error) {
+	if err := authorizeSuperAdmin(ctx); err != nil {
+		return nil, err
+	}
+	api.stateLock.Lock()
+	defer api.stateLock.Unlock()
+	result, err := course.RunLottery(ctx, api.Courses, api.Students, r.Phase, api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	response := &proto.RunLotteryResponse{
+		Order:    make([]uint64, len(result.Order)),
+		Enrolled: uint32(result.Enrolled),
+		Reserved: uint32(result.Reserved),
+	}
+	for i, id := range result.Order {
+		response.Order[i] = uint64(id)
+	}
+	return response, nil
+}
+
+// RunPendingLotteries runs the lottery of every closed lottery phase which still has wishlists.
+// The errors are only logged because it's meant to be called periodically.
+func (api *API) RunPendingLotteries(ctx context.Context) {
+	ctx = systemContext(ctx, "RunPendingLotteries")
+	api.stateLock.RLock()
+	phases := course.PendingLotteries(api.Courses, api.Students)
+	api.stateLock.RUnlock()
+	for _, phase := range phases {
+		result, err := api.RunLottery(ctx, &proto.RunLotteryRequest{Phase: phase})
+		if err != nil {
+			log.WithError(err).WithField("phase", phase).Error("cannot run lottery")
+			continue
+		}
+		log.WithFields(log.Fields{
+			"phase":    phase,
+			"students": len(result.Order),
+			"enrolled": result.Enrolled,
+			"reserved": result.Reserved,
+		}).Info("lottery done")
+	}
+}
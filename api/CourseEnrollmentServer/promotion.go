@@ -0,0 +1,60 @@
+package CourseEnrollmentServer
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// StudentConfirmPromotion confirms the seat of a student which is promoted from the reserve queue
+// of a course which needs confirmation
+func (api *API) StudentConfirmPromotion(ctx context.Context, r *proto.StudentDisenrollRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	// Get student
+	std, ok := api.Students[course.StudentID(r.StudentId)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "student_id")
+	}
+	// Confirm
+	err := std.ConfirmPromotion(ctx, api.Courses, course.CourseID(r.CourseId), api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	return new(emptypb.Empty), nil
+}
+
+// ExpirePromotions drops the promoted students which have not confirmed their seats in time. The
+// errors are only logged because it's meant to be called periodically.
+func (api *API) ExpirePromotions(ctx context.Context) {
+	ctx = systemContext(ctx, "ExpirePromotions")
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	expired, err := course.ExpirePromotions(ctx, api.Courses, api.Students, api.Broker)
+	if err != nil {
+		log.WithError(err).Error("cannot expire promotions")
+	}
+	// The seats which nobody was waiting for are freed
+	freed := make(map[course.CourseID]struct{}, len(expired))
+	for _, promotion := range expired {
+		if _, done := freed[promotion.CourseID]; !done {
+			freed[promotion.CourseID] = struct{}{}
+			api.fulfillIntents(ctx, promotion.CourseID)
+		}
+	}
+	if len(expired) != 0 {
+		log.WithField("students", len(expired)).Info("expired promotions")
+	}
+}
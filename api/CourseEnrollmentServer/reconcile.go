@@ -0,0 +1,22 @@
+package CourseEnrollmentServer
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reconcile compares the courses with the database and repairs the differences if requested
+func (api *API) Reconcile(ctx context.Context, req *proto.ReconcileRequest) (*proto.ReconcileResponse, error) {
+	if api.Reconciler == nil {
+		return nil, status.Error(codes.Unimplemented, "reconciliation is not enabled")
+	}
+	result, err := api.Reconciler.Run(ctx, api.Freeze, api.Courses, api.Students, req.Repair)
+	if err != nil {
+		log.WithError(err).Error("cannot reconcile")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return result, nil
+}
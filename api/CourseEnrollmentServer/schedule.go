@@ -0,0 +1,48 @@
+package CourseEnrollmentServer
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetSchedule returns the enrollment schedule. An empty schedule means that each student can act
+// in the hour after their enrollment start time.
+func (api *API) GetSchedule(context.Context, *emptypb.Empty) (*proto.EnrollmentSchedule, error) {
+	schedule := api.Courses.Schedule()
+	if schedule == nil {
+		return new(proto.EnrollmentSchedule), nil
+	}
+	return schedule.ToProto(), nil
+}
+
+// PutSchedule replaces the enrollment schedule. An empty schedule restores the default one.
+func (api *API) PutSchedule(ctx context.Context, req *proto.EnrollmentSchedule) (*emptypb.Empty, error) {
+	if err := authorizeSuperAdmin(ctx); err != nil {
+		return nil, err
+	}
+	schedule, err := course.NewScheduleFromProto(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	err = course.PutSchedule(ctx, api.Courses, schedule, api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	// Done
+	return new(emptypb.Empty), nil
+}
@@ -9,6 +9,8 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"math"
+	"time"
 )
 
 // GetStudentsInCourse gets all the students in a course
@@ -24,6 +26,11 @@ func (api *API) GetStudentsInCourse(_ context.Context, req *proto.StudentsOfCour
 
 // ForceEnroll will forcibly enroll a student in a course, increasing the capacity if needed
 func (api *API) ForceEnroll(ctx context.Context, req *proto.StudentEnrollRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	if err := api.authorizeCourse(ctx, course.CourseID(req.CourseId)); err != nil {
+		return nil, err
+	}
 	// Get student
 	std, ok := api.Students[course.StudentID(req.StudentId)]
 	if !ok {
@@ -49,6 +56,11 @@ func (api *API) ForceEnroll(ctx context.Context, req *proto.StudentEnrollRequest
 // This means that the api won't check for registration time nor remaining actions.
 // This call won't change the remaining actions of user.
 func (api *API) ForceDisenroll(ctx context.Context, req *proto.StudentDisenrollRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	if err := api.authorizeCourse(ctx, course.CourseID(req.CourseId)); err != nil {
+		return nil, err
+	}
 	// Get student
 	std, ok := api.Students[course.StudentID(req.StudentId)]
 	if !ok {
@@ -66,6 +78,8 @@ func (api *API) ForceDisenroll(ctx context.Context, req *proto.StudentDisenrollR
 		}
 		return nil, err
 	}
+	// A seat is freed
+	api.fulfillIntents(ctx, course.CourseID(req.CourseId))
 	// Done
 	return new(emptypb.Empty), nil
 }
@@ -73,11 +87,16 @@ func (api *API) ForceDisenroll(ctx context.Context, req *proto.StudentDisenrollR
 // ChangeCapacity will update a course's capacity. It can fail if we try to shrink the capacity
 // while users are registered in course.
 func (api *API) ChangeCapacity(ctx context.Context, req *proto.ChangeCourseCapacityRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
 	// Get the course
-	c := api.Courses.GetCourse(course.CourseID(req.CourseId), course.GroupID(req.CourseId))
+	c := api.Courses.GetCourse(course.CourseID(req.CourseId), course.GroupID(req.GroupId))
 	if c == nil {
 		return nil, status.Error(codes.NotFound, "course")
 	}
+	if err := authorizeDepartment(ctx, c.Department); err != nil {
+		return nil, err
+	}
 	// Update capacity
 	err := c.UpdateCapacity(ctx, int(req.NewCapacity), api.Broker)
 	if err != nil {
@@ -90,6 +109,116 @@ func (api *API) ChangeCapacity(ctx context.Context, req *proto.ChangeCourseCapac
 		}
 		return nil, err
 	}
+	// A seat might be freed
+	api.fulfillIntents(ctx, course.CourseID(req.CourseId))
+	// Done
+	return new(emptypb.Empty), nil
+}
+
+// PutStudent creates a new student or replaces the data of an existing one. Nothing else can
+// change the state while the student is being put.
+func (api *API) PutStudent(ctx context.Context, req *proto.PutStudentRequest) (*emptypb.Empty, error) {
+	if err := authorizeDepartment(ctx, course.DepartmentID(req.DepartmentId)); err != nil {
+		return nil, err
+	}
+	api.stateLock.Lock()
+	defer api.stateLock.Unlock()
+	// Staff cannot move the students of other departments to their own
+	if std, exists := api.Students[course.StudentID(req.StudentId)]; exists {
+		if err := authorizeDepartment(ctx, std.Department); err != nil {
+			return nil, err
+		}
+	}
+	sex := course.SexMale
+	if req.Female {
+		sex = course.SexFemale
+	}
+	err := course.PutStudent(ctx, api.Students, &proto.CourseDatabaseBatchPutStudent{
+		StudentId:           req.StudentId,
+		PasswordHash:        req.PasswordHash,
+		EnrollmentStartTime: req.EnrollmentStartTime,
+		MaxUnits:            req.MaxUnits,
+		RemainingActions:    req.RemainingActions,
+		DepartmentId:        req.DepartmentId,
+		EntryYear:           req.EntryYear,
+		Sex:                 uint32(sex),
+		GpaBand:             req.GpaBand,
+	}, api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	// Done
+	return new(emptypb.Empty), nil
+}
+
+// PutCourse creates a new course group or replaces the data of an existing one. Nothing else can
+// change the state while the course is being put.
+func (api *API) PutCourse(ctx context.Context, req *proto.PutCourseRequest) (*emptypb.Empty, error) {
+	// The department of an existing course cannot change, so checking the new one is enough
+	if err := authorizeDepartment(ctx, course.DepartmentID(req.DepartmentId)); err != nil {
+		return nil, err
+	}
+	// Check the values which do not fit in the course
+	if req.ClassStartMinute > req.ClassEndMinute || req.ClassEndMinute > course.TimeOnlyMax || req.Units > math.MaxUint8 || req.GroupId > math.MaxUint8 {
+		return nil, status.Error(codes.InvalidArgument, "invalid course")
+	}
+	days := make([]time.Weekday, len(req.ClassDays))
+	for i, day := range req.ClassDays {
+		if day > proto.Weekday_FRIDAY {
+			return nil, status.Error(codes.InvalidArgument, "invalid class day")
+		}
+		days[i] = time.Weekday(day)
+	}
+	var classTime course.ClassTime
+	classTime.Set(days, course.NewTimeOnly(uint16(req.ClassStartMinute)), course.NewTimeOnly(uint16(req.ClassEndMinute)))
+	// Put it
+	api.stateLock.Lock()
+	defer api.stateLock.Unlock()
+	err := course.PutCourse(ctx, api.Courses, api.Students, &proto.CourseDatabaseBatchPutCourse{
+		CourseId:           req.CourseId,
+		GroupId:            req.GroupId,
+		DepartmentId:       req.DepartmentId,
+		Name:               req.Name,
+		Lecturer:           req.Lecturer,
+		Units:              req.Units,
+		Capacity:           req.Capacity,
+		ReserveCapacity:    req.ReserveCapacity,
+		ExamTime:           req.ExamTime,
+		ClassTime:          classTime.Raw(),
+		SexLock:            req.SexLock,
+		Notes:              req.Notes,
+		Eligibility:        req.Eligibility,
+		ConfirmationWindow: req.ConfirmationWindow,
+	}, api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	// A seat might be freed
+	api.fulfillIntents(ctx, course.CourseID(req.CourseId))
 	// Done
 	return new(emptypb.Empty), nil
 }
+
+// authorizeCourse returns a PermissionDenied error if the request is sent for a staff which cannot
+// change the course. Courses which do not exist are left for the handlers to reject.
+func (api *API) authorizeCourse(ctx context.Context, courseID course.CourseID) error {
+	department, exists := api.Courses.DepartmentOf(courseID)
+	if !exists {
+		return nil
+	}
+	return authorizeDepartment(ctx, department)
+}
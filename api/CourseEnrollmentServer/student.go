@@ -16,6 +16,8 @@ import (
 
 // StudentEnroll must be called with PUT to enroll a student.
 func (api *API) StudentEnroll(ctx context.Context, r *proto.StudentEnrollRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
 	// Get student
 	std, ok := api.Students[course.StudentID(r.StudentId)]
 	if !ok {
@@ -39,6 +41,8 @@ func (api *API) StudentEnroll(ctx context.Context, r *proto.StudentEnrollRequest
 
 // StudentDisenroll must be called with DELETE to disenroll a student.
 func (api *API) StudentDisenroll(ctx context.Context, r *proto.StudentDisenrollRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
 	// Get student
 	std, ok := api.Students[course.StudentID(r.StudentId)]
 	if !ok {
@@ -56,12 +60,16 @@ func (api *API) StudentDisenroll(ctx context.Context, r *proto.StudentDisenrollR
 		}
 		return nil, err
 	}
+	// A seat is freed
+	api.fulfillIntents(ctx, course.CourseID(r.CourseId))
 	// Done
 	return new(emptypb.Empty), err
 }
 
 // StudentChangeGroup must be called with PATCH to change group of a student.
 func (api *API) StudentChangeGroup(ctx context.Context, r *proto.StudentChangeGroupRequest) (*emptypb.Empty, error) {
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
 	// Get student
 	std, ok := api.Students[course.StudentID(r.StudentId)]
 	if !ok {
@@ -79,6 +87,55 @@ func (api *API) StudentChangeGroup(ctx context.Context, r *proto.StudentChangeGr
 		}
 		return nil, err
 	}
+	// A seat is freed
+	api.fulfillIntents(ctx, course.CourseID(r.CourseId))
 	// Done
 	return new(emptypb.Empty), nil
 }
+
+// StudentApplyChanges applies a list of enroll, disenroll and change group actions of a student
+// atomically.
+func (api *API) StudentApplyChanges(ctx context.Context, r *proto.StudentApplyChangesRequest) (*emptypb.Empty, error) {
+	// Convert the changes
+	changes := make([]course.Change, len(r.Changes))
+	for i, change := range r.Changes {
+		switch change.Action {
+		case proto.StudentChange_ENROLL:
+			changes[i].Action = course.ActionEnroll
+		case proto.StudentChange_DISENROLL:
+			changes[i].Action = course.ActionDisenroll
+		case proto.StudentChange_CHANGE_GROUP:
+			changes[i].Action = course.ActionChangeGroup
+		default:
+			return nil, status.Error(codes.InvalidArgument, "invalid action")
+		}
+		changes[i].CourseID = course.CourseID(change.CourseId)
+		changes[i].GroupID = course.GroupID(change.GroupId)
+	}
+	api.stateLock.RLock()
+	defer api.stateLock.RUnlock()
+	// Get student
+	std, ok := api.Students[course.StudentID(r.StudentId)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "student_id")
+	}
+	// Apply them
+	err := std.ApplyChanges(ctx, api.Courses, changes, api.Broker)
+	if err != nil {
+		var batchError course.BatchError
+		if errors.As(err, &batchError) {
+			err = status.Error(codes.Internal, "")
+			log.WithError(batchError).Error("cannot batch data")
+		} else {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	// Seats are freed in the courses which the student left
+	for _, change := range changes {
+		if change.Action != course.ActionEnroll {
+			api.fulfillIntents(ctx, change.CourseID)
+		}
+	}
+	return new(emptypb.Empty), nil
+}
@@ -2,12 +2,15 @@ package main
 
 import (
 	api "CourseEnrollment/api/AuthCore"
+	"CourseEnrollment/internal/cache"
+	authCache "CourseEnrollment/internal/cache/AuthCore"
 	pg "CourseEnrollment/internal/database"
 	db "CourseEnrollment/internal/database/AuthCore"
+	"CourseEnrollment/internal/oidc"
+	"CourseEnrollment/internal/password"
 	pb "CourseEnrollment/pkg/proto"
 	"context"
 	"errors"
-	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -15,39 +18,33 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 )
 
+// oidcTimeout is the timeout of the requests to the identity provider
+const oidcTimeout = 10 * time.Second
+
 func main() {
 	// Create the API
 	endpointApi := new(api.API)
-	endpointApi.GenerateJWTKey()
-	endpointApi.Database = setupDatabase()
+	endpointApi.SetKeys(loadKeys())
+	endpointApi.LoginLimits = api.DefaultLoginLimits
+	endpointApi.TrustedProxies = setupTrustedProxies()
+	endpointApi.OIDC = setupOIDC()
+	endpointApi.PasswordPolicy = setupPasswordPolicy()
+	endpointApi.Database = setupDatabase(endpointApi.PasswordPolicy)
 	defer endpointApi.Database.Close()
+	var cacheCloser func()
+	endpointApi.Cache, cacheCloser = setupCache()
+	defer cacheCloser()
 	// Setup the gRPC client
 	var coreConnCloser func()
 	endpointApi.CoreClient, coreConnCloser = setupGRPCClient()
 	defer coreConnCloser()
 	// Setup endpoints
-	r := gin.New()
-	r.Use(gin.Recovery())
-	// Login and token refresh
-	r.POST("/login", endpointApi.LoginUser)
-	r.POST("/refresh", endpointApi.JWTAuthMiddleware(), endpointApi.RefreshJWTToken)
-	// Student endpoints
-	studentRouter := r.Group("/student", endpointApi.JWTAuthMiddleware(), api.StudentOnly())
-	studentRouter.PUT("/course", api.ParseEnrollmentBody(), endpointApi.EnrollStudent)
-	studentRouter.PATCH("/course", api.ParseEnrollmentBody(), endpointApi.ChangeGroupOfStudent)
-	studentRouter.DELETE("/course", endpointApi.DisenrollStudent)
-	studentRouter.GET("/course", endpointApi.EnrolledCoursesOfStudent)
-	studentRouter.GET("/courses", endpointApi.CoursesOfDepartment)
-	// Admin endpoints
-	staffRouter := r.Group("/staff", endpointApi.JWTAuthMiddleware(), api.StaffOnly())
-	staffRouter.PUT("/force-std", endpointApi.ForceEnroll)
-	staffRouter.DELETE("/force-std", endpointApi.ForceDisenroll)
-	staffRouter.GET("/student-courses", endpointApi.CoursesOfStudent)
-	staffRouter.GET("/course-students", endpointApi.StudentsOfCourse)
-	staffRouter.PATCH("/capacity", endpointApi.UpdateCourseCapacity)
+	r := endpointApi.Router()
 	// Listen
 	srv := &http.Server{
 		Handler: r,
@@ -59,12 +56,22 @@ func main() {
 	}()
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	for done := false; !done; {
+		select {
+		case <-reload:
+			log.Info("Reloading JWT keys...")
+			endpointApi.SetKeys(loadKeys())
+		case <-quit:
+			done = true
+		}
+	}
 	log.Println("Shutting down server...")
 	_ = srv.Shutdown(context.Background())
 }
 
-func setupDatabase() db.Database {
+func setupDatabase(policy password.Policy) db.Database {
 	// Check DB url
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -75,7 +82,89 @@ func setupDatabase() db.Database {
 	if err != nil {
 		log.Fatalf("cannot connect to database: %s\n", err)
 	}
-	return db.NewDatabase(database)
+	return db.NewDatabase(database, policy)
+}
+
+// setupPasswordPolicy will parse the policy of hashing passwords from PASSWORD_HASH, like
+// "bcrypt:12" or "argon2id:m=65536,t=3,p=4". The default is bcrypt with its default cost.
+func setupPasswordPolicy() password.Policy {
+	value := os.Getenv("PASSWORD_HASH")
+	if value == "" {
+		return password.DefaultPolicy
+	}
+	policy, err := password.ParsePolicy(value)
+	if err != nil {
+		log.Fatalf("invalid PASSWORD_HASH: %s", err)
+	}
+	return policy
+}
+
+// loadKeys will load the JWT keys from the file at JWT_KEYS_FILE or from the JSON in JWT_KEYS.
+// A random key is used if neither is set, which is only fine if there is a single auth core.
+// The file is read again on SIGHUP, so the keys can be rotated without a restart.
+func loadKeys() *api.KeySet {
+	var data []byte
+	if path := os.Getenv("JWT_KEYS_FILE"); path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("cannot read JWT keys: %s", err)
+		}
+	} else if keys := os.Getenv("JWT_KEYS"); keys != "" {
+		data = []byte(keys)
+	} else {
+		log.Warn("JWT_KEYS_FILE and JWT_KEYS are not set; tokens are signed with a random key")
+		return api.NewRandomKeySet()
+	}
+	keys, err := api.ParseKeySet(data)
+	if err != nil {
+		log.Fatalf("cannot load JWT keys: %s", err)
+	}
+	return keys
+}
+
+// setupOIDC will discover the identity provider at OIDC_ISSUER. Single sign-on is disabled if
+// it's not set.
+func setupOIDC() *oidc.Provider {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil
+	}
+	config := oidc.Config{
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+	}
+	if config.ClientID == "" || config.RedirectURL == "" {
+		log.Fatal("please set OIDC_CLIENT_ID and OIDC_REDIRECT_URL environment variables")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+	provider, err := oidc.Discover(ctx, config, &http.Client{Timeout: oidcTimeout})
+	if err != nil {
+		log.Fatalf("cannot setup sso: %s", err)
+	}
+	return provider
+}
+
+// setupCache will set up the allow list of tokens. Redis is used if REDIS_ADDRESS is set; otherwise
+// the tokens are kept in memory, which is only fine if there is a single auth core.
+// The function returned is the closer function which closes the connection to Redis.
+func setupCache() (authCache.Interface, func()) {
+	address := os.Getenv("REDIS_ADDRESS")
+	if address == "" {
+		log.Warn("REDIS_ADDRESS is not set; tokens are kept in memory")
+		return authCache.NewMemoryAuth(), func() {}
+	}
+	client, err := cache.NewRedisClient(address, os.Getenv("REDIS_PASSWORD"))
+	if err != nil {
+		log.Fatalf("cannot connect to Redis: %s", err)
+	}
+	return authCache.NewRedisAuth(client), func() {
+		_ = client.Close()
+	}
 }
 
 // setupGRPCClient will set up the grpc client for core.
@@ -96,6 +185,26 @@ func setupGRPCClient() (pb.CourseEnrollmentServerServiceClient, func()) {
 	}
 }
 
+// setupTrustedProxies reads the comma separated IPs and CIDRs of the reverse proxies. No proxy is
+// trusted if it's not set.
+func setupTrustedProxies() []string {
+	value := os.Getenv("TRUSTED_PROXIES")
+	if value == "" {
+		return nil
+	}
+	var result []string
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				log.Fatalf("invalid trusted proxy %q", proxy)
+			}
+		}
+		result = append(result, proxy)
+	}
+	return result
+}
+
 // getListener will start a listener based on environment variables
 func getListener() net.Listener {
 	// Get protocol
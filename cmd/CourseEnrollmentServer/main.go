@@ -5,26 +5,77 @@ import (
 	pg "CourseEnrollment/internal/database"
 	database "CourseEnrollment/internal/database/CourseEnrollmentServer"
 	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/internal/snapshot"
 	"CourseEnrollment/pkg/broker"
 	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/notification"
 	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/go-faster/errors"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
+// defaultSnapshotInterval is the interval which snapshots are taken in if SNAPSHOT_INTERVAL is not set
+const defaultSnapshotInterval = 5 * time.Minute
+
+// defaultLotteryInterval is the interval which closed lottery phases are checked in if
+// LOTTERY_INTERVAL is not set
+const defaultLotteryInterval = time.Minute
+
+// defaultPromotionExpiryInterval is the interval which the confirmation deadlines of promoted students
+// are checked in if PROMOTION_EXPIRY_INTERVAL is not set
+const defaultPromotionExpiryInterval = 10 * time.Second
+
+// defaultNotificationQueueSize is the number of notifications which can wait for each sink
+const defaultNotificationQueueSize = 1024
+
+// defaultWebhookTimeout is the timeout of the requests of the notification webhook
+const defaultWebhookTimeout = 5 * time.Second
+
 func main() {
-	// Connect to database and get initial data
+	// Reconciliation command
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		reconcileCommand(os.Args[2:])
+		return
+	}
+	// Connect to database
+	pgDB, closeDatabase := setupDatabase()
+	defer closeDatabase()
+	// Load the snapshot or get initial data from database
 	apiData := new(api.API)
-	_, apiData.Courses, apiData.Students = getInitialData()
+	store := openSnapshotStore()
+	apiData.Courses, apiData.Students = loadState(store, pgDB)
 	// Connect to message broker
 	var closeBroker func()
 	apiData.Broker, closeBroker = setupMessageBroker()
 	defer closeBroker()
-	var opts []grpc.ServerOption
+	// Notify the students. The inbox does not need the journal; so it uses the broker itself.
+	notifications := setupNotifications(apiData.Broker)
+	apiData.Courses.SetEventSink(notifications)
+	// Journal the messages and take snapshots periodically
+	stopSnapshots := func() {}
+	if store != nil {
+		apiData.Broker = store.Batcher(apiData.Broker)
+		if err := store.Save(apiData.Freeze, apiData.Courses, apiData.Students); err != nil {
+			log.Fatalf("cannot save the initial snapshot: %s", err)
+		}
+		stopSnapshots = saveSnapshots(store, apiData, getSnapshotInterval())
+	}
+	// Audit the changes. The journal keeps the audits, so it must be wrapped.
+	apiData.Broker = course.AuditBatcher(apiData.Broker)
+	// Reconcile the state with the database
+	apiData.Reconciler = newReconciler(pgDB, store, apiData)
+	stopReconciliation := reconcilePeriodically(apiData)
+	stopLotteries := runLotteriesPeriodically(apiData)
+	stopPromotionExpiry := expirePromotionsPeriodically(apiData)
+	opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(api.AuditInterceptor, api.StaffScopeInterceptor)}
 	grpcServer := grpc.NewServer(opts...)
 	proto.RegisterCourseEnrollmentServerServiceServer(grpcServer, apiData)
 	go func() {
@@ -37,9 +88,174 @@ func main() {
 	<-quit
 	log.Println("Graceful shutdown initiated...")
 	grpcServer.GracefulStop()
+	stopPromotionExpiry()
+	stopLotteries()
+	stopReconciliation()
+	apiData.Courses.SetEventSink(nil)
+	notifications.Close()
+	// Take the last snapshot
+	if store != nil {
+		stopSnapshots()
+		if err := store.Save(apiData.Freeze, apiData.Courses, apiData.Students); err != nil {
+			log.WithError(err).Error("cannot save snapshot")
+		}
+		_ = store.Close()
+	}
+}
+
+// openSnapshotStore opens the snapshot directory which is set in SNAPSHOT_DIR environment variable.
+// Returns nil if snapshots are disabled.
+func openSnapshotStore() *snapshot.Store {
+	dir := os.Getenv("SNAPSHOT_DIR")
+	if dir == "" {
+		return nil
+	}
+	store, err := snapshot.Open(dir)
+	if err != nil {
+		log.Fatalf("cannot open snapshot directory: %s", err)
+	}
+	return store
+}
+
+// loadState loads the courses and students from the last snapshot. If there is no usable
+// snapshot, they are loaded from the database.
+func loadState(store *snapshot.Store, pgDB database.Interface) (*course.Courses, map[course.StudentID]*course.Student) {
+	if store != nil {
+		courses, students, err := store.Load()
+		if err == nil {
+			return courses, students
+		}
+		if !errors.Is(err, snapshot.ErrNoSnapshot) {
+			log.WithError(err).Warn("cannot load snapshot; loading from database")
+		}
+	}
+	_, courses, students := getInitialData(pgDB)
+	return courses, students
+}
+
+// getSnapshotInterval gets the interval of snapshots from SNAPSHOT_INTERVAL environment variable
+func getSnapshotInterval() time.Duration {
+	intervalString := os.Getenv("SNAPSHOT_INTERVAL")
+	if intervalString == "" {
+		return defaultSnapshotInterval
+	}
+	interval, err := time.ParseDuration(intervalString)
+	if err != nil || interval <= 0 {
+		log.Fatalf("invalid SNAPSHOT_INTERVAL: %q", intervalString)
+	}
+	return interval
+}
+
+// saveSnapshots saves a snapshot every interval until the returned function is called
+func saveSnapshots(store *snapshot.Store, apiData *api.API, interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := store.Save(apiData.Freeze, apiData.Courses, apiData.Students); err != nil {
+					log.WithError(err).Error("cannot save snapshot")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
 }
 
-func getInitialData() (course.Departments, *course.Courses, map[course.StudentID]*course.Student) {
+// runLotteriesPeriodically runs the lottery of the closed lottery phases every LOTTERY_INTERVAL
+// until the returned function is called. Zero interval disables it.
+func runLotteriesPeriodically(apiData *api.API) func() {
+	interval := getEnvDuration("LOTTERY_INTERVAL", defaultLotteryInterval)
+	if interval == 0 {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				apiData.RunPendingLotteries(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+// expirePromotionsPeriodically drops the promoted students which have not confirmed their seats in
+// time every PROMOTION_EXPIRY_INTERVAL until the returned function is called. Zero interval disables it.
+func expirePromotionsPeriodically(apiData *api.API) func() {
+	interval := getEnvDuration("PROMOTION_EXPIRY_INTERVAL", defaultPromotionExpiryInterval)
+	if interval == 0 {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				apiData.ExpirePromotions(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+// setupNotifications creates the dispatcher of the notifications of students from environment
+// variables. The inbox is enabled unless NOTIFY_INBOX is false. The webhook and email sinks are
+// enabled if NOTIFY_WEBHOOK_URL and NOTIFY_SMTP_ADDRESS are set respectively.
+func setupNotifications(mq course.Batcher) *notification.Dispatcher {
+	var sinks []notification.Sink
+	if os.Getenv("NOTIFY_INBOX") != "false" {
+		sinks = append(sinks, notification.InboxSink{Batcher: mq})
+	}
+	if url := os.Getenv("NOTIFY_WEBHOOK_URL"); url != "" {
+		sinks = append(sinks, notification.WebhookSink{
+			URL:    url,
+			Secret: os.Getenv("NOTIFY_WEBHOOK_SECRET"),
+			Client: &http.Client{Timeout: defaultWebhookTimeout},
+		})
+	}
+	if address := os.Getenv("NOTIFY_SMTP_ADDRESS"); address != "" {
+		sink, err := notification.NewSMTPSink(address, os.Getenv("NOTIFY_SMTP_USERNAME"), os.Getenv("NOTIFY_SMTP_PASSWORD"),
+			os.Getenv("NOTIFY_SMTP_FROM"), os.Getenv("NOTIFY_SMTP_TO"))
+		if err != nil {
+			log.Fatalf("invalid SMTP config: %s", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return notification.NewDispatcher(defaultNotificationQueueSize, sinks...)
+}
+
+// setupDatabase connects to the database in DATABASE_URL environment variable.
+// The second returned value closes the connection.
+func setupDatabase() (*database.Database, func()) {
 	// Check DB url
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -50,8 +266,11 @@ func getInitialData() (course.Departments, *course.Courses, map[course.StudentID
 	if err != nil {
 		log.Fatalf("cannot connect to database: %s", err)
 	}
-	defer db.Close()
 	pgDB := database.NewDatabase(db)
+	return &pgDB, db.Close
+}
+
+func getInitialData(pgDB database.Interface) (course.Departments, *course.Courses, map[course.StudentID]*course.Student) {
 	// Fetch data
 	departments, err := pgDB.GetDepartments()
 	if err != nil {
@@ -73,19 +292,37 @@ func getInitialData() (course.Departments, *course.Courses, map[course.StudentID
 // The first returned value is the broker itself.
 // The second value is a closer function. IT MUST BE CALLED BEFORE APPLICATION EXITS.
 func setupMessageBroker() (course.Batcher, func()) {
-	address := os.Getenv("RABBITMQ_ADDRESS")
-	if address == "" {
-		log.Fatal("please set RABBITMQ_ADDRESS environment variable")
+	backend, address := getBrokerConfig()
+	layout, err := broker.ParseQueueLayout(shared.CourseEnrollmentServerDatabaseQueueName, os.Getenv("DEPARTMENT_SHARDS"))
+	if err != nil {
+		log.Fatalf("invalid DEPARTMENT_SHARDS: %s", err)
 	}
-	mq, err := broker.NewRabbitMQBroker(address, shared.CourseEnrollmentServerDatabaseQueueName)
+	mq, err := broker.Open(backend, address, layout)
 	if err != nil {
-		log.Fatalf("cannot instantiate the RabbitMQ client: %s", err)
+		log.Fatalf("cannot instantiate the %s broker: %s", backend, err)
 	}
 	return mq, func() {
 		_ = mq.Close()
 	}
 }
 
+// getBrokerConfig returns the broker backend and its address from environment variables.
+// RABBITMQ_ADDRESS is still accepted as the address of the rabbitmq backend.
+func getBrokerConfig() (string, string) {
+	backend := os.Getenv("BROKER")
+	if backend == "" {
+		backend = "rabbitmq"
+	}
+	address := os.Getenv("BROKER_ADDRESS")
+	if address == "" && backend == "rabbitmq" {
+		address = os.Getenv("RABBITMQ_ADDRESS")
+	}
+	if address == "" {
+		log.Fatal("please set BROKER_ADDRESS environment variable")
+	}
+	return backend, address
+}
+
 // getListener will start a listener based on environment variables
 func getListener() net.Listener {
 	// Get protocol
@@ -0,0 +1,163 @@
+package main
+
+import (
+	api "CourseEnrollment/api/CourseEnrollmentServer"
+	database "CourseEnrollment/internal/database/CourseEnrollmentServer"
+	"CourseEnrollment/internal/reconcile"
+	"CourseEnrollment/internal/snapshot"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"os"
+	"time"
+)
+
+// defaultReconcileSettle is the settle time of reconciliation if RECONCILE_SETTLE is not set
+const defaultReconcileSettle = 5 * time.Second
+
+// reconcileUsage is printed when the reconcile command is used incorrectly
+const reconcileUsage = `Usage: CourseEnrollmentServer reconcile [repair]
+
+Compares the state of a running server with the database and prints the differences.
+The server is reached at CORE_ADDRESS or LISTEN_ADDRESS.
+
+Repairs:
+  repair-database    Replace the differing groups in the database with the server state
+  repair-memory      Replace the differing groups in the server with the database
+`
+
+// reconcileCommand asks the running server to reconcile based on the command line arguments
+func reconcileCommand(args []string) {
+	repair := proto.ReconcileRepair_REPAIR_NONE
+	switch {
+	case len(args) == 0:
+	case len(args) == 1 && args[0] == "repair-database":
+		repair = proto.ReconcileRepair_REPAIR_DATABASE
+	case len(args) == 1 && args[0] == "repair-memory":
+		repair = proto.ReconcileRepair_REPAIR_MEMORY
+	default:
+		fmt.Fprint(os.Stderr, reconcileUsage)
+		os.Exit(2)
+	}
+	// Connect to the server
+	address := os.Getenv("CORE_ADDRESS")
+	if address == "" {
+		address = os.Getenv("LISTEN_ADDRESS")
+		if os.Getenv("LISTEN_PROTOCOL") == "unix" {
+			address = "unix:" + address
+		}
+	}
+	if address == "" {
+		fmt.Fprint(os.Stderr, reconcileUsage)
+		os.Exit(2)
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+	result, err := proto.NewCourseEnrollmentServerServiceClient(conn).Reconcile(context.Background(), &proto.ReconcileRequest{Repair: repair})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, difference := range result.Differences {
+		fmt.Printf("course=%d\tgroup=%d\tstudent=%d\t%s\n", difference.CourseId, difference.GroupId, difference.StudentId, difference.Kind)
+	}
+	fmt.Printf("%d differences\n", len(result.Differences))
+	if result.Repaired {
+		fmt.Println("repaired")
+	}
+}
+
+// newReconciler creates the reconciler of the server. If snapshots are enabled, a snapshot is
+// taken after the memory is repaired.
+func newReconciler(pgDB database.Interface, store *snapshot.Store, apiData *api.API) *reconcile.Reconciler {
+	reconciler := &reconcile.Reconciler{
+		Database: pgDB,
+		Settle:   getEnvDuration("RECONCILE_SETTLE", defaultReconcileSettle),
+	}
+	if store != nil {
+		reconciler.MemoryRepaired = func() {
+			if err := store.Save(apiData.Freeze, apiData.Courses, apiData.Students); err != nil {
+				log.WithError(err).Error("cannot save snapshot")
+			}
+		}
+	}
+	return reconciler
+}
+
+// reconcilePeriodically reconciles the state every RECONCILE_INTERVAL until the returned function
+// is called. The differences are repaired based on RECONCILE_REPAIR.
+func reconcilePeriodically(apiData *api.API) func() {
+	interval := getEnvDuration("RECONCILE_INTERVAL", 0)
+	if interval == 0 {
+		return func() {}
+	}
+	repair := getReconcileRepair()
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				result, err := apiData.Reconciler.Run(ctx, apiData.Freeze, apiData.Courses, apiData.Students, repair)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.WithError(err).Error("cannot reconcile")
+					}
+					continue
+				}
+				for _, difference := range result.Differences {
+					log.WithFields(log.Fields{
+						"course":  difference.CourseId,
+						"group":   difference.GroupId,
+						"student": difference.StudentId,
+						"kind":    difference.Kind,
+					}).Warn("state differs from database")
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+// getReconcileRepair gets the repair of periodic reconciliation from RECONCILE_REPAIR
+func getReconcileRepair() proto.ReconcileRepair {
+	switch repair := os.Getenv("RECONCILE_REPAIR"); repair {
+	case "", "none":
+		return proto.ReconcileRepair_REPAIR_NONE
+	case "database":
+		return proto.ReconcileRepair_REPAIR_DATABASE
+	case "memory":
+		return proto.ReconcileRepair_REPAIR_MEMORY
+	default:
+		log.Fatalf("invalid RECONCILE_REPAIR: %q", repair)
+		return proto.ReconcileRepair_REPAIR_NONE
+	}
+}
+
+// getEnvDuration gets a duration from environment variables or returns defaultValue if it's not set
+func getEnvDuration(name string, defaultValue time.Duration) time.Duration {
+	env := os.Getenv(name)
+	if env == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(env)
+	if err != nil || value < 0 {
+		log.Fatalf("invalid %s: %q", name, env)
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	db "CourseEnrollment/internal/database/DatabaseBatcher"
+	"CourseEnrollment/pkg/broker"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/go-faster/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// maxRetryBackoff is the maximum time which we wait between two attempts
+const maxRetryBackoff = 10 * time.Second
+
+// foreignKeyViolation is the SQLSTATE of a row which references a row that does not exist
+const foreignKeyViolation = "23503"
+
+// batcher applies the messages received from the broker in database
+type batcher struct {
+	database db.Interface
+	broker   broker.Broker
+	// Maximum number of messages in a batch
+	maxSize int
+	// Maximum time which a message waits before its batch is flushed
+	flushInterval time.Duration
+	// Number of times which we try to apply a failing message before dead lettering it
+	retryAttempts int
+	// Time to wait after the first failed attempt. It's doubled after each attempt.
+	retryBackoff time.Duration
+}
+
+// batchLoop reads the messages from data and applies them in batches.
+// Each batch is flushed when it reaches maxSize messages or when flushInterval
+// has passed since the first message of it has been received. This function returns
+// when data is closed and the remaining messages are flushed.
+func (b batcher) batchLoop(data <-chan broker.Delivery) {
+	batch := make([]broker.Delivery, 0, b.maxSize)
+	timer := time.NewTimer(b.flushInterval)
+	timer.Stop()
+	for {
+		select {
+		case delivery, ok := <-data:
+			if !ok {
+				timer.Stop()
+				b.flushBatch(batch)
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(b.flushInterval)
+			}
+			batch = append(batch, delivery)
+			if len(batch) >= b.maxSize {
+				timer.Stop()
+				b.flushBatch(batch)
+				batch = batch[:0]
+			}
+		case <-timer.C:
+			b.flushBatch(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// flushBatch will apply a batch of queries in database and acknowledges them once they are committed.
+// If the database is unreachable, the whole batch is retried until it's reachable again. If the batch
+// is rejected by the database, each query is applied on its own so that a single bad query does not
+// hold back the whole batch. Queries which still fail after all attempts are dead lettered.
+func (b batcher) flushBatch(batch []broker.Delivery) {
+	if len(batch) == 0 {
+		return
+	}
+	queries := make([]*proto.CourseDatabaseBatchMessage, len(batch))
+	for i, delivery := range batch {
+		queries[i] = delivery.Message
+	}
+	backoff := b.retryBackoff
+	for {
+		err := b.database.ApplyBatch(context.Background(), queries)
+		if err == nil {
+			for _, delivery := range batch {
+				ackDelivery(delivery)
+			}
+			log.WithField("size", len(batch)).Debug("applied batch")
+			return
+		}
+		if isDataError(err) {
+			log.WithError(err).WithField("size", len(batch)).Warn("cannot apply batch, applying queries one by one")
+			break
+		}
+		log.WithError(err).WithField("backoff", backoff).Warn("cannot apply batch, retrying")
+		backoff = sleepBackoff(backoff)
+	}
+	for _, delivery := range batch {
+		b.applySingle(delivery)
+	}
+}
+
+// applySingle applies a single query with retries. If all attempts fail, the query is dead lettered.
+// A query which references a missing row is retried without a limit, because the row might be
+// inserted by the batcher of another shard later; for example, a new student is only sent to the
+// shard of their own department. It's retried in place rather than requeued, so the queries after
+// it in this shard are still applied after it.
+func (b batcher) applySingle(delivery broker.Delivery) {
+	var err error
+	backoff := b.retryBackoff
+	for attempt := 1; attempt <= b.retryAttempts; attempt++ {
+		err = b.database.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{delivery.Message})
+		if err == nil {
+			log.WithField("query", delivery.Message).Debug("applied")
+			ackDelivery(delivery)
+			return
+		}
+		if isMissingReference(err) {
+			log.WithField("query", delivery.Message).WithError(err).Warn("cannot apply action, waiting for the referenced row")
+			backoff = sleepBackoff(backoff)
+			attempt-- // it's not counted as an attempt
+			continue
+		}
+		log.WithField("query", delivery.Message).WithField("attempt", attempt).WithError(err).Warn("cannot apply action")
+		if attempt != b.retryAttempts {
+			backoff = sleepBackoff(backoff)
+		}
+	}
+	// Give up on this message
+	log.WithField("query", delivery.Message).WithError(err).Error("cannot apply action, dead lettering it")
+	if dlErr := b.broker.DeadLetter(context.Background(), delivery, err, b.retryAttempts); dlErr != nil {
+		log.WithError(dlErr).Error("cannot dead letter message")
+		if err = delivery.Nack(true); err != nil {
+			log.WithError(err).Error("cannot nack message")
+		}
+	}
+}
+
+// isDataError checks if an error is caused by the data which we sent to the database rather than
+// the database itself being unavailable. Retrying a batch which has data errors is pointless.
+func isDataError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr)
+}
+
+// isMissingReference checks if an error is caused by a query which references a row that does not
+// exist in the database yet
+func isMissingReference(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
+
+// sleepBackoff sleeps for backoff and returns the next backoff
+func sleepBackoff(backoff time.Duration) time.Duration {
+	time.Sleep(backoff)
+	return min(backoff*2, maxRetryBackoff)
+}
+
+// ackDelivery acknowledges a delivery and logs the error if any.
+// The operation ID of the message makes it safe to receive it again if the ack is lost.
+func ackDelivery(delivery broker.Delivery) {
+	if err := delivery.Ack(); err != nil {
+		log.WithError(err).WithField("operation", delivery.Message.OperationId).Warn("cannot ack message")
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"CourseEnrollment/pkg/broker"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDatabase keeps the students and enrollments. Like the real database, an enrollment of a
+// student which does not exist is a foreign key violation.
+type fakeDatabase struct {
+	students    map[uint64]struct{}
+	enrollments []uint64
+	mu          sync.Mutex
+}
+
+func (db *fakeDatabase) ApplyBatch(_ context.Context, messages []*proto.CourseDatabaseBatchMessage) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, message := range messages {
+		if enroll := message.GetEnroll(); enroll != nil {
+			if _, exists := db.students[enroll.StudentId]; !exists {
+				return &pgconn.PgError{Code: foreignKeyViolation, Message: "student does not exist"}
+			}
+		}
+	}
+	for _, message := range messages {
+		if put := message.GetPutStudent(); put != nil {
+			db.students[put.StudentId] = struct{}{}
+		}
+		if enroll := message.GetEnroll(); enroll != nil {
+			db.enrollments = append(db.enrollments, enroll.StudentId)
+		}
+	}
+	return nil
+}
+
+func (db *fakeDatabase) Close() {}
+
+func TestCrossShardStudent(t *testing.T) {
+	// Department 2 has its own shard
+	layout, err := broker.ParseQueueLayout("test", "2")
+	require.NoError(t, err)
+	mqBroker := broker.NewMemoryBroker(layout)
+	database := &fakeDatabase{students: make(map[uint64]struct{})}
+	newShardBatcher := func(shard int) {
+		data, err := mqBroker.Consume("batcher", 10, []int{shard})
+		require.NoError(t, err)
+		b := batcher{database: database, broker: mqBroker, maxSize: 10, flushInterval: time.Millisecond, retryAttempts: 1, retryBackoff: time.Millisecond}
+		go b.batchLoop(data)
+	}
+	// A new student of department 1 enrolls in a course of department 2, and the shard of
+	// department 2 is applied before the shard of department 1
+	require.NoError(t, mqBroker.ProcessDatabaseQuery(context.Background(), 1, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_PutStudent{PutStudent: &proto.CourseDatabaseBatchPutStudent{StudentId: 1, DepartmentId: 1}},
+	}))
+	require.NoError(t, mqBroker.ProcessDatabaseQuery(context.Background(), 2, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_Enroll{Enroll: &proto.CourseDatabaseBatchEnrollMessage{StudentId: 1, CourseId: 10, GroupId: 1}},
+	}))
+	newShardBatcher(1)
+	time.Sleep(50 * time.Millisecond)
+	newShardBatcher(0)
+	// The enrollment waits for the student instead of being dead lettered
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, mqBroker.Wait(ctx))
+	letters, err := mqBroker.ListDeadLetters(layout.AllShards())
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+	assert.Equal(t, []uint64{1}, database.enrollments)
+	require.NoError(t, mqBroker.Close())
+}
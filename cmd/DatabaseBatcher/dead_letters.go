@@ -0,0 +1,105 @@
+package main
+
+import (
+	"CourseEnrollment/pkg/broker"
+	"context"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"os"
+	"slices"
+)
+
+// deadLettersUsage is printed when the dead letters command is used incorrectly
+const deadLettersUsage = `Usage: DatabaseBatcher dead-letters <command>
+
+Commands:
+  list                      List all dead letters
+  inspect <operation id>    Show the details of a dead letter
+  requeue <operation id>... Move dead letters back to the main queue
+  requeue-all               Move all dead letters back to the main queue
+`
+
+// deadLettersCommand manages the dead letters based on the command line arguments
+func deadLettersCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, deadLettersUsage)
+		os.Exit(2)
+	}
+	mqBroker, shards := setupMessageBroker()
+	defer mqBroker.Close()
+	var err error
+	switch args[0] {
+	case "list":
+		err = listDeadLetters(mqBroker, shards)
+	case "inspect":
+		if len(args) != 2 {
+			fmt.Fprint(os.Stderr, deadLettersUsage)
+			os.Exit(2)
+		}
+		err = inspectDeadLetter(mqBroker, shards, args[1])
+	case "requeue":
+		if len(args) < 2 {
+			fmt.Fprint(os.Stderr, deadLettersUsage)
+			os.Exit(2)
+		}
+		err = requeueDeadLetters(mqBroker, shards, func(letter broker.DeadLetter) bool {
+			return slices.Contains(args[1:], letter.OperationID)
+		})
+	case "requeue-all":
+		err = requeueDeadLetters(mqBroker, shards, func(broker.DeadLetter) bool {
+			return true
+		})
+	default:
+		fmt.Fprint(os.Stderr, deadLettersUsage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// listDeadLetters prints a summary of each dead letter
+func listDeadLetters(mqBroker broker.Broker, shards []int) error {
+	letters, err := mqBroker.ListDeadLetters(shards)
+	if err != nil {
+		return err
+	}
+	for _, letter := range letters {
+		fmt.Printf("%s\tshard=%d\t%s\tattempts=%d\t%s\n", letter.OperationID, letter.Shard, letter.FailedAt.Format("2006-01-02 15:04:05"), letter.Attempts, letter.Error)
+	}
+	fmt.Printf("%d dead letters\n", len(letters))
+	return nil
+}
+
+// inspectDeadLetter prints every detail of a dead letter
+func inspectDeadLetter(mqBroker broker.Broker, shards []int, operationID string) error {
+	letters, err := mqBroker.ListDeadLetters(shards)
+	if err != nil {
+		return err
+	}
+	for _, letter := range letters {
+		if letter.OperationID != operationID {
+			continue
+		}
+		fmt.Printf("Operation ID: %s\n", letter.OperationID)
+		fmt.Printf("Shard: %d\n", letter.Shard)
+		fmt.Printf("Failed at: %s\n", letter.FailedAt)
+		fmt.Printf("Attempts: %d\n", letter.Attempts)
+		fmt.Printf("Error: %s\n", letter.Error)
+		if letter.Message != nil {
+			fmt.Printf("Message: %s\n", protojson.Format(letter.Message))
+		} else {
+			fmt.Printf("Raw body: %x\n", letter.Body)
+		}
+		return nil
+	}
+	return fmt.Errorf("dead letter %s not found", operationID)
+}
+
+// requeueDeadLetters moves the dead letters which match the filter back to the main queue
+func requeueDeadLetters(mqBroker broker.Broker, shards []int, filter func(broker.DeadLetter) bool) error {
+	requeued, err := mqBroker.RequeueDeadLetters(context.Background(), shards, filter)
+	fmt.Printf("%d dead letters requeued\n", requeued)
+	return err
+}
@@ -5,23 +5,30 @@ import (
 	db "CourseEnrollment/internal/database/DatabaseBatcher"
 	"CourseEnrollment/internal/shared"
 	"CourseEnrollment/pkg/broker"
-	"CourseEnrollment/pkg/course"
-	"CourseEnrollment/pkg/proto"
 	log "github.com/sirupsen/logrus"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 )
 
 const consumerName = "course-enrollment-database-batcher"
 
 func main() {
+	// Dead letter management
+	if len(os.Args) > 1 && os.Args[1] == "dead-letters" {
+		deadLettersCommand(os.Args[2:])
+		return
+	}
 	database := setupDatabase()
 	defer database.Close()
-	mqBroker := setupMessageBroker()
+	mqBroker, shards := setupMessageBroker()
 	defer mqBroker.Close()
+	b := newBatcher(database, mqBroker)
 	// Listen to changes
-	data, err := mqBroker.Consume(consumerName)
+	log.WithField("shards", shards).Info("consuming shards")
+	data, err := mqBroker.Consume(consumerName, b.maxSize, shards)
 	if err != nil {
 		log.WithError(err).Fatalf("cannot consumer queue")
 	}
@@ -31,40 +38,55 @@ func main() {
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 		<-quit
 		log.Println("Shutting down...")
-		err := mqBroker.CancelConsumer(consumerName)
+		err := mqBroker.CancelConsumer(consumerName, shards)
 		if err != nil {
 			log.WithError(err).Warn("cannot cancel consumer")
 		}
 	}()
-	// Loop over them
-	for query := range data {
-		processQuery(database, query)
-	}
+	// Apply them in batches
+	b.batchLoop(data)
 	// Done
 	log.Info("clean shutdown")
 }
 
-// processQuery will apply the query in database
-func processQuery(database db.Database, query *proto.CourseDatabaseBatchMessage) {
-	var err error
-	switch data := query.GetAction().(type) {
-	case *proto.CourseDatabaseBatchMessage_Enroll:
-		err = database.EnrollCourse(course.StudentID(data.Enroll.StudentId), course.CourseID(data.Enroll.CourseId), course.GroupID(data.Enroll.GroupId), data.Enroll.Reserved)
-	case *proto.CourseDatabaseBatchMessage_Disenroll:
-		err = database.DisenrollCourse(course.StudentID(data.Disenroll.StudentId), course.CourseID(data.Disenroll.CourseId))
-	case *proto.CourseDatabaseBatchMessage_ChangeGroup:
-		err = database.ChangeCourseGroup(course.StudentID(data.ChangeGroup.StudentId), course.CourseID(data.ChangeGroup.CourseId), course.GroupID(data.ChangeGroup.GroupId), data.ChangeGroup.Reserved)
-	case *proto.CourseDatabaseBatchMessage_UpdateCapacity:
-		err = database.UpdateCapacity(course.CourseID(data.UpdateCapacity.CourseId), course.GroupID(data.UpdateCapacity.GroupId), data.UpdateCapacity.NewCapacity, data.UpdateCapacity.MovedStudents)
-	default:
-		log.WithField("query", query).Error("invalid action")
-		return
+// newBatcher will create a batcher and reads its config from environment variables
+func newBatcher(database db.Interface, mqBroker broker.Broker) batcher {
+	return batcher{
+		database:      database,
+		broker:        mqBroker,
+		maxSize:       getEnvInt("BATCH_SIZE", 1000),
+		flushInterval: getEnvDuration("BATCH_INTERVAL", 100*time.Millisecond),
+		retryAttempts: getEnvInt("RETRY_ATTEMPTS", 5),
+		retryBackoff:  getEnvDuration("RETRY_BACKOFF", 100*time.Millisecond),
 	}
-	if err != nil {
-		log.WithField("query", query).WithError(err).Error("cannot apply action")
-	} else {
-		log.WithField("query", query).Debug("applied")
+}
+
+// getEnvInt will get a positive integer from environment variables or returns
+// defaultValue if it's not set
+func getEnvInt(name string, defaultValue int) int {
+	env := os.Getenv(name)
+	if env == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(env)
+	if err != nil || value <= 0 {
+		log.Fatalf("invalid %s: %s", name, env)
+	}
+	return value
+}
+
+// getEnvDuration will get a positive duration from environment variables or returns
+// defaultValue if it's not set
+func getEnvDuration(name string, defaultValue time.Duration) time.Duration {
+	env := os.Getenv(name)
+	if env == "" {
+		return defaultValue
 	}
+	value, err := time.ParseDuration(env)
+	if err != nil || value <= 0 {
+		log.Fatalf("invalid %s: %s", name, env)
+	}
+	return value
 }
 
 // setupDatabase will set up the database which is used to apply the courses
@@ -82,15 +104,38 @@ func setupDatabase() db.Database {
 	return db.NewDatabase(database)
 }
 
-// setupMessageBroker creates and connects to our message broker
-func setupMessageBroker() broker.RabbitMQBroker {
-	address := os.Getenv("RABBITMQ_ADDRESS")
-	if address == "" {
-		log.Fatal("please set RABBITMQ_ADDRESS environment variable")
+// setupMessageBroker creates and connects to our message broker.
+// The second returned value is the list of shards which this batcher must consume.
+func setupMessageBroker() (broker.Broker, []int) {
+	backend, address := getBrokerConfig()
+	layout, err := broker.ParseQueueLayout(shared.CourseEnrollmentServerDatabaseQueueName, os.Getenv("DEPARTMENT_SHARDS"))
+	if err != nil {
+		log.Fatalf("invalid DEPARTMENT_SHARDS: %s", err)
 	}
-	mq, err := broker.NewRabbitMQBroker(address, shared.CourseEnrollmentServerDatabaseQueueName)
+	shards, err := layout.ParseShards(os.Getenv("BATCHER_SHARDS"))
 	if err != nil {
-		log.Fatalf("cannot instantiate the RabbitMQ client: %s", err)
+		log.Fatalf("invalid BATCHER_SHARDS: %s", err)
+	}
+	mq, err := broker.Open(backend, address, layout)
+	if err != nil {
+		log.Fatalf("cannot instantiate the %s broker: %s", backend, err)
+	}
+	return mq, shards
+}
+
+// getBrokerConfig returns the broker backend and its address from environment variables.
+// RABBITMQ_ADDRESS is still accepted as the address of the rabbitmq backend.
+func getBrokerConfig() (string, string) {
+	backend := os.Getenv("BROKER")
+	if backend == "" {
+		backend = "rabbitmq"
+	}
+	address := os.Getenv("BROKER_ADDRESS")
+	if address == "" && backend == "rabbitmq" {
+		address = os.Getenv("RABBITMQ_ADDRESS")
+	}
+	if address == "" {
+		log.Fatal("please set BROKER_ADDRESS environment variable")
 	}
-	return mq
+	return backend, address
 }
@@ -1,11 +1,37 @@
 -- Create sex type
 CREATE TYPE sex AS ENUM ('male', 'female');
 
+-- Department staff can only change the courses and the students of their own department. Super
+-- admins can change everything, like the schedule.
+CREATE TYPE staff_role AS ENUM ('department', 'super_admin');
+
 CREATE TABLE staff
 (
     id            INTEGER PRIMARY KEY NOT NULL,
     password      TEXT                NOT NULL,
-    department_id SMALLSERIAL         NOT NULL
+    department_id SMALLSERIAL         NOT NULL,
+    role          staff_role          NOT NULL DEFAULT 'department'
+);
+
+-- Links the identities of the identity provider of the university (the sub claim of its OIDC
+-- tokens) to students and staff, so they can login with single sign-on
+CREATE TABLE external_identities
+(
+    issuer   TEXT    NOT NULL,
+    subject  TEXT    NOT NULL,
+    is_staff BOOLEAN NOT NULL,
+    user_id  BIGINT  NOT NULL,
+    PRIMARY KEY (issuer, subject, is_staff)
+);
+
+-- One-time tokens which staff give to users to reset their password. Only the SHA-256 of the
+-- tokens are stored.
+CREATE TABLE password_resets
+(
+    token_hash TEXT PRIMARY KEY NOT NULL,
+    user_id    BIGINT           NOT NULL,
+    is_staff   BOOLEAN          NOT NULL,
+    expires_at TIMESTAMPTZ      NOT NULL
 );
 
 CREATE TABLE students
@@ -17,7 +43,9 @@ CREATE TABLE students
     remaining_actions     SMALLINT            NOT NULL,
     department_id         SMALLSERIAL         NOT NULL,
     entry_year            SMALLINT            NOT NULL,
-    gender                sex                 NOT NULL
+    gender                sex                 NOT NULL,
+    -- Higher bands get seats earlier in the lottery phases which are ordered by GPA band
+    gpa_band              SMALLINT            NOT NULL DEFAULT 0
 );
 
 CREATE TABLE departments
@@ -28,28 +56,39 @@ CREATE TABLE departments
 
 CREATE TABLE courses
 (
-    course_id        INTEGER     NOT NULL,
-    group_id         INTEGER     NOT NULL,
-    for_department   SMALLSERIAL NOT NULL,
-    name             TEXT        NOT NULL,
-    lecturer         TEXT        NOT NULL,
-    units            SMALLINT    NOT NULL,
-    capacity         INTEGER     NOT NULL,
-    reserve_capacity INTEGER     NOT NULL,
-    exam_time        TIMESTAMPTZ,
-    class_time       INTEGER     NOT NULL,
-    sex_lock         sex,
-    notes            TEXT        NOT NULL,
+    course_id           INTEGER     NOT NULL,
+    group_id            INTEGER     NOT NULL,
+    for_department      SMALLSERIAL NOT NULL,
+    name                TEXT        NOT NULL,
+    lecturer            TEXT        NOT NULL,
+    units               SMALLINT    NOT NULL,
+    capacity            INTEGER     NOT NULL,
+    reserve_capacity    INTEGER     NOT NULL,
+    exam_time           TIMESTAMPTZ,
+    class_time          INTEGER     NOT NULL,
+    sex_lock            sex,
+    notes               TEXT        NOT NULL,
+    -- Who can take this group. Empty arrays and zero entry years do not restrict anyone.
+    departments         SMALLINT[]  NOT NULL DEFAULT '{}',
+    min_entry_year      SMALLINT    NOT NULL DEFAULT 0,
+    max_entry_year      SMALLINT    NOT NULL DEFAULT 0,
+    allowed_students    INTEGER[]   NOT NULL DEFAULT '{}',
+    denied_students     INTEGER[]   NOT NULL DEFAULT '{}',
+    -- How many seconds the students which are promoted from the reserve queue have to confirm their seat.
+    -- Zero means that no confirmation is needed.
+    confirmation_window INTEGER     NOT NULL DEFAULT 0,
     PRIMARY KEY (course_id, group_id)
 );
 
 CREATE TABLE enrolled_courses
 (
-    id         SERIAL PRIMARY KEY NOT NULL,
-    course_id  INTEGER            NOT NULL,
-    group_id   INTEGER            NOT NULL,
-    student_id INTEGER            NOT NULL,
-    reserved   BOOLEAN            NOT NULL
+    id                    SERIAL PRIMARY KEY NOT NULL,
+    course_id             INTEGER            NOT NULL,
+    group_id              INTEGER            NOT NULL,
+    student_id            INTEGER            NOT NULL,
+    reserved              BOOLEAN            NOT NULL,
+    -- Set for the promoted students which have not confirmed their seat yet
+    confirmation_deadline TIMESTAMPTZ
 );
 
 ALTER TABLE staff
@@ -61,4 +100,130 @@ ALTER TABLE courses
 ALTER TABLE enrolled_courses
     ADD CONSTRAINT enrolled_courses_course_id_courses_course_id FOREIGN KEY (course_id, group_id) REFERENCES courses (course_id, group_id);
 ALTER TABLE enrolled_courses
-    ADD CONSTRAINT enrolled_courses_student_id_users_id FOREIGN KEY (student_id) REFERENCES students (id);
\ No newline at end of file
+    ADD CONSTRAINT enrolled_courses_student_id_users_id FOREIGN KEY (student_id) REFERENCES students (id);
+
+-- Students must have passed the prerequisites of a course before enrolling in it. Co-requisites can also be
+-- enrolled in the same enrollment session.
+CREATE TABLE course_requisites
+(
+    course_id    INTEGER NOT NULL,
+    requisite_id INTEGER NOT NULL,
+    corequisite  BOOLEAN NOT NULL,
+    PRIMARY KEY (course_id, requisite_id)
+);
+
+CREATE TABLE passed_courses
+(
+    student_id INTEGER NOT NULL REFERENCES students (id),
+    course_id  INTEGER NOT NULL,
+    PRIMARY KEY (student_id, course_id)
+);
+
+-- The students which want to move to another group of a course they are enrolled in when it has a
+-- free seat. Intents of each group are fulfilled in the order of their ids.
+CREATE TABLE group_change_intents
+(
+    id         SERIAL PRIMARY KEY NOT NULL,
+    student_id INTEGER            NOT NULL REFERENCES students (id),
+    course_id  INTEGER            NOT NULL,
+    group_id   INTEGER            NOT NULL,
+    UNIQUE (student_id, course_id),
+    FOREIGN KEY (course_id, group_id) REFERENCES courses (course_id, group_id)
+);
+
+CREATE TABLE applied_operations
+(
+    id         TEXT PRIMARY KEY NOT NULL,
+    applied_at TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+-- The enrollment schedule. A phase is open for a student in any of its windows which matches the
+-- department and the entry year of the student. Zero department or entry year matches everyone.
+CREATE TABLE enrollment_phases
+(
+    name               TEXT PRIMARY KEY NOT NULL,
+    position           SMALLINT         NOT NULL,
+    allow_enroll       BOOLEAN          NOT NULL,
+    allow_disenroll    BOOLEAN          NOT NULL,
+    allow_change_group BOOLEAN          NOT NULL,
+    -- In milliseconds. Zero means that students can act in the whole window.
+    student_duration   BIGINT           NOT NULL,
+    -- In a lottery phase, students submit wishlists instead of acting directly. The students are
+    -- ordered by the priorities (0 is seniority and 1 is GPA band) and the ties are broken randomly
+    -- based on the seed.
+    lottery            BOOLEAN          NOT NULL DEFAULT FALSE,
+    priorities         SMALLINT[]       NOT NULL DEFAULT '{}',
+    seed               BIGINT           NOT NULL DEFAULT 0
+);
+
+CREATE TABLE enrollment_windows
+(
+    phase         TEXT        NOT NULL REFERENCES enrollment_phases (name) ON DELETE CASCADE,
+    department_id SMALLINT    NOT NULL,
+    entry_year    SMALLINT    NOT NULL,
+    start_time    TIMESTAMPTZ NOT NULL,
+    end_time      TIMESTAMPTZ NOT NULL
+);
+
+-- The ranked wishlists which students submit in lottery phases. Rank zero is the most wanted group.
+-- The wishlists are removed when the lottery of their phase runs.
+CREATE TABLE wishlists
+(
+    student_id INTEGER  NOT NULL REFERENCES students (id),
+    phase      TEXT     NOT NULL,
+    rank       SMALLINT NOT NULL,
+    course_id  INTEGER  NOT NULL,
+    group_id   INTEGER  NOT NULL,
+    PRIMARY KEY (student_id, rank),
+    FOREIGN KEY (course_id, group_id) REFERENCES courses (course_id, group_id)
+);
+
+-- The inbox of students. The rows are only added by the enrollment server and read by the auth core.
+CREATE TABLE notifications
+(
+    id                    BIGSERIAL PRIMARY KEY NOT NULL,
+    student_id            INTEGER               NOT NULL REFERENCES students (id),
+    -- 1 is promoted from the reserve queue, 2 is force enrolled, 3 is force disenrolled, 4 is
+    -- capacity changed while in the reserve queue and 5 is dropped for not confirming a promotion
+    kind                  SMALLINT              NOT NULL,
+    course_id             INTEGER               NOT NULL,
+    group_id              INTEGER               NOT NULL,
+    -- The capacity of the group after the event
+    capacity              INTEGER               NOT NULL,
+    -- The position in the reserve queue after the event. Zero means registered.
+    reserve_position      INTEGER               NOT NULL,
+    created_at            TIMESTAMPTZ           NOT NULL,
+    -- The deadline of confirming the promotion. Null means no confirmation is needed.
+    confirmation_deadline TIMESTAMPTZ
+);
+
+CREATE INDEX notifications_student_id ON notifications (student_id, id);
+
+-- The audit log of every enrollment change. The rows are only added by the batcher and never
+-- updated or removed.
+CREATE TABLE enrollment_audit
+(
+    id         BIGSERIAL PRIMARY KEY NOT NULL,
+    -- 1 is enroll, 2 is reserve, 3 is disenroll, 4 is change group, 5 is promote, 6 is confirm
+    -- promotion, 7 is update capacity, 8 is add intent and 9 is remove intent
+    action     SMALLINT              NOT NULL,
+    -- Zero if the change is not about a single student
+    student_id INTEGER               NOT NULL,
+    course_id  INTEGER               NOT NULL,
+    -- Zero if unknown
+    group_id   INTEGER               NOT NULL,
+    -- The capacity of the group after a capacity change
+    capacity   INTEGER               NOT NULL,
+    -- 0 is the system, 1 is a student and 2 is a staff
+    actor_kind SMALLINT              NOT NULL,
+    actor_id   BIGINT                NOT NULL,
+    -- The RPC or the job which has made the change
+    origin     TEXT                  NOT NULL,
+    reason     TEXT                  NOT NULL,
+    created_at TIMESTAMPTZ           NOT NULL
+);
+
+CREATE INDEX enrollment_audit_student_id ON enrollment_audit (student_id, id);
+CREATE INDEX enrollment_audit_course_id ON enrollment_audit (course_id, id);
+CREATE RULE enrollment_audit_no_update AS ON UPDATE TO enrollment_audit DO INSTEAD NOTHING;
+CREATE RULE enrollment_audit_no_delete AS ON DELETE TO enrollment_audit DO INSTEAD NOTHING;
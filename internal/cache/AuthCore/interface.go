@@ -1,14 +1,75 @@
 package AuthCore
 
-import "context"
+import (
+	"context"
+	"strconv"
+	"time"
+)
 
+// Session is a token which is given to a user on login or refresh
+type Session struct {
+	// The jti of the token
+	ID      string
+	User    uint64
+	IsStaff bool
+	// The session is forgotten after this time, because the token is not valid anymore
+	ExpiresAt time.Time
+}
+
+// userKey is the key of the user of a session. Staff and students can have the same IDs.
+func (s Session) userKey() string {
+	return userKey(s.User, s.IsStaff)
+}
+
+// userKey is the key of a user which their sessions are grouped by
+func userKey(user uint64, isStaff bool) string {
+	if isStaff {
+		return "staff:" + strconv.FormatUint(user, 10)
+	}
+	return "student:" + strconv.FormatUint(user, 10)
+}
+
+// UserLimitKey is the key which the login attempts of a user are limited by
+func UserLimitKey(user uint64, isStaff bool) string {
+	return "user:" + userKey(user, isStaff)
+}
+
+// IPLimitKey is the key which the login attempts from an IP are limited by
+func IPLimitKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Interface is the allow list of the tokens of AuthCore. A token is valid only if its session is
+// set and not deleted. It also keeps the login attempts and the lockouts of the users and the IPs.
 type Interface interface {
-	// Set must set the token for user
-	Set(ctx context.Context, token string) error
-	// Delete must revoke a token of user
-	Delete(ctx context.Context, token string) error
-	// IsValid must check if a token is valid or not
-	IsValid(ctx context.Context, token string) (bool, error)
+	// Set must allow the token of a session until it expires
+	Set(ctx context.Context, session Session) error
+	// Delete must revoke a token by its ID
+	Delete(ctx context.Context, id string) error
+	// IsValid must check if a token is allowed or not
+	IsValid(ctx context.Context, id string) (bool, error)
 	// Replace must do something like token refresh. Delete followed by Set
-	Replace(ctx context.Context, old, new string) error
+	Replace(ctx context.Context, old string, new Session) error
+	// DeleteUser must revoke every token of a user
+	DeleteUser(ctx context.Context, user uint64, isStaff bool) error
+	// AddLoginAttempt must record a login attempt of a key and return the number of its attempts
+	// in the last window, including this one
+	AddLoginAttempt(ctx context.Context, key string, window time.Duration) (int, error)
+	// Lock must lock a key and forget its attempts. The first lock lasts lockout and each next
+	// one twice the previous, at most maxLockout. The previous locks are forgotten after a
+	// maxLockout without any. The duration of the lock is returned.
+	Lock(ctx context.Context, key string, lockout, maxLockout time.Duration) (time.Duration, error)
+	// LockedFor must return how long a key is still locked. Zero means it's not locked.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Unlock must forget the lock, the previous locks and the attempts of a key
+	Unlock(ctx context.Context, key string) error
+}
+
+// lockoutDuration is the duration of a lock of a key which has been locked previous times
+func lockoutDuration(previous int64, lockout, maxLockout time.Duration) time.Duration {
+	result := lockout
+	for ; previous > 0 && result < maxLockout; previous-- {
+		result *= 2
+	}
+	return min(result, maxLockout)
 }
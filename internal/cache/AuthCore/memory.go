@@ -0,0 +1,151 @@
+package AuthCore
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryAuth is an in-memory token storage. It's used when there is no Redis, which is only fine
+// for a single AuthCore, and in tests.
+type MemoryAuth struct {
+	sessions map[string]Session
+	attempts map[string]memoryAttempts
+	locks    map[string]memoryLock
+	mu       sync.Mutex
+}
+
+// memoryAttempts is the login attempts of a key in MemoryAuth
+type memoryAttempts struct {
+	// Sorted, so the old ones are at the start
+	times []time.Time
+	// The attempts are forgotten after this time, because none of them are in the window
+	forgetAt time.Time
+}
+
+// memoryLock is the lock of a key in MemoryAuth
+type memoryLock struct {
+	until time.Time
+	// The number of the locks of the key so far
+	count int64
+	// The count is forgotten after this time
+	forgetAt time.Time
+}
+
+// NewMemoryAuth creates an empty MemoryAuth
+func NewMemoryAuth() *MemoryAuth {
+	return &MemoryAuth{
+		sessions: make(map[string]Session),
+		attempts: make(map[string]memoryAttempts),
+		locks:    make(map[string]memoryLock),
+	}
+}
+
+func (a *MemoryAuth) Set(_ context.Context, session Session) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeExpired()
+	a.sessions[session.ID] = session
+	return nil
+}
+
+func (a *MemoryAuth) Delete(_ context.Context, id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.sessions, id)
+	return nil
+}
+
+func (a *MemoryAuth) IsValid(_ context.Context, id string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	session, exists := a.sessions[id]
+	return exists && time.Now().Before(session.ExpiresAt), nil
+}
+
+func (a *MemoryAuth) Replace(_ context.Context, old string, new Session) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.sessions, old)
+	a.sessions[new.ID] = new
+	return nil
+}
+
+func (a *MemoryAuth) DeleteUser(_ context.Context, user uint64, isStaff bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id, session := range a.sessions {
+		if session.User == user && session.IsStaff == isStaff {
+			delete(a.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (a *MemoryAuth) AddLoginAttempt(_ context.Context, key string, window time.Duration) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeExpired()
+	now := time.Now()
+	attempts := a.attempts[key]
+	for len(attempts.times) > 0 && !attempts.times[0].After(now.Add(-window)) {
+		attempts.times = attempts.times[1:]
+	}
+	attempts.times = append(attempts.times, now)
+	attempts.forgetAt = now.Add(window)
+	a.attempts[key] = attempts
+	return len(attempts.times), nil
+}
+
+func (a *MemoryAuth) Lock(_ context.Context, key string, lockout, maxLockout time.Duration) (time.Duration, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	lock := a.locks[key]
+	if now.After(lock.forgetAt) {
+		lock.count = 0
+	}
+	duration := lockoutDuration(lock.count, lockout, maxLockout)
+	a.locks[key] = memoryLock{
+		until:    now.Add(duration),
+		count:    lock.count + 1,
+		forgetAt: now.Add(duration + maxLockout),
+	}
+	delete(a.attempts, key)
+	return duration, nil
+}
+
+func (a *MemoryAuth) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return max(time.Until(a.locks[key].until), 0), nil
+}
+
+func (a *MemoryAuth) Unlock(_ context.Context, key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.attempts, key)
+	delete(a.locks, key)
+	return nil
+}
+
+// removeExpired removes the sessions, the attempts and the locks which have expired. a.mu must
+// be held.
+func (a *MemoryAuth) removeExpired() {
+	now := time.Now()
+	for id, session := range a.sessions {
+		if !now.Before(session.ExpiresAt) {
+			delete(a.sessions, id)
+		}
+	}
+	for key, attempts := range a.attempts {
+		if now.After(attempts.forgetAt) {
+			delete(a.attempts, key)
+		}
+	}
+	for key, lock := range a.locks {
+		if now.After(lock.forgetAt) {
+			delete(a.locks, key)
+		}
+	}
+}
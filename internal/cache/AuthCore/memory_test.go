@@ -0,0 +1,64 @@
+package AuthCore
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMemoryAuthLock(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryAuth()
+	key := UserLimitKey(1, false)
+	// Each lock lasts twice the previous one, at most maxLockout
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		duration, err := cache.Lock(ctx, key, time.Minute, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, expected, duration)
+	}
+	locked, err := cache.LockedFor(ctx, key)
+	require.NoError(t, err)
+	assert.InDelta(t, 4*time.Minute, locked, float64(time.Second))
+	duration, err := cache.Lock(ctx, key, time.Minute, 3*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Minute, duration)
+	// Other keys are not locked
+	locked, err = cache.LockedFor(ctx, UserLimitKey(1, true))
+	require.NoError(t, err)
+	assert.Zero(t, locked)
+	// Unlocking forgets the previous locks
+	require.NoError(t, cache.Unlock(ctx, key))
+	locked, err = cache.LockedFor(ctx, key)
+	require.NoError(t, err)
+	assert.Zero(t, locked)
+	duration, err = cache.Lock(ctx, key, time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, duration)
+}
+
+func TestMemoryAuthSessions(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryAuth()
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, cache.Set(ctx, Session{ID: "a", User: 1, ExpiresAt: expiresAt}))
+	require.NoError(t, cache.Set(ctx, Session{ID: "b", User: 1, IsStaff: true, ExpiresAt: expiresAt}))
+	require.NoError(t, cache.Set(ctx, Session{ID: "expired", User: 1, ExpiresAt: time.Now().Add(-time.Second)}))
+	isValid := func(id string) bool {
+		t.Helper()
+		valid, err := cache.IsValid(ctx, id)
+		require.NoError(t, err)
+		return valid
+	}
+	assert.True(t, isValid("a"))
+	assert.False(t, isValid("expired"))
+	// Replacing revokes the old one
+	require.NoError(t, cache.Replace(ctx, "a", Session{ID: "c", User: 1, ExpiresAt: expiresAt}))
+	assert.False(t, isValid("a"))
+	assert.True(t, isValid("c"))
+	// Staff and students with the same ID are different users
+	require.NoError(t, cache.DeleteUser(ctx, 1, false))
+	assert.False(t, isValid("c"))
+	assert.True(t, isValid("b"))
+}
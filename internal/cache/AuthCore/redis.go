@@ -1,15 +1,30 @@
 package AuthCore
 
 import (
-	"CourseEnrollment/internal/shared"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/go-faster/errors"
 	"github.com/go-redis/redis/v8"
+	"strconv"
+	"time"
 )
 
 // Prefix for course enrollment auth
 const prefix = "cea:"
 
-// RedisAuth is a token storage for authentication based on Redis
+// The prefixes of the keys of tokens, the sets of the tokens of each user, the sorted sets of
+// login attempts, the locks and the number of the previous locks
+const (
+	tokenPrefix    = prefix + "token:"
+	userPrefix     = prefix + "user:"
+	attemptsPrefix = prefix + "attempts:"
+	lockPrefix     = prefix + "lock:"
+	lockoutsPrefix = prefix + "lockouts:"
+)
+
+// RedisAuth is a token storage for authentication based on Redis. Each token is a key which expires
+// with the token, and the tokens of each user are also kept in a set to revoke all of them at once.
 type RedisAuth struct {
 	client *redis.Client
 }
@@ -19,29 +34,104 @@ func NewRedisAuth(client *redis.Client) RedisAuth {
 	return RedisAuth{client}
 }
 
-func (a RedisAuth) Set(ctx context.Context, token string) error {
-	return a.client.Set(ctx, prefix+token, 0, shared.AuthCoreTokenTTL).Err()
+func (a RedisAuth) Set(ctx context.Context, session Session) error {
+	_, err := a.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		set(ctx, pipe, session)
+		return nil
+	})
+	return errors.Wrap(err, "cannot set token")
 }
 
-func (a RedisAuth) Delete(ctx context.Context, token string) error {
-	return a.client.Del(ctx, prefix+token).Err()
+// set queues the commands of setting a session in pipe. The set of the user expires with its
+// newest token.
+func set(ctx context.Context, pipe redis.Pipeliner, session Session) {
+	pipe.Set(ctx, tokenPrefix+session.ID, session.userKey(), 0)
+	pipe.ExpireAt(ctx, tokenPrefix+session.ID, session.ExpiresAt)
+	pipe.SAdd(ctx, userPrefix+session.userKey(), session.ID)
+	pipe.ExpireAt(ctx, userPrefix+session.userKey(), session.ExpiresAt)
 }
 
-func (a RedisAuth) IsValid(ctx context.Context, token string) (bool, error) {
-	result := a.client.Exists(ctx, prefix+token)
-	if err := result.Err(); err != nil {
-		return false, err
-	}
-	keys, err := result.Result()
+func (a RedisAuth) Delete(ctx context.Context, id string) error {
+	// The ID stays in the set of the user until the set expires, which is harmless
+	return errors.Wrap(a.client.Del(ctx, tokenPrefix+id).Err(), "cannot delete token")
+}
+
+func (a RedisAuth) IsValid(ctx context.Context, id string) (bool, error) {
+	keys, err := a.client.Exists(ctx, tokenPrefix+id).Result()
 	if err != nil {
-		return false, err
+		return false, errors.Wrap(err, "cannot check token")
 	}
 	return keys == 1, nil
 }
 
-func (a RedisAuth) Replace(ctx context.Context, old, new string) error {
-	if err := a.Delete(ctx, old); err != nil {
-		return err
+func (a RedisAuth) Replace(ctx context.Context, old string, new Session) error {
+	_, err := a.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, tokenPrefix+old)
+		set(ctx, pipe, new)
+		return nil
+	})
+	return errors.Wrap(err, "cannot replace token")
+}
+
+func (a RedisAuth) DeleteUser(ctx context.Context, user uint64, isStaff bool) error {
+	key := userPrefix + userKey(user, isStaff)
+	ids, err := a.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return errors.Wrap(err, "cannot get tokens of user")
+	}
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, tokenPrefix+id)
 	}
-	return a.Set(ctx, new)
+	keys = append(keys, key)
+	return errors.Wrap(a.client.Del(ctx, keys...).Err(), "cannot delete tokens of user")
+}
+
+// AddLoginAttempt keeps the attempts in a sorted set by their time in nanoseconds, which is a
+// sliding window
+func (a RedisAuth) AddLoginAttempt(ctx context.Context, key string, window time.Duration) (int, error) {
+	now := time.Now()
+	// Attempts at the same time must be different members
+	member := make([]byte, 8)
+	_, _ = rand.Read(member)
+	var count *redis.IntCmd
+	_, err := a.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, attemptsPrefix+key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+		pipe.ZAdd(ctx, attemptsPrefix+key, &redis.Z{Score: float64(now.UnixNano()), Member: hex.EncodeToString(member)})
+		count = pipe.ZCard(ctx, attemptsPrefix+key)
+		pipe.PExpire(ctx, attemptsPrefix+key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot add login attempt")
+	}
+	return int(count.Val()), nil
+}
+
+func (a RedisAuth) Lock(ctx context.Context, key string, lockout, maxLockout time.Duration) (time.Duration, error) {
+	locks, err := a.client.Incr(ctx, lockoutsPrefix+key).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot count locks")
+	}
+	duration := lockoutDuration(locks-1, lockout, maxLockout)
+	_, err = a.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, lockPrefix+key, "", duration)
+		pipe.PExpire(ctx, lockoutsPrefix+key, duration+maxLockout)
+		pipe.Del(ctx, attemptsPrefix+key)
+		return nil
+	})
+	return duration, errors.Wrap(err, "cannot lock")
+}
+
+func (a RedisAuth) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := a.client.PTTL(ctx, lockPrefix+key).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot check lock")
+	}
+	// Negative if the key does not exist
+	return max(ttl, 0), nil
+}
+
+func (a RedisAuth) Unlock(ctx context.Context, key string) error {
+	return errors.Wrap(a.client.Del(ctx, attemptsPrefix+key, lockPrefix+key, lockoutsPrefix+key).Err(), "cannot unlock")
 }
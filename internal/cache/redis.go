@@ -7,7 +7,7 @@ import (
 )
 
 // NewRedisClient creates a new Redis client and tests it by pinging it
-func NewRedisClient(address, password string) error {
+func NewRedisClient(address, password string) (*redis.Client, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     address,
 		Password: password,
@@ -15,7 +15,8 @@ func NewRedisClient(address, password string) error {
 	})
 	err := client.Ping(context.Background()).Err()
 	if err != nil {
-		return errors.Wrap(err, "cannot ping database")
+		_ = client.Close()
+		return nil, errors.Wrap(err, "cannot ping database")
 	}
-	return nil
+	return client, nil
 }
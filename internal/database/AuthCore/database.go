@@ -1,43 +1,336 @@
 package AuthCore
 
 import (
+	"CourseEnrollment/internal/password"
+	"CourseEnrollment/internal/shared"
 	"CourseEnrollment/pkg/course"
 	"context"
+	"database/sql"
+	"fmt"
 	"github.com/go-faster/errors"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"golang.org/x/crypto/bcrypt"
+	log "github.com/sirupsen/logrus"
+	"time"
 )
 
+// Interface is the storage which AuthCore authorizes the users with
+type Interface interface {
+	// AuthUser must check the password of a user. It returns false if the user does not exist
+	// or the password is wrong. The department and the role of user are also returned. The hash of
+	// the password must be upgraded if it's not made with the password policy.
+	AuthUser(ctx context.Context, id uint64, password string, isStaff bool) (bool, User, error)
+	// GetUser must return the department and the role of a user. It returns false if the user
+	// does not exist.
+	GetUser(ctx context.Context, id uint64, isStaff bool) (bool, User, error)
+	// SetPassword must replace the password of a user. It returns false if the user does not exist.
+	SetPassword(ctx context.Context, id uint64, isStaff bool, password string) (bool, error)
+	// AddPasswordReset must store a one-time token which resets the password of a user until
+	// expiresAt. Only the hash of the token is stored.
+	AddPasswordReset(ctx context.Context, tokenHash string, reset PasswordReset, expiresAt time.Time) error
+	// ResetPassword must replace the password of the user of a reset token and remove every reset
+	// token of the user. It returns false if the token does not exist or has expired.
+	ResetPassword(ctx context.Context, tokenHash, password string) (bool, PasswordReset, error)
+	// AuthExternalUser must find the student or the staff which an identity of an identity provider
+	// is linked to. It returns false if the identity is not linked to any.
+	AuthExternalUser(ctx context.Context, issuer, subject string, isStaff bool) (bool, uint64, User, error)
+	// GetNotifications must return at most limit notifications of a student which their ID is
+	// greater than after, ordered by their ID
+	GetNotifications(ctx context.Context, studentID, after uint64, limit int) ([]Notification, error)
+	// GetAuditLog must return at most limit entries of the audit log which their ID is greater than
+	// after, ordered by their ID. Zero student or course ID matches every student or course.
+	GetAuditLog(ctx context.Context, studentID uint64, courseID int32, after uint64, limit int) ([]AuditEntry, error)
+	// GetCourseDepartment must return the department of a course. It returns false if the course
+	// does not exist.
+	GetCourseDepartment(ctx context.Context, courseID int32) (bool, course.DepartmentID, error)
+	// Close must close the connection to the storage
+	Close()
+}
+
+// User is the data of an authorized user
+type User struct {
+	Department course.DepartmentID
+	// Always StaffRoleDepartment for students
+	Role shared.StaffRole
+}
+
+// PasswordReset is the user whose password is reset with a reset token
+type PasswordReset struct {
+	User    uint64
+	IsStaff bool
+}
+
+// Notification is a notification in the inbox of a student
+type Notification struct {
+	ID uint64
+	course.Event
+}
+
+// AuditEntry is an entry of the audit log of enrollment changes
+type AuditEntry struct {
+	ID uint64
+	course.AuditEntry
+}
+
+// Database is the PostgreSQL implementation of Interface
 type Database struct {
 	db *pgxpool.Pool
+	// The policy which the passwords are hashed with
+	policy password.Policy
+}
+
+func NewDatabase(db *pgxpool.Pool, policy password.Policy) Database {
+	return Database{db, policy}
 }
 
-func NewDatabase(db *pgxpool.Pool) Database {
-	return Database{db}
+// userTable is the table of students or staff
+func userTable(isStaff bool) string {
+	if isStaff {
+		return "staff"
+	}
+	return "students"
 }
 
 // AuthUser will authorize the user
-func (db Database) AuthUser(ctx context.Context, id uint64, password string, isStaff bool) (bool, course.DepartmentID, error) {
+func (db Database) AuthUser(ctx context.Context, id uint64, pass string, isStaff bool) (bool, User, error) {
 	// Query data
 	var hashedPassword string
-	var departmentID course.DepartmentID
+	user, err := db.queryUser(ctx, id, isStaff, &hashedPassword)
+	// No user found
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, User{}, nil
+	}
+	if err != nil {
+		return false, User{}, err
+	}
+	// Check password
+	ok, err := password.Verify(hashedPassword, pass)
+	if err != nil {
+		return false, User{}, errors.Wrapf(err, "invalid password hash of %d", id)
+	}
+	if !ok {
+		return false, User{}, nil
+	}
+	// Upgrade the hash. The login is fine even if it fails, so it's tried again on the next one.
+	if db.policy.NeedsRehash(hashedPassword) {
+		newHash, err := db.policy.Hash(pass)
+		if err == nil {
+			// Only if the password is not changed meanwhile
+			_, err = db.db.Exec(ctx, "UPDATE "+userTable(isStaff)+" SET password=$1 WHERE id=$2 AND password=$3", newHash, id, hashedPassword)
+		}
+		if err != nil {
+			log.WithError(err).WithField("id", id).Warn("cannot upgrade password hash")
+		}
+	}
+	return true, user, nil
+}
+
+// GetUser will get the department and the role of a user
+func (db Database) GetUser(ctx context.Context, id uint64, isStaff bool) (bool, User, error) {
+	var hashedPassword string
+	user, err := db.queryUser(ctx, id, isStaff, &hashedPassword)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, User{}, nil
+	}
+	if err != nil {
+		return false, User{}, err
+	}
+	return true, user, nil
+}
+
+// queryUser will query the data and the password hash of a user. pgx.ErrNoRows is returned as is.
+func (db Database) queryUser(ctx context.Context, id uint64, isStaff bool, hashedPassword *string) (User, error) {
+	var user User
+	if isStaff {
+		var role string
+		err := db.db.QueryRow(ctx, "SELECT password, department_id, role::text FROM staff WHERE id=$1", id).Scan(hashedPassword, &user.Department, &role)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return User{}, err
+		}
+		if err != nil {
+			return User{}, errors.Wrap(err, "cannot query row")
+		}
+		var ok bool
+		if user.Role, ok = shared.ParseStaffRole(role); !ok {
+			return User{}, errors.Errorf("invalid role: %s", role)
+		}
+		return user, nil
+	}
+	err := db.db.QueryRow(ctx, "SELECT password, department_id FROM students WHERE id=$1", id).Scan(hashedPassword, &user.Department)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, err
+	}
+	if err != nil {
+		return User{}, errors.Wrap(err, "cannot query row")
+	}
+	return user, nil
+}
+
+// SetPassword will hash the password of a user with the policy and store it
+func (db Database) SetPassword(ctx context.Context, id uint64, isStaff bool, pass string) (bool, error) {
+	hash, err := db.policy.Hash(pass)
+	if err != nil {
+		return false, err
+	}
+	tag, err := db.db.Exec(ctx, "UPDATE "+userTable(isStaff)+" SET password=$1 WHERE id=$2", hash, id)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot update password")
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// AddPasswordReset will store a reset token
+func (db Database) AddPasswordReset(ctx context.Context, tokenHash string, reset PasswordReset, expiresAt time.Time) error {
+	_, err := db.db.Exec(ctx, "INSERT INTO password_resets (token_hash, user_id, is_staff, expires_at) VALUES ($1, $2, $3, $4)",
+		tokenHash, reset.User, reset.IsStaff, expiresAt)
+	return errors.Wrap(err, "cannot insert password reset")
+}
+
+// ResetPassword will use a reset token to change the password of its user
+func (db Database) ResetPassword(ctx context.Context, tokenHash, pass string) (bool, PasswordReset, error) {
+	hash, err := db.policy.Hash(pass)
+	if err != nil {
+		return false, PasswordReset{}, err
+	}
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return false, PasswordReset{}, errors.Wrap(err, "cannot start transaction")
+	}
+	defer tx.Rollback(ctx)
+	// Take the token, so it cannot be used twice
+	var reset PasswordReset
+	err = tx.QueryRow(ctx, "DELETE FROM password_resets WHERE token_hash=$1 AND expires_at>NOW() RETURNING user_id, is_staff",
+		tokenHash).Scan(&reset.User, &reset.IsStaff)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, PasswordReset{}, nil
+	}
+	if err != nil {
+		return false, PasswordReset{}, errors.Wrap(err, "cannot take password reset")
+	}
+	_, err = tx.Exec(ctx, "UPDATE "+userTable(reset.IsStaff)+" SET password=$1 WHERE id=$2", hash, reset.User)
+	if err != nil {
+		return false, PasswordReset{}, errors.Wrap(err, "cannot update password")
+	}
+	// The other tokens of the user and the expired ones are not needed anymore
+	_, err = tx.Exec(ctx, "DELETE FROM password_resets WHERE (user_id=$1 AND is_staff=$2) OR expires_at<=NOW()", reset.User, reset.IsStaff)
+	if err != nil {
+		return false, PasswordReset{}, errors.Wrap(err, "cannot remove password resets")
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return false, PasswordReset{}, errors.Wrap(err, "cannot commit password reset")
+	}
+	return true, reset, nil
+}
+
+// AuthExternalUser will find the user of an external identity
+func (db Database) AuthExternalUser(ctx context.Context, issuer, subject string, isStaff bool) (bool, uint64, User, error) {
+	var id uint64
+	var user User
 	var err error
 	if isStaff {
-		err = db.db.QueryRow(ctx, "SELECT password, department_id FROM staff WHERE id=$1", id).Scan(&hashedPassword, &departmentID)
+		var role string
+		err = db.db.QueryRow(ctx, "SELECT staff.id, staff.department_id, staff.role::text FROM external_identities "+
+			"JOIN staff ON staff.id=external_identities.user_id WHERE issuer=$1 AND subject=$2 AND is_staff",
+			issuer, subject).Scan(&id, &user.Department, &role)
+		if err == nil {
+			var ok bool
+			if user.Role, ok = shared.ParseStaffRole(role); !ok {
+				return false, 0, User{}, errors.Errorf("invalid role: %s", role)
+			}
+		}
 	} else {
-		err = db.db.QueryRow(ctx, "SELECT password, department_id FROM students WHERE id=$1", id).Scan(&hashedPassword, &departmentID)
+		err = db.db.QueryRow(ctx, "SELECT students.id, students.department_id FROM external_identities "+
+			"JOIN students ON students.id=external_identities.user_id WHERE issuer=$1 AND subject=$2 AND NOT is_staff",
+			issuer, subject).Scan(&id, &user.Department)
 	}
-	// No user found
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, 0, User{}, nil
+	}
+	if err != nil {
+		return false, 0, User{}, errors.Wrap(err, "cannot query row")
+	}
+	return true, id, user, nil
+}
+
+// GetNotifications will get the notifications of a student after a notification ID
+func (db Database) GetNotifications(ctx context.Context, studentID, after uint64, limit int) ([]Notification, error) {
+	rows, err := db.db.Query(ctx, "SELECT id, kind, course_id, group_id, capacity, reserve_position, created_at, confirmation_deadline FROM notifications "+
+		"WHERE student_id=$1 AND id>$2 ORDER BY id LIMIT $3", studentID, after, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot query notifications")
+	}
+	defer rows.Close()
+	var result []Notification
+	for rows.Next() {
+		notification := Notification{Event: course.Event{StudentID: course.StudentID(studentID)}}
+		var createdAt time.Time
+		var deadline sql.NullTime
+		err = rows.Scan(&notification.ID, &notification.Kind, &notification.CourseID, &notification.GroupID,
+			&notification.Capacity, &notification.ReservePosition, &createdAt, &deadline)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot scan notification")
+		}
+		notification.Time = createdAt.UnixMilli()
+		if deadline.Valid {
+			notification.ConfirmationDeadline = deadline.Time.UnixMilli()
+		}
+		result = append(result, notification)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "cannot read notifications")
+	}
+	return result, nil
+}
+
+// GetAuditLog will get the entries of the audit log of a student, a course or both after an entry ID
+func (db Database) GetAuditLog(ctx context.Context, studentID uint64, courseID int32, after uint64, limit int) ([]AuditEntry, error) {
+	// Only filter the given columns, so the indexes are used
+	query := "SELECT id, action, student_id, course_id, group_id, capacity, actor_kind, actor_id, origin, reason, created_at FROM enrollment_audit WHERE id>$1"
+	args := []any{after}
+	if studentID != 0 {
+		args = append(args, studentID)
+		query += fmt.Sprintf(" AND student_id=$%d", len(args))
+	}
+	if courseID != 0 {
+		args = append(args, courseID)
+		query += fmt.Sprintf(" AND course_id=$%d", len(args))
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY id LIMIT $%d", len(args))
+	rows, err := db.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot query audit log")
+	}
+	defer rows.Close()
+	var result []AuditEntry
+	for rows.Next() {
+		var entry AuditEntry
+		var createdAt time.Time
+		err = rows.Scan(&entry.ID, &entry.Action, &entry.StudentID, &entry.CourseID, &entry.GroupID, &entry.Capacity,
+			&entry.ActorKind, &entry.ActorID, &entry.Origin, &entry.Reason, &createdAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot scan audit entry")
+		}
+		entry.Time = createdAt.UnixMilli()
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "cannot read audit log")
+	}
+	return result, nil
+}
+
+// GetCourseDepartment will get the department of a course
+func (db Database) GetCourseDepartment(ctx context.Context, courseID int32) (bool, course.DepartmentID, error) {
+	var department course.DepartmentID
+	err := db.db.QueryRow(ctx, "SELECT for_department FROM courses WHERE course_id=$1 LIMIT 1", courseID).Scan(&department)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return false, 0, nil
 	}
 	if err != nil {
-		return false, 0, errors.Wrap(err, "cannot query row")
+		return false, 0, errors.Wrap(err, "cannot query course")
 	}
-	// Check password
-	err = bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil, departmentID, nil
+	return true, department, nil
 }
 
 // Close will close the database connection
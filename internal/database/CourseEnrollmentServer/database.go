@@ -2,14 +2,52 @@ package CourseEnrollmentServer
 
 import (
 	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
 	"CourseEnrollment/pkg/util"
 	"context"
 	"database/sql"
 	"github.com/go-faster/errors"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 )
 
+// Interface is the storage which the enrollment server loads its initial data from
+// and reconciles its state with
+type Interface interface {
+	// GetDepartments must get the list of departments
+	GetDepartments() (course.Departments, error)
+	// GetCourses must get the list of courses alongside their registered students and reserve queues
+	GetCourses() (*course.Courses, error)
+	// GetStudents must get all students alongside their enrolled courses
+	GetStudents() (map[course.StudentID]*course.Student, error)
+	// GetEnrolledCourses must get all rows of enrolled courses ordered by their id
+	GetEnrolledCourses(ctx context.Context) ([]EnrolledCourse, error)
+	// ReplaceEnrolledCourses must atomically replace the rows of the given course groups
+	ReplaceEnrolledCourses(ctx context.Context, enrollments []CourseEnrollments) error
+}
+
+// EnrolledCourse is a row of enrolled courses
+type EnrolledCourse struct {
+	CourseID  course.CourseID
+	GroupID   course.GroupID
+	StudentID course.StudentID
+	Reserved  bool
+}
+
+// CourseEnrollments is the list of all students of a course group
+type CourseEnrollments struct {
+	CourseID course.CourseID
+	GroupID  course.GroupID
+	// Registered students in no specific order
+	Registered []course.StudentID
+	// The reserve queue in order
+	Reserved []course.StudentID
+	// The confirmation deadlines of the promoted students in milliseconds
+	ConfirmationDeadlines map[course.StudentID]int64
+}
+
+// Database is the PostgreSQL implementation of Interface
 type Database struct {
 	db *pgxpool.Pool
 }
@@ -42,9 +80,10 @@ func (db *Database) GetDepartments() (course.Departments, error) {
 }
 
 // GetCourses will get the list of courses from database.
-// It also updates the courses registered list.
+// It also fills the registered students and the reserve queue of each course with a single
+// query over enrolled_courses.
 func (db *Database) GetCourses() (*course.Courses, error) {
-	rows, err := db.db.Query(context.Background(), "SELECT course_id, group_id, for_department, units, capacity, reserve_capacity, exam_time, class_time, sex_lock, lecturer FROM courses")
+	rows, err := db.db.Query(context.Background(), "SELECT course_id, group_id, for_department, units, capacity, reserve_capacity, exam_time, class_time, sex_lock, lecturer, departments, min_entry_year, max_entry_year, allowed_students, denied_students, confirmation_window FROM courses")
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot query courses")
 	}
@@ -55,11 +94,18 @@ func (db *Database) GetCourses() (*course.Courses, error) {
 		// Create the course
 		currentCourse := new(course.Course)
 		var examTime sql.NullTime
+		var departments []int16
+		var minEntryYear, maxEntryYear int16
+		var allowedStudents, deniedStudents []int64
+		var confirmationWindow int32
 		err = rows.Scan(&currentCourse.ID, &currentCourse.GroupID, &currentCourse.Department, &currentCourse.Units, &currentCourse.Capacity, &currentCourse.ReserveCapacity,
-			&examTime, &currentCourse.ClassHeldTime, &currentCourse.SexLock, &currentCourse.Lecturer)
+			&examTime, &currentCourse.ClassHeldTime, &currentCourse.SexLock, &currentCourse.Lecturer,
+			&departments, &minEntryYear, &maxEntryYear, &allowedStudents, &deniedStudents, &confirmationWindow)
 		if err != nil {
 			return nil, errors.Wrap(err, "cannot scan course")
 		}
+		currentCourse.Eligibility = newEligibility(departments, minEntryYear, maxEntryYear, allowedStudents, deniedStudents)
+		currentCourse.ConfirmationWindow = time.Duration(confirmationWindow) * time.Second
 		// Update some missing info based on scanned ones
 		currentCourse.ReserveQueue = util.NewQueue[course.StudentID]()
 		currentCourse.RegisteredStudents = make(map[course.StudentID]struct{}, currentCourse.Capacity)
@@ -68,47 +114,213 @@ func (db *Database) GetCourses() (*course.Courses, error) {
 		} else {
 			currentCourse.ExamTime.Store(0)
 		}
-		// Get the courses registered list
-		err = db.updateCourseRegistered(currentCourse)
-		if err != nil {
-			return nil, errors.Wrap(err, "cannot set registered users")
-		}
 		// Insert it into map
 		result[currentCourse.ID] = append(result[currentCourse.ID], currentCourse)
 	}
-	return course.NewCourses(result), nil
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "cannot read courses")
+	}
+	courses := course.NewCourses(result)
+	// Get the registered lists
+	err = db.updateCoursesRegistered(courses)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot set registered users")
+	}
+	// Get the intents
+	err = db.updateCoursesIntents(courses)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot set intents")
+	}
+	// Get the schedule
+	schedule, err := db.getSchedule()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get schedule")
+	}
+	courses.SetSchedule(schedule)
+	// Get the requisites
+	requisites, err := db.getRequisites()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get requisites")
+	}
+	courses.SetRequisites(requisites)
+	return courses, nil
 }
 
-// updateCourseRegistered updates the registered users in the
-func (db *Database) updateCourseRegistered(c *course.Course) error {
-	rows, err := db.db.Query(context.Background(), "SELECT student_id, reserved FROM enrolled_courses WHERE course_id=$1 AND group_id=$2 ORDER BY id", c.ID, c.GroupID)
+// getRequisites gets the prerequisites and co-requisites of all courses
+func (db *Database) getRequisites() (map[course.CourseID]course.Requisites, error) {
+	rows, err := db.db.Query(context.Background(), "SELECT course_id, requisite_id, corequisite FROM course_requisites")
 	if err != nil {
-		return errors.Wrapf(err, "cannot query course %d-%d", c.ID, c.GroupID)
+		return nil, errors.Wrap(err, "cannot query requisites")
+	}
+	defer rows.Close()
+	result := make(map[course.CourseID]course.Requisites)
+	for rows.Next() {
+		var courseID, requisiteID course.CourseID
+		var corequisite bool
+		err = rows.Scan(&courseID, &requisiteID, &corequisite)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot scan requisite")
+		}
+		requisites := result[courseID]
+		if corequisite {
+			requisites.Corequisites = append(requisites.Corequisites, requisiteID)
+		} else {
+			requisites.Prerequisites = append(requisites.Prerequisites, requisiteID)
+		}
+		result[courseID] = requisites
+	}
+	return result, rows.Err()
+}
+
+// getSchedule gets the enrollment schedule. Nil is returned if there are no phases.
+func (db *Database) getSchedule() (*course.Schedule, error) {
+	rows, err := db.db.Query(context.Background(), "SELECT name, allow_enroll, allow_disenroll, allow_change_group, student_duration, lottery, priorities, seed FROM enrollment_phases ORDER BY position")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot query phases")
+	}
+	phases, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (course.Phase, error) {
+		var phase course.Phase
+		var allowEnroll, allowDisenroll, allowChangeGroup bool
+		var studentDuration int64
+		var priorities []int16
+		err := row.Scan(&phase.Name, &allowEnroll, &allowDisenroll, &allowChangeGroup, &studentDuration, &phase.Lottery, &priorities, &phase.Seed)
+		for _, priority := range priorities {
+			phase.Priorities = append(phase.Priorities, course.Priority(priority))
+		}
+		if allowEnroll {
+			phase.Actions |= course.ActionEnroll
+		}
+		if allowDisenroll {
+			phase.Actions |= course.ActionDisenroll
+		}
+		if allowChangeGroup {
+			phase.Actions |= course.ActionChangeGroup
+		}
+		phase.StudentDuration = time.Duration(studentDuration) * time.Millisecond
+		return phase, err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot scan phases")
+	}
+	if len(phases) == 0 {
+		return nil, nil
+	}
+	phaseIndex := make(map[string]int, len(phases))
+	for i, phase := range phases {
+		phaseIndex[phase.Name] = i
+	}
+	// Get the windows
+	rows, err = db.db.Query(context.Background(), "SELECT phase, department_id, entry_year, start_time, end_time FROM enrollment_windows")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot query windows")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var phase string
+		var window course.Window
+		var start, end time.Time
+		err = rows.Scan(&phase, &window.Department, &window.EntryYear, &start, &end)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot scan window")
+		}
+		window.Start, window.End = start.UnixMilli(), end.UnixMilli()
+		phases[phaseIndex[phase]].Windows = append(phases[phaseIndex[phase]].Windows, window)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "cannot read windows")
+	}
+	return &course.Schedule{Phases: phases}, nil
+}
+
+// newEligibility creates the eligibility rules of a course from its columns
+func newEligibility(departments []int16, minEntryYear, maxEntryYear int16, allowedStudents, deniedStudents []int64) *course.Eligibility {
+	data := &proto.CourseEligibility{
+		Departments:     make([]uint32, len(departments)),
+		MinEntryYear:    int32(minEntryYear),
+		MaxEntryYear:    int32(maxEntryYear),
+		AllowedStudents: make([]uint64, len(allowedStudents)),
+		DeniedStudents:  make([]uint64, len(deniedStudents)),
+	}
+	for i, department := range departments {
+		data.Departments[i] = uint32(department)
+	}
+	for i, student := range allowedStudents {
+		data.AllowedStudents[i] = uint64(student)
+	}
+	for i, student := range deniedStudents {
+		data.DeniedStudents[i] = uint64(student)
+	}
+	return course.NewEligibilityFromProto(data)
+}
+
+// updateCoursesRegistered updates the registered users and the reserve queues of all courses
+func (db *Database) updateCoursesRegistered(courses *course.Courses) error {
+	rows, err := db.db.Query(context.Background(), "SELECT course_id, group_id, student_id, reserved, confirmation_deadline FROM enrolled_courses ORDER BY id")
+	if err != nil {
+		return errors.Wrap(err, "cannot query enrolled courses")
 	}
 	defer rows.Close()
 	// Get the students
 	for rows.Next() {
+		var courseID course.CourseID
+		var groupID course.GroupID
 		var stdID course.StudentID
 		var reserved bool
-		err = rows.Scan(&stdID, &reserved)
+		var confirmationDeadline sql.NullTime
+		err = rows.Scan(&courseID, &groupID, &stdID, &reserved, &confirmationDeadline)
 		if err != nil {
 			return errors.Wrap(err, "cannot scan row")
 		}
+		c := courses.GetCourse(courseID, groupID)
+		if c == nil {
+			return errors.Errorf("student %d is enrolled in course %d-%d which does not exist", stdID, courseID, groupID)
+		}
 		// Add to course
 		if reserved {
 			// They will be queued in order
 			c.ReserveQueue.Enqueue(stdID)
 		} else {
 			c.RegisteredStudents[stdID] = struct{}{}
+			if confirmationDeadline.Valid {
+				if c.ConfirmationDeadlines == nil {
+					c.ConfirmationDeadlines = make(map[course.StudentID]int64)
+				}
+				c.ConfirmationDeadlines[stdID] = confirmationDeadline.Time.UnixMilli()
+			}
 		}
 	}
-	return nil
+	return rows.Err()
+}
+
+// updateCoursesIntents fills the group change intents of all courses in order
+func (db *Database) updateCoursesIntents(courses *course.Courses) error {
+	rows, err := db.db.Query(context.Background(), "SELECT course_id, group_id, student_id FROM group_change_intents ORDER BY id")
+	if err != nil {
+		return errors.Wrap(err, "cannot query intents")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var courseID course.CourseID
+		var groupID course.GroupID
+		var stdID course.StudentID
+		err = rows.Scan(&courseID, &groupID, &stdID)
+		if err != nil {
+			return errors.Wrap(err, "cannot scan row")
+		}
+		c := courses.GetCourse(courseID, groupID)
+		if c == nil {
+			return errors.Errorf("student %d has an intent in course %d-%d which does not exist", stdID, courseID, groupID)
+		}
+		c.Intents = append(c.Intents, stdID)
+	}
+	return rows.Err()
 }
 
 // GetStudents will get all students in the database as a map.
+// The enrolled courses of all students are fetched with a single query.
 func (db *Database) GetStudents() (map[course.StudentID]*course.Student, error) {
 	// Get all students
-	rows, err := db.db.Query(context.Background(), "SELECT id, enrollment_start_time, max_units, remaining_actions, gender FROM students")
+	rows, err := db.db.Query(context.Background(), "SELECT id, enrollment_start_time, max_units, remaining_actions, gender, department_id, entry_year, gpa_band FROM students")
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot query students")
 	}
@@ -118,43 +330,205 @@ func (db *Database) GetStudents() (map[course.StudentID]*course.Student, error)
 	for rows.Next() {
 		student := new(course.Student)
 		var enrollmentStartTime time.Time
-		err = rows.Scan(&student.ID, &enrollmentStartTime, &student.MaxUnits, &student.RemainingActions, &student.StudentSex)
+		err = rows.Scan(&student.ID, &enrollmentStartTime, &student.MaxUnits, &student.RemainingActions, &student.StudentSex, &student.Department, &student.EntryYear, &student.GPABand)
 		if err != nil {
 			return nil, errors.Wrap(err, "cannot scan row")
 		}
-		student.RegisteredCourses, student.RegisteredUnits, err = db.getEnrolledCoursesOfStudent(student.ID)
-		if err != nil {
-			return nil, errors.Wrapf(err, "cannot get student registered courses for %d", student.ID)
-		}
+		student.RegisteredCourses = make(map[course.CourseID]course.GroupID)
 		student.EnrollmentStartTime = enrollmentStartTime.UnixMilli()
 		// Add to map
 		result[student.ID] = student
 	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "cannot read students")
+	}
+	err = db.updateEnrolledCoursesOfStudents(result)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get students registered courses")
+	}
+	err = db.updatePassedCoursesOfStudents(result)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get students passed courses")
+	}
+	err = db.updateIntentsOfStudents(result)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get students intents")
+	}
+	err = db.updateWishlistsOfStudents(result)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get students wishlists")
+	}
 	return result, nil
 }
 
-// getEnrolledCoursesOfStudent gets the list of enrolled (reserved and registered) of a user
-// and the number of courses
-func (db *Database) getEnrolledCoursesOfStudent(stdID course.StudentID) (map[course.CourseID]course.GroupID, uint8, error) {
-	rows, err := db.db.Query(context.Background(), "SELECT enrolled_courses.course_id, enrolled_courses.group_id, c.units FROM enrolled_courses JOIN courses c on c.course_id = enrolled_courses.course_id and c.group_id = enrolled_courses.group_id WHERE student_id=$1", stdID)
+// updatePassedCoursesOfStudents fills the passed courses of all students
+func (db *Database) updatePassedCoursesOfStudents(students map[course.StudentID]*course.Student) error {
+	rows, err := db.db.Query(context.Background(), "SELECT student_id, course_id FROM passed_courses")
+	if err != nil {
+		return errors.Wrap(err, "cannot query")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var stdID course.StudentID
+		var courseID course.CourseID
+		err = rows.Scan(&stdID, &courseID)
+		if err != nil {
+			return errors.Wrap(err, "cannot scan")
+		}
+		student, exists := students[stdID]
+		if !exists {
+			return errors.Errorf("student %d with passed courses does not exist", stdID)
+		}
+		if student.PassedCourses == nil {
+			student.PassedCourses = make(map[course.CourseID]struct{})
+		}
+		student.PassedCourses[courseID] = struct{}{}
+	}
+	return rows.Err()
+}
+
+// updateIntentsOfStudents fills the group change intents of all students
+func (db *Database) updateIntentsOfStudents(students map[course.StudentID]*course.Student) error {
+	rows, err := db.db.Query(context.Background(), "SELECT student_id, course_id, group_id FROM group_change_intents")
+	if err != nil {
+		return errors.Wrap(err, "cannot query")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var stdID course.StudentID
+		var courseID course.CourseID
+		var groupID course.GroupID
+		err = rows.Scan(&stdID, &courseID, &groupID)
+		if err != nil {
+			return errors.Wrap(err, "cannot scan")
+		}
+		student, exists := students[stdID]
+		if !exists {
+			return errors.Errorf("student %d with intents does not exist", stdID)
+		}
+		if student.Intents == nil {
+			student.Intents = make(map[course.CourseID]course.GroupID)
+		}
+		student.Intents[courseID] = groupID
+	}
+	return rows.Err()
+}
+
+// updateWishlistsOfStudents fills the wishlists of all students
+func (db *Database) updateWishlistsOfStudents(students map[course.StudentID]*course.Student) error {
+	rows, err := db.db.Query(context.Background(), "SELECT student_id, phase, course_id, group_id FROM wishlists ORDER BY student_id, rank")
+	if err != nil {
+		return errors.Wrap(err, "cannot query")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var stdID course.StudentID
+		var phase string
+		var wish course.Wish
+		err = rows.Scan(&stdID, &phase, &wish.CourseID, &wish.GroupID)
+		if err != nil {
+			return errors.Wrap(err, "cannot scan")
+		}
+		student, exists := students[stdID]
+		if !exists {
+			return errors.Errorf("student %d with wishlist does not exist", stdID)
+		}
+		if student.Wishlist == nil {
+			student.Wishlist = &course.Wishlist{Phase: phase}
+		}
+		student.Wishlist.Wishes = append(student.Wishlist.Wishes, wish)
+	}
+	return rows.Err()
+}
+
+// updateEnrolledCoursesOfStudents fills the list of enrolled (reserved and registered) courses
+// of all students and their number of units
+func (db *Database) updateEnrolledCoursesOfStudents(students map[course.StudentID]*course.Student) error {
+	rows, err := db.db.Query(context.Background(), "SELECT enrolled_courses.student_id, enrolled_courses.course_id, enrolled_courses.group_id, c.units FROM enrolled_courses JOIN courses c on c.course_id = enrolled_courses.course_id and c.group_id = enrolled_courses.group_id")
 	if err != nil {
-		return nil, 0, errors.Wrap(err, "cannot query")
+		return errors.Wrap(err, "cannot query")
 	}
 	defer rows.Close()
 	// Get them
-	var totalUnits uint8
-	courses := make(map[course.CourseID]course.GroupID)
 	for rows.Next() {
+		var stdID course.StudentID
 		var courseID course.CourseID
 		var groupID course.GroupID
 		var units uint8
-		err = rows.Scan(&courseID, &groupID, &units)
+		err = rows.Scan(&stdID, &courseID, &groupID, &units)
 		if err != nil {
-			return nil, 0, errors.Wrap(err, "cannot scan")
+			return errors.Wrap(err, "cannot scan")
+		}
+		student, exists := students[stdID]
+		if !exists {
+			return errors.Errorf("enrolled student %d does not exist", stdID)
 		}
 		// Apply
-		totalUnits += units
-		courses[courseID] = groupID
+		student.RegisteredUnits += units
+		student.RegisteredCourses[courseID] = groupID
+	}
+	return rows.Err()
+}
+
+// GetEnrolledCourses will get all rows of enrolled_courses ordered by id
+func (db *Database) GetEnrolledCourses(ctx context.Context) ([]EnrolledCourse, error) {
+	rows, err := db.db.Query(ctx, "SELECT course_id, group_id, student_id, reserved FROM enrolled_courses ORDER BY id")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot query enrolled courses")
+	}
+	result, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (EnrolledCourse, error) {
+		var enrolled EnrolledCourse
+		err := row.Scan(&enrolled.CourseID, &enrolled.GroupID, &enrolled.StudentID, &enrolled.Reserved)
+		return enrolled, err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot scan enrolled courses")
+	}
+	return result, nil
+}
+
+// ReplaceEnrolledCourses will delete all rows of the given course groups and insert their students in
+// a single transaction. The registered students are inserted first and then the reserve queue in order,
+// so the reserve queue follows the order of ids.
+func (db *Database) ReplaceEnrolledCourses(ctx context.Context, enrollments []CourseEnrollments) error {
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot start transaction")
+	}
+	defer tx.Rollback(ctx)
+	// Delete everything first because students might move between the groups
+	courseIDs := make([]int32, len(enrollments))
+	groupIDs := make([]int32, len(enrollments))
+	var rows [][]any
+	for i, enrollment := range enrollments {
+		courseIDs[i] = int32(enrollment.CourseID)
+		groupIDs[i] = int32(enrollment.GroupID)
+		for _, studentID := range enrollment.Registered {
+			var deadline *time.Time
+			if ms, exists := enrollment.ConfirmationDeadlines[studentID]; exists {
+				t := time.UnixMilli(ms)
+				deadline = &t
+			}
+			rows = append(rows, []any{int32(enrollment.CourseID), int32(enrollment.GroupID), int64(studentID), false, deadline})
+		}
+		for _, studentID := range enrollment.Reserved {
+			rows = append(rows, []any{int32(enrollment.CourseID), int32(enrollment.GroupID), int64(studentID), true, nil})
+		}
 	}
-	return courses, totalUnits, nil
+	_, err = tx.Exec(ctx, "DELETE FROM enrolled_courses e USING unnest($1::integer[], $2::integer[]) AS d(course_id, group_id) WHERE e.course_id=d.course_id AND e.group_id=d.group_id", courseIDs, groupIDs)
+	if err != nil {
+		return errors.Wrap(err, "cannot delete enrolled courses")
+	}
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"enrolled_courses"},
+		[]string{"course_id", "group_id", "student_id", "reserved", "confirmation_deadline"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return errors.Wrap(err, "cannot insert enrolled courses")
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot commit")
+	}
+	return nil
 }
@@ -0,0 +1,98 @@
+package DatabaseBatcher
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"reflect"
+)
+
+// splitBatch will split a batch of messages into runs. Each run only contains consecutive messages
+// of a same kind which can be applied with a single statement. The order of the runs is the order of
+// messages. A student appears at most once in each run; so the order of actions of a single student is
+// kept intact. Update capacity messages are always in a run of their own.
+func splitBatch(messages []*proto.CourseDatabaseBatchMessage) [][]*proto.CourseDatabaseBatchMessage {
+	var result [][]*proto.CourseDatabaseBatchMessage
+	var currentRun []*proto.CourseDatabaseBatchMessage
+	currentRunStudents := make(map[uint64]struct{})
+	for _, message := range messages {
+		studentID, hasStudent := messageStudentID(message)
+		promotedID := messagePromotedStudentID(message)
+		if len(currentRun) != 0 {
+			_, studentExists := currentRunStudents[studentID]
+			_, promotedExists := currentRunStudents[promotedID]
+			sameKind := reflect.TypeOf(currentRun[0].GetAction()) == reflect.TypeOf(message.GetAction())
+			if !hasStudent || !sameKind || studentExists || (promotedID != 0 && promotedExists) {
+				result = append(result, currentRun)
+				currentRun = nil
+				clear(currentRunStudents)
+			}
+		}
+		currentRun = append(currentRun, message)
+		if hasStudent {
+			currentRunStudents[studentID] = struct{}{}
+			if promotedID != 0 {
+				currentRunStudents[promotedID] = struct{}{}
+			}
+		} else {
+			// Messages without a student must be in a run of their own
+			result = append(result, currentRun)
+			currentRun = nil
+		}
+	}
+	if len(currentRun) != 0 {
+		result = append(result, currentRun)
+	}
+	return result
+}
+
+// messageStudentID returns the student which a message is about.
+// The second return value is false if the message is not about a single student.
+func messageStudentID(message *proto.CourseDatabaseBatchMessage) (uint64, bool) {
+	switch data := message.GetAction().(type) {
+	case *proto.CourseDatabaseBatchMessage_Enroll:
+		return data.Enroll.StudentId, true
+	case *proto.CourseDatabaseBatchMessage_Disenroll:
+		return data.Disenroll.StudentId, true
+	case *proto.CourseDatabaseBatchMessage_ChangeGroup:
+		return data.ChangeGroup.StudentId, true
+	case *proto.CourseDatabaseBatchMessage_AddIntent:
+		return data.AddIntent.StudentId, true
+	case *proto.CourseDatabaseBatchMessage_RemoveIntent:
+		return data.RemoveIntent.StudentId, true
+	case *proto.CourseDatabaseBatchMessage_PutWishlist:
+		return data.PutWishlist.StudentId, true
+	case *proto.CourseDatabaseBatchMessage_AddNotification:
+		return data.AddNotification.StudentId, true
+	case *proto.CourseDatabaseBatchMessage_ConfirmPromotion:
+		return data.ConfirmPromotion.StudentId, true
+	default:
+		return 0, false
+	}
+}
+
+// messagePromotedStudentID returns the student which is promoted from a reserve queue by a message.
+// Zero means nobody. The promoted student is changed by the message too; so it must not be in the
+// same run as the other messages of the promoted student.
+func messagePromotedStudentID(message *proto.CourseDatabaseBatchMessage) uint64 {
+	switch data := message.GetAction().(type) {
+	case *proto.CourseDatabaseBatchMessage_Disenroll:
+		return data.Disenroll.PromotedStudentId
+	case *proto.CourseDatabaseBatchMessage_ChangeGroup:
+		return data.ChangeGroup.PromotedStudentId
+	default:
+		return 0
+	}
+}
+
+// flattenTransactions replaces the transaction messages with their inner messages. The whole batch
+// is applied in a single database transaction; so the inner messages are still applied together.
+func flattenTransactions(messages []*proto.CourseDatabaseBatchMessage) []*proto.CourseDatabaseBatchMessage {
+	result := make([]*proto.CourseDatabaseBatchMessage, 0, len(messages))
+	for _, message := range messages {
+		if transaction := message.GetTransaction(); transaction != nil {
+			result = append(result, flattenTransactions(transaction.Messages)...)
+		} else {
+			result = append(result, message)
+		}
+	}
+	return result
+}
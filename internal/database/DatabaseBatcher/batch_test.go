@@ -0,0 +1,92 @@
+package DatabaseBatcher
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitBatch(t *testing.T) {
+	enroll := func(std uint64) *proto.CourseDatabaseBatchMessage {
+		return &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Enroll{
+			Enroll: &proto.CourseDatabaseBatchEnrollMessage{StudentId: std, CourseId: 1, GroupId: 1},
+		}}
+	}
+	disenroll := func(std uint64) *proto.CourseDatabaseBatchMessage {
+		return &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Disenroll{
+			Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: std, CourseId: 1},
+		}}
+	}
+	updateCapacity := func() *proto.CourseDatabaseBatchMessage {
+		return &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_UpdateCapacity{
+			UpdateCapacity: &proto.CourseDatabaseBatchUpdateCapacity{CourseId: 1, GroupId: 1, NewCapacity: 10},
+		}}
+	}
+	e1, e2, e3 := enroll(1), enroll(2), enroll(3)
+	d1, d2 := disenroll(1), disenroll(2)
+	e1Again := enroll(1)
+	// Student 1 leaves and student 3 is promoted to the seat
+	d1Promote := &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Disenroll{
+		Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: 1, CourseId: 1, PromotedStudentId: 3},
+	}}
+	d3 := disenroll(3)
+	u1, u2 := updateCapacity(), updateCapacity()
+	tests := []struct {
+		Name     string
+		Messages []*proto.CourseDatabaseBatchMessage
+		Expected [][]*proto.CourseDatabaseBatchMessage
+	}{
+		{
+			Name:     "empty",
+			Messages: nil,
+			Expected: nil,
+		},
+		{
+			Name:     "same kind",
+			Messages: []*proto.CourseDatabaseBatchMessage{e1, e2, e3},
+			Expected: [][]*proto.CourseDatabaseBatchMessage{{e1, e2, e3}},
+		},
+		{
+			Name:     "different kinds",
+			Messages: []*proto.CourseDatabaseBatchMessage{e1, e2, d1, d2, e3},
+			Expected: [][]*proto.CourseDatabaseBatchMessage{{e1, e2}, {d1, d2}, {e3}},
+		},
+		{
+			Name:     "repeated student",
+			Messages: []*proto.CourseDatabaseBatchMessage{e1, e2, e1Again, e3},
+			Expected: [][]*proto.CourseDatabaseBatchMessage{{e1, e2}, {e1Again, e3}},
+		},
+		{
+			Name:     "promoted student",
+			Messages: []*proto.CourseDatabaseBatchMessage{d2, d1Promote, d3},
+			Expected: [][]*proto.CourseDatabaseBatchMessage{{d2, d1Promote}, {d3}},
+		},
+		{
+			Name:     "update capacity",
+			Messages: []*proto.CourseDatabaseBatchMessage{e1, u1, u2, e2},
+			Expected: [][]*proto.CourseDatabaseBatchMessage{{e1}, {u1}, {u2}, {e2}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, splitBatch(test.Messages))
+		})
+	}
+}
+
+func TestFlattenTransactions(t *testing.T) {
+	enroll := &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Enroll{
+		Enroll: &proto.CourseDatabaseBatchEnrollMessage{StudentId: 1, CourseId: 1, GroupId: 1},
+	}}
+	disenroll := &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Disenroll{
+		Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: 1, CourseId: 2},
+	}}
+	other := &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Disenroll{
+		Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: 2, CourseId: 2},
+	}}
+	transaction := &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Transaction{
+		Transaction: &proto.CourseDatabaseBatchTransaction{Messages: []*proto.CourseDatabaseBatchMessage{disenroll, enroll}},
+	}}
+	assert.Equal(t, []*proto.CourseDatabaseBatchMessage{other, disenroll, enroll, other},
+		flattenTransactions([]*proto.CourseDatabaseBatchMessage{other, transaction, other}))
+}
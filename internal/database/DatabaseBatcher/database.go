@@ -2,11 +2,24 @@ package DatabaseBatcher
 
 import (
 	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
 	"context"
 	"github.com/go-faster/errors"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
 )
 
+// Interface is the storage which the batcher applies the messages in
+type Interface interface {
+	// ApplyBatch must apply a batch of messages atomically and in order. Messages which their
+	// operation ID is already applied must be skipped.
+	ApplyBatch(ctx context.Context, messages []*proto.CourseDatabaseBatchMessage) error
+	// Close must close the connection to the storage
+	Close()
+}
+
+// Database is the PostgreSQL implementation of Interface
 type Database struct {
 	db *pgxpool.Pool
 }
@@ -17,52 +30,481 @@ func NewDatabase(db *pgxpool.Pool) Database {
 	return Database{db}
 }
 
-// EnrollCourse will enroll a student in a course
-func (db Database) EnrollCourse(stdID course.StudentID, courseID course.CourseID, groupID course.GroupID, reserved bool) error {
-	_, err := db.db.Exec(context.Background(), "INSERT INTO enrolled_courses (course_id, group_id, student_id, reserved) VALUES ($1, $2, $3, $4)", courseID, groupID, stdID, reserved)
-	return err
+// ApplyBatch will apply a batch of messages in a single transaction.
+// Consecutive messages of the same kind are merged into a single statement. The order of messages
+// is preserved, so an enroll followed by a disenroll of the same student is applied correctly.
+// Either all messages are applied, or none of them. Messages which their operation ID is already
+// applied are skipped.
+func (db Database) ApplyBatch(ctx context.Context, messages []*proto.CourseDatabaseBatchMessage) error {
+	// Start a transaction
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot start transaction")
+	}
+	defer tx.Rollback(ctx)
+	// Remove the replayed messages
+	messages, err = recordOperations(ctx, tx, messages)
+	if err != nil {
+		return err
+	}
+	// Apply each run
+	for _, run := range splitBatch(flattenTransactions(messages)) {
+		switch run[0].GetAction().(type) {
+		case *proto.CourseDatabaseBatchMessage_Enroll:
+			err = enrollCourses(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_Disenroll:
+			err = disenrollCourses(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_ChangeGroup:
+			err = changeCourseGroups(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_AddIntent:
+			err = addIntents(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_RemoveIntent:
+			err = removeIntents(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_PutWishlist:
+			err = putWishlists(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_AddNotification:
+			err = addNotifications(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_ConfirmPromotion:
+			err = confirmPromotions(ctx, tx, run)
+		case *proto.CourseDatabaseBatchMessage_UpdateCapacity:
+			err = updateCapacity(ctx, tx, run[0].GetUpdateCapacity())
+		case *proto.CourseDatabaseBatchMessage_PutStudent:
+			err = putStudent(ctx, tx, run[0].GetPutStudent())
+		case *proto.CourseDatabaseBatchMessage_PutCourse:
+			err = putCourse(ctx, tx, run[0].GetPutCourse())
+		case *proto.CourseDatabaseBatchMessage_PutSchedule:
+			err = putSchedule(ctx, tx, run[0].GetPutSchedule())
+		default:
+			err = errors.Errorf("invalid action: %v", run[0])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Log the changes
+	if err = addAuditEntries(ctx, tx, messages); err != nil {
+		return err
+	}
+	// Done
+	err = tx.Commit(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot commit")
+	}
+	return nil
 }
 
-// DisenrollCourse will disenroll a student in a course
-func (db Database) DisenrollCourse(stdID course.StudentID, courseID course.CourseID) error {
-	_, err := db.db.Exec(context.Background(), "DELETE FROM enrolled_courses WHERE course_id=$1 AND student_id=$2", courseID, stdID)
-	return err
+// recordOperations will record the operation IDs of messages as applied and returns the messages
+// which have not been applied before. Messages without an operation ID are always returned.
+func recordOperations(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) ([]*proto.CourseDatabaseBatchMessage, error) {
+	operationIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if message.OperationId != "" {
+			operationIDs = append(operationIDs, message.OperationId)
+		}
+	}
+	if len(operationIDs) == 0 {
+		return messages, nil
+	}
+	// Insert the IDs. Only the new ones are returned.
+	rows, err := tx.Query(ctx, "INSERT INTO applied_operations (id) SELECT unnest($1::text[]) ON CONFLICT DO NOTHING RETURNING id", operationIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot record operations")
+	}
+	newOperations, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot scan operations")
+	}
+	newOperationsSet := make(map[string]struct{}, len(newOperations))
+	for _, id := range newOperations {
+		newOperationsSet[id] = struct{}{}
+	}
+	// Filter the messages
+	result := make([]*proto.CourseDatabaseBatchMessage, 0, len(messages))
+	for _, message := range messages {
+		if message.OperationId == "" {
+			result = append(result, message)
+			continue
+		}
+		if _, isNew := newOperationsSet[message.OperationId]; isNew {
+			result = append(result, message)
+			// Duplicates in the same batch must be applied only once
+			delete(newOperationsSet, message.OperationId)
+		}
+	}
+	return result, nil
 }
 
-// ChangeCourseGroup will change the group of a user in an enrolled course
-func (db Database) ChangeCourseGroup(stdID course.StudentID, courseID course.CourseID, newGroupID course.GroupID, reserved bool) error {
-	_, err := db.db.Exec(context.Background(), "UPDATE enrolled_courses SET group_id=$1, reserved=$2 WHERE course_id=$3 AND student_id=$4", newGroupID, reserved, courseID, stdID)
-	return err
+// enrollCourses will enroll students in courses. All messages must be enroll messages.
+// The rows are copied in order, so the ids of the rows follow the order of messages.
+func enrollCourses(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	rows := make([][]any, len(messages))
+	for i, message := range messages {
+		enroll := message.GetEnroll()
+		rows[i] = []any{enroll.CourseId, int32(enroll.GroupId), int64(enroll.StudentId), enroll.Reserved}
+	}
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"enrolled_courses"},
+		[]string{"course_id", "group_id", "student_id", "reserved"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return errors.Wrap(err, "cannot enroll courses")
+	}
+	return nil
 }
 
-// UpdateCapacity will update the capacity of a course
-func (db Database) UpdateCapacity(courseID course.CourseID, groupID course.GroupID, newCapacity int32, movedStudents []uint64) error {
-	// Start a transaction
-	tx, err := db.db.Begin(context.Background())
+// disenrollCourses will disenroll students from courses. All messages must be disenroll messages.
+func disenrollCourses(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	courseIDs := make([]int32, len(messages))
+	studentIDs := make([]int64, len(messages))
+	var promotions promotions
+	for i, message := range messages {
+		disenroll := message.GetDisenroll()
+		courseIDs[i] = disenroll.CourseId
+		studentIDs[i] = int64(disenroll.StudentId)
+		promotions.add(disenroll.CourseId, disenroll.PromotedStudentId, disenroll.ConfirmationDeadline)
+	}
+	_, err := tx.Exec(ctx, "DELETE FROM enrolled_courses e USING unnest($1::integer[], $2::bigint[]) AS d(course_id, student_id) WHERE e.course_id=d.course_id AND e.student_id=d.student_id", courseIDs, studentIDs)
 	if err != nil {
-		return errors.Wrap(err, "cannot start transaction")
+		return errors.Wrap(err, "cannot disenroll courses")
+	}
+	if err = deleteIntents(ctx, tx, courseIDs, studentIDs); err != nil {
+		return err
+	}
+	return promotions.apply(ctx, tx)
+}
+
+// promotions are the students which are moved from the reserve queues to the freed seats
+type promotions struct {
+	courseIDs  []int32
+	studentIDs []int64
+	// Nil means that no confirmation is needed
+	deadlines []*time.Time
+}
+
+// add adds a promotion. Zero student is ignored and zero deadline means no confirmation.
+func (p *promotions) add(courseID int32, studentID uint64, deadline int64) {
+	if studentID == 0 {
+		return
+	}
+	p.courseIDs = append(p.courseIDs, courseID)
+	p.studentIDs = append(p.studentIDs, int64(studentID))
+	if deadline != 0 {
+		t := time.UnixMilli(deadline)
+		p.deadlines = append(p.deadlines, &t)
+	} else {
+		p.deadlines = append(p.deadlines, nil)
+	}
+}
+
+// apply marks the promoted students as registered alongside their confirmation deadlines
+func (p *promotions) apply(ctx context.Context, tx pgx.Tx) error {
+	if len(p.studentIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, "UPDATE enrolled_courses e SET reserved=FALSE, confirmation_deadline=p.deadline FROM unnest($1::integer[], $2::bigint[], $3::timestamptz[]) AS p(course_id, student_id, deadline) WHERE e.course_id=p.course_id AND e.student_id=p.student_id",
+		p.courseIDs, p.studentIDs, p.deadlines)
+	if err != nil {
+		return errors.Wrap(err, "cannot promote students")
+	}
+	return nil
+}
+
+// confirmPromotions will confirm the seats of promoted students. All messages must be confirm
+// promotion messages.
+func confirmPromotions(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	courseIDs := make([]int32, len(messages))
+	studentIDs := make([]int64, len(messages))
+	for i, message := range messages {
+		confirm := message.GetConfirmPromotion()
+		courseIDs[i] = confirm.CourseId
+		studentIDs[i] = int64(confirm.StudentId)
+	}
+	_, err := tx.Exec(ctx, "UPDATE enrolled_courses e SET confirmation_deadline=NULL FROM unnest($1::integer[], $2::bigint[]) AS c(course_id, student_id) WHERE e.course_id=c.course_id AND e.student_id=c.student_id", courseIDs, studentIDs)
+	if err != nil {
+		return errors.Wrap(err, "cannot confirm promotions")
+	}
+	return nil
+}
+
+// changeCourseGroups will change the group of students in courses. All messages must be change group messages.
+func changeCourseGroups(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	courseIDs := make([]int32, len(messages))
+	studentIDs := make([]int64, len(messages))
+	groupIDs := make([]int32, len(messages))
+	reserved := make([]bool, len(messages))
+	var promotions promotions
+	for i, message := range messages {
+		changeGroup := message.GetChangeGroup()
+		courseIDs[i] = changeGroup.CourseId
+		studentIDs[i] = int64(changeGroup.StudentId)
+		groupIDs[i] = int32(changeGroup.GroupId)
+		reserved[i] = changeGroup.Reserved
+		promotions.add(changeGroup.CourseId, changeGroup.PromotedStudentId, changeGroup.ConfirmationDeadline)
+	}
+	_, err := tx.Exec(ctx, "UPDATE enrolled_courses e SET group_id=c.group_id, reserved=c.reserved, confirmation_deadline=NULL FROM unnest($1::integer[], $2::bigint[], $3::integer[], $4::boolean[]) AS c(course_id, student_id, group_id, reserved) WHERE e.course_id=c.course_id AND e.student_id=c.student_id", courseIDs, studentIDs, groupIDs, reserved)
+	if err != nil {
+		return errors.Wrap(err, "cannot change course groups")
+	}
+	if err = deleteIntents(ctx, tx, courseIDs, studentIDs); err != nil {
+		return err
+	}
+	return promotions.apply(ctx, tx)
+}
+
+// addIntents will add or replace the group change intents of students. All messages must be add
+// intent messages. The rows are copied in order, so the ids of the rows follow the order of messages.
+func addIntents(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	courseIDs := make([]int32, len(messages))
+	studentIDs := make([]int64, len(messages))
+	rows := make([][]any, len(messages))
+	for i, message := range messages {
+		intent := message.GetAddIntent()
+		courseIDs[i] = intent.CourseId
+		studentIDs[i] = int64(intent.StudentId)
+		rows[i] = []any{intent.CourseId, int32(intent.GroupId), int64(intent.StudentId)}
+	}
+	// Remove the old intents at first
+	if err := deleteIntents(ctx, tx, courseIDs, studentIDs); err != nil {
+		return err
+	}
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"group_change_intents"},
+		[]string{"course_id", "group_id", "student_id"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return errors.Wrap(err, "cannot add intents")
 	}
-	defer tx.Rollback(context.Background())
+	return nil
+}
+
+// removeIntents will remove the group change intents of students. All messages must be remove
+// intent messages.
+func removeIntents(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	courseIDs := make([]int32, len(messages))
+	studentIDs := make([]int64, len(messages))
+	for i, message := range messages {
+		intent := message.GetRemoveIntent()
+		courseIDs[i] = intent.CourseId
+		studentIDs[i] = int64(intent.StudentId)
+	}
+	return deleteIntents(ctx, tx, courseIDs, studentIDs)
+}
+
+// deleteIntents deletes the group change intents of students in courses
+func deleteIntents(ctx context.Context, tx pgx.Tx, courseIDs []int32, studentIDs []int64) error {
+	_, err := tx.Exec(ctx, "DELETE FROM group_change_intents i USING unnest($1::integer[], $2::bigint[]) AS d(course_id, student_id) WHERE i.course_id=d.course_id AND i.student_id=d.student_id", courseIDs, studentIDs)
+	if err != nil {
+		return errors.Wrap(err, "cannot delete intents")
+	}
+	return nil
+}
+
+// putWishlists will replace the wishlists of students. All messages must be put wishlist messages.
+func putWishlists(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	studentIDs := make([]int64, len(messages))
+	var rows [][]any
+	for i, message := range messages {
+		data := message.GetPutWishlist()
+		studentIDs[i] = int64(data.StudentId)
+		for rank, entry := range data.GetWishlist().GetEntries() {
+			rows = append(rows, []any{int64(data.StudentId), data.Wishlist.Phase, int16(rank), entry.CourseId, int32(entry.GroupId)})
+		}
+	}
+	// Remove the old wishlists at first
+	_, err := tx.Exec(ctx, "DELETE FROM wishlists WHERE student_id = ANY($1)", studentIDs)
+	if err != nil {
+		return errors.Wrap(err, "cannot delete wishlists")
+	}
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"wishlists"},
+		[]string{"student_id", "phase", "rank", "course_id", "group_id"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return errors.Wrap(err, "cannot insert wishlists")
+	}
+	return nil
+}
+
+// addNotifications will add notifications to the inboxes of students. All messages must be add
+// notification messages.
+func addNotifications(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	rows := make([][]any, len(messages))
+	for i, message := range messages {
+		data := message.GetAddNotification()
+		var deadline *time.Time
+		if data.ConfirmationDeadline != 0 {
+			t := time.UnixMilli(data.ConfirmationDeadline)
+			deadline = &t
+		}
+		rows[i] = []any{int64(data.StudentId), int16(data.Kind), data.CourseId, int32(data.GroupId),
+			data.Capacity, int32(data.ReservePosition), time.UnixMilli(data.Time), deadline}
+	}
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"notifications"},
+		[]string{"student_id", "kind", "course_id", "group_id", "capacity", "reserve_position", "created_at", "confirmation_deadline"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return errors.Wrap(err, "cannot insert notifications")
+	}
+	return nil
+}
+
+// addAuditEntries will append the enrollment changes of the messages to the audit log. The
+// messages which are not audited, like the ones in the journals of older versions, are logged as
+// the changes of the system at the current time.
+func addAuditEntries(ctx context.Context, tx pgx.Tx, messages []*proto.CourseDatabaseBatchMessage) error {
+	var rows [][]any
+	now := time.Now()
+	for _, message := range messages {
+		for _, entry := range course.NewAuditEntries(message) {
+			createdAt := now
+			if entry.Time != 0 {
+				createdAt = time.UnixMilli(entry.Time)
+			}
+			rows = append(rows, []any{int16(entry.Action), int32(entry.StudentID), int32(entry.CourseID), int32(entry.GroupID),
+				int32(entry.Capacity), int16(entry.ActorKind), int64(entry.ActorID), entry.Origin, entry.Reason, createdAt})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"enrollment_audit"},
+		[]string{"action", "student_id", "course_id", "group_id", "capacity", "actor_kind", "actor_id", "origin", "reason", "created_at"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return errors.Wrap(err, "cannot insert audit entries")
+	}
+	return nil
+}
+
+// updateCapacity will update the capacity of a course
+func updateCapacity(ctx context.Context, tx pgx.Tx, data *proto.CourseDatabaseBatchUpdateCapacity) error {
 	// Put people from reserve into main class capacity if needed
-	if len(movedStudents) != 0 {
-		_, err = tx.Exec(context.Background(), "UPDATE enrolled_courses SET reserved=FALSE WHERE id = ANY($1)", movedStudents)
+	if len(data.MovedStudents) != 0 {
+		movedStudents := make([]int64, len(data.MovedStudents))
+		for i, student := range data.MovedStudents {
+			movedStudents[i] = int64(student)
+		}
+		var deadline *time.Time
+		if data.ConfirmationDeadline != 0 {
+			t := time.UnixMilli(data.ConfirmationDeadline)
+			deadline = &t
+		}
+		_, err := tx.Exec(ctx, "UPDATE enrolled_courses SET reserved=FALSE, confirmation_deadline=$4 WHERE course_id=$1 AND group_id=$2 AND student_id = ANY($3)",
+			data.CourseId, int32(data.GroupId), movedStudents, deadline)
 		if err != nil {
 			return errors.Wrap(err, "cannot update reserved status")
 		}
 	}
 	// Update the course capacity
-	_, err = tx.Exec(context.Background(), "UPDATE courses SET capacity=$1 WHERE course_id=$2 AND group_id=$3", newCapacity, courseID, groupID)
+	_, err := tx.Exec(ctx, "UPDATE courses SET capacity=$1 WHERE course_id=$2 AND group_id=$3", data.NewCapacity, data.CourseId, int32(data.GroupId))
 	if err != nil {
 		return errors.Wrap(err, "cannot update course capacity")
 	}
-	// Done
-	err = tx.Commit(context.Background())
+	return nil
+}
+
+// putStudent will insert a student or update it if it exists. The password is kept if the hash is empty.
+func putStudent(ctx context.Context, tx pgx.Tx, data *proto.CourseDatabaseBatchPutStudent) error {
+	_, err := tx.Exec(ctx, `INSERT INTO students (id, password, enrollment_start_time, max_units, remaining_actions, department_id, entry_year, gender, gpa_band)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (id) DO UPDATE SET password=COALESCE(NULLIF(excluded.password, ''), students.password), enrollment_start_time=excluded.enrollment_start_time,
+max_units=excluded.max_units, remaining_actions=excluded.remaining_actions, department_id=excluded.department_id, entry_year=excluded.entry_year, gender=excluded.gender,
+gpa_band=excluded.gpa_band`,
+		int64(data.StudentId), data.PasswordHash, time.UnixMilli(data.EnrollmentStartTime), int16(data.MaxUnits), int16(data.RemainingActions),
+		int16(data.DepartmentId), int16(data.EntryYear), sexName(data.Sex), int16(data.GpaBand))
 	if err != nil {
-		return errors.Wrap(err, "cannot commit")
+		return errors.Wrap(err, "cannot put student")
 	}
 	return nil
 }
 
+// putCourse will insert a course group or update it if it exists
+func putCourse(ctx context.Context, tx pgx.Tx, data *proto.CourseDatabaseBatchPutCourse) error {
+	var examTime *time.Time
+	if data.ExamTime != 0 {
+		t := time.Unix(data.ExamTime, 0)
+		examTime = &t
+	}
+	var sexLock *string
+	if data.SexLock != 0 {
+		sex := sexName(data.SexLock)
+		sexLock = &sex
+	}
+	eligibility := data.GetEligibility()
+	departments := make([]int16, len(eligibility.GetDepartments()))
+	for i, department := range eligibility.GetDepartments() {
+		departments[i] = int16(department)
+	}
+	allowedStudents, deniedStudents := make([]int64, len(eligibility.GetAllowedStudents())), make([]int64, len(eligibility.GetDeniedStudents()))
+	for i, student := range eligibility.GetAllowedStudents() {
+		allowedStudents[i] = int64(student)
+	}
+	for i, student := range eligibility.GetDeniedStudents() {
+		deniedStudents[i] = int64(student)
+	}
+	_, err := tx.Exec(ctx, `INSERT INTO courses (course_id, group_id, for_department, name, lecturer, units, capacity, reserve_capacity, exam_time, class_time, sex_lock, notes,
+departments, min_entry_year, max_entry_year, allowed_students, denied_students, confirmation_window)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+ON CONFLICT (course_id, group_id) DO UPDATE SET for_department=excluded.for_department, name=excluded.name, lecturer=excluded.lecturer, units=excluded.units,
+capacity=excluded.capacity, reserve_capacity=excluded.reserve_capacity, exam_time=excluded.exam_time, class_time=excluded.class_time, sex_lock=excluded.sex_lock, notes=excluded.notes,
+departments=excluded.departments, min_entry_year=excluded.min_entry_year, max_entry_year=excluded.max_entry_year, allowed_students=excluded.allowed_students, denied_students=excluded.denied_students,
+confirmation_window=excluded.confirmation_window`,
+		data.CourseId, int32(data.GroupId), int16(data.DepartmentId), data.Name, data.Lecturer, int16(data.Units), data.Capacity, data.ReserveCapacity,
+		examTime, int32(data.ClassTime), sexLock, data.Notes,
+		departments, int16(eligibility.GetMinEntryYear()), int16(eligibility.GetMaxEntryYear()), allowedStudents, deniedStudents, int32(data.ConfirmationWindow))
+	if err != nil {
+		return errors.Wrap(err, "cannot put course")
+	}
+	return nil
+}
+
+// putSchedule will replace the enrollment schedule
+func putSchedule(ctx context.Context, tx pgx.Tx, data *proto.EnrollmentSchedule) error {
+	// The windows are deleted by cascade
+	_, err := tx.Exec(ctx, "DELETE FROM enrollment_phases")
+	if err != nil {
+		return errors.Wrap(err, "cannot delete phases")
+	}
+	phases := make([][]any, len(data.Phases))
+	var windows [][]any
+	for i, phase := range data.Phases {
+		priorities := make([]int16, len(phase.Priorities))
+		for j, priority := range phase.Priorities {
+			priorities[j] = int16(priority)
+		}
+		phases[i] = []any{phase.Name, int16(i), phase.AllowEnroll, phase.AllowDisenroll, phase.AllowChangeGroup, phase.StudentDuration,
+			phase.Lottery, priorities, phase.Seed}
+		for _, window := range phase.Windows {
+			windows = append(windows, []any{phase.Name, int16(window.DepartmentId), int16(window.EntryYear), time.UnixMilli(window.Start), time.UnixMilli(window.End)})
+		}
+	}
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"enrollment_phases"},
+		[]string{"name", "position", "allow_enroll", "allow_disenroll", "allow_change_group", "student_duration", "lottery", "priorities", "seed"},
+		pgx.CopyFromRows(phases))
+	if err != nil {
+		return errors.Wrap(err, "cannot insert phases")
+	}
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"enrollment_windows"},
+		[]string{"phase", "department_id", "entry_year", "start_time", "end_time"},
+		pgx.CopyFromRows(windows))
+	if err != nil {
+		return errors.Wrap(err, "cannot insert windows")
+	}
+	return nil
+}
+
+// sexName converts course.Sex or course.SexLock to the sex enum in database
+func sexName(sex uint32) string {
+	if course.Sex(sex) == course.SexFemale {
+		return "female"
+	}
+	return "male"
+}
+
 // Close will close the connection
 func (db Database) Close() {
 	db.db.Close()
@@ -0,0 +1,851 @@
+package database
+
+import (
+	authDatabase "CourseEnrollment/internal/database/AuthCore"
+	coreDatabase "CourseEnrollment/internal/database/CourseEnrollmentServer"
+	"CourseEnrollment/internal/password"
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"fmt"
+	"github.com/go-faster/errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStaff is a row of staff table in MemoryDatabase
+type MemoryStaff struct {
+	ID uint64
+	// The hash of password
+	Password   string
+	Department course.DepartmentID
+	Role       shared.StaffRole
+}
+
+// MemoryStudent is a row of students table in MemoryDatabase
+type MemoryStudent struct {
+	ID course.StudentID
+	// The hash of password
+	Password            string
+	EnrollmentStartTime time.Time
+	MaxUnits            uint8
+	RemainingActions    uint8
+	Department          course.DepartmentID
+	EntryYear           int16
+	Sex                 course.Sex
+	GPABand             uint8
+	// The rows of passed_courses of this student
+	PassedCourses []course.CourseID
+}
+
+// MemoryExternalIdentity is the key of a row of external_identities table in MemoryDatabase
+type MemoryExternalIdentity struct {
+	Issuer  string
+	Subject string
+	IsStaff bool
+}
+
+// memoryPasswordReset is a row of password_resets table in MemoryDatabase
+type memoryPasswordReset struct {
+	authDatabase.PasswordReset
+	expiresAt time.Time
+}
+
+// MemoryCourse is a row of courses table in MemoryDatabase
+type MemoryCourse struct {
+	ID              course.CourseID
+	GroupID         course.GroupID
+	Department      course.DepartmentID
+	Name            string
+	Lecturer        string
+	Units           uint8
+	Capacity        int
+	ReserveCapacity int
+	// Zero means no exam
+	ExamTime time.Time
+	// The raw value of course.ClassTime
+	ClassTime uint32
+	SexLock   course.SexLock
+	Notes     string
+	// The eligibility columns. Nil means that everyone can take the group.
+	Eligibility *proto.CourseEligibility
+	// Zero means that promoted students do not have to confirm their seats
+	ConfirmationWindow time.Duration
+}
+
+// MemoryEnrolledCourse is a row of enrolled_courses table in MemoryDatabase
+type MemoryEnrolledCourse struct {
+	ID        int
+	CourseID  course.CourseID
+	GroupID   course.GroupID
+	StudentID course.StudentID
+	Reserved  bool
+	// Zero means that the seat does not need confirmation
+	ConfirmationDeadline time.Time
+}
+
+// MemoryIntent is a row of group_change_intents table in MemoryDatabase
+type MemoryIntent struct {
+	StudentID course.StudentID
+	CourseID  course.CourseID
+	// The destination group
+	GroupID course.GroupID
+}
+
+// memoryCourseKey is the primary key of courses
+type memoryCourseKey struct {
+	course course.CourseID
+	group  course.GroupID
+}
+
+// MemoryDatabase is an in-memory replacement of the PostgreSQL database. It implements the storage
+// interface of every service, so they can share it in a single process. It's meant for tests.
+type MemoryDatabase struct {
+	departments course.Departments
+	staff       map[uint64]MemoryStaff
+	students    map[course.StudentID]MemoryStudent
+	// The rows of external_identities
+	externalIdentities map[MemoryExternalIdentity]uint64
+	// The rows of password_resets by their token hash
+	passwordResets map[string]memoryPasswordReset
+	// The policy which the passwords are hashed with
+	passwordPolicy password.Policy
+	courses        map[memoryCourseKey]MemoryCourse
+	// Ordered by ID
+	enrolledCourses []MemoryEnrolledCourse
+	lastEnrolledID  int
+	// Ordered by ID
+	intents []MemoryIntent
+	// The rows of wishlists of each student
+	wishlists map[course.StudentID]*proto.Wishlist
+	// Ordered by ID
+	notifications      []authDatabase.Notification
+	lastNotificationID uint64
+	// Ordered by ID
+	auditLog    []authDatabase.AuditEntry
+	lastAuditID uint64
+	// Set of applied operation IDs
+	appliedOperations map[string]struct{}
+	// The rows of course_requisites
+	requisites map[course.CourseID]course.Requisites
+	// The rows of enrollment_phases and enrollment_windows. Nil if there are no phases.
+	schedule *proto.EnrollmentSchedule
+	mu       sync.RWMutex
+}
+
+// NewMemoryDatabase creates an empty in-memory database
+func NewMemoryDatabase() *MemoryDatabase {
+	return &MemoryDatabase{
+		departments:        make(course.Departments),
+		staff:              make(map[uint64]MemoryStaff),
+		students:           make(map[course.StudentID]MemoryStudent),
+		externalIdentities: make(map[MemoryExternalIdentity]uint64),
+		passwordResets:     make(map[string]memoryPasswordReset),
+		passwordPolicy:     password.DefaultPolicy,
+		courses:            make(map[memoryCourseKey]MemoryCourse),
+		requisites:         make(map[course.CourseID]course.Requisites),
+		wishlists:          make(map[course.StudentID]*proto.Wishlist),
+		appliedOperations:  make(map[string]struct{}),
+	}
+}
+
+// AddDepartment inserts or replaces a department
+func (db *MemoryDatabase) AddDepartment(id course.DepartmentID, name string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.departments[id] = name
+}
+
+// AddStaff inserts or replaces a staff
+func (db *MemoryDatabase) AddStaff(staff MemoryStaff) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.staff[staff.ID] = staff
+}
+
+// DeleteStaff removes a staff
+func (db *MemoryDatabase) DeleteStaff(id uint64) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	delete(db.staff, id)
+}
+
+// AddStudent inserts or replaces a student
+func (db *MemoryDatabase) AddStudent(student MemoryStudent) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.students[student.ID] = student
+}
+
+// AddExternalIdentity links an identity of an identity provider to a student or a staff
+func (db *MemoryDatabase) AddExternalIdentity(identity MemoryExternalIdentity, user uint64) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.externalIdentities[identity] = user
+}
+
+// SetPasswordPolicy sets the policy which the passwords are hashed with. It's DefaultPolicy by
+// default.
+func (db *MemoryDatabase) SetPasswordPolicy(policy password.Policy) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.passwordPolicy = policy
+}
+
+// AddCourse inserts or replaces a course
+func (db *MemoryDatabase) AddCourse(c MemoryCourse) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.courses[memoryCourseKey{c.ID, c.GroupID}] = c
+}
+
+// SetRequisites inserts or replaces the requisites of a course
+func (db *MemoryDatabase) SetRequisites(courseID course.CourseID, requisites course.Requisites) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.requisites[courseID] = requisites
+}
+
+// AddEnrolledCourse inserts a row in enrolled_courses. The ID of row is assigned automatically.
+func (db *MemoryDatabase) AddEnrolledCourse(enrolled MemoryEnrolledCourse) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	enrolledCourses, lastID, err := enroll(db.courses, db.students, db.enrolledCourses, db.lastEnrolledID, enrolled.CourseID, enrolled.GroupID, enrolled.StudentID, enrolled.Reserved)
+	if err != nil {
+		return err
+	}
+	db.enrolledCourses, db.lastEnrolledID = enrolledCourses, lastID
+	return nil
+}
+
+// Course returns a course by its ID and group ID
+func (db *MemoryDatabase) Course(courseID course.CourseID, groupID course.GroupID) (MemoryCourse, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	c, exists := db.courses[memoryCourseKey{courseID, groupID}]
+	return c, exists
+}
+
+// Student gets a row of students
+func (db *MemoryDatabase) Student(id course.StudentID) (MemoryStudent, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	student, exists := db.students[id]
+	return student, exists
+}
+
+// Schedule returns the enrollment schedule. Nil is returned if there are no phases.
+func (db *MemoryDatabase) Schedule() *proto.EnrollmentSchedule {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.schedule
+}
+
+// EnrolledCourses returns a copy of enrolled_courses table ordered by ID
+func (db *MemoryDatabase) EnrolledCourses() []MemoryEnrolledCourse {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return slices.Clone(db.enrolledCourses)
+}
+
+// Intents returns a copy of group_change_intents table ordered by ID
+func (db *MemoryDatabase) Intents() []MemoryIntent {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return slices.Clone(db.intents)
+}
+
+// Wishlists returns the wishlists of the students
+func (db *MemoryDatabase) Wishlists() map[course.StudentID]*proto.Wishlist {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return maps.Clone(db.wishlists)
+}
+
+// Notifications returns the rows of notifications
+func (db *MemoryDatabase) Notifications() []authDatabase.Notification {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return slices.Clone(db.notifications)
+}
+
+// GetNotifications gets the notifications of a student after a notification ID
+func (db *MemoryDatabase) GetNotifications(_ context.Context, studentID, after uint64, limit int) ([]authDatabase.Notification, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var result []authDatabase.Notification
+	for _, notification := range db.notifications {
+		if len(result) == limit {
+			break
+		}
+		if notification.StudentID == course.StudentID(studentID) && notification.ID > after {
+			result = append(result, notification)
+		}
+	}
+	return result, nil
+}
+
+// AuditLog returns the rows of enrollment_audit
+func (db *MemoryDatabase) AuditLog() []authDatabase.AuditEntry {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return slices.Clone(db.auditLog)
+}
+
+// GetAuditLog gets the entries of the audit log of a student, a course or both after an entry ID
+func (db *MemoryDatabase) GetAuditLog(_ context.Context, studentID uint64, courseID int32, after uint64, limit int) ([]authDatabase.AuditEntry, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var result []authDatabase.AuditEntry
+	for _, entry := range db.auditLog {
+		if len(result) == limit {
+			break
+		}
+		if entry.ID > after && (studentID == 0 || entry.StudentID == course.StudentID(studentID)) &&
+			(courseID == 0 || entry.CourseID == course.CourseID(courseID)) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// GetCourseDepartment gets the department of a course
+func (db *MemoryDatabase) GetCourseDepartment(_ context.Context, courseID int32) (bool, course.DepartmentID, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	for key, c := range db.courses {
+		if key.course == course.CourseID(courseID) {
+			return true, c.Department, nil
+		}
+	}
+	return false, 0, nil
+}
+
+// AuthUser will authorize the user
+func (db *MemoryDatabase) AuthUser(_ context.Context, id uint64, pass string, isStaff bool) (bool, authDatabase.User, error) {
+	db.mu.RLock()
+	hashedPassword, user, exists := db.user(id, isStaff)
+	policy := db.passwordPolicy
+	db.mu.RUnlock()
+	if !exists {
+		return false, authDatabase.User{}, nil
+	}
+	// Check password
+	ok, err := password.Verify(hashedPassword, pass)
+	if err != nil || !ok {
+		return false, authDatabase.User{}, err
+	}
+	// Upgrade the hash if the password is not changed meanwhile
+	if policy.NeedsRehash(hashedPassword) {
+		newHash, err := policy.Hash(pass)
+		if err != nil {
+			return false, authDatabase.User{}, err
+		}
+		db.mu.Lock()
+		if current, _, _ := db.user(id, isStaff); current == hashedPassword {
+			db.setPassword(id, isStaff, newHash)
+		}
+		db.mu.Unlock()
+	}
+	return true, user, nil
+}
+
+// GetUser will get the department and the role of a user
+func (db *MemoryDatabase) GetUser(_ context.Context, id uint64, isStaff bool) (bool, authDatabase.User, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	_, user, exists := db.user(id, isStaff)
+	return exists, user, nil
+}
+
+// SetPassword will hash the password of a user with the policy and store it
+func (db *MemoryDatabase) SetPassword(_ context.Context, id uint64, isStaff bool, pass string) (bool, error) {
+	db.mu.RLock()
+	policy := db.passwordPolicy
+	db.mu.RUnlock()
+	hash, err := policy.Hash(pass)
+	if err != nil {
+		return false, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.setPassword(id, isStaff, hash), nil
+}
+
+// AddPasswordReset will store a reset token
+func (db *MemoryDatabase) AddPasswordReset(_ context.Context, tokenHash string, reset authDatabase.PasswordReset, expiresAt time.Time) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.passwordResets[tokenHash] = memoryPasswordReset{reset, expiresAt}
+	return nil
+}
+
+// ResetPassword will use a reset token to change the password of its user
+func (db *MemoryDatabase) ResetPassword(_ context.Context, tokenHash, pass string) (bool, authDatabase.PasswordReset, error) {
+	db.mu.RLock()
+	policy := db.passwordPolicy
+	db.mu.RUnlock()
+	hash, err := policy.Hash(pass)
+	if err != nil {
+		return false, authDatabase.PasswordReset{}, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	reset, exists := db.passwordResets[tokenHash]
+	if !exists || !time.Now().Before(reset.expiresAt) {
+		return false, authDatabase.PasswordReset{}, nil
+	}
+	db.setPassword(reset.User, reset.IsStaff, hash)
+	for key, other := range db.passwordResets {
+		if other.PasswordReset == reset.PasswordReset || !time.Now().Before(other.expiresAt) {
+			delete(db.passwordResets, key)
+		}
+	}
+	return true, reset.PasswordReset, nil
+}
+
+// user returns the password hash and the data of a user. db.mu must be held.
+func (db *MemoryDatabase) user(id uint64, isStaff bool) (string, authDatabase.User, bool) {
+	if isStaff {
+		staff, exists := db.staff[id]
+		return staff.Password, authDatabase.User{Department: staff.Department, Role: staff.Role}, exists
+	}
+	student, exists := db.students[course.StudentID(id)]
+	return student.Password, authDatabase.User{Department: student.Department}, exists
+}
+
+// setPassword replaces the password hash of a user. It returns false if the user does not exist.
+// db.mu must be held.
+func (db *MemoryDatabase) setPassword(id uint64, isStaff bool, hash string) bool {
+	if isStaff {
+		staff, exists := db.staff[id]
+		if exists {
+			staff.Password = hash
+			db.staff[id] = staff
+		}
+		return exists
+	}
+	student, exists := db.students[course.StudentID(id)]
+	if exists {
+		student.Password = hash
+		db.students[course.StudentID(id)] = student
+	}
+	return exists
+}
+
+// AuthExternalUser will find the user of an external identity
+func (db *MemoryDatabase) AuthExternalUser(_ context.Context, issuer, subject string, isStaff bool) (bool, uint64, authDatabase.User, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	id, exists := db.externalIdentities[MemoryExternalIdentity{issuer, subject, isStaff}]
+	if !exists {
+		return false, 0, authDatabase.User{}, nil
+	}
+	if isStaff {
+		staff, exists := db.staff[id]
+		return exists, id, authDatabase.User{Department: staff.Department, Role: staff.Role}, nil
+	}
+	student, exists := db.students[course.StudentID(id)]
+	return exists, id, authDatabase.User{Department: student.Department}, nil
+}
+
+// GetDepartments will get the list of departments
+func (db *MemoryDatabase) GetDepartments() (course.Departments, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return maps.Clone(db.departments), nil
+}
+
+// GetCourses will get the list of courses alongside their registered students and reserve queues
+func (db *MemoryDatabase) GetCourses() (*course.Courses, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	courses := make(map[memoryCourseKey]*course.Course, len(db.courses))
+	coursesMap := make(map[course.CourseID][]*course.Course)
+	for key, row := range db.courses {
+		c := &course.Course{
+			ID:                 row.ID,
+			GroupID:            row.GroupID,
+			Department:         row.Department,
+			Lecturer:           row.Lecturer,
+			Units:              row.Units,
+			Capacity:           row.Capacity,
+			RegisteredStudents: make(map[course.StudentID]struct{}, row.Capacity),
+			ReserveCapacity:    row.ReserveCapacity,
+			ReserveQueue:       util.NewQueue[course.StudentID](),
+			SexLock:            row.SexLock,
+			Eligibility:        course.NewEligibilityFromProto(row.Eligibility),
+			ConfirmationWindow: row.ConfirmationWindow,
+		}
+		if !row.ExamTime.IsZero() {
+			c.ExamTime.Store(row.ExamTime.Unix())
+		}
+		_ = c.ClassHeldTime.Scan(int64(row.ClassTime))
+		courses[key] = c
+		coursesMap[c.ID] = append(coursesMap[c.ID], c)
+	}
+	// Rows are in order of their ID, so the reserve queues are in order as well
+	for _, enrolled := range db.enrolledCourses {
+		c := courses[memoryCourseKey{enrolled.CourseID, enrolled.GroupID}]
+		if enrolled.Reserved {
+			c.ReserveQueue.Enqueue(enrolled.StudentID)
+		} else {
+			c.RegisteredStudents[enrolled.StudentID] = struct{}{}
+		}
+		if !enrolled.ConfirmationDeadline.IsZero() {
+			if c.ConfirmationDeadlines == nil {
+				c.ConfirmationDeadlines = make(map[course.StudentID]int64)
+			}
+			c.ConfirmationDeadlines[enrolled.StudentID] = enrolled.ConfirmationDeadline.UnixMilli()
+		}
+	}
+	// The intents are in order as well
+	for _, intent := range db.intents {
+		c := courses[memoryCourseKey{intent.CourseID, intent.GroupID}]
+		c.Intents = append(c.Intents, intent.StudentID)
+	}
+	result := course.NewCourses(coursesMap)
+	if db.schedule != nil {
+		schedule, err := course.NewScheduleFromProto(db.schedule)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid schedule")
+		}
+		result.SetSchedule(schedule)
+	}
+	result.SetRequisites(maps.Clone(db.requisites))
+	return result, nil
+}
+
+// GetStudents will get all students alongside their enrolled courses
+func (db *MemoryDatabase) GetStudents() (map[course.StudentID]*course.Student, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	result := make(map[course.StudentID]*course.Student, len(db.students))
+	for id, row := range db.students {
+		result[id] = &course.Student{
+			ID:                  row.ID,
+			EnrollmentStartTime: row.EnrollmentStartTime.UnixMilli(),
+			RemainingActions:    row.RemainingActions,
+			MaxUnits:            row.MaxUnits,
+			StudentSex:          row.Sex,
+			Department:          row.Department,
+			EntryYear:           row.EntryYear,
+			GPABand:             row.GPABand,
+			RegisteredCourses:   make(map[course.CourseID]course.GroupID),
+			Wishlist:            course.NewWishlistFromProto(db.wishlists[id]),
+		}
+		for _, courseID := range row.PassedCourses {
+			if result[id].PassedCourses == nil {
+				result[id].PassedCourses = make(map[course.CourseID]struct{})
+			}
+			result[id].PassedCourses[courseID] = struct{}{}
+		}
+	}
+	for _, enrolled := range db.enrolledCourses {
+		student := result[enrolled.StudentID]
+		student.RegisteredCourses[enrolled.CourseID] = enrolled.GroupID
+		student.RegisteredUnits += db.courses[memoryCourseKey{enrolled.CourseID, enrolled.GroupID}].Units
+	}
+	for _, intent := range db.intents {
+		student := result[intent.StudentID]
+		if student.Intents == nil {
+			student.Intents = make(map[course.CourseID]course.GroupID)
+		}
+		student.Intents[intent.CourseID] = intent.GroupID
+	}
+	return result, nil
+}
+
+// ApplyBatch will apply a batch of messages atomically and in order. Messages which their operation ID
+// is already applied are skipped.
+func (db *MemoryDatabase) ApplyBatch(_ context.Context, messages []*proto.CourseDatabaseBatchMessage) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	// Work on a copy of the tables to either apply all messages or none of them
+	enrolledCourses := slices.Clone(db.enrolledCourses)
+	lastID := db.lastEnrolledID
+	intents := slices.Clone(db.intents)
+	wishlists := maps.Clone(db.wishlists)
+	notifications, lastNotificationID := slices.Clone(db.notifications), db.lastNotificationID
+	courses := maps.Clone(db.courses)
+	students := maps.Clone(db.students)
+	appliedOperations := maps.Clone(db.appliedOperations)
+	schedule := db.schedule
+	auditLog, lastAuditID := slices.Clone(db.auditLog), db.lastAuditID
+	// Skip the applied operations and replace the transactions with their messages
+	pending := make([]*proto.CourseDatabaseBatchMessage, 0, len(messages))
+	now := time.Now().UnixMilli()
+	for _, message := range messages {
+		if message.OperationId != "" {
+			if _, applied := appliedOperations[message.OperationId]; applied {
+				continue
+			}
+			appliedOperations[message.OperationId] = struct{}{}
+		}
+		for _, entry := range course.NewAuditEntries(message) {
+			if entry.Time == 0 {
+				entry.Time = now
+			}
+			lastAuditID++
+			auditLog = append(auditLog, authDatabase.AuditEntry{ID: lastAuditID, AuditEntry: entry})
+		}
+		if transaction := message.GetTransaction(); transaction != nil {
+			pending = append(pending, transaction.Messages...)
+		} else {
+			pending = append(pending, message)
+		}
+	}
+	var err error
+	for _, message := range pending {
+		switch action := message.GetAction().(type) {
+		case *proto.CourseDatabaseBatchMessage_Enroll:
+			enrolledCourses, lastID, err = enroll(courses, students, enrolledCourses, lastID, course.CourseID(action.Enroll.CourseId),
+				course.GroupID(action.Enroll.GroupId), course.StudentID(action.Enroll.StudentId), action.Enroll.Reserved)
+		case *proto.CourseDatabaseBatchMessage_Disenroll:
+			enrolledCourses = slices.DeleteFunc(enrolledCourses, func(enrolled MemoryEnrolledCourse) bool {
+				return enrolled.CourseID == course.CourseID(action.Disenroll.CourseId) && enrolled.StudentID == course.StudentID(action.Disenroll.StudentId)
+			})
+			intents = removeIntent(intents, course.StudentID(action.Disenroll.StudentId), course.CourseID(action.Disenroll.CourseId))
+			promote(enrolledCourses, course.CourseID(action.Disenroll.CourseId), course.StudentID(action.Disenroll.PromotedStudentId), action.Disenroll.ConfirmationDeadline)
+		case *proto.CourseDatabaseBatchMessage_ChangeGroup:
+			key := memoryCourseKey{course.CourseID(action.ChangeGroup.CourseId), course.GroupID(action.ChangeGroup.GroupId)}
+			if _, exists := courses[key]; !exists {
+				return fmt.Errorf("course %d-%d does not exist", key.course, key.group)
+			}
+			for i := range enrolledCourses {
+				if enrolledCourses[i].CourseID == key.course && enrolledCourses[i].StudentID == course.StudentID(action.ChangeGroup.StudentId) {
+					enrolledCourses[i].GroupID = key.group
+					enrolledCourses[i].Reserved = action.ChangeGroup.Reserved
+					enrolledCourses[i].ConfirmationDeadline = time.Time{}
+				}
+			}
+			intents = removeIntent(intents, course.StudentID(action.ChangeGroup.StudentId), key.course)
+			promote(enrolledCourses, key.course, course.StudentID(action.ChangeGroup.PromotedStudentId), action.ChangeGroup.ConfirmationDeadline)
+		case *proto.CourseDatabaseBatchMessage_AddIntent:
+			intent := MemoryIntent{
+				StudentID: course.StudentID(action.AddIntent.StudentId),
+				CourseID:  course.CourseID(action.AddIntent.CourseId),
+				GroupID:   course.GroupID(action.AddIntent.GroupId),
+			}
+			if _, exists := courses[memoryCourseKey{intent.CourseID, intent.GroupID}]; !exists {
+				return fmt.Errorf("course %d-%d does not exist", intent.CourseID, intent.GroupID)
+			}
+			if _, exists := students[intent.StudentID]; !exists {
+				return fmt.Errorf("student %d does not exist", intent.StudentID)
+			}
+			intents = append(removeIntent(intents, intent.StudentID, intent.CourseID), intent)
+		case *proto.CourseDatabaseBatchMessage_ConfirmPromotion:
+			for i := range enrolledCourses {
+				if enrolledCourses[i].CourseID == course.CourseID(action.ConfirmPromotion.CourseId) && enrolledCourses[i].StudentID == course.StudentID(action.ConfirmPromotion.StudentId) {
+					enrolledCourses[i].ConfirmationDeadline = time.Time{}
+				}
+			}
+		case *proto.CourseDatabaseBatchMessage_RemoveIntent:
+			intents = removeIntent(intents, course.StudentID(action.RemoveIntent.StudentId), course.CourseID(action.RemoveIntent.CourseId))
+		case *proto.CourseDatabaseBatchMessage_PutWishlist:
+			studentID := course.StudentID(action.PutWishlist.StudentId)
+			if _, exists := students[studentID]; !exists {
+				return fmt.Errorf("student %d does not exist", studentID)
+			}
+			for _, entry := range action.PutWishlist.GetWishlist().GetEntries() {
+				if _, exists := courses[memoryCourseKey{course.CourseID(entry.CourseId), course.GroupID(entry.GroupId)}]; !exists {
+					return fmt.Errorf("course %d-%d does not exist", entry.CourseId, entry.GroupId)
+				}
+			}
+			if len(action.PutWishlist.GetWishlist().GetEntries()) == 0 {
+				delete(wishlists, studentID)
+			} else {
+				wishlists[studentID] = action.PutWishlist.Wishlist
+			}
+		case *proto.CourseDatabaseBatchMessage_AddNotification:
+			notification := authDatabase.Notification{Event: course.NewEventFromProto(action.AddNotification)}
+			if _, exists := students[notification.StudentID]; !exists {
+				return fmt.Errorf("student %d does not exist", notification.StudentID)
+			}
+			lastNotificationID++
+			notification.ID = lastNotificationID
+			notifications = append(notifications, notification)
+		case *proto.CourseDatabaseBatchMessage_UpdateCapacity:
+			key := memoryCourseKey{course.CourseID(action.UpdateCapacity.CourseId), course.GroupID(action.UpdateCapacity.GroupId)}
+			for i := range enrolledCourses {
+				if enrolledCourses[i].CourseID == key.course && enrolledCourses[i].GroupID == key.group &&
+					slices.Contains(action.UpdateCapacity.MovedStudents, uint64(enrolledCourses[i].StudentID)) {
+					enrolledCourses[i].Reserved = false
+					enrolledCourses[i].ConfirmationDeadline = deadlineTime(action.UpdateCapacity.ConfirmationDeadline)
+				}
+			}
+			if c, exists := courses[key]; exists {
+				c.Capacity = int(action.UpdateCapacity.NewCapacity)
+				courses[key] = c
+			}
+		case *proto.CourseDatabaseBatchMessage_PutStudent:
+			student := MemoryStudent{
+				ID:                  course.StudentID(action.PutStudent.StudentId),
+				Password:            action.PutStudent.PasswordHash,
+				EnrollmentStartTime: time.UnixMilli(action.PutStudent.EnrollmentStartTime),
+				MaxUnits:            uint8(action.PutStudent.MaxUnits),
+				RemainingActions:    uint8(action.PutStudent.RemainingActions),
+				Department:          course.DepartmentID(action.PutStudent.DepartmentId),
+				EntryYear:           int16(action.PutStudent.EntryYear),
+				Sex:                 course.Sex(action.PutStudent.Sex),
+				GPABand:             uint8(action.PutStudent.GpaBand),
+			}
+			if old, exists := students[student.ID]; exists {
+				if student.Password == "" {
+					student.Password = old.Password
+				}
+				student.PassedCourses = old.PassedCourses
+			}
+			students[student.ID] = student
+		case *proto.CourseDatabaseBatchMessage_PutCourse:
+			c := MemoryCourse{
+				ID:                 course.CourseID(action.PutCourse.CourseId),
+				GroupID:            course.GroupID(action.PutCourse.GroupId),
+				Department:         course.DepartmentID(action.PutCourse.DepartmentId),
+				Name:               action.PutCourse.Name,
+				Lecturer:           action.PutCourse.Lecturer,
+				Units:              uint8(action.PutCourse.Units),
+				Capacity:           int(action.PutCourse.Capacity),
+				ReserveCapacity:    int(action.PutCourse.ReserveCapacity),
+				ClassTime:          action.PutCourse.ClassTime,
+				SexLock:            course.SexLock(action.PutCourse.SexLock),
+				Notes:              action.PutCourse.Notes,
+				Eligibility:        action.PutCourse.Eligibility,
+				ConfirmationWindow: time.Duration(action.PutCourse.ConfirmationWindow) * time.Second,
+			}
+			if action.PutCourse.ExamTime != 0 {
+				c.ExamTime = time.Unix(action.PutCourse.ExamTime, 0)
+			}
+			courses[memoryCourseKey{c.ID, c.GroupID}] = c
+		case *proto.CourseDatabaseBatchMessage_PutSchedule:
+			schedule = action.PutSchedule
+			if len(schedule.Phases) == 0 {
+				schedule = nil
+			}
+		default:
+			err = errors.Errorf("invalid action: %v", message)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Commit
+	db.enrolledCourses, db.lastEnrolledID = enrolledCourses, lastID
+	db.intents = intents
+	db.wishlists = wishlists
+	db.notifications, db.lastNotificationID = notifications, lastNotificationID
+	db.courses = courses
+	db.students = students
+	db.appliedOperations = appliedOperations
+	db.schedule = schedule
+	db.auditLog, db.lastAuditID = auditLog, lastAuditID
+	return nil
+}
+
+// enroll appends a row to enrolledCourses after checking the foreign keys in courses and students.
+// The new table and the last ID are returned.
+func enroll(courses map[memoryCourseKey]MemoryCourse, students map[course.StudentID]MemoryStudent, enrolledCourses []MemoryEnrolledCourse, lastID int,
+	courseID course.CourseID, groupID course.GroupID, studentID course.StudentID, reserved bool) ([]MemoryEnrolledCourse, int, error) {
+	if _, exists := courses[memoryCourseKey{courseID, groupID}]; !exists {
+		return nil, 0, fmt.Errorf("course %d-%d does not exist", courseID, groupID)
+	}
+	if _, exists := students[studentID]; !exists {
+		return nil, 0, fmt.Errorf("student %d does not exist", studentID)
+	}
+	lastID++
+	return append(enrolledCourses, MemoryEnrolledCourse{
+		ID:        lastID,
+		CourseID:  courseID,
+		GroupID:   groupID,
+		StudentID: studentID,
+		Reserved:  reserved,
+	}), lastID, nil
+}
+
+// promote marks a student as registered in a course alongside their confirmation deadline in
+// milliseconds. Zero student is ignored and zero deadline means no confirmation.
+func promote(enrolledCourses []MemoryEnrolledCourse, courseID course.CourseID, studentID course.StudentID, deadline int64) {
+	if studentID == 0 {
+		return
+	}
+	for i := range enrolledCourses {
+		if enrolledCourses[i].CourseID == courseID && enrolledCourses[i].StudentID == studentID {
+			enrolledCourses[i].Reserved = false
+			enrolledCourses[i].ConfirmationDeadline = deadlineTime(deadline)
+		}
+	}
+}
+
+// deadlineTime converts a deadline in milliseconds to time. Zero deadline is zero time.
+func deadlineTime(deadline int64) time.Time {
+	if deadline == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(deadline)
+}
+
+// removeIntent deletes the intent of a student in a course from intents
+func removeIntent(intents []MemoryIntent, studentID course.StudentID, courseID course.CourseID) []MemoryIntent {
+	return slices.DeleteFunc(intents, func(intent MemoryIntent) bool {
+		return intent.StudentID == studentID && intent.CourseID == courseID
+	})
+}
+
+// GetEnrolledCourses gets all rows of enrolled_courses ordered by ID
+func (db *MemoryDatabase) GetEnrolledCourses(context.Context) ([]coreDatabase.EnrolledCourse, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	result := make([]coreDatabase.EnrolledCourse, len(db.enrolledCourses))
+	for i, enrolled := range db.enrolledCourses {
+		result[i] = coreDatabase.EnrolledCourse{
+			CourseID:  enrolled.CourseID,
+			GroupID:   enrolled.GroupID,
+			StudentID: enrolled.StudentID,
+			Reserved:  enrolled.Reserved,
+		}
+	}
+	return result, nil
+}
+
+// ReplaceEnrolledCourses atomically deletes the rows of the given course groups and inserts their
+// registered students and then their reserve queues
+func (db *MemoryDatabase) ReplaceEnrolledCourses(_ context.Context, enrollments []coreDatabase.CourseEnrollments) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	replaced := make(map[memoryCourseKey]struct{}, len(enrollments))
+	for _, enrollment := range enrollments {
+		replaced[memoryCourseKey{enrollment.CourseID, enrollment.GroupID}] = struct{}{}
+	}
+	enrolledCourses := slices.DeleteFunc(slices.Clone(db.enrolledCourses), func(enrolled MemoryEnrolledCourse) bool {
+		_, exists := replaced[memoryCourseKey{enrolled.CourseID, enrolled.GroupID}]
+		return exists
+	})
+	lastID := db.lastEnrolledID
+	var err error
+	for _, enrollment := range enrollments {
+		for _, studentID := range enrollment.Registered {
+			enrolledCourses, lastID, err = enroll(db.courses, db.students, enrolledCourses, lastID, enrollment.CourseID, enrollment.GroupID, studentID, false)
+			if err != nil {
+				return err
+			}
+			if deadline, exists := enrollment.ConfirmationDeadlines[studentID]; exists {
+				enrolledCourses[len(enrolledCourses)-1].ConfirmationDeadline = deadlineTime(deadline)
+			}
+		}
+		for _, studentID := range enrollment.Reserved {
+			enrolledCourses, lastID, err = enroll(db.courses, db.students, enrolledCourses, lastID, enrollment.CourseID, enrollment.GroupID, studentID, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	db.enrolledCourses, db.lastEnrolledID = enrolledCourses, lastID
+	return nil
+}
+
+// Close does nothing
+func (db *MemoryDatabase) Close() {}
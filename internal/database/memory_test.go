@@ -0,0 +1,189 @@
+package database
+
+import (
+	authDatabase "CourseEnrollment/internal/database/AuthCore"
+	"CourseEnrollment/internal/password"
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// enrollMessage creates an enroll message for tests
+func enrollMessage(operationID string, studentID course.StudentID, groupID course.GroupID, reserved bool) *proto.CourseDatabaseBatchMessage {
+	return &proto.CourseDatabaseBatchMessage{
+		OperationId: operationID,
+		Action: &proto.CourseDatabaseBatchMessage_Enroll{Enroll: &proto.CourseDatabaseBatchEnrollMessage{
+			StudentId: uint64(studentID),
+			CourseId:  10,
+			GroupId:   uint32(groupID),
+			Reserved:  reserved,
+		}},
+	}
+}
+
+func TestMemoryDatabaseApplyBatch(t *testing.T) {
+	db := NewMemoryDatabase()
+	db.AddStudent(MemoryStudent{ID: 1})
+	db.AddStudent(MemoryStudent{ID: 2})
+	db.AddCourse(MemoryCourse{ID: 10, GroupID: 1, Units: 3, Capacity: 1})
+	db.AddCourse(MemoryCourse{ID: 10, GroupID: 2, Units: 3, Capacity: 1})
+	// Enroll and replay
+	assert.NoError(t, db.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{
+		enrollMessage("a", 1, 1, false),
+		enrollMessage("b", 2, 1, true),
+		enrollMessage("a", 1, 1, false),
+	}))
+	assert.NoError(t, db.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{enrollMessage("b", 2, 1, true)}))
+	assert.Equal(t, []MemoryEnrolledCourse{
+		{ID: 1, CourseID: 10, GroupID: 1, StudentID: 1},
+		{ID: 2, CourseID: 10, GroupID: 1, StudentID: 2, Reserved: true},
+	}, db.EnrolledCourses())
+	// A failing batch is not applied at all
+	assert.Error(t, db.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{
+		{OperationId: "c", Action: &proto.CourseDatabaseBatchMessage_Disenroll{Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: 1, CourseId: 10}}},
+		enrollMessage("d", 3, 1, false),
+	}))
+	assert.Len(t, db.EnrolledCourses(), 2)
+	// Update capacity and change group
+	assert.NoError(t, db.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{
+		{OperationId: "e", Action: &proto.CourseDatabaseBatchMessage_UpdateCapacity{UpdateCapacity: &proto.CourseDatabaseBatchUpdateCapacity{
+			CourseId: 10, GroupId: 1, NewCapacity: 2, MovedStudents: []uint64{2},
+		}}},
+		{OperationId: "f", Action: &proto.CourseDatabaseBatchMessage_ChangeGroup{ChangeGroup: &proto.CourseDatabaseBatchChangeGroupMessage{
+			StudentId: 1, CourseId: 10, GroupId: 2,
+		}}},
+	}))
+	c, _ := db.Course(10, 1)
+	assert.Equal(t, 2, c.Capacity)
+	assert.Equal(t, []MemoryEnrolledCourse{
+		{ID: 1, CourseID: 10, GroupID: 2, StudentID: 1},
+		{ID: 2, CourseID: 10, GroupID: 1, StudentID: 2},
+	}, db.EnrolledCourses())
+	// Load the data
+	courses, err := db.GetCourses()
+	assert.NoError(t, err)
+	assert.Equal(t, map[course.StudentID]struct{}{2: {}}, courses.GetCourse(10, 1).RegisteredStudents)
+	students, err := db.GetStudents()
+	assert.NoError(t, err)
+	assert.Equal(t, map[course.CourseID]course.GroupID{10: 2}, students[1].RegisteredCourses)
+	assert.Equal(t, uint8(3), students[1].RegisteredUnits)
+}
+
+func TestMemoryDatabasePromotions(t *testing.T) {
+	deadline := time.UnixMilli(1662976800000)
+	db := NewMemoryDatabase()
+	for id := course.StudentID(1); id <= 3; id++ {
+		db.AddStudent(MemoryStudent{ID: id})
+	}
+	assert.NoError(t, db.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{
+		{Action: &proto.CourseDatabaseBatchMessage_PutCourse{PutCourse: &proto.CourseDatabaseBatchPutCourse{
+			CourseId: 10, GroupId: 1, Units: 3, Capacity: 1, ReserveCapacity: 2, ConfirmationWindow: 3600,
+		}}},
+		enrollMessage("", 1, 1, false),
+		enrollMessage("", 2, 1, true),
+		enrollMessage("", 3, 1, true),
+		// Student 1 leaves and student 2 must confirm the seat
+		{Action: &proto.CourseDatabaseBatchMessage_Disenroll{Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{
+			StudentId: 1, CourseId: 10, PromotedStudentId: 2, ConfirmationDeadline: deadline.UnixMilli(),
+		}}},
+	}))
+	c, _ := db.Course(10, 1)
+	assert.Equal(t, time.Hour, c.ConfirmationWindow)
+	assert.Equal(t, []MemoryEnrolledCourse{
+		{ID: 2, CourseID: 10, GroupID: 1, StudentID: 2, ConfirmationDeadline: deadline},
+		{ID: 3, CourseID: 10, GroupID: 1, StudentID: 3, Reserved: true},
+	}, db.EnrolledCourses())
+	courses, err := db.GetCourses()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, courses.GetCourse(10, 1).ConfirmationWindow)
+	assert.Equal(t, map[course.StudentID]int64{2: deadline.UnixMilli()}, courses.GetCourse(10, 1).ConfirmationDeadlines)
+	// Confirm it
+	assert.NoError(t, db.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{
+		{Action: &proto.CourseDatabaseBatchMessage_ConfirmPromotion{ConfirmPromotion: &proto.CourseDatabaseBatchConfirmPromotion{StudentId: 2, CourseId: 10}}},
+	}))
+	courses, err = db.GetCourses()
+	assert.NoError(t, err)
+	assert.Empty(t, courses.GetCourse(10, 1).ConfirmationDeadlines)
+}
+
+func TestMemoryDatabaseAuditLog(t *testing.T) {
+	db := NewMemoryDatabase()
+	db.AddStudent(MemoryStudent{ID: 1})
+	db.AddStudent(MemoryStudent{ID: 2})
+	db.AddCourse(MemoryCourse{ID: 10, GroupID: 1, Units: 3, Capacity: 1})
+	audit := course.Audit{ActorKind: course.ActorStudent, ActorID: 1, Time: 1000, Origin: "StudentEnroll"}
+	enroll := enrollMessage("a", 1, 1, false)
+	enroll.Audit = audit.ToProto()
+	// Replays are not logged again
+	assert.NoError(t, db.ApplyBatch(context.Background(), []*proto.CourseDatabaseBatchMessage{enroll, enrollMessage("b", 2, 1, true), enroll}))
+	entries := db.AuditLog()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, authDatabase.AuditEntry{ID: 1, AuditEntry: course.AuditEntry{Audit: audit, Action: course.AuditEnroll, StudentID: 1, CourseID: 10, GroupID: 1}}, entries[0])
+		// Messages without an audit are logged at the time of applying
+		assert.Equal(t, course.AuditReserve, entries[1].Action)
+		assert.Equal(t, course.ActorSystem, entries[1].ActorKind)
+		assert.WithinDuration(t, time.Now(), time.UnixMilli(entries[1].Time), time.Minute)
+	}
+	// Filters
+	result, err := db.GetAuditLog(context.Background(), 2, 0, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, entries[1:], result)
+	result, err = db.GetAuditLog(context.Background(), 0, 10, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, entries[1:], result)
+	result, err = db.GetAuditLog(context.Background(), 1, 11, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestMemoryDatabasePasswords(t *testing.T) {
+	ctx := context.Background()
+	bcryptPolicy := password.Policy{Algorithm: password.Bcrypt, BcryptCost: bcrypt.MinCost}
+	hash, err := bcryptPolicy.Hash("password")
+	require.NoError(t, err)
+	db := NewMemoryDatabase()
+	db.SetPasswordPolicy(bcryptPolicy)
+	db.AddStudent(MemoryStudent{ID: 1, Password: hash, Department: 2})
+	ok, user, err := db.AuthUser(ctx, 1, "password", false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, course.DepartmentID(2), user.Department)
+	ok, _, err = db.AuthUser(ctx, 1, "password", true)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	// Hashes are upgraded to the policy on the next login
+	db.SetPasswordPolicy(password.Policy{Algorithm: password.Argon2id, Argon2Time: 1, Argon2Memory: 64, Argon2Threads: 1})
+	student, _ := db.Student(1)
+	assert.True(t, strings.HasPrefix(student.Password, "$2a$"))
+	ok, _, err = db.AuthUser(ctx, 1, "password", false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	student, _ = db.Student(1)
+	assert.True(t, strings.HasPrefix(student.Password, "$argon2id$"))
+	ok, _, err = db.AuthUser(ctx, 1, "password", false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	// Reset tokens can be used once before they expire
+	reset := authDatabase.PasswordReset{User: 1}
+	require.NoError(t, db.AddPasswordReset(ctx, "expired", reset, time.Now().Add(-time.Second)))
+	require.NoError(t, db.AddPasswordReset(ctx, "token", reset, time.Now().Add(time.Hour)))
+	ok, _, err = db.ResetPassword(ctx, "expired", "new password")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, result, err := db.ResetPassword(ctx, "token", "new password")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, reset, result)
+	ok, _, err = db.ResetPassword(ctx, "token", "other password")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, _, err = db.AuthUser(ctx, 1, "new password", false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,290 @@
+// Package harness runs AuthCore, the enrollment server and the batcher in a single process on
+// in-memory storage and connects them with bufconn. It's used to write end-to-end scenario tests
+// without any external service.
+package harness
+
+import (
+	authApi "CourseEnrollment/api/AuthCore"
+	coreApi "CourseEnrollment/api/CourseEnrollmentServer"
+	authCache "CourseEnrollment/internal/cache/AuthCore"
+	"CourseEnrollment/internal/database"
+	authDatabase "CourseEnrollment/internal/database/AuthCore"
+	coreDatabase "CourseEnrollment/internal/database/CourseEnrollmentServer"
+	batcherDatabase "CourseEnrollment/internal/database/DatabaseBatcher"
+	"CourseEnrollment/internal/password"
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/broker"
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/notification"
+	"CourseEnrollment/pkg/proto"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// bufferSize is the buffer size of the in-memory connections
+const bufferSize = 1024 * 1024
+
+// batchSize is the maximum number of messages which the batcher applies at once
+const batchSize = 100
+
+// syncTimeout is the maximum time which Sync waits for the batcher
+const syncTimeout = 5 * time.Second
+
+// TestPasswordPolicy is the policy of passwords in the harness. It uses the minimum bcrypt cost to
+// keep the tests fast.
+var TestPasswordPolicy = password.Policy{Algorithm: password.Bcrypt, BcryptCost: bcrypt.MinCost}
+
+// ClientIP is the IP which the auth core sees as the address of every request in the harness
+const ClientIP = "192.0.2.1"
+
+// consumerName is the name of the batcher consumer
+const consumerName = "harness-batcher"
+
+// Harness is a running instance of all services
+type Harness struct {
+	// The storage of all services
+	Database *database.MemoryDatabase
+	// The broker between the enrollment server and the batcher
+	Broker *broker.MemoryBroker
+	// The enrollment server
+	Core *coreApi.API
+	// The auth core
+	Auth *authApi.API
+	// Sends the events of students to their inbox
+	Notifications *notification.Dispatcher
+	// The queue layout of broker
+	layout broker.QueueLayout
+	// The HTTP client which is connected to the auth core
+	client *http.Client
+}
+
+// Start loads the initial data of the enrollment server from db and starts all services.
+// Everything is stopped when the test finishes.
+func Start(t testing.TB, db *database.MemoryDatabase) *Harness {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := &Harness{Database: db}
+	// The enrollment server
+	var coreStorage coreDatabase.Interface = db
+	courses, err := coreStorage.GetCourses()
+	require.NoError(t, err)
+	students, err := coreStorage.GetStudents()
+	require.NoError(t, err)
+	layout := broker.QueueLayout{Name: shared.CourseEnrollmentServerDatabaseQueueName}
+	h.layout = layout
+	h.Broker = broker.NewMemoryBroker(layout)
+	h.Core = &coreApi.API{Broker: course.AuditBatcher(h.Broker), Courses: courses, Students: students}
+	grpcListener := bufconn.Listen(bufferSize)
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(coreApi.AuditInterceptor, coreApi.StaffScopeInterceptor))
+	proto.RegisterCourseEnrollmentServerServiceServer(grpcServer, h.Core)
+	go func() {
+		_ = grpcServer.Serve(grpcListener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+	// The batcher
+	deliveries, err := h.Broker.Consume(consumerName, batchSize, layout.AllShards())
+	require.NoError(t, err)
+	batcherDone := make(chan struct{})
+	go func() {
+		runBatcher(db, h.Broker, deliveries)
+		close(batcherDone)
+	}()
+	t.Cleanup(func() {
+		_ = h.Broker.CancelConsumer(consumerName, layout.AllShards())
+		<-batcherDone
+	})
+	// The notifications. They are sent before the batcher is stopped.
+	h.Notifications = notification.NewDispatcher(batchSize, notification.InboxSink{Batcher: h.Broker})
+	h.Core.Courses.SetEventSink(h.Notifications)
+	t.Cleanup(h.Notifications.Close)
+	// The auth core
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return grpcListener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	var authStorage authDatabase.Interface = db
+	db.SetPasswordPolicy(TestPasswordPolicy)
+	h.Auth = &authApi.API{
+		Database:       authStorage,
+		Cache:          authCache.NewMemoryAuth(),
+		PasswordPolicy: TestPasswordPolicy,
+		LoginLimits:    authApi.DefaultLoginLimits,
+		CoreClient:     proto.NewCourseEnrollmentServerServiceClient(conn),
+	}
+	h.Auth.GenerateJWTKey()
+	httpListener := bufconn.Listen(bufferSize)
+	httpServer := &http.Server{Handler: h.Auth.Router()}
+	go func() {
+		_ = httpServer.Serve(clientListener{httpListener})
+	}()
+	t.Cleanup(func() {
+		_ = httpServer.Close()
+	})
+	h.client = &http.Client{Transport: &http.Transport{
+		// Reused bufconn connections sometimes cancel the context of the next request on the server
+		DisableKeepAlives: true,
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return httpListener.DialContext(ctx)
+		},
+	}}
+	return h
+}
+
+// runBatcher applies the deliveries in storage until the channel is closed. Every message which is
+// available is applied in a single batch. Failed batches are dead lettered.
+func runBatcher(storage batcherDatabase.Interface, b broker.Broker, deliveries <-chan broker.Delivery) {
+	for delivery := range deliveries {
+		batch := []broker.Delivery{delivery}
+	collect:
+		for len(batch) < batchSize {
+			select {
+			case delivery, ok := <-deliveries:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, delivery)
+			default:
+				break collect
+			}
+		}
+		messages := make([]*proto.CourseDatabaseBatchMessage, len(batch))
+		for i, delivery := range batch {
+			messages[i] = delivery.Message
+		}
+		err := storage.ApplyBatch(context.Background(), messages)
+		for _, delivery := range batch {
+			if err != nil {
+				_ = b.DeadLetter(context.Background(), delivery, err, 1)
+			} else {
+				_ = delivery.Ack()
+			}
+		}
+	}
+}
+
+// Sync waits until the batcher has applied every message which is published until now.
+// The test fails if any message is dead lettered.
+func (h *Harness) Sync(t testing.TB) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+	require.NoError(t, h.Broker.Wait(ctx), "batcher did not apply the messages")
+	deadLetters, err := h.Broker.ListDeadLetters(h.layout.AllShards())
+	require.NoError(t, err)
+	for _, letter := range deadLetters {
+		t.Errorf("message %v is dead lettered: %s", letter.Message, letter.Error)
+	}
+}
+
+// Reload waits for the batcher and then reloads the data of the enrollment server from the database,
+// just like restarting it. No request must be in flight while reloading.
+func (h *Harness) Reload(t testing.TB) {
+	t.Helper()
+	h.Sync(t)
+	var coreStorage coreDatabase.Interface = h.Database
+	courses, err := coreStorage.GetCourses()
+	require.NoError(t, err)
+	students, err := coreStorage.GetStudents()
+	require.NoError(t, err)
+	courses.SetEventSink(h.Notifications)
+	h.Core.Courses, h.Core.Students = courses, students
+}
+
+// Login logs in a user and returns its token. The test fails if the login fails.
+func (h *Harness) Login(t testing.TB, user uint64, password string, isStaff bool) string {
+	t.Helper()
+	var result authApi.TokenResult
+	status := h.Request(t, "", http.MethodPost, "/login", authApi.LoginRequest{
+		User:     user,
+		Password: password,
+		IsStaff:  isStaff,
+	}, &result)
+	require.Equal(t, http.StatusOK, status, "cannot login")
+	return result.Token
+}
+
+// Request sends a request to the auth core and returns the status code. If body is not nil, it's sent
+// as JSON. If result is not nil, the response is parsed into it.
+func (h *Harness) Request(t testing.TB, token, method, path string, body, result any) int {
+	t.Helper()
+	return h.RequestWithHeader(t, token, method, path, nil, body, result)
+}
+
+// RequestWithHeader is like Request but also sends the given headers
+func (h *Harness) RequestWithHeader(t testing.TB, token, method, path string, header http.Header, body, result any) int {
+	t.Helper()
+	var requestBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		requestBody = bytes.NewReader(data)
+	}
+	request, err := http.NewRequest(method, "http://auth-core"+path, requestBody)
+	require.NoError(t, err)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := h.client.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	if result != nil && response.StatusCode < 300 {
+		require.NoError(t, json.Unmarshal(data, result), "cannot parse response: %s", data)
+	}
+	return response.StatusCode
+}
+
+// HashPassword hashes a password to be stored in MemoryDatabase with TestPasswordPolicy
+func HashPassword(t testing.TB, pass string) string {
+	t.Helper()
+	hash, err := TestPasswordPolicy.Hash(pass)
+	require.NoError(t, err)
+	return hash
+}
+
+// clientListener makes the connections of a bufconn listener look like TCP connections from
+// ClientIP, because bufconn addresses are not IPs
+type clientListener struct {
+	*bufconn.Listener
+}
+
+func (l clientListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return clientConn{conn}, nil
+}
+
+// clientConn is a connection of clientListener
+type clientConn struct {
+	net.Conn
+}
+
+func (clientConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP(ClientIP), Port: 1024}
+}
@@ -0,0 +1,204 @@
+package harness
+
+import (
+	authApi "CourseEnrollment/api/AuthCore"
+	"CourseEnrollment/internal/oidc"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The client of the harness in MockIdP
+const (
+	idpClientID     = "course-enrollment"
+	idpClientSecret = "secret"
+	// The callback of the auth core in the harness
+	idpRedirectURL = "http://auth-core/sso/callback"
+)
+
+// MockIdP is a minimal OpenID Connect identity provider on a local HTTP server. Every user who
+// visits its authorization endpoint is logged in without any prompt, as set with LoginAs.
+type MockIdP struct {
+	Server *httptest.Server
+	// The identity of the next logins. Empty means that the logins are denied.
+	subject string
+	key     ed25519.PrivateKey
+	// The codes which are not exchanged yet
+	codes map[string]mockIdPCode
+	mu    sync.Mutex
+}
+
+// mockIdPCode is an authorization code of MockIdP
+type mockIdPCode struct {
+	subject     string
+	nonce       string
+	challenge   string
+	redirectURI string
+}
+
+// StartMockIdP starts a MockIdP which is stopped when the test finishes
+func StartMockIdP(t testing.TB) *MockIdP {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	idp := &MockIdP{key: key, codes: make(map[string]mockIdPCode)}
+	r := gin.New()
+	r.GET("/.well-known/openid-configuration", idp.discovery)
+	r.GET("/authorize", idp.authorize)
+	r.POST("/token", idp.token)
+	r.GET("/jwks", idp.jwks)
+	idp.Server = httptest.NewServer(r)
+	t.Cleanup(idp.Server.Close)
+	return idp
+}
+
+// LoginAs sets the identity which the next logins get. Empty denies them.
+func (idp *MockIdP) LoginAs(subject string) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.subject = subject
+}
+
+// EnableSSO makes the auth core use idp as its identity provider
+func (h *Harness) EnableSSO(t testing.TB, idp *MockIdP) {
+	t.Helper()
+	provider, err := oidc.Discover(context.Background(), oidc.Config{
+		Issuer:       idp.Server.URL,
+		ClientID:     idpClientID,
+		ClientSecret: idpClientSecret,
+		RedirectURL:  idpRedirectURL,
+	}, idp.Server.Client())
+	require.NoError(t, err)
+	h.Auth.OIDC = provider
+}
+
+// SSOLogin goes through single sign-on like a browser and returns the status code of the callback
+// and the token, if any
+func (h *Harness) SSOLogin(t testing.TB, idp *MockIdP, isStaff bool) (int, string) {
+	t.Helper()
+	authClient := *h.client
+	authClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	idpClient := idp.Server.Client()
+	idpClient.CheckRedirect = authClient.CheckRedirect
+	// Start the login in the auth core
+	path := "/sso/login"
+	if isStaff {
+		path += "?staff=true"
+	}
+	response, err := authClient.Get("http://auth-core" + path)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	require.Equal(t, http.StatusFound, response.StatusCode)
+	cookies := response.Cookies()
+	// Login in the provider
+	response, err = idpClient.Get(response.Header.Get("Location"))
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	require.Equal(t, http.StatusFound, response.StatusCode)
+	// Return to the auth core
+	request, err := http.NewRequest(http.MethodGet, response.Header.Get("Location"), nil)
+	require.NoError(t, err)
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	response, err = authClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	var result authApi.TokenResult
+	if response.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&result))
+	}
+	return response.StatusCode, result.Token
+}
+
+func (idp *MockIdP) discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"issuer":                 idp.Server.URL,
+		"authorization_endpoint": idp.Server.URL + "/authorize",
+		"token_endpoint":         idp.Server.URL + "/token",
+		"jwks_uri":               idp.Server.URL + "/jwks",
+	})
+}
+
+func (idp *MockIdP) authorize(c *gin.Context) {
+	redirectURI, err := url.Parse(c.Query("redirect_uri"))
+	if err != nil || c.Query("client_id") != idpClientID || c.Query("response_type") != "code" ||
+		c.Query("code_challenge_method") != "S256" || c.Query("code_challenge") == "" {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	query := url.Values{"state": {c.Query("state")}}
+	idp.mu.Lock()
+	if idp.subject == "" {
+		query.Set("error", "access_denied")
+	} else {
+		code := oidc.NewRandom()
+		idp.codes[code] = mockIdPCode{
+			subject:     idp.subject,
+			nonce:       c.Query("nonce"),
+			challenge:   c.Query("code_challenge"),
+			redirectURI: redirectURI.String(),
+		}
+		query.Set("code", code)
+	}
+	idp.mu.Unlock()
+	redirectURI.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, redirectURI.String())
+}
+
+func (idp *MockIdP) token(c *gin.Context) {
+	if id, secret, ok := c.Request.BasicAuth(); !ok || id != idpClientID || secret != idpClientSecret {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		return
+	}
+	// Codes can only be used once
+	idp.mu.Lock()
+	code, exists := idp.codes[c.PostForm("code")]
+	delete(idp.codes, c.PostForm("code"))
+	idp.mu.Unlock()
+	if !exists || c.PostForm("grant_type") != "authorization_code" || c.PostForm("redirect_uri") != code.redirectURI ||
+		oidc.Challenge(c.PostForm("code_verifier")) != code.challenge {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_grant"})
+		return
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, oidc.IDToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    idp.Server.URL,
+			Subject:   code.subject,
+			Audience:  jwt.ClaimStrings{idpClientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Nonce: code.nonce,
+	})
+	token.Header["kid"] = "mock"
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"access_token": oidc.NewRandom(), "token_type": "Bearer", "id_token": idToken})
+}
+
+func (idp *MockIdP) jwks(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"keys": []gin.H{{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"use": "sig",
+		"kid": "mock",
+		"x":   base64.RawURLEncoding.EncodeToString(idp.key.Public().(ed25519.PublicKey)),
+	}}})
+}
@@ -0,0 +1,258 @@
+package harness
+
+import (
+	authApi "CourseEnrollment/api/AuthCore"
+	"CourseEnrollment/internal/database"
+	"CourseEnrollment/internal/shared"
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	testPassword   = "password"
+	testDepartment = course.DepartmentID(1)
+	// A super admin
+	testStaff = 100
+	// A staff of testDepartment
+	testDepartmentStaff = 101
+	testCourse          = course.CourseID(40101)
+)
+
+// newTestDatabase creates a database with a department, a super admin, a staff of the department,
+// the given students and a course with two groups. The first group has the given capacity and
+// reserve capacity.
+func newTestDatabase(t *testing.T, students []course.StudentID, capacity, reserveCapacity int) *database.MemoryDatabase {
+	db := database.NewMemoryDatabase()
+	db.AddDepartment(testDepartment, "Computer Engineering")
+	hash := HashPassword(t, testPassword)
+	db.AddStaff(database.MemoryStaff{ID: testStaff, Password: hash, Department: testDepartment, Role: shared.StaffRoleSuperAdmin})
+	db.AddStaff(database.MemoryStaff{ID: testDepartmentStaff, Password: hash, Department: testDepartment})
+	for _, id := range students {
+		db.AddStudent(database.MemoryStudent{
+			ID:                  id,
+			Password:            hash,
+			EnrollmentStartTime: time.Now().Add(-time.Minute),
+			MaxUnits:            20,
+			RemainingActions:    5,
+			Department:          testDepartment,
+			EntryYear:           1400,
+			Sex:                 course.SexMale,
+		})
+	}
+	db.AddCourse(database.MemoryCourse{ID: testCourse, GroupID: 1, Department: testDepartment, Units: 3, Capacity: capacity, ReserveCapacity: reserveCapacity})
+	db.AddCourse(database.MemoryCourse{ID: testCourse, GroupID: 2, Department: testDepartment, Units: 3, Capacity: 10, ReserveCapacity: 10})
+	return db
+}
+
+// enrollmentRequest is the body of enroll and change group requests
+func enrollmentRequest(groupID course.GroupID) map[string]any {
+	return map[string]any{"course_id": testCourse, "group_id": groupID}
+}
+
+// studentsOfCourse gets the students of a course from the staff endpoint
+func studentsOfCourse(t *testing.T, h *Harness, staffToken string, groupID course.GroupID) *proto.StudentsOfCourseResponse {
+	t.Helper()
+	result := new(proto.StudentsOfCourseResponse)
+	status := h.Request(t, staffToken, http.MethodGet, "/staff/course-students?course_id=40101&group_id="+strconv.Itoa(int(groupID)), nil, result)
+	assert.Equal(t, http.StatusOK, status)
+	return result
+}
+
+// notificationsOf waits until the student of token has at least count notifications, which are
+// sent to the inbox through the broker and the batcher
+func notificationsOf(t *testing.T, h *Harness, token string, count int) []authApi.Notification {
+	t.Helper()
+	var result authApi.NotificationsResult
+	for deadline := time.Now().Add(syncTimeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		h.Sync(t)
+		require.Equal(t, http.StatusOK, h.Request(t, token, http.MethodGet, "/student/notifications", nil, &result))
+		if len(result.Notifications) >= count {
+			break
+		}
+	}
+	require.Len(t, result.Notifications, count)
+	return result.Notifications
+}
+
+func TestScenarioEnroll(t *testing.T) {
+	h := Start(t, newTestDatabase(t, []course.StudentID{1}, 10, 10))
+	// Wrong password
+	assert.Equal(t, http.StatusUnauthorized, h.Request(t, "", http.MethodPost, "/login", map[string]any{"user": 1, "password": "wrong"}, nil))
+	token := h.Login(t, 1, testPassword, false)
+	// Enroll
+	assert.Equal(t, http.StatusNoContent, h.Request(t, token, http.MethodPut, "/student/course", enrollmentRequest(1), nil))
+	assert.Equal(t, http.StatusBadRequest, h.Request(t, token, http.MethodPut, "/student/course", enrollmentRequest(1), nil))
+	var enrolled proto.StudentCourseDataArray
+	assert.Equal(t, http.StatusOK, h.Request(t, token, http.MethodGet, "/student/course", nil, &enrolled))
+	if assert.Len(t, enrolled.Data, 1) {
+		assert.Equal(t, int32(testCourse), enrolled.Data[0].Course.CourseId)
+		assert.Zero(t, enrolled.Data[0].ReserveQueuePosition)
+	}
+	// Check the database
+	h.Sync(t)
+	rows := h.Database.EnrolledCourses()
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, database.MemoryEnrolledCourse{ID: 1, CourseID: testCourse, GroupID: 1, StudentID: 1, Reserved: false}, rows[0])
+	}
+	// Change group and disenroll
+	assert.Equal(t, http.StatusNoContent, h.Request(t, token, http.MethodPatch, "/student/course", enrollmentRequest(2), nil))
+	h.Sync(t)
+	assert.Equal(t, course.GroupID(2), h.Database.EnrolledCourses()[0].GroupID)
+	assert.Equal(t, http.StatusNoContent, h.Request(t, token, http.MethodDelete, "/student/course?course_id=40101", nil, nil))
+	h.Sync(t)
+	assert.Empty(t, h.Database.EnrolledCourses())
+	// A restart must see the same state
+	assert.Equal(t, http.StatusNoContent, h.Request(t, token, http.MethodPut, "/student/course", enrollmentRequest(2), nil))
+	h.Reload(t)
+	assert.Equal(t, map[course.CourseID]course.GroupID{testCourse: 2}, h.Core.Students[1].RegisteredCourses)
+	assert.Equal(t, uint8(3), h.Core.Students[1].RegisteredUnits)
+}
+
+func TestScenarioReservePromotion(t *testing.T) {
+	h := Start(t, newTestDatabase(t, []course.StudentID{1, 2, 3}, 1, 2))
+	staffToken := h.Login(t, testStaff, testPassword, true)
+	tokens := make(map[course.StudentID]string)
+	for _, id := range []course.StudentID{1, 2, 3} {
+		tokens[id] = h.Login(t, uint64(id), testPassword, false)
+		assert.Equal(t, http.StatusNoContent, h.Request(t, tokens[id], http.MethodPut, "/student/course", enrollmentRequest(1), nil))
+	}
+	students := studentsOfCourse(t, h, staffToken, 1)
+	assert.Equal(t, []uint64{1}, students.RegisteredStudents)
+	assert.Equal(t, []uint64{2, 3}, students.ReservedQueueStudents)
+	h.Sync(t)
+	rows := h.Database.EnrolledCourses()
+	if assert.Len(t, rows, 3) {
+		assert.False(t, rows[0].Reserved)
+		assert.True(t, rows[1].Reserved)
+		assert.True(t, rows[2].Reserved)
+	}
+	// The first student in the reserve queue is promoted
+	assert.Equal(t, http.StatusNoContent, h.Request(t, tokens[1], http.MethodDelete, "/student/course?course_id=40101", nil, nil))
+	students = studentsOfCourse(t, h, staffToken, 1)
+	assert.Equal(t, []uint64{2}, students.RegisteredStudents)
+	assert.Equal(t, []uint64{3}, students.ReservedQueueStudents)
+	var enrolled proto.StudentCourseDataArray
+	assert.Equal(t, http.StatusOK, h.Request(t, tokens[3], http.MethodGet, "/student/course", nil, &enrolled))
+	if assert.Len(t, enrolled.Data, 1) {
+		assert.Equal(t, uint32(1), enrolled.Data[0].ReserveQueuePosition)
+	}
+	h.Sync(t)
+	rows = h.Database.EnrolledCourses()
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, course.StudentID(2), rows[0].StudentID)
+		assert.Equal(t, course.StudentID(3), rows[1].StudentID)
+	}
+	promoted := notificationsOf(t, h, tokens[2], 1)[0]
+	assert.Equal(t, "promoted", promoted.Kind)
+	assert.Equal(t, "You are enrolled in course 40101-1", promoted.Subject)
+	// The staff drops the second student with a reason, which promotes the third one
+	const reason = "ثبت‌نام تکراری"
+	assert.Equal(t, http.StatusNoContent, h.RequestWithHeader(t, staffToken, http.MethodDelete, "/staff/force-std?course_id=40101&group_id=1&std_id=2",
+		http.Header{"X-Audit-Reason": {reason}}, nil, nil))
+	assert.Equal(t, "force_disenrolled", notificationsOf(t, h, tokens[2], 2)[1].Kind)
+	third := notificationsOf(t, h, tokens[3], 1)
+	assert.Equal(t, "promoted", third[len(third)-1].Kind)
+	// Everything is audited with the user who has done it
+	var audit authApi.AuditResult
+	require.Equal(t, http.StatusOK, h.Request(t, staffToken, http.MethodGet, "/staff/audit?student_id=2", nil, &audit))
+	for i := range audit.Entries {
+		assert.WithinDuration(t, time.Now(), audit.Entries[i].Time, time.Minute)
+		audit.Entries[i].Time = time.Time{}
+	}
+	assert.Equal(t, []authApi.AuditEntry{
+		{ID: 2, Action: "reserve", StudentID: 2, CourseID: testCourse, GroupID: 1, ActorKind: "student", ActorID: 2, Origin: "StudentEnroll"},
+		{ID: 5, Action: "promote", StudentID: 2, CourseID: testCourse, GroupID: 1, ActorKind: "student", ActorID: 1, Origin: "StudentDisenroll"},
+		{ID: 6, Action: "disenroll", StudentID: 2, CourseID: testCourse, GroupID: 1, ActorKind: "staff", ActorID: testStaff, Origin: "ForceDisenroll", Reason: reason},
+	}, audit.Entries)
+	// A restart must see the same state
+	h.Reload(t)
+	students = studentsOfCourse(t, h, staffToken, 1)
+	assert.Equal(t, []uint64{3}, students.RegisteredStudents)
+	assert.Empty(t, students.ReservedQueueStudents)
+}
+
+func TestScenarioStaffDepartments(t *testing.T) {
+	db := newTestDatabase(t, []course.StudentID{1, 2}, 10, 10)
+	const otherDepartment = testDepartment + 1
+	const otherCourse = testCourse + 1
+	db.AddDepartment(otherDepartment, "Mathematics")
+	db.AddCourse(database.MemoryCourse{ID: otherCourse, GroupID: 1, Department: otherDepartment, Units: 3, Capacity: 10})
+	db.AddStudent(database.MemoryStudent{ID: 3, Password: HashPassword(t, testPassword), EnrollmentStartTime: time.Now().Add(-time.Minute),
+		MaxUnits: 20, Department: otherDepartment, EntryYear: 1400, Sex: course.SexFemale})
+	h := Start(t, db)
+	adminToken := h.Login(t, testStaff, testPassword, true)
+	staffToken := h.Login(t, testDepartmentStaff, testPassword, true)
+	// The courses of the department
+	assert.Equal(t, http.StatusNoContent, h.Request(t, staffToken, http.MethodPut, "/staff/force-std",
+		map[string]any{"course_id": testCourse, "group_id": 1, "std_id": 3}, nil))
+	assert.Equal(t, http.StatusNoContent, h.Request(t, staffToken, http.MethodPatch, "/staff/capacity",
+		map[string]any{"course_id": testCourse, "group_id": 1, "capacity": 5}, nil))
+	assert.Equal(t, http.StatusNoContent, h.Request(t, staffToken, http.MethodDelete, "/staff/force-std?course_id=40101&group_id=1&std_id=3", nil, nil))
+	// Other departments
+	assert.Equal(t, http.StatusForbidden, h.Request(t, staffToken, http.MethodPut, "/staff/force-std",
+		map[string]any{"course_id": otherCourse, "group_id": 1, "std_id": 1}, nil))
+	assert.Equal(t, http.StatusForbidden, h.Request(t, staffToken, http.MethodPatch, "/staff/capacity",
+		map[string]any{"course_id": otherCourse, "group_id": 1, "capacity": 5}, nil))
+	assert.Equal(t, http.StatusForbidden, h.Request(t, staffToken, http.MethodPut, "/staff/course", map[string]any{
+		"course_id": otherCourse, "group_id": 2, "department": otherDepartment, "name": "Calculus", "units": 3,
+	}, nil))
+	// A student of another department cannot be moved to the department
+	assert.Equal(t, http.StatusForbidden, h.Request(t, staffToken, http.MethodPut, "/staff/student", map[string]any{
+		"std_id": 3, "enrollment_start_time": time.Now(), "max_units": 20, "department": testDepartment, "entry_year": 1400, "sex": "female",
+	}, nil))
+	// Only super admins can change the schedule and run lotteries
+	assert.Equal(t, http.StatusForbidden, h.Request(t, staffToken, http.MethodPut, "/staff/schedule", map[string]any{"phases": []any{}}, nil))
+	assert.Equal(t, http.StatusForbidden, h.Request(t, staffToken, http.MethodPost, "/staff/lottery", map[string]any{"phase": "lottery"}, nil))
+	assert.Equal(t, http.StatusNoContent, h.Request(t, adminToken, http.MethodPut, "/staff/schedule", map[string]any{"phases": []any{}}, nil))
+	// Super admins can act on every department
+	assert.Equal(t, http.StatusNoContent, h.Request(t, adminToken, http.MethodPut, "/staff/force-std",
+		map[string]any{"course_id": otherCourse, "group_id": 1, "std_id": 1}, nil))
+	assert.Equal(t, http.StatusNoContent, h.Request(t, adminToken, http.MethodPatch, "/staff/capacity",
+		map[string]any{"course_id": otherCourse, "group_id": 1, "capacity": 5}, nil))
+	// The enrollment server sees the department of refreshed tokens
+	db.AddStaff(database.MemoryStaff{ID: testStaff, Password: HashPassword(t, testPassword), Department: otherDepartment})
+	var refreshed authApi.TokenResult
+	require.Equal(t, http.StatusOK, h.Request(t, adminToken, http.MethodPost, "/refresh", nil, &refreshed))
+	assert.Equal(t, http.StatusForbidden, h.Request(t, refreshed.Token, http.MethodPatch, "/staff/capacity",
+		map[string]any{"course_id": testCourse, "group_id": 1, "capacity": 5}, nil))
+	assert.Equal(t, http.StatusNoContent, h.Request(t, refreshed.Token, http.MethodPatch, "/staff/capacity",
+		map[string]any{"course_id": otherCourse, "group_id": 1, "capacity": 6}, nil))
+}
+
+func TestScenarioSSO(t *testing.T) {
+	h := Start(t, newTestDatabase(t, []course.StudentID{1, 2}, 10, 10))
+	assert.Equal(t, http.StatusNotFound, h.Request(t, "", http.MethodGet, "/sso/login", nil, nil))
+	idp := StartMockIdP(t)
+	h.EnableSSO(t, idp)
+	h.Database.AddExternalIdentity(database.MemoryExternalIdentity{Issuer: idp.Server.URL, Subject: "alice"}, 1)
+	h.Database.AddExternalIdentity(database.MemoryExternalIdentity{Issuer: idp.Server.URL, Subject: "bob", IsStaff: true}, testDepartmentStaff)
+	// Linked identities get the token of their user
+	idp.LoginAs("alice")
+	status, token := h.SSOLogin(t, idp, false)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, http.StatusOK, h.Request(t, token, http.MethodGet, "/student/course", nil, nil))
+	assert.Equal(t, http.StatusUnauthorized, h.Request(t, token, http.MethodGet, "/staff/audit?student_id=1", nil, nil))
+	idp.LoginAs("bob")
+	status, token = h.SSOLogin(t, idp, true)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, http.StatusOK, h.Request(t, token, http.MethodGet, "/staff/audit?student_id=1", nil, nil))
+	// Identities are linked to either a student or a staff
+	status, _ = h.SSOLogin(t, idp, false)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	idp.LoginAs("mallory")
+	status, _ = h.SSOLogin(t, idp, false)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	// Denied logins and callbacks of other browsers are rejected
+	idp.LoginAs("")
+	status, _ = h.SSOLogin(t, idp, false)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, http.StatusBadRequest, h.Request(t, "", http.MethodGet, "/sso/callback?code=code&state=state", nil, nil))
+	// Passwords still work
+	h.Login(t, 2, testPassword, false)
+}
@@ -0,0 +1,252 @@
+// Package oidc is a client of the authorization code flow of OpenID Connect. It only does what
+// AuthCore needs: discovering the provider, building the authorization URL and exchanging the
+// code for a verified ID token.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/go-faster/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// discoveryPath is the path of the configuration of providers after their issuer
+const discoveryPath = "/.well-known/openid-configuration"
+
+// keysRefreshInterval is the minimum time between fetching the keys of the provider again because
+// of an unknown kid, so invalid tokens cannot make us flood the provider
+const keysRefreshInterval = time.Minute
+
+// Config is the registration of AuthCore in the identity provider
+type Config struct {
+	// The URL of the provider, which is also the iss claim of its tokens
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// The callback of AuthCore which the provider redirects the users to
+	RedirectURL string
+	// The scopes which are requested besides openid
+	Scopes []string
+}
+
+// IDToken is the claims of an ID token which are used
+type IDToken struct {
+	jwt.RegisteredClaims
+	Nonce string `json:"nonce"`
+}
+
+// Provider is a discovered identity provider
+type Provider struct {
+	config                Config
+	client                *http.Client
+	authorizationEndpoint string
+	tokenEndpoint         string
+	jwksURI               string
+	// The public keys of the provider by their kid
+	keys          map[string]any
+	keysFetchedAt time.Time
+	keysMu        sync.Mutex
+}
+
+// Discover gets the endpoints of a provider from its configuration
+func Discover(ctx context.Context, config Config, client *http.Client) (*Provider, error) {
+	var document struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	if err := getJSON(ctx, client, strings.TrimSuffix(config.Issuer, "/")+discoveryPath, &document); err != nil {
+		return nil, errors.Wrap(err, "cannot discover provider")
+	}
+	if document.Issuer != config.Issuer {
+		return nil, errors.Errorf("provider issuer %q does not match %q", document.Issuer, config.Issuer)
+	}
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return nil, errors.New("provider configuration is incomplete")
+	}
+	return &Provider{
+		config:                config,
+		client:                client,
+		authorizationEndpoint: document.AuthorizationEndpoint,
+		tokenEndpoint:         document.TokenEndpoint,
+		jwksURI:               document.JWKSURI,
+	}, nil
+}
+
+// Config returns the config which the provider is discovered with
+func (p *Provider) Config() Config {
+	return p.config
+}
+
+// AuthCodeURL returns the URL which users must be redirected to, to login in the provider. The
+// challenge is the PKCE challenge of the verifier which the code is exchanged with.
+func (p *Provider) AuthCodeURL(state, nonce, challenge string) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(append([]string{"openid"}, p.config.Scopes...), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(p.authorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.authorizationEndpoint + separator + query.Encode()
+}
+
+// Exchange exchanges the code which the provider has redirected the user with for their ID token.
+// The token is verified and must have the nonce which the login is started with.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (IDToken, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return IDToken{}, errors.Wrap(err, "cannot create token request")
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	response, err := p.client.Do(request)
+	if err != nil {
+		return IDToken{}, errors.Wrap(err, "cannot request token")
+	}
+	defer response.Body.Close()
+	var result struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return IDToken{}, errors.Wrapf(err, "cannot parse token response (status %d)", response.StatusCode)
+	}
+	if result.Error != "" {
+		return IDToken{}, errors.Errorf("token error: %s: %s", result.Error, result.ErrorDescription)
+	}
+	if response.StatusCode != http.StatusOK || result.IDToken == "" {
+		return IDToken{}, errors.Errorf("no id token (status %d)", response.StatusCode)
+	}
+	return p.Verify(ctx, result.IDToken, nonce)
+}
+
+// Verify verifies the signature and the claims of an ID token
+func (p *Provider) Verify(ctx context.Context, token, nonce string) (IDToken, error) {
+	var claims IDToken
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}))
+	_, err := parser.ParseWithClaims(token, &claims, func(token *jwt.Token) (any, error) {
+		id, _ := token.Header["kid"].(string)
+		return p.key(ctx, id)
+	})
+	if err != nil {
+		return IDToken{}, errors.Wrap(err, "invalid id token")
+	}
+	now := time.Now()
+	switch {
+	case !claims.VerifyIssuer(p.config.Issuer, true):
+		return IDToken{}, errors.New("id token of another issuer")
+	case !claims.VerifyAudience(p.config.ClientID, true):
+		return IDToken{}, errors.New("id token of another client")
+	case !claims.VerifyExpiresAt(now, true):
+		return IDToken{}, errors.New("id token without expiry")
+	case claims.Subject == "":
+		return IDToken{}, errors.New("id token without subject")
+	case nonce == "" || claims.Nonce != nonce:
+		return IDToken{}, errors.New("id token nonce mismatch")
+	}
+	return claims, nil
+}
+
+// key returns the public key of the provider by its kid. The keys are fetched again if the kid is
+// unknown, because the provider might have rotated them.
+func (p *Provider) key(ctx context.Context, id string) (any, error) {
+	p.keysMu.Lock()
+	defer p.keysMu.Unlock()
+	if key, exists := p.keys[id]; exists {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("unknown kid: %q", id)
+	}
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.keys, p.keysFetchedAt = keys, time.Now()
+	// Tokens without a kid are fine if the provider has a single key
+	if id == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	if key, exists := keys[id]; exists {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown kid: %q", id)
+}
+
+// fetchKeys gets the signing keys of the provider from its JWKS
+func (p *Provider) fetchKeys(ctx context.Context) (map[string]any, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, p.client, p.jwksURI, &set); err != nil {
+		return nil, errors.Wrap(err, "cannot get provider keys")
+	}
+	result := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Keys of unknown types are ignored, like the encryption keys
+		if key, err := k.publicKey(); err == nil {
+			result[k.KeyID] = key
+		}
+	}
+	return result, nil
+}
+
+// NewRandom returns a random URL safe string, which is used as states, nonces and PKCE verifiers
+func NewRandom() string {
+	data := make([]byte, 32)
+	_, _ = rand.Read(data)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Challenge returns the S256 PKCE challenge of a verifier
+func Challenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// getJSON gets a JSON document
+func getJSON(ctx context.Context, client *http.Client, url string, result any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("status %d", response.StatusCode)
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,203 @@
+// Package password hashes and verifies the passwords of students and staff. Passwords can be
+// hashed with bcrypt or argon2id, and the hashes of both are verified regardless of the policy, so
+// the policy can be changed and the old hashes upgraded on the next login.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"github.com/go-faster/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strconv"
+	"strings"
+)
+
+// The limits of the length of passwords in bytes. bcrypt only uses the first 72 bytes.
+const (
+	MinLength = 8
+	MaxLength = 72
+)
+
+// The sizes of the salt and the key of argon2id hashes in bytes
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// argon2Prefix is the prefix of argon2id hashes in the PHC string format
+const argon2Prefix = "$argon2id$"
+
+// Algorithm is the hash function of passwords
+type Algorithm uint8
+
+const (
+	Bcrypt Algorithm = iota
+	Argon2id
+)
+
+// Policy is how new passwords are hashed
+type Policy struct {
+	Algorithm  Algorithm
+	BcryptCost int
+	// The number of passes, the memory in KiB and the parallelism of argon2id
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+// DefaultPolicy is bcrypt with its default cost, which is what the passwords are hashed with
+// before the policy is configurable
+var DefaultPolicy = Policy{Algorithm: Bcrypt, BcryptCost: bcrypt.DefaultCost}
+
+// defaultArgon2Policy is the argon2id policy of RFC 9106 which uses 64 MiB of memory
+var defaultArgon2Policy = Policy{Algorithm: Argon2id, Argon2Time: 3, Argon2Memory: 64 * 1024, Argon2Threads: 4}
+
+// ParsePolicy parses a policy like "bcrypt", "bcrypt:12", "argon2id" or "argon2id:m=65536,t=3,p=4".
+// The parameters which are not set are the defaults.
+func ParsePolicy(value string) (Policy, error) {
+	algorithm, params, _ := strings.Cut(value, ":")
+	switch algorithm {
+	case "bcrypt":
+		policy := DefaultPolicy
+		if params != "" {
+			cost, err := strconv.Atoi(params)
+			if err != nil {
+				return Policy{}, errors.Wrap(err, "invalid bcrypt cost")
+			}
+			if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+				return Policy{}, errors.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+			}
+			policy.BcryptCost = cost
+		}
+		return policy, nil
+	case "argon2id":
+		policy := defaultArgon2Policy
+		if params != "" {
+			if err := policy.parseArgon2Params(params); err != nil {
+				return Policy{}, err
+			}
+		}
+		if policy.Argon2Time == 0 || policy.Argon2Memory < 8*uint32(policy.Argon2Threads) || policy.Argon2Threads == 0 {
+			return Policy{}, errors.New("invalid argon2id parameters")
+		}
+		return policy, nil
+	default:
+		return Policy{}, errors.Errorf("unknown algorithm: %q", algorithm)
+	}
+}
+
+// parseArgon2Params parses the parameters of argon2id like "m=65536,t=3,p=4" into p
+func (p *Policy) parseArgon2Params(params string) error {
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(param, "=")
+		number, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return errors.Wrapf(err, "invalid argon2id parameter %q", param)
+		}
+		switch key {
+		case "m":
+			p.Argon2Memory = uint32(number)
+		case "t":
+			p.Argon2Time = uint32(number)
+		case "p":
+			if number > 255 {
+				return errors.Errorf("invalid argon2id parameter %q", param)
+			}
+			p.Argon2Threads = uint8(number)
+		default:
+			return errors.Errorf("unknown argon2id parameter %q", param)
+		}
+	}
+	return nil
+}
+
+// Check checks if a new password is acceptable
+func Check(password string) error {
+	switch {
+	case len(password) < MinLength:
+		return errors.Errorf("password must have at least %d characters", MinLength)
+	case len(password) > MaxLength:
+		return errors.Errorf("password must have at most %d bytes", MaxLength)
+	}
+	return nil
+}
+
+// Hash hashes a password with the policy
+func (p Policy) Hash(password string) (string, error) {
+	if p.Algorithm == Argon2id {
+		salt := make([]byte, argon2SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return "", errors.Wrap(err, "cannot generate salt")
+		}
+		key := argon2.IDKey([]byte(password), salt, p.Argon2Time, p.Argon2Memory, p.Argon2Threads, argon2KeyLength)
+		return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2Prefix, argon2.Version, p.Argon2Memory, p.Argon2Time,
+			p.Argon2Threads, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), p.BcryptCost)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot hash password")
+	}
+	return string(hash), nil
+}
+
+// NeedsRehash checks if a hash is not made with the policy, so it must be hashed again on the
+// next login
+func (p Policy) NeedsRehash(hash string) bool {
+	if p.Algorithm == Argon2id {
+		stored, _, _, err := parseArgon2Hash(hash)
+		return err != nil || stored.Argon2Memory != p.Argon2Memory || stored.Argon2Time != p.Argon2Time ||
+			stored.Argon2Threads != p.Argon2Threads
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != p.BcryptCost
+}
+
+// Verify checks a password against its hash, which can be made with any policy
+func Verify(hash, password string) (bool, error) {
+	if strings.HasPrefix(hash, argon2Prefix) {
+		policy, salt, key, err := parseArgon2Hash(hash)
+		if err != nil {
+			return false, err
+		}
+		actual := argon2.IDKey([]byte(password), salt, policy.Argon2Time, policy.Argon2Memory, policy.Argon2Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(actual, key) == 1, nil
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "invalid bcrypt hash")
+	}
+	return true, nil
+}
+
+// parseArgon2Hash parses an argon2id hash into its parameters, its salt and its key
+func parseArgon2Hash(hash string) (Policy, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(hash, argon2Prefix), "$")
+	if !strings.HasPrefix(hash, argon2Prefix) || len(parts) != 4 {
+		return Policy{}, nil, nil, errors.New("invalid argon2id hash")
+	}
+	if parts[0] != fmt.Sprintf("v=%d", argon2.Version) {
+		return Policy{}, nil, nil, errors.Errorf("unsupported argon2id version %q", parts[0])
+	}
+	policy := Policy{Algorithm: Argon2id}
+	if err := policy.parseArgon2Params(parts[1]); err != nil {
+		return Policy{}, nil, nil, err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Policy{}, nil, nil, errors.Wrap(err, "invalid argon2id salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return Policy{}, nil, nil, errors.New("invalid argon2id key")
+	}
+	if policy.Argon2Time == 0 || policy.Argon2Threads == 0 {
+		return Policy{}, nil, nil, errors.New("invalid argon2id parameters")
+	}
+	return policy, salt, key, nil
+}
@@ -0,0 +1,75 @@
+package password
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Policy
+		err      bool
+	}{
+		{value: "bcrypt", expected: DefaultPolicy},
+		{value: "bcrypt:12", expected: Policy{Algorithm: Bcrypt, BcryptCost: 12}},
+		{value: "bcrypt:1", err: true},
+		{value: "argon2id", expected: defaultArgon2Policy},
+		{value: "argon2id:m=1024,t=1", expected: Policy{Algorithm: Argon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 4}},
+		{value: "argon2id:t=0", err: true},
+		{value: "argon2id:x=1", err: true},
+		{value: "scrypt", err: true},
+	}
+	for _, test := range tests {
+		policy, err := ParsePolicy(test.value)
+		if test.err {
+			assert.Error(t, err, test.value)
+			continue
+		}
+		require.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, policy, test.value)
+	}
+}
+
+func TestHash(t *testing.T) {
+	policies := []Policy{
+		{Algorithm: Bcrypt, BcryptCost: bcrypt.MinCost},
+		{Algorithm: Argon2id, Argon2Time: 1, Argon2Memory: 64, Argon2Threads: 1},
+	}
+	for _, policy := range policies {
+		hash, err := policy.Hash("password")
+		require.NoError(t, err)
+		ok, err := Verify(hash, "password")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = Verify(hash, "wrong")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		// Only the hashes of other policies must be upgraded
+		assert.False(t, policy.NeedsRehash(hash))
+		for _, other := range policies {
+			if other != policy {
+				assert.True(t, other.NeedsRehash(hash))
+			}
+		}
+	}
+	stronger := Policy{Algorithm: Argon2id, Argon2Time: 2, Argon2Memory: 64, Argon2Threads: 1}
+	hash, err := policies[1].Hash("password")
+	require.NoError(t, err)
+	assert.True(t, stronger.NeedsRehash(hash))
+	// Invalid hashes are errors, not wrong passwords
+	_, err = Verify("$argon2id$v=19$m=64,t=1,p=1$salt", "password")
+	assert.Error(t, err)
+	_, err = Verify("plain", "password")
+	assert.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	assert.Error(t, Check("short"))
+	assert.NoError(t, Check("password"))
+	assert.NoError(t, Check(strings.Repeat("a", MaxLength)))
+	assert.Error(t, Check(strings.Repeat("a", MaxLength+1)))
+}
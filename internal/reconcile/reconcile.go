@@ -0,0 +1,278 @@
+// Package reconcile compares the in-memory state of the enrollment server with the enrolled
+// courses in the database, and repairs the differences in either direction.
+package reconcile
+
+import (
+	db "CourseEnrollment/internal/database/CourseEnrollmentServer"
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	"slices"
+	"time"
+)
+
+// Reconciler compares the state of the enrollment server with the database
+type Reconciler struct {
+	// The database which the state is compared with
+	Database db.Interface
+	// A difference is only reported if it still exists after this duration. The batcher might be
+	// behind, so some differences are temporary.
+	Settle time.Duration
+	// MemoryRepaired is called after the in-memory state is repaired if it's not nil.
+	// The repairs are not batched as messages, so for example, a new snapshot must be taken.
+	MemoryRepaired func()
+}
+
+// courseKey identifies a group of a course
+type courseKey struct {
+	course course.CourseID
+	group  course.GroupID
+}
+
+// differenceKey identifies a difference to compare the differences of two runs
+type differenceKey struct {
+	courseKey
+	student course.StudentID
+	kind    proto.ReconcileDifferenceKind
+}
+
+// Run compares the courses with the database and repairs the differences if requested.
+// freeze must call its argument while nothing can change the courses and students.
+func (r *Reconciler) Run(ctx context.Context, freeze func(func()), courses *course.Courses, students map[course.StudentID]*course.Student, repair proto.ReconcileRepair) (*proto.ReconcileResponse, error) {
+	differences, err := r.compare(ctx, courses)
+	if err != nil {
+		return nil, err
+	}
+	// Only keep the differences which persist
+	if len(differences) != 0 && r.Settle > 0 {
+		select {
+		case <-time.After(r.Settle):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		later, err := r.compare(ctx, courses)
+		if err != nil {
+			return nil, err
+		}
+		laterKeys := make(map[differenceKey]struct{}, len(later))
+		for _, difference := range later {
+			laterKeys[keyOf(difference)] = struct{}{}
+		}
+		differences = slices.DeleteFunc(differences, func(difference *proto.ReconcileDifference) bool {
+			_, exists := laterKeys[keyOf(difference)]
+			return !exists
+		})
+	}
+	result := &proto.ReconcileResponse{Differences: differences}
+	if len(differences) == 0 || repair == proto.ReconcileRepair_REPAIR_NONE {
+		return result, nil
+	}
+	// Repair the groups which have a difference
+	groups := make(map[courseKey]struct{})
+	for _, difference := range differences {
+		groups[keyOf(difference).courseKey] = struct{}{}
+	}
+	switch repair {
+	case proto.ReconcileRepair_REPAIR_DATABASE:
+		freeze(func() {
+			err = r.repairDatabase(ctx, courses, groups)
+		})
+	case proto.ReconcileRepair_REPAIR_MEMORY:
+		freeze(func() {
+			err = r.repairMemory(ctx, courses, students, groups)
+		})
+		if err == nil && r.MemoryRepaired != nil {
+			r.MemoryRepaired()
+		}
+	default:
+		err = errors.Errorf("invalid repair: %v", repair)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Repaired = true
+	return result, nil
+}
+
+// compare returns the differences of all courses with the database
+func (r *Reconciler) compare(ctx context.Context, courses *course.Courses) ([]*proto.ReconcileDifference, error) {
+	// Get the memory state first. If something changes between reading the memory and the database,
+	// the batcher will apply it soon and the difference goes away in the next run.
+	memory := make(map[courseKey]*proto.StudentsOfCourseResponse)
+	for _, c := range courses.All() {
+		memory[courseKey{c.ID, c.GroupID}] = c.ToStudentsOfCourseResponseProto()
+	}
+	rows, err := r.Database.GetEnrolledCourses(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get enrolled courses")
+	}
+	database := make(map[courseKey][]db.EnrolledCourse)
+	for _, row := range rows {
+		key := courseKey{row.CourseID, row.GroupID}
+		database[key] = append(database[key], row)
+	}
+	// Compare each group
+	var result []*proto.ReconcileDifference
+	for key, students := range memory {
+		result = append(result, compareCourse(key, students, database[key])...)
+	}
+	for key, rows := range database {
+		if _, exists := memory[key]; !exists {
+			result = append(result, compareCourse(key, new(proto.StudentsOfCourseResponse), rows)...)
+		}
+	}
+	slices.SortFunc(result, func(a, b *proto.ReconcileDifference) int {
+		switch {
+		case a.CourseId != b.CourseId:
+			return int(a.CourseId) - int(b.CourseId)
+		case a.GroupId != b.GroupId:
+			return int(a.GroupId) - int(b.GroupId)
+		case a.StudentId != b.StudentId:
+			if a.StudentId < b.StudentId {
+				return -1
+			}
+			return 1
+		default:
+			return int(a.Kind) - int(b.Kind)
+		}
+	})
+	return result, nil
+}
+
+// compareCourse compares the students of a course group in memory with its rows in the database
+func compareCourse(key courseKey, memory *proto.StudentsOfCourseResponse, rows []db.EnrolledCourse) []*proto.ReconcileDifference {
+	var result []*proto.ReconcileDifference
+	addDifference := func(student course.StudentID, kind proto.ReconcileDifferenceKind) {
+		result = append(result, &proto.ReconcileDifference{
+			CourseId:  int32(key.course),
+			GroupId:   uint32(key.group),
+			StudentId: uint64(student),
+			Kind:      kind,
+		})
+	}
+	// Index the rows
+	rowOf := make(map[course.StudentID]db.EnrolledCourse, len(rows))
+	var databaseQueue []course.StudentID
+	for _, row := range rows {
+		if _, duplicate := rowOf[row.StudentID]; duplicate {
+			addDifference(row.StudentID, proto.ReconcileDifferenceKind_DUPLICATE_ROW)
+			continue
+		}
+		rowOf[row.StudentID] = row
+		if row.Reserved {
+			databaseQueue = append(databaseQueue, row.StudentID)
+		}
+	}
+	// Check the memory
+	inMemory := make(map[course.StudentID]struct{}, len(memory.RegisteredStudents)+len(memory.ReservedQueueStudents))
+	check := func(student course.StudentID, reserved bool) {
+		inMemory[student] = struct{}{}
+		row, exists := rowOf[student]
+		if !exists {
+			addDifference(student, proto.ReconcileDifferenceKind_MISSING_ROW)
+		} else if row.Reserved != reserved {
+			addDifference(student, proto.ReconcileDifferenceKind_WRONG_RESERVED)
+		}
+	}
+	for _, student := range memory.RegisteredStudents {
+		check(course.StudentID(student), false)
+	}
+	for _, student := range memory.ReservedQueueStudents {
+		check(course.StudentID(student), true)
+	}
+	for student := range rowOf {
+		if _, exists := inMemory[student]; !exists {
+			addDifference(student, proto.ReconcileDifferenceKind_EXTRA_ROW)
+		}
+	}
+	// Check the order of the students which are reserved in both
+	memoryQueue := make([]course.StudentID, 0, len(memory.ReservedQueueStudents))
+	inMemoryQueue := make(map[course.StudentID]struct{}, len(memory.ReservedQueueStudents))
+	for _, student := range memory.ReservedQueueStudents {
+		inMemoryQueue[course.StudentID(student)] = struct{}{}
+		if row, exists := rowOf[course.StudentID(student)]; exists && row.Reserved {
+			memoryQueue = append(memoryQueue, course.StudentID(student))
+		}
+	}
+	databaseQueue = slices.DeleteFunc(databaseQueue, func(student course.StudentID) bool {
+		_, exists := inMemoryQueue[student]
+		return !exists
+	})
+	if !slices.Equal(memoryQueue, databaseQueue) {
+		addDifference(0, proto.ReconcileDifferenceKind_RESERVE_ORDER)
+	}
+	return result
+}
+
+// repairDatabase replaces the rows of the given groups with the in-memory state
+func (r *Reconciler) repairDatabase(ctx context.Context, courses *course.Courses, groups map[courseKey]struct{}) error {
+	enrollments := make([]db.CourseEnrollments, 0, len(groups))
+	for key := range groups {
+		enrollment := db.CourseEnrollments{CourseID: key.course, GroupID: key.group}
+		// Groups which only exist in the database are emptied
+		if c := courses.GetCourse(key.course, key.group); c != nil {
+			students := c.ToStudentsOfCourseResponseProto()
+			for _, student := range students.RegisteredStudents {
+				enrollment.Registered = append(enrollment.Registered, course.StudentID(student))
+			}
+			for _, student := range students.ReservedQueueStudents {
+				enrollment.Reserved = append(enrollment.Reserved, course.StudentID(student))
+			}
+			enrollment.ConfirmationDeadlines = c.ConfirmationDeadlinesOfStudents()
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+	if err := r.Database.ReplaceEnrolledCourses(ctx, enrollments); err != nil {
+		return errors.Wrap(err, "cannot repair database")
+	}
+	log.WithField("groups", len(enrollments)).Info("repaired database")
+	return nil
+}
+
+// repairMemory replaces the students of the given groups with their rows in the database
+func (r *Reconciler) repairMemory(ctx context.Context, courses *course.Courses, students map[course.StudentID]*course.Student, groups map[courseKey]struct{}) error {
+	rows, err := r.Database.GetEnrolledCourses(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get enrolled courses")
+	}
+	replacements := make(map[courseKey]*course.CourseStudents, len(groups))
+	for key := range groups {
+		c := courses.GetCourse(key.course, key.group)
+		if c == nil {
+			log.WithField("course", key.course).WithField("group", key.group).Warn("cannot repair a course which does not exist in memory")
+			continue
+		}
+		replacements[key] = &course.CourseStudents{Course: c}
+	}
+	for _, row := range rows {
+		replacement, exists := replacements[courseKey{row.CourseID, row.GroupID}]
+		if !exists || slices.Contains(replacement.Registered, row.StudentID) || slices.Contains(replacement.Reserved, row.StudentID) {
+			continue
+		}
+		if row.Reserved {
+			replacement.Reserved = append(replacement.Reserved, row.StudentID)
+		} else {
+			replacement.Registered = append(replacement.Registered, row.StudentID)
+		}
+	}
+	list := make([]course.CourseStudents, 0, len(replacements))
+	for _, replacement := range replacements {
+		list = append(list, *replacement)
+	}
+	if err = course.ReplaceStudents(courses, students, list); err != nil {
+		return errors.Wrap(err, "cannot repair memory")
+	}
+	log.WithField("groups", len(list)).Info("repaired memory")
+	return nil
+}
+
+// keyOf returns the key of a difference
+func keyOf(difference *proto.ReconcileDifference) differenceKey {
+	return differenceKey{
+		courseKey: courseKey{course.CourseID(difference.CourseId), course.GroupID(difference.GroupId)},
+		student:   course.StudentID(difference.StudentId),
+		kind:      difference.Kind,
+	}
+}
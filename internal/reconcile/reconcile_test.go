@@ -0,0 +1,173 @@
+package reconcile
+
+import (
+	"CourseEnrollment/internal/database"
+	db "CourseEnrollment/internal/database/CourseEnrollmentServer"
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// noFreeze calls the function directly
+func noFreeze(f func()) {
+	f()
+}
+
+func TestCompareCourse(t *testing.T) {
+	key := courseKey{course: 10, group: 1}
+	tests := []struct {
+		Name     string
+		Memory   *proto.StudentsOfCourseResponse
+		Rows     []db.EnrolledCourse
+		Expected []proto.ReconcileDifferenceKind
+	}{
+		{
+			Name:   "equal",
+			Memory: &proto.StudentsOfCourseResponse{RegisteredStudents: []uint64{1}, ReservedQueueStudents: []uint64{2, 3}},
+			Rows: []db.EnrolledCourse{
+				{CourseID: 10, GroupID: 1, StudentID: 1},
+				{CourseID: 10, GroupID: 1, StudentID: 2, Reserved: true},
+				{CourseID: 10, GroupID: 1, StudentID: 3, Reserved: true},
+			},
+		},
+		{
+			Name:     "missing row",
+			Memory:   &proto.StudentsOfCourseResponse{RegisteredStudents: []uint64{1}},
+			Expected: []proto.ReconcileDifferenceKind{proto.ReconcileDifferenceKind_MISSING_ROW},
+		},
+		{
+			Name:     "extra row",
+			Memory:   &proto.StudentsOfCourseResponse{},
+			Rows:     []db.EnrolledCourse{{CourseID: 10, GroupID: 1, StudentID: 1}},
+			Expected: []proto.ReconcileDifferenceKind{proto.ReconcileDifferenceKind_EXTRA_ROW},
+		},
+		{
+			Name:     "wrong reserved",
+			Memory:   &proto.StudentsOfCourseResponse{RegisteredStudents: []uint64{1}},
+			Rows:     []db.EnrolledCourse{{CourseID: 10, GroupID: 1, StudentID: 1, Reserved: true}},
+			Expected: []proto.ReconcileDifferenceKind{proto.ReconcileDifferenceKind_WRONG_RESERVED},
+		},
+		{
+			Name:   "reserve order",
+			Memory: &proto.StudentsOfCourseResponse{ReservedQueueStudents: []uint64{2, 3}},
+			Rows: []db.EnrolledCourse{
+				{CourseID: 10, GroupID: 1, StudentID: 3, Reserved: true},
+				{CourseID: 10, GroupID: 1, StudentID: 2, Reserved: true},
+			},
+			Expected: []proto.ReconcileDifferenceKind{proto.ReconcileDifferenceKind_RESERVE_ORDER},
+		},
+		{
+			Name:   "order ignores other differences",
+			Memory: &proto.StudentsOfCourseResponse{ReservedQueueStudents: []uint64{2, 3, 4}},
+			Rows: []db.EnrolledCourse{
+				{CourseID: 10, GroupID: 1, StudentID: 2, Reserved: true},
+				{CourseID: 10, GroupID: 1, StudentID: 4, Reserved: true},
+			},
+			Expected: []proto.ReconcileDifferenceKind{proto.ReconcileDifferenceKind_MISSING_ROW},
+		},
+		{
+			Name:   "duplicate row",
+			Memory: &proto.StudentsOfCourseResponse{RegisteredStudents: []uint64{1}},
+			Rows: []db.EnrolledCourse{
+				{CourseID: 10, GroupID: 1, StudentID: 1},
+				{CourseID: 10, GroupID: 1, StudentID: 1},
+			},
+			Expected: []proto.ReconcileDifferenceKind{proto.ReconcileDifferenceKind_DUPLICATE_ROW},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var kinds []proto.ReconcileDifferenceKind
+			for _, difference := range compareCourse(key, test.Memory, test.Rows) {
+				kinds = append(kinds, difference.Kind)
+			}
+			assert.Equal(t, test.Expected, kinds)
+		})
+	}
+}
+
+// newTestDatabase creates a database with two groups of a course and three students.
+// Student 1 is registered in group 1 and students 2 and 3 are in its reserve queue.
+func newTestDatabase(t *testing.T) *database.MemoryDatabase {
+	t.Helper()
+	memoryDB := database.NewMemoryDatabase()
+	for id := course.StudentID(1); id <= 3; id++ {
+		memoryDB.AddStudent(database.MemoryStudent{ID: id, MaxUnits: 20})
+	}
+	memoryDB.AddCourse(database.MemoryCourse{ID: 10, GroupID: 1, Units: 3, Capacity: 1, ReserveCapacity: 2})
+	memoryDB.AddCourse(database.MemoryCourse{ID: 10, GroupID: 2, Units: 3, Capacity: 1, ReserveCapacity: 2})
+	require.NoError(t, memoryDB.AddEnrolledCourse(database.MemoryEnrolledCourse{CourseID: 10, GroupID: 1, StudentID: 1}))
+	require.NoError(t, memoryDB.AddEnrolledCourse(database.MemoryEnrolledCourse{CourseID: 10, GroupID: 1, StudentID: 2, Reserved: true}))
+	require.NoError(t, memoryDB.AddEnrolledCourse(database.MemoryEnrolledCourse{CourseID: 10, GroupID: 1, StudentID: 3, Reserved: true}))
+	return memoryDB
+}
+
+// loadState loads the courses and students from the database
+func loadState(t *testing.T, memoryDB *database.MemoryDatabase) (*course.Courses, map[course.StudentID]*course.Student) {
+	t.Helper()
+	courses, err := memoryDB.GetCourses()
+	require.NoError(t, err)
+	students, err := memoryDB.GetStudents()
+	require.NoError(t, err)
+	return courses, students
+}
+
+func TestReconcilerRun(t *testing.T) {
+	t.Run("no difference", func(t *testing.T) {
+		memoryDB := newTestDatabase(t)
+		courses, students := loadState(t, memoryDB)
+		reconciler := &Reconciler{Database: memoryDB}
+		result, err := reconciler.Run(context.Background(), noFreeze, courses, students, proto.ReconcileRepair_REPAIR_DATABASE)
+		require.NoError(t, err)
+		assert.Empty(t, result.Differences)
+		assert.False(t, result.Repaired)
+	})
+	t.Run("repair database", func(t *testing.T) {
+		memoryDB := newTestDatabase(t)
+		courses, students := loadState(t, memoryDB)
+		// Reverse the queue and move student 1 to group 2 in memory
+		require.NoError(t, course.ReplaceStudents(courses, students, []course.CourseStudents{
+			{Course: courses.GetCourse(10, 1), Reserved: []course.StudentID{3, 2}},
+			{Course: courses.GetCourse(10, 2), Registered: []course.StudentID{1}},
+		}))
+		reconciler := &Reconciler{Database: memoryDB}
+		result, err := reconciler.Run(context.Background(), noFreeze, courses, students, proto.ReconcileRepair_REPAIR_NONE)
+		require.NoError(t, err)
+		assert.Equal(t, []*proto.ReconcileDifference{
+			{CourseId: 10, GroupId: 1, Kind: proto.ReconcileDifferenceKind_RESERVE_ORDER},
+			{CourseId: 10, GroupId: 1, StudentId: 1, Kind: proto.ReconcileDifferenceKind_EXTRA_ROW},
+			{CourseId: 10, GroupId: 2, StudentId: 1, Kind: proto.ReconcileDifferenceKind_MISSING_ROW},
+		}, result.Differences)
+		assert.False(t, result.Repaired)
+		// Repair
+		result, err = reconciler.Run(context.Background(), noFreeze, courses, students, proto.ReconcileRepair_REPAIR_DATABASE)
+		require.NoError(t, err)
+		assert.True(t, result.Repaired)
+		result, err = reconciler.Run(context.Background(), noFreeze, courses, students, proto.ReconcileRepair_REPAIR_NONE)
+		require.NoError(t, err)
+		assert.Empty(t, result.Differences)
+		loadedCourses, _ := loadState(t, memoryDB)
+		assert.Equal(t, []course.StudentID{3, 2}, loadedCourses.GetCourse(10, 1).ReserveQueue.CopyAsArray())
+	})
+	t.Run("repair memory", func(t *testing.T) {
+		memoryDB := newTestDatabase(t)
+		memoryDB.AddStudent(database.MemoryStudent{ID: 4, MaxUnits: 20})
+		courses, students := loadState(t, memoryDB)
+		require.NoError(t, memoryDB.AddEnrolledCourse(database.MemoryEnrolledCourse{CourseID: 10, GroupID: 2, StudentID: 4}))
+		repaired := false
+		reconciler := &Reconciler{Database: memoryDB, MemoryRepaired: func() { repaired = true }}
+		result, err := reconciler.Run(context.Background(), noFreeze, courses, students, proto.ReconcileRepair_REPAIR_MEMORY)
+		require.NoError(t, err)
+		assert.Equal(t, []*proto.ReconcileDifference{
+			{CourseId: 10, GroupId: 2, StudentId: 4, Kind: proto.ReconcileDifferenceKind_EXTRA_ROW},
+		}, result.Differences)
+		assert.True(t, result.Repaired)
+		assert.True(t, repaired)
+		assert.Equal(t, map[course.StudentID]struct{}{4: {}}, courses.GetCourse(10, 2).RegisteredStudents)
+		assert.Equal(t, map[course.CourseID]course.GroupID{10: 2}, students[4].RegisteredCourses)
+		assert.Equal(t, uint8(3), students[4].RegisteredUnits)
+	})
+}
@@ -1,7 +1,18 @@
 package shared
 
-import "time"
-
 const CourseEnrollmentServerDatabaseQueueName = "course-enrollment-database-queue"
 
-const AuthCoreTokenTTL = time.Minute * 5
+// The gRPC metadata keys which AuthCore sends the actor of enrollment changes with, so the
+// enrollment server can audit them. The reason is binary, because staff can enter any text.
+const (
+	AuditActorKindMetadataKey = "audit-actor-kind"
+	AuditActorIDMetadataKey   = "audit-actor-id"
+	AuditReasonMetadataKey    = "audit-reason-bin"
+)
+
+// The gRPC metadata keys which AuthCore sends the department and the role of staff with, so the
+// enrollment server can limit them to their department
+const (
+	StaffDepartmentMetadataKey = "staff-department"
+	StaffRoleMetadataKey       = "staff-role"
+)
@@ -0,0 +1,35 @@
+package shared
+
+// StaffRole is the role of a staff which decides what they can change
+type StaffRole uint8
+
+const (
+	// StaffRoleDepartment can only change the courses and the students of their own department
+	StaffRoleDepartment StaffRole = iota
+	// StaffRoleSuperAdmin can change everything in every department, like the schedule
+	StaffRoleSuperAdmin
+)
+
+func (r StaffRole) String() string {
+	switch r {
+	case StaffRoleDepartment:
+		return "department"
+	case StaffRoleSuperAdmin:
+		return "super_admin"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseStaffRole parses the name of a role. The second returned value is false if the name is
+// not a role.
+func ParseStaffRole(name string) (StaffRole, bool) {
+	switch name {
+	case "department":
+		return StaffRoleDepartment, true
+	case "super_admin":
+		return StaffRoleSuperAdmin, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,82 @@
+package snapshot
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"bufio"
+	"encoding/binary"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// recordHeaderSize is the size of the header of each record in a journal.
+// Each record is the length of the payload (4 bytes), the CRC32 of the payload (4 bytes)
+// and the payload itself. All numbers are big endian.
+const recordHeaderSize = 8
+
+// maxRecordSize is the maximum size of a payload. Anything bigger than this is considered corruption.
+const maxRecordSize = 16 * 1024 * 1024
+
+// encodeRecord encodes a payload as a journal record
+func encodeRecord(payload []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[recordHeaderSize:], payload)
+	return record
+}
+
+// readJournal calls callback for each message of a journal in order. If last is true, a torn
+// record at the end of the journal is ignored because the server might have crashed while writing it.
+func readJournal(path string, last bool, callback func(*proto.CourseDatabaseBatchMessage) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "cannot open journal")
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for {
+		var header [recordHeaderSize]byte
+		_, err = io.ReadFull(reader, header[:])
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var payload []byte
+		if err == nil {
+			length := binary.BigEndian.Uint32(header[0:4])
+			if length > maxRecordSize {
+				err = errors.New("record is too big")
+			} else {
+				payload = make([]byte, length)
+				_, err = io.ReadFull(reader, payload)
+			}
+		}
+		if err == nil && crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			err = errors.New("checksum mismatch")
+		}
+		if err != nil {
+			if last && !isTrailingGarbage(reader) {
+				log.WithError(err).WithField("journal", path).Warn("ignoring torn record at the end of journal")
+				return nil
+			}
+			return errors.Wrap(err, "invalid record")
+		}
+		message := new(proto.CourseDatabaseBatchMessage)
+		if err = protobuf.Unmarshal(payload, message); err != nil {
+			return errors.Wrap(err, "cannot parse message")
+		}
+		if err = callback(message); err != nil {
+			return errors.Wrapf(err, "cannot replay operation %s", message.OperationId)
+		}
+	}
+}
+
+// isTrailingGarbage checks if there is more data after a broken record. A torn record must
+// be the last thing in the journal.
+func isTrailingGarbage(reader *bufio.Reader) bool {
+	_, err := reader.Peek(1)
+	return err == nil
+}
@@ -0,0 +1,392 @@
+// Package snapshot stores the state of the enrollment server on disk, so it can start without
+// loading everything from the database.
+//
+// A snapshot is the whole state of the courses and students at a point of time. Every message
+// which the enrollment server batches after a snapshot is also appended to a journal. On startup,
+// the last snapshot is loaded and the journal is replayed on it.
+//
+// Each snapshot belongs to a generation. The messages which are batched after the snapshot of
+// generation g are in the journals of generation g and later.
+//
+// The messages are appended to the journal while the courses and students are locked, so they are
+// only buffered there. They are written and synced to disk in the background, together with every
+// other message which is appended meanwhile. So the messages of the last moments before a crash
+// might not be in the journal. To detect it, a marker file is created when the store is opened and
+// removed when it's closed cleanly. If the marker is left from the last run, the snapshot is not
+// loaded at all.
+package snapshot
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"fmt"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snapshotFileName is the name of the snapshot file in the directory
+const snapshotFileName = "snapshot.bin"
+
+// journalFilePrefix is the prefix of the journal files. The generation comes after it.
+const journalFilePrefix = "journal."
+
+// dirtyFileName is the name of the marker file which exists while a store is open
+const dirtyFileName = "dirty"
+
+// ErrNoSnapshot is returned from Store.Load when there is no snapshot to load
+var ErrNoSnapshot = errors.New("no snapshot")
+
+// ErrUncleanShutdown is returned from Store.Load when the store was not closed in the last run. The
+// journal might miss the last messages; so the snapshot cannot be trusted.
+var ErrUncleanShutdown = errors.New("store was not closed cleanly")
+
+// Store keeps the snapshots and journals in a directory
+type Store struct {
+	dir string
+	// True if the dirty marker of the last run was found when the store was opened
+	unclean bool
+	// The latest generation which is used in the directory
+	generation uint64
+	// The journal which the batched messages are appended to. It's nil until the first snapshot
+	// is saved and after appending to it fails.
+	journal *os.File
+	// The records which are appended but not written to the journal yet
+	pending []byte
+	// Guards journal and pending. It's never held while waiting for the disk.
+	mu sync.Mutex
+	// Held while writing to the journal and syncing it. It's locked before mu.
+	syncMu sync.Mutex
+	// Wakes up the syncer when there are pending records
+	wake chan struct{}
+	// Closed to stop the syncer, which closes stopped when it returns
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// Open opens a store in a directory. The directory is created if it does not exist.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "cannot create directory")
+	}
+	s := &Store{
+		dir:     dir,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	journals, err := s.listJournals()
+	if err != nil {
+		return nil, err
+	}
+	if len(journals) != 0 {
+		s.generation = journals[len(journals)-1]
+	}
+	// Mark the store as open until it's closed
+	_, err = os.Stat(s.dirtyPath())
+	s.unclean = err == nil
+	if err = writeFileSync(s.dirtyPath(), nil); err != nil {
+		return nil, errors.Wrap(err, "cannot create dirty marker")
+	}
+	go s.syncLoop()
+	return s, nil
+}
+
+// Load loads the last snapshot and replays the journals after it. If there is no snapshot,
+// ErrNoSnapshot is returned. If the store was not closed in the last run, ErrUncleanShutdown is
+// returned and the state must be loaded from the database.
+func (s *Store) Load() (*course.Courses, map[course.StudentID]*course.Student, error) {
+	if s.unclean {
+		return nil, nil, ErrUncleanShutdown
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, ErrNoSnapshot
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot read snapshot")
+	}
+	snapshot := new(proto.EnrollmentSnapshot)
+	if err = protobuf.Unmarshal(data, snapshot); err != nil {
+		return nil, nil, errors.Wrap(err, "cannot parse snapshot")
+	}
+	courses, students, err := course.NewStateFromSnapshotProto(snapshot)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid snapshot")
+	}
+	// Replay the journals
+	journals, err := s.listJournals()
+	if err != nil {
+		return nil, nil, err
+	}
+	replayed := 0
+	for i, generation := range journals {
+		if generation < snapshot.Generation {
+			continue
+		}
+		err = readJournal(s.journalPath(generation), i == len(journals)-1, func(message *proto.CourseDatabaseBatchMessage) error {
+			replayed++
+			return course.ReplayMessage(courses, students, message)
+		})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot replay journal %d", generation)
+		}
+	}
+	log.WithField("created_at", time.UnixMilli(snapshot.CreatedAt)).
+		WithField("replayed", replayed).
+		Info("loaded snapshot")
+	return courses, students, nil
+}
+
+// Save takes a snapshot of courses and students and starts a new journal for the messages after it.
+// freeze must call its argument while nothing can change the courses and students or batch a message.
+func (s *Store) Save(freeze func(func()), courses *course.Courses, students map[course.StudentID]*course.Student) error {
+	var snapshot *proto.EnrollmentSnapshot
+	var err error
+	freeze(func() {
+		snapshot = course.NewSnapshotProto(courses, students)
+		err = s.rotateJournal()
+		snapshot.Generation = s.generation
+	})
+	if err != nil {
+		return err
+	}
+	snapshot.CreatedAt = time.Now().UnixMilli()
+	data, err := protobuf.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal snapshot")
+	}
+	// Write the snapshot atomically
+	if err = writeFileSync(filepath.Join(s.dir, snapshotFileName), data); err != nil {
+		return err
+	}
+	// Remove the old journals
+	journals, err := s.listJournals()
+	if err != nil {
+		return err
+	}
+	for _, generation := range journals {
+		if generation < snapshot.Generation {
+			if err = os.Remove(s.journalPath(generation)); err != nil {
+				return errors.Wrap(err, "cannot remove old journal")
+			}
+		}
+	}
+	return nil
+}
+
+// rotateJournal closes the current journal and creates the journal of the next generation. The
+// pending messages are written to the current journal at first, because they are before the snapshot.
+func (s *Store) rotateJournal() error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	_ = s.threadUnsafeSync()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal != nil {
+		_ = s.journal.Close()
+		s.journal = nil
+	}
+	journal, err := os.OpenFile(s.journalPath(s.generation+1), os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "cannot create journal")
+	}
+	s.journal = journal
+	s.generation++
+	return nil
+}
+
+// append appends a message to the journal. It's only buffered, and the syncer writes it to disk.
+func (s *Store) append(msg *proto.CourseDatabaseBatchMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal == nil {
+		return
+	}
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		s.threadUnsafeDropJournal(err)
+		return
+	}
+	s.pending = append(s.pending, encodeRecord(data)...)
+	select {
+	case s.wake <- struct{}{}:
+	default: // the syncer is already woken up
+	}
+}
+
+// Sync writes the pending messages to the journal and syncs it to disk. If it fails, the snapshot
+// is removed because it cannot be used to recover the state anymore, and nothing is journaled until
+// the next snapshot.
+func (s *Store) Sync() error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	return s.threadUnsafeSync()
+}
+
+// threadUnsafeSync is Sync without locking syncMu
+func (s *Store) threadUnsafeSync() error {
+	s.mu.Lock()
+	journal, pending := s.journal, s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	if journal == nil || len(pending) == 0 {
+		return nil
+	}
+	_, err := journal.Write(pending)
+	if err == nil {
+		err = journal.Sync()
+	}
+	if err != nil {
+		s.mu.Lock()
+		s.threadUnsafeDropJournal(err)
+		s.mu.Unlock()
+		return errors.Wrap(err, "cannot write journal")
+	}
+	return nil
+}
+
+// threadUnsafeDropJournal closes the journal after it fails and removes the snapshot. mu must be held.
+func (s *Store) threadUnsafeDropJournal(err error) {
+	log.WithError(err).Error("cannot append to journal; removing the snapshot")
+	_ = s.journal.Close()
+	s.journal = nil
+	s.pending = nil
+	if err = os.Remove(filepath.Join(s.dir, snapshotFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.WithError(err).Error("cannot remove snapshot")
+	}
+}
+
+// syncLoop syncs the pending messages whenever there are any until the store is closed. The
+// messages which are appended while a sync is running are synced together in the next one.
+func (s *Store) syncLoop() {
+	defer close(s.stopped)
+	for {
+		select {
+		case <-s.wake:
+			_ = s.Sync() // the error is logged
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Close syncs the pending messages and closes the journal. Nothing is journaled after it. The
+// dirty marker is removed only if everything is synced.
+func (s *Store) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	if err := s.threadUnsafeSync(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal != nil {
+		err := s.journal.Close()
+		s.journal = nil
+		if err != nil {
+			return errors.Wrap(err, "cannot close journal")
+		}
+	}
+	if err := os.Remove(s.dirtyPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, "cannot remove dirty marker")
+	}
+	return nil
+}
+
+// Batcher returns a batcher which batches the messages with b and then appends them to the journal
+func (s *Store) Batcher(b course.Batcher) course.Batcher {
+	return journalBatcher{store: s, next: b}
+}
+
+// journalBatcher appends every message which is batched to the journal of a store
+type journalBatcher struct {
+	store *Store
+	next  course.Batcher
+}
+
+// ProcessDatabaseQuery will batch the message and then append it to the journal. If batching fails,
+// nothing is journaled because the state is not changed.
+func (b journalBatcher) ProcessDatabaseQuery(ctx context.Context, department course.DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	if err := b.next.ProcessDatabaseQuery(ctx, department, msg); err != nil {
+		return err
+	}
+	b.store.append(msg)
+	return nil
+}
+
+// ShardOf returns the shard of a department in the wrapped batcher
+func (b journalBatcher) ShardOf(department course.DepartmentID) int {
+	return course.ShardOf(b.next, department)
+}
+
+// dirtyPath returns the path of the dirty marker
+func (s *Store) dirtyPath() string {
+	return filepath.Join(s.dir, dirtyFileName)
+}
+
+// journalPath returns the path of the journal of a generation
+func (s *Store) journalPath(generation uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%020d", journalFilePrefix, generation))
+}
+
+// listJournals returns the generations of all journals in sorted order
+func (s *Store) listJournals() ([]uint64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read directory")
+	}
+	var result []uint64
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), journalFilePrefix) {
+			continue
+		}
+		generation, err := strconv.ParseUint(strings.TrimPrefix(entry.Name(), journalFilePrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		result = append(result, generation)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
+
+// writeFileSync writes a file and syncs it to disk. The file is written in a temporary file
+// first, so it's either fully written or not changed at all.
+func writeFileSync(path string, data []byte) error {
+	tempPath := path + ".tmp"
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return errors.Wrap(err, "cannot create file")
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return errors.Wrap(err, "cannot write file")
+	}
+	if err = os.Rename(tempPath, path); err != nil {
+		return errors.Wrap(err, "cannot rename file")
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package snapshot
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// noOpBatcher accepts every message
+type noOpBatcher struct{}
+
+func (noOpBatcher) ProcessDatabaseQuery(context.Context, course.DepartmentID, *proto.CourseDatabaseBatchMessage) error {
+	return nil
+}
+
+// errorBatcher rejects every message
+type errorBatcher struct{}
+
+func (errorBatcher) ProcessDatabaseQuery(context.Context, course.DepartmentID, *proto.CourseDatabaseBatchMessage) error {
+	return errors.New("broker is down")
+}
+
+// noFreeze calls the function directly
+func noFreeze(f func()) {
+	f()
+}
+
+// newTestState creates a course with capacity of one and two students
+func newTestState() (*course.Courses, map[course.StudentID]*course.Student) {
+	courses := course.NewCourses(map[course.CourseID][]*course.Course{1: {{
+		ID:                 1,
+		GroupID:            1,
+		Units:              3,
+		Capacity:           1,
+		RegisteredStudents: make(map[course.StudentID]struct{}),
+		ReserveCapacity:    1,
+		ReserveQueue:       util.NewQueue[course.StudentID](),
+	}}})
+	students := map[course.StudentID]*course.Student{
+		1: {ID: 1, MaxUnits: 20, RemainingActions: 3, RegisteredCourses: make(map[course.CourseID]course.GroupID)},
+		2: {ID: 2, MaxUnits: 20, RemainingActions: 3, RegisteredCourses: make(map[course.CourseID]course.GroupID)},
+	}
+	return courses, students
+}
+
+// enroll batches a message which enrolls a student in the test course
+func enroll(t *testing.T, b course.Batcher, courses *course.Courses, students map[course.StudentID]*course.Student, id course.StudentID) {
+	t.Helper()
+	c := courses.GetCourse(1, 1)
+	registered, err := c.EnrollStudent(context.Background(), id, b)
+	require.NoError(t, err)
+	require.True(t, registered)
+	students[id].RegisteredCourses[1] = 1
+	students[id].RegisteredUnits += c.Units
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	require.NoError(t, err)
+	_, _, err = store.Load()
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+	courses, students := newTestState()
+	// Messages before the first snapshot are not journaled
+	b := store.Batcher(noOpBatcher{})
+	enroll(t, b, courses, students, 1)
+	require.NoError(t, store.Save(noFreeze, courses, students))
+	enroll(t, b, courses, students, 2)
+	// Failed messages are not journaled
+	assert.Error(t, store.Batcher(errorBatcher{}).ProcessDatabaseQuery(context.Background(), 0, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_Disenroll{Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: 1, CourseId: 1}},
+	}))
+	require.NoError(t, store.Close())
+	// Load it again
+	store, err = Open(dir)
+	require.NoError(t, err)
+	loadedCourses, loadedStudents, err := store.Load()
+	require.NoError(t, err)
+	c := loadedCourses.GetCourse(1, 1)
+	assert.Equal(t, map[course.StudentID]struct{}{1: {}}, c.RegisteredStudents)
+	assert.Equal(t, []course.StudentID{2}, c.ReserveQueue.CopyAsArray())
+	assert.Equal(t, map[course.CourseID]course.GroupID{1: 1}, loadedStudents[2].RegisteredCourses)
+	assert.Equal(t, uint8(3), loadedStudents[2].RegisteredUnits)
+	// Saving removes the old journals
+	require.NoError(t, store.Save(noFreeze, loadedCourses, loadedStudents))
+	journals, err := store.listJournals()
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2}, journals)
+	require.NoError(t, store.Close())
+}
+
+func TestStoreTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	require.NoError(t, err)
+	courses, students := newTestState()
+	require.NoError(t, store.Save(noFreeze, courses, students))
+	enroll(t, store.Batcher(noOpBatcher{}), courses, students, 1)
+	require.NoError(t, store.Close())
+	// Write half of a record at the end
+	journal, err := os.OpenFile(store.journalPath(1), os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = journal.Write(encodeRecord([]byte("message"))[:10])
+	require.NoError(t, err)
+	require.NoError(t, journal.Close())
+	loadedCourses, _, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[course.StudentID]struct{}{1: {}}, loadedCourses.GetCourse(1, 1).RegisteredStudents)
+	// A broken record in an older journal is not ignored
+	store, err = Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.rotateJournal())
+	require.NoError(t, store.Close())
+	_, _, err = store.Load()
+	assert.Error(t, err)
+}
+
+func TestStoreFailedAppend(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	require.NoError(t, err)
+	courses, students := newTestState()
+	require.NoError(t, store.Save(noFreeze, courses, students))
+	// Close the journal under the store to make the writes fail
+	_ = store.journal.Close()
+	enroll(t, store.Batcher(noOpBatcher{}), courses, students, 1)
+	// The write fails either in the syncer or here
+	_ = store.Sync()
+	assert.Nil(t, store.journal)
+	_, err = os.Stat(filepath.Join(dir, snapshotFileName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, _, err = store.Load()
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+	// The next snapshot starts journaling again
+	require.NoError(t, store.Save(noFreeze, courses, students))
+	assert.NotNil(t, store.journal)
+	require.NoError(t, store.Close())
+}
+
+func TestStoreGroupCommit(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	require.NoError(t, err)
+	courses, students := newTestState()
+	require.NoError(t, store.Save(noFreeze, courses, students))
+	// Append from many goroutines while the syncer is writing
+	b := store.Batcher(noOpBatcher{})
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, b.ProcessDatabaseQuery(context.Background(), 0, &proto.CourseDatabaseBatchMessage{
+				Action: &proto.CourseDatabaseBatchMessage_Disenroll{Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: 1, CourseId: 1}},
+			}))
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, store.Sync())
+	count := 0
+	require.NoError(t, readJournal(store.journalPath(1), true, func(*proto.CourseDatabaseBatchMessage) error {
+		count++
+		return nil
+	}))
+	assert.Equal(t, 100, count)
+	require.NoError(t, store.Close())
+}
+
+func TestStoreUncleanShutdown(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	require.NoError(t, err)
+	courses, students := newTestState()
+	require.NoError(t, store.Save(noFreeze, courses, students))
+	enroll(t, store.Batcher(noOpBatcher{}), courses, students, 1)
+	// Crash without closing the store
+	close(store.done)
+	<-store.stopped
+	_ = store.journal.Close()
+	store, err = Open(dir)
+	require.NoError(t, err)
+	_, _, err = store.Load()
+	assert.ErrorIs(t, err, ErrUncleanShutdown)
+	// The state is loaded from the database and saved again, then it can be loaded after a clean close
+	require.NoError(t, store.Save(noFreeze, courses, students))
+	require.NoError(t, store.Close())
+	store, err = Open(dir)
+	require.NoError(t, err)
+	loadedCourses, _, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[course.StudentID]struct{}{1: {}}, loadedCourses.GetCourse(1, 1).RegisteredStudents)
+	require.NoError(t, store.Close())
+}
@@ -0,0 +1,62 @@
+package broker
+
+import "sync"
+
+// ackWindow tracks the messages of a shard which are delivered but not acknowledged yet.
+// It limits the number of unacknowledged messages and finds the longest prefix of acknowledged
+// messages, which is what the log based backends can commit.
+type ackWindow[P any] struct {
+	// Limits the number of unacknowledged messages
+	semaphore chan struct{}
+	// The unacknowledged messages in the order of delivery
+	pending []*ackEntry[P]
+	mu      sync.Mutex
+}
+
+// ackEntry is a single delivered message in an ackWindow
+type ackEntry[P any] struct {
+	// The position which must be committed once this message and everything before it is acknowledged
+	position P
+	done     bool
+}
+
+// newAckWindow creates an ackWindow which allows at most prefetch unacknowledged messages
+func newAckWindow[P any](prefetch int) *ackWindow[P] {
+	return &ackWindow[P]{semaphore: make(chan struct{}, max(prefetch, 1))}
+}
+
+// add waits until a new message can be delivered and adds it to the window.
+// Returns nil if stop is closed before that.
+func (w *ackWindow[P]) add(stop <-chan struct{}, position P) *ackEntry[P] {
+	select {
+	case w.semaphore <- struct{}{}:
+	case <-stop:
+		return nil
+	}
+	entry := &ackEntry[P]{position: position}
+	w.mu.Lock()
+	w.pending = append(w.pending, entry)
+	w.mu.Unlock()
+	return entry
+}
+
+// ack marks a message as acknowledged. If the acknowledged prefix grows, commit is called with the position
+// of the last message of it. Calls of commit are serialized and their positions only move forward.
+func (w *ackWindow[P]) ack(entry *ackEntry[P], commit func(P) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if entry.done {
+		return nil
+	}
+	entry.done = true
+	<-w.semaphore
+	var last *ackEntry[P]
+	for len(w.pending) != 0 && w.pending[0].done {
+		last = w.pending[0]
+		w.pending = w.pending[1:]
+	}
+	if last == nil {
+		return nil
+	}
+	return commit(last.position)
+}
@@ -0,0 +1,148 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"fmt"
+	protobuf "google.golang.org/protobuf/proto"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Broker is a message broker which the enrollment server publishes the database queries in and
+// the batcher consumes them from.
+type Broker interface {
+	// Every broker can batch the queries of the enrollment server, and knows the shard of each
+	// department
+	course.ShardedBatcher
+	// Consume must consume the messages which are received on the queues of the given shards.
+	// The order of messages of each shard must be kept. Each Delivery must be acknowledged after it
+	// has been processed. At most prefetch messages of each shard are unacknowledged at any time.
+	Consume(consumer string, prefetch int, shards []int) (<-chan Delivery, error)
+	// CancelConsumer must stop a consumer. The channel returned from Consume is closed after that.
+	CancelConsumer(consumer string, shards []int) error
+	// DeadLetter must move a delivery to the dead letter queue of its shard with the reason and
+	// the number of attempts attached to it. The delivery is acknowledged after that.
+	DeadLetter(ctx context.Context, d Delivery, reason error, attempts int) error
+	// ListDeadLetters must return all the dead letters of shards without removing them
+	ListDeadLetters(shards []int) ([]DeadLetter, error)
+	// RequeueDeadLetters must move the dead letters of shards which the filter returns true for back
+	// to the queue of their shard. Returns the number of requeued messages.
+	RequeueDeadLetters(ctx context.Context, shards []int, filter func(DeadLetter) bool) (int, error)
+	// Close must close the connection to the broker
+	Close() error
+}
+
+// Factory creates a Broker based on its address and the queue layout
+type Factory func(address string, layout QueueLayout) (Broker, error)
+
+// factories is the registry of all broker backends
+var factories = struct {
+	m  map[string]Factory
+	mu sync.RWMutex
+}{m: make(map[string]Factory)}
+
+// Register adds a broker backend to the registry. It panics if a backend with the same name exists.
+func Register(name string, factory Factory) {
+	factories.mu.Lock()
+	defer factories.mu.Unlock()
+	if _, exists := factories.m[name]; exists {
+		panic("duplicate broker backend " + name)
+	}
+	factories.m[name] = factory
+}
+
+// Open creates a broker with a registered backend
+func Open(name, address string, layout QueueLayout) (Broker, error) {
+	factories.mu.RLock()
+	factory, exists := factories.m[name]
+	factories.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown broker backend %q; available backends are %v", name, Backends())
+	}
+	return factory(address, layout)
+}
+
+// Backends returns the name of all registered backends in sorted order
+func Backends() []string {
+	factories.mu.RLock()
+	defer factories.mu.RUnlock()
+	result := make([]string, 0, len(factories.m))
+	for name := range factories.m {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Delivery is a parsed message which is received from the broker.
+// Each delivery must be acknowledged with Ack or Nack once it has been processed.
+type Delivery struct {
+	// The parsed message
+	Message *proto.CourseDatabaseBatchMessage
+	// The shard which this message is received from
+	Shard int
+	// The raw body of the message
+	Body         []byte
+	acknowledger acknowledger
+}
+
+// acknowledger acknowledges a single message in its backend
+type acknowledger interface {
+	Ack() error
+	Nack(requeue bool) error
+}
+
+// Ack tells the broker that this message has been processed and can be removed from the queue
+func (d Delivery) Ack() error {
+	return d.acknowledger.Ack()
+}
+
+// Nack tells the broker that this message could not be processed. If requeue is true,
+// the message is delivered again.
+func (d Delivery) Nack(requeue bool) error {
+	return d.acknowledger.Nack(requeue)
+}
+
+// Names of the headers which are attached to dead letters
+const (
+	deadLetterErrorHeader    = "x-error"
+	deadLetterAttemptsHeader = "x-attempts"
+)
+
+// DeadLetter is a message which could not be processed and is stored in the dead letter queue
+type DeadLetter struct {
+	// The shard which this message was received from
+	Shard int
+	// The operation ID of the message. Might be empty for old or unparseable messages.
+	OperationID string
+	// The parsed message. It's nil if the message could not be parsed.
+	Message *proto.CourseDatabaseBatchMessage
+	// The raw body of the message
+	Body []byte
+	// The last error which happened while processing the message
+	Error string
+	// Number of times which we tried to apply this message
+	Attempts int32
+	// When was this message dead lettered
+	FailedAt time.Time
+}
+
+// deadLetterQueueName returns the name of the dead letter queue of a queue
+func deadLetterQueueName(queueName string) string {
+	return queueName + "-dead-letters"
+}
+
+// parseDeadLetterBody parses the body of a dead letter and fills its message.
+// If the dead letter does not have an operation ID, the operation ID of the message is used.
+func parseDeadLetterBody(letter *DeadLetter) {
+	message := new(proto.CourseDatabaseBatchMessage)
+	if protobuf.Unmarshal(letter.Body, message) == nil {
+		letter.Message = message
+		if letter.OperationID == "" {
+			letter.OperationID = message.OperationId
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"fmt"
+	"github.com/go-faster/errors"
+	"github.com/segmentio/kafka-go"
+	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// kafkaBatchTimeout is the time which the writer waits for more messages before sending a batch
+const kafkaBatchTimeout = 5 * time.Millisecond
+
+func init() {
+	Register("kafka", func(address string, layout QueueLayout) (Broker, error) {
+		return NewKafkaBroker(address, layout)
+	})
+}
+
+// KafkaBroker uses a Kafka cluster as the broker. Each shard is a topic with a single partition
+// to keep the order of messages. The consumers use consumer groups to store their offsets.
+type KafkaBroker struct {
+	brokers []string
+	layout  QueueLayout
+	writer  *kafka.Writer
+	// Active readers by their shard consumer name
+	readers map[string]*kafkaConsumer
+	mu      sync.Mutex
+}
+
+// NewKafkaBroker connects to a Kafka cluster and creates the topics of the layout alongside
+// their dead letter topics. The address is a comma separated list of brokers.
+func NewKafkaBroker(address string, layout QueueLayout) (*KafkaBroker, error) {
+	var brokers []string
+	for _, broker := range strings.Split(address, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, errors.New("empty broker list")
+	}
+	// Create the topics
+	var topics []kafka.TopicConfig
+	for _, shard := range layout.AllShards() {
+		for _, topic := range []string{layout.QueueName(shard), deadLetterQueueName(layout.QueueName(shard))} {
+			topics = append(topics, kafka.TopicConfig{Topic: topic, NumPartitions: 1, ReplicationFactor: -1})
+		}
+	}
+	client := &kafka.Client{Addr: kafka.TCP(brokers...), Timeout: 10 * time.Second}
+	response, err := client.CreateTopics(context.Background(), &kafka.CreateTopicsRequest{Topics: topics})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create topics")
+	}
+	for topic, err := range response.Errors {
+		if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+			return nil, errors.Wrapf(err, "cannot create topic %s", topic)
+		}
+	}
+	return &KafkaBroker{
+		brokers: brokers,
+		layout:  layout,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: kafkaBatchTimeout,
+		},
+		readers: make(map[string]*kafkaConsumer),
+	}, nil
+}
+
+// ShardOf returns the shard of a department in the queue layout
+func (b *KafkaBroker) ShardOf(department course.DepartmentID) int {
+	return b.layout.ShardOf(department)
+}
+
+// ProcessDatabaseQuery will publish a database query in the topic of its department.
+// If the message does not have an operation ID, a new one is assigned to it.
+func (b *KafkaBroker) ProcessDatabaseQuery(ctx context.Context, department course.DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	if msg.OperationId == "" {
+		msg.OperationId = util.NewOperationID()
+	}
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal")
+	}
+	return b.writer.WriteMessages(ctx, kafka.Message{
+		Topic: b.layout.QueueName(b.layout.ShardOf(department)),
+		Key:   []byte(msg.OperationId),
+		Value: data,
+	})
+}
+
+// Consume will consume the topics of the given shards. Offsets are committed in the consumer group
+// of each shard once all the messages before them are acknowledged.
+func (b *KafkaBroker) Consume(consumer string, prefetch int, shards []int) (<-chan Delivery, error) {
+	messageChannel := make(chan Delivery)
+	var wg sync.WaitGroup
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		name := shardConsumerName(consumer, shard)
+		if _, exists := b.readers[name]; exists {
+			return nil, fmt.Errorf("consumer %s already exists", name)
+		}
+		c := &kafkaConsumer{
+			broker: b,
+			shard:  shard,
+			reader: kafka.NewReader(kafka.ReaderConfig{
+				Brokers:     b.brokers,
+				GroupID:     name,
+				Topic:       b.layout.QueueName(shard),
+				StartOffset: kafka.FirstOffset,
+			}),
+			window: newAckWindow[kafka.Message](prefetch),
+			stop:   make(chan struct{}),
+		}
+		b.readers[name] = c
+		wg.Add(1)
+		go func() {
+			c.run(messageChannel)
+			wg.Done()
+		}()
+	}
+	// When all consumers are stopped, also close the outgoing channel
+	go func() {
+		wg.Wait()
+		close(messageChannel)
+	}()
+	return messageChannel, nil
+}
+
+// CancelConsumer stops the readers of a consumer
+func (b *KafkaBroker) CancelConsumer(consumer string, shards []int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		if c, exists := b.readers[shardConsumerName(consumer, shard)]; exists {
+			c.cancel()
+		}
+	}
+	return nil
+}
+
+// Close stops all readers and closes the writer
+func (b *KafkaBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var result error
+	for name, c := range b.readers {
+		c.cancel()
+		if err := c.reader.Close(); err != nil {
+			result = err
+		}
+		delete(b.readers, name)
+	}
+	if err := b.writer.Close(); err != nil {
+		result = err
+	}
+	return result
+}
+
+// kafkaConsumer reads the topic of a single shard
+type kafkaConsumer struct {
+	broker *KafkaBroker
+	shard  int
+	reader *kafka.Reader
+	window *ackWindow[kafka.Message]
+	// Closed when the consumer must stop reading
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// run reads the messages and sends them to out until the consumer is canceled
+func (c *kafkaConsumer) run(out chan<- Delivery) {
+	// Cancel the fetches once the consumer is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+	defer cancel()
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.WithError(err).WithField("topic", c.reader.Config().Topic).Error("cannot fetch message")
+			}
+			return
+		}
+		entry := c.window.add(c.stop, message)
+		if entry == nil {
+			return
+		}
+		acker := kafkaAcknowledger{consumer: c, entry: entry, message: message}
+		parsedMessage := new(proto.CourseDatabaseBatchMessage)
+		err = protobuf.Unmarshal(message.Value, parsedMessage)
+		if err != nil {
+			log.WithError(err).Warn("cannot parse proto buffer message")
+			err = c.broker.publishDeadLetter(ctx, c.shard, string(message.Key), message.Value, errors.Wrap(err, "cannot parse message"), 0)
+			if err != nil {
+				log.WithError(err).Error("cannot dead letter unparseable message")
+				_ = acker.Nack(true)
+			} else {
+				_ = acker.Ack()
+			}
+			continue
+		}
+		select {
+		case out <- Delivery{Message: parsedMessage, Shard: c.shard, Body: message.Value, acknowledger: acker}:
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// cancel stops reading new messages. The offsets of the messages which are acknowledged
+// after this are still committed.
+func (c *kafkaConsumer) cancel() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// kafkaAcknowledger acknowledges a message of a kafkaConsumer
+type kafkaAcknowledger struct {
+	consumer *kafkaConsumer
+	entry    *ackEntry[kafka.Message]
+	message  kafka.Message
+}
+
+func (a kafkaAcknowledger) Ack() error {
+	return a.consumer.window.ack(a.entry, func(message kafka.Message) error {
+		return a.consumer.reader.CommitMessages(context.Background(), message)
+	})
+}
+
+// Nack publishes the message at the end of its topic again if requeue is true
+func (a kafkaAcknowledger) Nack(requeue bool) error {
+	if requeue {
+		err := a.consumer.broker.writer.WriteMessages(context.Background(), kafka.Message{
+			Topic: a.message.Topic,
+			Key:   a.message.Key,
+			Value: a.message.Value,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot requeue message")
+		}
+	}
+	return a.Ack()
+}
+
+// kafkaRequeuedHeader marks a record in the dead letter topic which says that the dead letter
+// at the offset in its value is requeued. Kafka cannot remove single records from a topic.
+const kafkaRequeuedHeader = "x-requeued"
+
+// DeadLetter will publish a delivery in the dead letter topic of its shard with the reason and the number
+// of attempts attached to it. The delivery is acknowledged after it has been published.
+func (b *KafkaBroker) DeadLetter(ctx context.Context, d Delivery, reason error, attempts int) error {
+	err := b.publishDeadLetter(ctx, d.Shard, d.Message.OperationId, d.Body, reason, attempts)
+	if err != nil {
+		return err
+	}
+	return d.Ack()
+}
+
+// publishDeadLetter publishes a raw message in the dead letter topic of a shard
+func (b *KafkaBroker) publishDeadLetter(ctx context.Context, shard int, operationID string, body []byte, reason error, attempts int) error {
+	err := b.writer.WriteMessages(ctx, kafka.Message{
+		Topic: deadLetterQueueName(b.layout.QueueName(shard)),
+		Key:   []byte(operationID),
+		Value: body,
+		Headers: []kafka.Header{
+			{Key: deadLetterErrorHeader, Value: []byte(reason.Error())},
+			{Key: deadLetterAttemptsHeader, Value: []byte(strconv.Itoa(attempts))},
+		},
+		Time: time.Now(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot publish dead letter")
+	}
+	return nil
+}
+
+// ListDeadLetters returns all the messages in the dead letter topics of shards which are not requeued.
+func (b *KafkaBroker) ListDeadLetters(shards []int) ([]DeadLetter, error) {
+	var result []DeadLetter
+	for _, shard := range shards {
+		letters, err := b.readDeadLetters(shard)
+		if err != nil {
+			return result, err
+		}
+		for _, letter := range letters {
+			result = append(result, letter.DeadLetter)
+		}
+	}
+	return result, nil
+}
+
+// RequeueDeadLetters publishes the dead letters of shards which the filter returns true for at the end of
+// the topic of their shard. Requeued messages are marked in the dead letter topic so they are not listed anymore.
+// Returns the number of requeued messages.
+func (b *KafkaBroker) RequeueDeadLetters(ctx context.Context, shards []int, filter func(DeadLetter) bool) (int, error) {
+	requeued := 0
+	for _, shard := range shards {
+		letters, err := b.readDeadLetters(shard)
+		if err != nil {
+			return requeued, err
+		}
+		for _, letter := range letters {
+			if !filter(letter.DeadLetter) {
+				continue
+			}
+			err = b.writer.WriteMessages(ctx, kafka.Message{
+				Topic: b.layout.QueueName(shard),
+				Key:   []byte(letter.OperationID),
+				Value: letter.Body,
+			})
+			if err != nil {
+				return requeued, errors.Wrap(err, "cannot requeue message")
+			}
+			err = b.writer.WriteMessages(ctx, kafka.Message{
+				Topic:   deadLetterQueueName(b.layout.QueueName(shard)),
+				Key:     []byte(letter.OperationID),
+				Value:   []byte(strconv.FormatInt(letter.offset, 10)),
+				Headers: []kafka.Header{{Key: kafkaRequeuedHeader, Value: []byte("true")}},
+			})
+			if err != nil {
+				return requeued, errors.Wrap(err, "cannot mark dead letter as requeued")
+			}
+			requeued++
+		}
+	}
+	return requeued, nil
+}
+
+// kafkaDeadLetter is a DeadLetter with its offset in the dead letter topic
+type kafkaDeadLetter struct {
+	DeadLetter
+	offset int64
+}
+
+// readDeadLetters reads the dead letter topic of a shard from start to end and returns the
+// dead letters which are not requeued
+func (b *KafkaBroker) readDeadLetters(shard int) ([]kafkaDeadLetter, error) {
+	topic := deadLetterQueueName(b.layout.QueueName(shard))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	// Find the end of topic
+	conn, err := kafka.DialLeader(ctx, "tcp", b.brokers[0], topic, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot connect to partition leader")
+	}
+	first, last, err := conn.ReadOffsets()
+	_ = conn.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read offsets")
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   b.brokers,
+		Topic:     topic,
+		Partition: 0,
+	})
+	defer reader.Close()
+	if err = reader.SetOffset(first); err != nil {
+		return nil, errors.Wrap(err, "cannot seek")
+	}
+	var letters []kafkaDeadLetter
+	requeued := make(map[int64]bool)
+	for offset := first; offset < last; {
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read dead letter")
+		}
+		offset = message.Offset + 1
+		headers := make(map[string]string, len(message.Headers))
+		for _, header := range message.Headers {
+			headers[header.Key] = string(header.Value)
+		}
+		if _, marker := headers[kafkaRequeuedHeader]; marker {
+			requeuedOffset, err := strconv.ParseInt(string(message.Value), 10, 64)
+			if err == nil {
+				requeued[requeuedOffset] = true
+			}
+			continue
+		}
+		attempts, _ := strconv.ParseInt(headers[deadLetterAttemptsHeader], 10, 32)
+		letter := kafkaDeadLetter{
+			DeadLetter: DeadLetter{
+				Shard:       shard,
+				OperationID: string(message.Key),
+				Body:        message.Value,
+				Error:       headers[deadLetterErrorHeader],
+				Attempts:    int32(attempts),
+				FailedAt:    message.Time,
+			},
+			offset: message.Offset,
+		}
+		parseDeadLetterBody(&letter.DeadLetter)
+		letters = append(letters, letter)
+	}
+	// Remove the requeued letters
+	result := letters[:0]
+	for _, letter := range letters {
+		if !requeued[letter.offset] {
+			result = append(result, letter)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"fmt"
+	"github.com/go-faster/errors"
+	"strconv"
+	"strings"
+)
+
+// QueueLayout describes how the messages are distributed between the queues.
+//
+// Messages are published to a topic exchange with the routing key of their department.
+// Each shard is a queue which is bound to a list of departments. Shard 0 is the default
+// queue which receives the messages of every department which is not in any other shard.
+type QueueLayout struct {
+	// The name which exchanges and queues are named after it
+	Name string
+	// Shards[i] contains the departments of shard i+1
+	Shards [][]course.DepartmentID
+}
+
+// ParseQueueLayout parses the shards of a layout. Shards are separated by semicolons and the departments of
+// each shard are separated by commas. A range of departments can be written as "from-to". For example,
+// "1,2;3-5" creates two shards; one for departments 1 and 2 and one for departments 3, 4 and 5.
+// An empty string creates a layout which only has the default queue.
+func ParseQueueLayout(name, shards string) (QueueLayout, error) {
+	layout := QueueLayout{Name: name}
+	if strings.TrimSpace(shards) == "" {
+		return layout, nil
+	}
+	seen := make(map[course.DepartmentID]int)
+	for i, shard := range strings.Split(shards, ";") {
+		var departments []course.DepartmentID
+		for _, part := range strings.Split(shard, ",") {
+			from, to, err := parseDepartmentRange(strings.TrimSpace(part))
+			if err != nil {
+				return QueueLayout{}, errors.Wrapf(err, "invalid shard %d", i+1)
+			}
+			for department := from; department <= to; department++ {
+				if previousShard, exists := seen[course.DepartmentID(department)]; exists {
+					return QueueLayout{}, fmt.Errorf("department %d is in shards %d and %d", department, previousShard, i+1)
+				}
+				seen[course.DepartmentID(department)] = i + 1
+				departments = append(departments, course.DepartmentID(department))
+			}
+		}
+		layout.Shards = append(layout.Shards, departments)
+	}
+	return layout, nil
+}
+
+// parseDepartmentRange parses a single department or a range of departments
+func parseDepartmentRange(s string) (int, int, error) {
+	fromString, toString, isRange := strings.Cut(s, "-")
+	from, err := strconv.ParseUint(fromString, 10, 8)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid department %q", fromString)
+	}
+	if !isRange {
+		return int(from), int(from), nil
+	}
+	to, err := strconv.ParseUint(toString, 10, 8)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid department %q", toString)
+	}
+	if to < from {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	return int(from), int(to), nil
+}
+
+// ParseShards parses a comma separated list of shard indexes of this layout.
+// An empty string means all shards.
+func (l QueueLayout) ParseShards(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return l.AllShards(), nil
+	}
+	var result []int
+	for _, part := range strings.Split(s, ",") {
+		shard, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid shard %q", part)
+		}
+		if shard < 0 || shard > len(l.Shards) {
+			return nil, fmt.Errorf("shard %d does not exist", shard)
+		}
+		result = append(result, shard)
+	}
+	return result, nil
+}
+
+// AllShards returns the indexes of all shards including the default one
+func (l QueueLayout) AllShards() []int {
+	result := make([]int, len(l.Shards)+1)
+	for i := range result {
+		result[i] = i
+	}
+	return result
+}
+
+// ShardOf returns the shard which the messages of a department go to
+func (l QueueLayout) ShardOf(department course.DepartmentID) int {
+	for i, departments := range l.Shards {
+		for _, d := range departments {
+			if d == department {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// QueueName returns the queue name of a shard
+func (l QueueLayout) QueueName(shard int) string {
+	if shard == 0 {
+		return l.Name
+	}
+	return l.Name + "-shard-" + strconv.Itoa(shard)
+}
+
+// exchangeName is the name of the topic exchange which messages are published to
+func (l QueueLayout) exchangeName() string {
+	return l.Name + "-exchange"
+}
+
+// unroutedExchangeName is the name of the alternate exchange which receives the messages
+// that are not routed to any shard. It forwards them to the default queue.
+func (l QueueLayout) unroutedExchangeName() string {
+	return l.Name + "-unrouted"
+}
+
+// departmentRoutingKey returns the routing key of messages of a department
+func departmentRoutingKey(department course.DepartmentID) string {
+	return "department." + strconv.Itoa(int(department))
+}
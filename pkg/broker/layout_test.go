@@ -0,0 +1,81 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseQueueLayout(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Shards         string
+		ExpectedShards [][]course.DepartmentID
+		ExpectedError  bool
+	}{
+		{
+			Name:           "empty",
+			Shards:         "",
+			ExpectedShards: nil,
+		},
+		{
+			Name:           "single shard",
+			Shards:         "1,2",
+			ExpectedShards: [][]course.DepartmentID{{1, 2}},
+		},
+		{
+			Name:           "ranges",
+			Shards:         "1, 2; 3-5;7",
+			ExpectedShards: [][]course.DepartmentID{{1, 2}, {3, 4, 5}, {7}},
+		},
+		{
+			Name:          "overlap",
+			Shards:        "1-3;3",
+			ExpectedError: true,
+		},
+		{
+			Name:          "invalid range",
+			Shards:        "5-3",
+			ExpectedError: true,
+		},
+		{
+			Name:          "out of range department",
+			Shards:        "256",
+			ExpectedError: true,
+		},
+		{
+			Name:          "empty department",
+			Shards:        "1;;2",
+			ExpectedError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			layout, err := ParseQueueLayout("queue", test.Shards)
+			if test.ExpectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "queue", layout.Name)
+			assert.Equal(t, test.ExpectedShards, layout.Shards)
+		})
+	}
+}
+
+func TestQueueLayoutShards(t *testing.T) {
+	assertion := assert.New(t)
+	layout, err := ParseQueueLayout("queue", "1;2;3")
+	assertion.NoError(err)
+	assertion.Equal([]int{0, 1, 2, 3}, layout.AllShards())
+	shards, err := layout.ParseShards("")
+	assertion.NoError(err)
+	assertion.Equal([]int{0, 1, 2, 3}, shards)
+	shards, err = layout.ParseShards("0, 2")
+	assertion.NoError(err)
+	assertion.Equal([]int{0, 2}, shards)
+	_, err = layout.ParseShards("4")
+	assertion.Error(err)
+	assertion.Equal("queue", layout.QueueName(0))
+	assertion.Equal("queue-shard-2", layout.QueueName(2))
+}
@@ -0,0 +1,279 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"fmt"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
+	"sync"
+	"time"
+)
+
+// MemoryBroker is an in-process broker which keeps the messages of each shard in memory.
+// It's only useful when the enrollment server and the batcher live in the same process,
+// for example in tests. Nothing survives a restart.
+type MemoryBroker struct {
+	layout QueueLayout
+	// The messages of each shard which are not delivered yet
+	queues [][][]byte
+	// Number of delivered but unacknowledged messages of each shard
+	inFlight []int
+	// Number of published messages which are not acknowledged yet
+	pending     int
+	deadLetters [][]DeadLetter
+	// The active consumers by their shard consumer name
+	consumers map[string]*memoryConsumer
+	mu        sync.Mutex
+	// Signaled whenever any of the fields above change
+	cond *sync.Cond
+}
+
+// memoryConsumer is a consumer of a single shard of MemoryBroker
+type memoryConsumer struct {
+	// Closed when the consumer must stop
+	stop    chan struct{}
+	stopped bool
+}
+
+// NewMemoryBroker creates an empty in-memory broker with the given layout
+func NewMemoryBroker(layout QueueLayout) *MemoryBroker {
+	shards := len(layout.AllShards())
+	b := &MemoryBroker{
+		layout:      layout,
+		queues:      make([][][]byte, shards),
+		inFlight:    make([]int, shards),
+		deadLetters: make([][]DeadLetter, shards),
+		consumers:   make(map[string]*memoryConsumer),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// ShardOf returns the shard of a department in the queue layout
+func (b *MemoryBroker) ShardOf(department course.DepartmentID) int {
+	return b.layout.ShardOf(department)
+}
+
+// ProcessDatabaseQuery will put a database query in the queue of its department.
+// If the message does not have an operation ID, a new one is assigned to it.
+func (b *MemoryBroker) ProcessDatabaseQuery(_ context.Context, department course.DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	if msg.OperationId == "" {
+		msg.OperationId = util.NewOperationID()
+	}
+	// Marshal the message to not share it with the publisher
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal")
+	}
+	shard := b.layout.ShardOf(department)
+	b.mu.Lock()
+	b.queues[shard] = append(b.queues[shard], data)
+	b.pending++
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	return nil
+}
+
+// Consume will consume the queues of the given shards
+func (b *MemoryBroker) Consume(consumer string, prefetch int, shards []int) (<-chan Delivery, error) {
+	messageChannel := make(chan Delivery)
+	var wg sync.WaitGroup
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		name := shardConsumerName(consumer, shard)
+		if _, exists := b.consumers[name]; exists {
+			return nil, fmt.Errorf("consumer %s already exists", name)
+		}
+		c := &memoryConsumer{stop: make(chan struct{})}
+		b.consumers[name] = c
+		wg.Add(1)
+		go func(shard int) {
+			b.receiveMessages(c, shard, max(prefetch, 1), messageChannel)
+			wg.Done()
+		}(shard)
+	}
+	// When all consumers are stopped, also close the outgoing channel
+	go func() {
+		wg.Wait()
+		close(messageChannel)
+	}()
+	return messageChannel, nil
+}
+
+// receiveMessages sends the messages of a shard to outgoing until the consumer is stopped
+func (b *MemoryBroker) receiveMessages(c *memoryConsumer, shard, prefetch int, outgoing chan<- Delivery) {
+	for {
+		// Wait for a message
+		b.mu.Lock()
+		for !c.stopped && (len(b.queues[shard]) == 0 || b.inFlight[shard] >= prefetch) {
+			b.cond.Wait()
+		}
+		if c.stopped {
+			b.mu.Unlock()
+			return
+		}
+		body := b.queues[shard][0]
+		b.queues[shard] = b.queues[shard][1:]
+		b.inFlight[shard]++
+		b.mu.Unlock()
+		// Parse and send it
+		acker := &memoryAcknowledger{broker: b, shard: shard, body: body}
+		message := new(proto.CourseDatabaseBatchMessage)
+		if err := protobuf.Unmarshal(body, message); err != nil {
+			log.WithError(err).Warn("cannot parse proto buffer message")
+			b.mu.Lock()
+			b.deadLetters[shard] = append(b.deadLetters[shard], DeadLetter{
+				Shard:    shard,
+				Body:     body,
+				Error:    errors.Wrap(err, "cannot parse message").Error(),
+				FailedAt: time.Now(),
+			})
+			b.mu.Unlock()
+			_ = acker.Ack()
+			continue
+		}
+		select {
+		case outgoing <- Delivery{Message: message, Shard: shard, Body: body, acknowledger: acker}:
+		case <-c.stop:
+			// Put it back
+			_ = acker.Nack(true)
+			return
+		}
+	}
+}
+
+// CancelConsumer stops the consumers of the given shards
+func (b *MemoryBroker) CancelConsumer(consumer string, shards []int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		name := shardConsumerName(consumer, shard)
+		if c, exists := b.consumers[name]; exists {
+			c.stopped = true
+			close(c.stop)
+			delete(b.consumers, name)
+		}
+	}
+	b.cond.Broadcast()
+	return nil
+}
+
+// Close stops all consumers. Messages which are not acknowledged are dropped.
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for name, c := range b.consumers {
+		c.stopped = true
+		close(c.stop)
+		delete(b.consumers, name)
+	}
+	b.cond.Broadcast()
+	return nil
+}
+
+// Wait blocks until every published message is acknowledged or dead lettered
+func (b *MemoryBroker) Wait(ctx context.Context) error {
+	// Wake up the waiter if the context is done
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	})
+	defer stop()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.pending != 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b.cond.Wait()
+	}
+	return nil
+}
+
+// DeadLetter will put a delivery in the dead letters of its shard and acknowledges it
+func (b *MemoryBroker) DeadLetter(_ context.Context, d Delivery, reason error, attempts int) error {
+	b.mu.Lock()
+	b.deadLetters[d.Shard] = append(b.deadLetters[d.Shard], DeadLetter{
+		Shard:       d.Shard,
+		OperationID: d.Message.OperationId,
+		Message:     d.Message,
+		Body:        d.Body,
+		Error:       reason.Error(),
+		Attempts:    int32(attempts),
+		FailedAt:    time.Now(),
+	})
+	b.mu.Unlock()
+	return d.Ack()
+}
+
+// ListDeadLetters returns all the dead letters of shards
+func (b *MemoryBroker) ListDeadLetters(shards []int) ([]DeadLetter, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var result []DeadLetter
+	for _, shard := range shards {
+		result = append(result, b.deadLetters[shard]...)
+	}
+	return result, nil
+}
+
+// RequeueDeadLetters moves the dead letters of shards which the filter returns true for back to
+// the end of the queue of their shard. Returns the number of requeued messages.
+func (b *MemoryBroker) RequeueDeadLetters(_ context.Context, shards []int, filter func(DeadLetter) bool) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	requeued := 0
+	for _, shard := range shards {
+		kept := b.deadLetters[shard][:0:0]
+		for _, letter := range b.deadLetters[shard] {
+			if !filter(letter) {
+				kept = append(kept, letter)
+				continue
+			}
+			b.queues[shard] = append(b.queues[shard], letter.Body)
+			b.pending++
+			requeued++
+		}
+		b.deadLetters[shard] = kept
+	}
+	b.cond.Broadcast()
+	return requeued, nil
+}
+
+// memoryAcknowledger acknowledges a message of MemoryBroker
+type memoryAcknowledger struct {
+	broker *MemoryBroker
+	shard  int
+	body   []byte
+	// Guarded by the mutex of broker
+	done bool
+}
+
+func (a *memoryAcknowledger) Ack() error {
+	return a.Nack(false)
+}
+
+// Nack puts the message at the front of its queue if requeue is true
+func (a *memoryAcknowledger) Nack(requeue bool) error {
+	b := a.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if a.done {
+		return nil
+	}
+	a.done = true
+	b.inFlight[a.shard]--
+	if requeue {
+		b.queues[a.shard] = append([][]byte{a.body}, b.queues[a.shard]...)
+	} else {
+		b.pending--
+	}
+	b.cond.Broadcast()
+	return nil
+}
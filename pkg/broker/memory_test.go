@@ -0,0 +1,64 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	layout, err := ParseQueueLayout("queue", "1")
+	assert.NoError(t, err)
+	b := NewMemoryBroker(layout)
+	defer b.Close()
+	for _, id := range []string{"a", "b", "c"} {
+		assert.NoError(t, b.ProcessDatabaseQuery(context.Background(), 1, &proto.CourseDatabaseBatchMessage{OperationId: id}))
+	}
+	assert.NoError(t, b.ProcessDatabaseQuery(context.Background(), 2, &proto.CourseDatabaseBatchMessage{OperationId: "other"}))
+	// Nothing is acknowledged yet
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	assert.ErrorIs(t, b.Wait(ctx), context.DeadlineExceeded)
+	cancel()
+	// Only one message is delivered at a time with prefetch of one
+	messages, err := b.Consume("test", 1, []int{1})
+	assert.NoError(t, err)
+	first := receiveWAL(t, messages)
+	assert.Equal(t, "a", first.Message.OperationId)
+	select {
+	case <-messages:
+		t.Fatal("prefetch limit is not respected")
+	case <-time.After(10 * time.Millisecond):
+	}
+	// Requeued messages are delivered again in order
+	assert.NoError(t, first.Nack(true))
+	first = receiveWAL(t, messages)
+	assert.Equal(t, "a", first.Message.OperationId)
+	assert.NoError(t, first.Ack())
+	second := receiveWAL(t, messages)
+	assert.NoError(t, b.DeadLetter(context.Background(), second, errors.New("failed"), 1))
+	assert.NoError(t, receiveWAL(t, messages).Ack())
+	letters, err := b.ListDeadLetters([]int{1})
+	assert.NoError(t, err)
+	if assert.Len(t, letters, 1) {
+		assert.Equal(t, "b", letters[0].OperationID)
+		assert.Equal(t, "failed", letters[0].Error)
+	}
+	requeued, err := b.RequeueDeadLetters(context.Background(), []int{1}, func(DeadLetter) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requeued)
+	assert.NoError(t, receiveWAL(t, messages).Ack())
+	// Drain the other shard
+	otherMessages, err := b.Consume("test", 1, []int{0})
+	assert.NoError(t, err)
+	assert.NoError(t, receiveWAL(t, otherMessages).Ack())
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	assert.NoError(t, b.Wait(ctx))
+	cancel()
+	// Canceling closes the channel
+	assert.NoError(t, b.CancelConsumer("test", []int{1}))
+	_, open := <-messages
+	assert.False(t, open)
+}
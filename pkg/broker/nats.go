@@ -0,0 +1,315 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"github.com/go-faster/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// natsOperationIDHeader is the header which contains the operation ID of a dead letter
+const natsOperationIDHeader = "x-operation-id"
+
+func init() {
+	Register("nats", func(address string, layout QueueLayout) (Broker, error) {
+		return NewNATSBroker(address, layout)
+	})
+}
+
+// NATSBroker uses NATS JetStream as the broker. All the shards are stored in a single stream
+// and each shard has its own subject. Each consumer is a durable pull consumer of a shard.
+type NATSBroker struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	layout QueueLayout
+	// The message iterators of the active consumers by their shard consumer name
+	iterators map[string]jetstream.MessagesContext
+	mu        sync.Mutex
+}
+
+// NewNATSBroker connects to a NATS server and creates the stream of the layout and its dead letter stream
+func NewNATSBroker(address string, layout QueueLayout) (*NATSBroker, error) {
+	conn, err := nats.Connect(address)
+	if err != nil {
+		return nil, err
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "cannot create JetStream context")
+	}
+	b := &NATSBroker{conn: conn, js: js, layout: layout, iterators: make(map[string]jetstream.MessagesContext)}
+	// Create the streams
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, stream := range []string{layout.Name, deadLetterQueueName(layout.Name)} {
+		_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:     stream,
+			Subjects: []string{stream + ".shard.*"},
+			Storage:  jetstream.FileStorage,
+		})
+		if err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "cannot create stream %s", stream)
+		}
+	}
+	return b, nil
+}
+
+// natsShardSubject returns the subject of a shard in a stream
+func natsShardSubject(stream string, shard int) string {
+	return stream + ".shard." + strconv.Itoa(shard)
+}
+
+// ShardOf returns the shard of a department in the queue layout
+func (b *NATSBroker) ShardOf(department course.DepartmentID) int {
+	return b.layout.ShardOf(department)
+}
+
+// ProcessDatabaseQuery will publish a database query in the subject of its department.
+// If the message does not have an operation ID, a new one is assigned to it. The operation ID is
+// also used as the message ID, so JetStream drops duplicate publishes.
+func (b *NATSBroker) ProcessDatabaseQuery(ctx context.Context, department course.DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	if msg.OperationId == "" {
+		msg.OperationId = util.NewOperationID()
+	}
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal")
+	}
+	_, err = b.js.Publish(ctx, natsShardSubject(b.layout.Name, b.layout.ShardOf(department)), data, jetstream.WithMsgID(msg.OperationId))
+	return err
+}
+
+// Consume will consume the subjects of the given shards with durable pull consumers.
+// At most prefetch messages of each shard are unacknowledged at any time.
+func (b *NATSBroker) Consume(consumer string, prefetch int, shards []int) (<-chan Delivery, error) {
+	messageChannel := make(chan Delivery)
+	var wg sync.WaitGroup
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		name := shardConsumerName(consumer, shard)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		natsConsumer, err := b.js.CreateOrUpdateConsumer(ctx, b.layout.Name, jetstream.ConsumerConfig{
+			Durable:       name,
+			FilterSubject: natsShardSubject(b.layout.Name, shard),
+			AckPolicy:     jetstream.AckExplicitPolicy,
+			DeliverPolicy: jetstream.DeliverAllPolicy,
+			MaxAckPending: max(prefetch, 1),
+		})
+		cancel()
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot create consumer %s", name)
+		}
+		iterator, err := natsConsumer.Messages(jetstream.PullMaxMessages(max(prefetch, 1)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot consume %s", name)
+		}
+		b.iterators[name] = iterator
+		wg.Add(1)
+		go func(shard int) {
+			b.receiveMessages(shard, iterator, messageChannel)
+			wg.Done()
+		}(shard)
+	}
+	// When all consumers are stopped, also close the outgoing channel
+	go func() {
+		wg.Wait()
+		close(messageChannel)
+	}()
+	return messageChannel, nil
+}
+
+// receiveMessages will receive messages from an iterator and parses them as proto.CourseDatabaseBatchMessage.
+// Messages which cannot be parsed are sent to the dead letter stream.
+func (b *NATSBroker) receiveMessages(shard int, iterator jetstream.MessagesContext, outgoing chan<- Delivery) {
+	for {
+		message, err := iterator.Next()
+		if err != nil {
+			if !errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				log.WithError(err).Error("cannot receive message")
+			}
+			return
+		}
+		parsedMessage := new(proto.CourseDatabaseBatchMessage)
+		err = protobuf.Unmarshal(message.Data(), parsedMessage)
+		if err != nil {
+			log.WithError(err).Warn("cannot parse proto buffer message")
+			err = b.publishDeadLetter(context.Background(), shard, message.Headers().Get(jetstream.MsgIDHeader), message.Data(), errors.Wrap(err, "cannot parse message"), 0)
+			if err != nil {
+				log.WithError(err).Error("cannot dead letter unparseable message")
+				_ = message.Nak()
+			} else {
+				_ = message.Ack()
+			}
+			continue
+		}
+		outgoing <- Delivery{
+			Message:      parsedMessage,
+			Shard:        shard,
+			Body:         message.Data(),
+			acknowledger: natsAcknowledger{message},
+		}
+	}
+}
+
+// natsAcknowledger acknowledges a JetStream message
+type natsAcknowledger struct {
+	message jetstream.Msg
+}
+
+func (a natsAcknowledger) Ack() error {
+	return a.message.Ack()
+}
+
+// Nack asks the server to deliver the message again if requeue is true. Otherwise, the message is terminated.
+func (a natsAcknowledger) Nack(requeue bool) error {
+	if requeue {
+		return a.message.Nak()
+	}
+	return a.message.Term()
+}
+
+// CancelConsumer stops the pull consumers of a consumer. The durable consumers are kept on the server.
+func (b *NATSBroker) CancelConsumer(consumer string, shards []int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		name := shardConsumerName(consumer, shard)
+		if iterator, exists := b.iterators[name]; exists {
+			iterator.Stop()
+			delete(b.iterators, name)
+		}
+	}
+	return nil
+}
+
+// Close stops all consumers and closes the connection
+func (b *NATSBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for name, iterator := range b.iterators {
+		iterator.Stop()
+		delete(b.iterators, name)
+	}
+	b.conn.Close()
+	return nil
+}
+
+// DeadLetter will publish a delivery in the dead letter stream of its shard with the reason and the number
+// of attempts attached to it. The delivery is acknowledged after it has been published.
+func (b *NATSBroker) DeadLetter(ctx context.Context, d Delivery, reason error, attempts int) error {
+	err := b.publishDeadLetter(ctx, d.Shard, d.Message.OperationId, d.Body, reason, attempts)
+	if err != nil {
+		return err
+	}
+	return d.Ack()
+}
+
+// publishDeadLetter publishes a raw message in the dead letter stream of a shard
+func (b *NATSBroker) publishDeadLetter(ctx context.Context, shard int, operationID string, body []byte, reason error, attempts int) error {
+	msg := nats.NewMsg(natsShardSubject(deadLetterQueueName(b.layout.Name), shard))
+	msg.Data = body
+	msg.Header.Set(natsOperationIDHeader, operationID)
+	msg.Header.Set(deadLetterErrorHeader, reason.Error())
+	msg.Header.Set(deadLetterAttemptsHeader, strconv.Itoa(attempts))
+	if _, err := b.js.PublishMsg(ctx, msg); err != nil {
+		return errors.Wrap(err, "cannot publish dead letter")
+	}
+	return nil
+}
+
+// ListDeadLetters returns all the messages in the dead letter stream of shards without removing them.
+func (b *NATSBroker) ListDeadLetters(shards []int) ([]DeadLetter, error) {
+	var result []DeadLetter
+	for _, shard := range shards {
+		err := b.browseDeadLetters(context.Background(), shard, func(letter DeadLetter, _ uint64) error {
+			result = append(result, letter)
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// RequeueDeadLetters publishes the dead letters of shards which the filter returns true for at the end of
+// the subject of their shard and deletes them from the dead letter stream. Returns the number of requeued messages.
+func (b *NATSBroker) RequeueDeadLetters(ctx context.Context, shards []int, filter func(DeadLetter) bool) (int, error) {
+	requeued := 0
+	stream, err := b.js.Stream(ctx, deadLetterQueueName(b.layout.Name))
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot get dead letter stream")
+	}
+	for _, shard := range shards {
+		err = b.browseDeadLetters(ctx, shard, func(letter DeadLetter, sequence uint64) error {
+			if !filter(letter) {
+				return nil
+			}
+			// Do not set the message ID; otherwise, JetStream might drop it as a duplicate
+			_, err := b.js.Publish(ctx, natsShardSubject(b.layout.Name, shard), letter.Body)
+			if err != nil {
+				return errors.Wrap(err, "cannot requeue message")
+			}
+			if err = stream.DeleteMsg(ctx, sequence); err != nil {
+				return errors.Wrap(err, "cannot delete dead letter")
+			}
+			requeued++
+			return nil
+		})
+		if err != nil {
+			return requeued, err
+		}
+	}
+	return requeued, nil
+}
+
+// browseDeadLetters calls the callback for every message in the dead letter stream of a shard alongside
+// its sequence number in the stream
+func (b *NATSBroker) browseDeadLetters(ctx context.Context, shard int, callback func(DeadLetter, uint64) error) error {
+	stream, err := b.js.Stream(ctx, deadLetterQueueName(b.layout.Name))
+	if err != nil {
+		return errors.Wrap(err, "cannot get dead letter stream")
+	}
+	info, err := stream.Info(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get dead letter stream info")
+	}
+	subject := natsShardSubject(deadLetterQueueName(b.layout.Name), shard)
+	for sequence := info.State.FirstSeq; sequence != 0 && sequence <= info.State.LastSeq; sequence++ {
+		msg, err := stream.GetMsg(ctx, sequence)
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "cannot get dead letter")
+		}
+		if msg.Subject != subject {
+			continue
+		}
+		attempts, _ := strconv.ParseInt(msg.Header.Get(deadLetterAttemptsHeader), 10, 32)
+		letter := DeadLetter{
+			Shard:       shard,
+			OperationID: msg.Header.Get(natsOperationIDHeader),
+			Body:        msg.Data,
+			Error:       msg.Header.Get(deadLetterErrorHeader),
+			Attempts:    int32(attempts),
+			FailedAt:    msg.Time,
+		}
+		parseDeadLetterBody(&letter)
+		if err = callback(letter, sequence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
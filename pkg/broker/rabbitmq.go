@@ -3,22 +3,45 @@ package broker
 import (
 	"CourseEnrollment/pkg/course"
 	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
 	"context"
 	"github.com/go-faster/errors"
 	amqp "github.com/rabbitmq/amqp091-go"
 	log "github.com/sirupsen/logrus"
 	protobuf "google.golang.org/protobuf/proto"
+	"strconv"
+	"sync"
 )
 
 // RabbitMQBroker instantiates a RabbitMQ broker for general use
 type RabbitMQBroker struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
-	queue   amqp.Queue
+	layout  QueueLayout
 }
 
-// NewRabbitMQBroker creates a RabbitMQ connection and declares a durable queue
-func NewRabbitMQBroker(connectionUrl, queueName string) (RabbitMQBroker, error) {
+func init() {
+	Register("rabbitmq", func(address string, layout QueueLayout) (Broker, error) {
+		return NewRabbitMQBroker(address, layout)
+	})
+}
+
+// rabbitMQAcknowledger acknowledges a RabbitMQ delivery
+type rabbitMQAcknowledger struct {
+	delivery amqp.Delivery
+}
+
+func (a rabbitMQAcknowledger) Ack() error {
+	return a.delivery.Ack(false)
+}
+
+func (a rabbitMQAcknowledger) Nack(requeue bool) error {
+	return a.delivery.Nack(false, requeue)
+}
+
+// NewRabbitMQBroker creates a RabbitMQ connection and declares the exchanges and the durable
+// queues of the layout alongside their dead letter queues
+func NewRabbitMQBroker(connectionUrl string, layout QueueLayout) (RabbitMQBroker, error) {
 	// Connect to rabbit mq server
 	conn, err := amqp.Dial(connectionUrl)
 	if err != nil {
@@ -30,20 +53,54 @@ func NewRabbitMQBroker(connectionUrl, queueName string) (RabbitMQBroker, error)
 		_ = conn.Close()
 		return RabbitMQBroker{}, err
 	}
-	// Create the queue
-	queue, err := ch.QueueDeclare(
-		queueName,
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
+	// Create the queues
+	err = declareLayout(ch, layout)
 	if err != nil {
 		_ = conn.Close() // channel will be closed as well
 		return RabbitMQBroker{}, err
 	}
-	return RabbitMQBroker{conn, ch, queue}, nil
+	return RabbitMQBroker{conn, ch, layout}, nil
+}
+
+// declareLayout declares every exchange and queue in a layout and binds them together
+func declareLayout(ch *amqp.Channel, layout QueueLayout) error {
+	// The messages which are not routed to any shard go to the default queue
+	err := ch.ExchangeDeclare(layout.unroutedExchangeName(), amqp.ExchangeFanout, true, false, false, false, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot declare unrouted exchange")
+	}
+	err = ch.ExchangeDeclare(layout.exchangeName(), amqp.ExchangeTopic, true, false, false, false, amqp.Table{
+		"alternate-exchange": layout.unroutedExchangeName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot declare exchange")
+	}
+	for _, shard := range layout.AllShards() {
+		queueName := layout.QueueName(shard)
+		_, err = ch.QueueDeclare(queueName, true, false, false, false, nil)
+		if err != nil {
+			return errors.Wrapf(err, "cannot declare queue %s", queueName)
+		}
+		err = declareDeadLetterQueue(ch, queueName)
+		if err != nil {
+			return err
+		}
+		// Bind the queue
+		if shard == 0 {
+			err = ch.QueueBind(queueName, "", layout.unroutedExchangeName(), false, nil)
+			if err != nil {
+				return errors.Wrapf(err, "cannot bind queue %s", queueName)
+			}
+			continue
+		}
+		for _, department := range layout.Shards[shard-1] {
+			err = ch.QueueBind(queueName, departmentRoutingKey(department), layout.exchangeName(), false, nil)
+			if err != nil {
+				return errors.Wrapf(err, "cannot bind queue %s", queueName)
+			}
+		}
+	}
+	return nil
 }
 
 func (c RabbitMQBroker) Close() error {
@@ -51,62 +108,115 @@ func (c RabbitMQBroker) Close() error {
 	return c.conn.Close()
 }
 
-// ProcessDatabaseQuery will push a database query into queue.
-// DepartmentID is currently unused.
-func (c RabbitMQBroker) ProcessDatabaseQuery(ctx context.Context, _ course.DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+// ShardOf returns the shard of a department in the queue layout
+func (c RabbitMQBroker) ShardOf(department course.DepartmentID) int {
+	return c.layout.ShardOf(department)
+}
+
+// ProcessDatabaseQuery will push a database query into the queue of its department.
+// If the message does not have an operation ID, a new one is assigned to it.
+func (c RabbitMQBroker) ProcessDatabaseQuery(ctx context.Context, department course.DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	if msg.OperationId == "" {
+		msg.OperationId = util.NewOperationID()
+	}
 	data, err := protobuf.Marshal(msg)
 	if err != nil {
 		return errors.Wrap(err, "cannot marshal")
 	}
 	return c.channel.PublishWithContext(ctx,
-		"",
-		c.queue.Name,
+		c.layout.exchangeName(),
+		departmentRoutingKey(department),
 		false,
 		false,
 		amqp.Publishing{
-			Body: data,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.OperationId,
+			Body:         data,
 		})
 }
 
-// Consume will consume the messages which are received on
-func (c RabbitMQBroker) Consume(consumer string) (<-chan *proto.CourseDatabaseBatchMessage, error) {
-	// Create the consumer
-	messages, err := c.channel.Consume(
-		c.queue.Name,
-		consumer,
-		true,
-		true,
-		false,
-		false,
-		nil,
-	)
+// Consume will consume the messages which are received on the queues of the given shards.
+// The order of messages of each shard is kept, but messages of different shards are interleaved.
+// Messages are not acknowledged automatically; each Delivery must be acknowledged
+// after it has been processed. At most prefetch messages of each shard are unacknowledged at any time.
+func (c RabbitMQBroker) Consume(consumer string, prefetch int, shards []int) (<-chan Delivery, error) {
+	// Limit the unacknowledged messages
+	err := c.channel.Qos(prefetch, 0, false)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "cannot set prefetch count")
 	}
 	// Create the channel to send the parsed proto buffer messages in it
-	messageChannel := make(chan *proto.CourseDatabaseBatchMessage)
-	// Loop over messages and send them in another goroutine
-	go receiveMessages(messages, messageChannel)
+	messageChannel := make(chan Delivery)
+	var wg sync.WaitGroup
+	for _, shard := range shards {
+		// Create the consumer
+		messages, err := c.channel.Consume(
+			c.layout.QueueName(shard),
+			shardConsumerName(consumer, shard),
+			false,
+			true,
+			false,
+			false,
+			nil,
+		)
+		if err != nil {
+			_ = c.CancelConsumer(consumer, shards)
+			return nil, err
+		}
+		// Loop over messages and send them in another goroutine
+		wg.Add(1)
+		go func(shard int) {
+			c.receiveMessages(shard, messages, messageChannel)
+			wg.Done()
+		}(shard)
+	}
+	// When all consumers are closed, also close the outgoing channel
+	go func() {
+		wg.Wait()
+		close(messageChannel)
+	}()
 	return messageChannel, nil
 }
 
 // CancelConsumer will cancel a consumer by its name
-func (c RabbitMQBroker) CancelConsumer(consumer string) error {
-	return c.channel.Cancel(consumer, false)
+func (c RabbitMQBroker) CancelConsumer(consumer string, shards []int) error {
+	var result error
+	for _, shard := range shards {
+		if err := c.channel.Cancel(shardConsumerName(consumer, shard), false); err != nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// shardConsumerName is the name of the consumer of a single shard
+func shardConsumerName(consumer string, shard int) string {
+	return consumer + "-" + strconv.Itoa(shard)
 }
 
-// receiveMessages will receive messages from a channel and parses them as proto.CourseDatabaseBatchMessage
-func receiveMessages(incoming <-chan amqp.Delivery, outgoing chan<- *proto.CourseDatabaseBatchMessage) {
+// receiveMessages will receive messages from a channel and parses them as proto.CourseDatabaseBatchMessage.
+// Messages which cannot be parsed are sent to the dead letter queue.
+func (c RabbitMQBroker) receiveMessages(shard int, incoming <-chan amqp.Delivery, outgoing chan<- Delivery) {
 	for message := range incoming {
 		parsedMessage := new(proto.CourseDatabaseBatchMessage)
 		err := protobuf.Unmarshal(message.Body, parsedMessage)
 		if err != nil {
 			log.WithError(err).Warn("cannot parse proto buffer message")
+			err = c.publishDeadLetter(context.Background(), shard, message.MessageId, message.Body, errors.Wrap(err, "cannot parse message"), 0)
+			if err != nil {
+				log.WithError(err).Error("cannot dead letter unparseable message")
+				_ = message.Nack(false, true)
+			} else {
+				_ = message.Ack(false)
+			}
 			continue
 		}
 		// Send to channel
-		outgoing <- parsedMessage
+		outgoing <- Delivery{
+			Message:      parsedMessage,
+			Shard:        shard,
+			Body:         message.Body,
+			acknowledger: rabbitMQAcknowledger{message},
+		}
 	}
-	// When incoming channel is closed, also close the outgoing channel
-	close(outgoing)
 }
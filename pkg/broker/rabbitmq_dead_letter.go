@@ -0,0 +1,165 @@
+package broker
+
+import (
+	"context"
+	"github.com/go-faster/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"time"
+)
+
+// deadLetterExchangeName returns the name of the dead letter exchange of a queue
+func deadLetterExchangeName(queueName string) string {
+	return queueName + "-dlx"
+}
+
+// declareDeadLetterQueue declares the dead letter exchange and its queue and binds them together
+func declareDeadLetterQueue(ch *amqp.Channel, queueName string) error {
+	err := ch.ExchangeDeclare(deadLetterExchangeName(queueName), amqp.ExchangeFanout, true, false, false, false, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot declare dead letter exchange")
+	}
+	_, err = ch.QueueDeclare(deadLetterQueueName(queueName), true, false, false, false, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot declare dead letter queue")
+	}
+	err = ch.QueueBind(deadLetterQueueName(queueName), "", deadLetterExchangeName(queueName), false, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot bind dead letter queue")
+	}
+	return nil
+}
+
+// DeadLetter will send a delivery to the dead letter exchange of its shard with the reason and the number
+// of attempts attached to it. The delivery is acknowledged after it has been published.
+func (c RabbitMQBroker) DeadLetter(ctx context.Context, d Delivery, reason error, attempts int) error {
+	err := c.publishDeadLetter(ctx, d.Shard, d.Message.OperationId, d.Body, reason, attempts)
+	if err != nil {
+		return err
+	}
+	return d.Ack()
+}
+
+// publishDeadLetter publishes a raw message in the dead letter exchange of a shard
+func (c RabbitMQBroker) publishDeadLetter(ctx context.Context, shard int, operationID string, body []byte, reason error, attempts int) error {
+	err := c.channel.PublishWithContext(ctx,
+		deadLetterExchangeName(c.layout.QueueName(shard)),
+		"",
+		false,
+		false,
+		amqp.Publishing{
+			Headers: amqp.Table{
+				deadLetterErrorHeader:    reason.Error(),
+				deadLetterAttemptsHeader: int32(attempts),
+			},
+			DeliveryMode: amqp.Persistent,
+			MessageId:    operationID,
+			Timestamp:    time.Now(),
+			Body:         body,
+		})
+	if err != nil {
+		return errors.Wrap(err, "cannot publish dead letter")
+	}
+	return nil
+}
+
+// ListDeadLetters returns all the messages in the dead letter queues of shards without removing them.
+func (c RabbitMQBroker) ListDeadLetters(shards []int) ([]DeadLetter, error) {
+	var result []DeadLetter
+	for _, shard := range shards {
+		err := c.browseDeadLetters(shard, func(letter DeadLetter) bool {
+			result = append(result, letter)
+			return false
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// RequeueDeadLetters moves the dead letters of shards which the filter returns true for back to
+// the queue of their shard. The requeued messages are placed at the end of the queue.
+// Returns the number of requeued messages.
+func (c RabbitMQBroker) RequeueDeadLetters(ctx context.Context, shards []int, filter func(DeadLetter) bool) (int, error) {
+	requeued := 0
+	for _, shard := range shards {
+		shardRequeued, err := c.requeueShardDeadLetters(ctx, shard, filter)
+		requeued += shardRequeued
+		if err != nil {
+			return requeued, err
+		}
+	}
+	return requeued, nil
+}
+
+// requeueShardDeadLetters is RequeueDeadLetters for a single shard
+func (c RabbitMQBroker) requeueShardDeadLetters(ctx context.Context, shard int, filter func(DeadLetter) bool) (int, error) {
+	requeued := 0
+	var publishErr error
+	err := c.browseDeadLetters(shard, func(letter DeadLetter) bool {
+		if publishErr != nil || !filter(letter) {
+			return false
+		}
+		publishErr = c.channel.PublishWithContext(ctx,
+			"",
+			c.layout.QueueName(shard),
+			false,
+			false,
+			amqp.Publishing{
+				DeliveryMode: amqp.Persistent,
+				MessageId:    letter.OperationID,
+				Body:         letter.Body,
+			})
+		if publishErr != nil {
+			return false
+		}
+		requeued++
+		return true
+	})
+	if publishErr != nil {
+		return requeued, errors.Wrap(publishErr, "cannot requeue message")
+	}
+	return requeued, err
+}
+
+// browseDeadLetters goes over every message in the dead letter queue of a shard. If the callback returns true,
+// the message is removed from the dead letter queue. Otherwise, it's kept in the queue.
+func (c RabbitMQBroker) browseDeadLetters(shard int, callback func(DeadLetter) bool) error {
+	var kept []amqp.Delivery
+	// Put the kept messages back in the queue once we are done
+	defer func() {
+		for _, d := range kept {
+			_ = d.Nack(false, true)
+		}
+	}()
+	for {
+		d, ok, err := c.channel.Get(deadLetterQueueName(c.layout.QueueName(shard)), false)
+		if err != nil {
+			return errors.Wrap(err, "cannot get dead letter")
+		}
+		if !ok {
+			return nil
+		}
+		if callback(parseDeadLetter(shard, d)) {
+			if err = d.Ack(false); err != nil {
+				return errors.Wrap(err, "cannot ack dead letter")
+			}
+		} else {
+			kept = append(kept, d)
+		}
+	}
+}
+
+// parseDeadLetter converts a delivery from the dead letter queue into a DeadLetter
+func parseDeadLetter(shard int, d amqp.Delivery) DeadLetter {
+	letter := DeadLetter{
+		Shard:       shard,
+		OperationID: d.MessageId,
+		Body:        d.Body,
+		FailedAt:    d.Timestamp,
+	}
+	letter.Error, _ = d.Headers[deadLetterErrorHeader].(string)
+	letter.Attempts, _ = d.Headers[deadLetterAttemptsHeader].(int32)
+	parseDeadLetterBody(&letter)
+	return letter
+}
@@ -0,0 +1,586 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-faster/errors"
+	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// walSegmentSize is the size which after it, the writer starts a new segment
+const walSegmentSize = 64 * 1024 * 1024
+
+// walPollInterval is the interval which the consumers check the log for new records
+const walPollInterval = 10 * time.Millisecond
+
+// walOffsetFlushInterval is the interval which the consumers store their committed offset on disk
+const walOffsetFlushInterval = 100 * time.Millisecond
+
+func init() {
+	Register("wal", func(address string, layout QueueLayout) (Broker, error) {
+		return NewWALBroker(address, layout)
+	})
+}
+
+// WALBroker is a broker without any server. The messages of each shard are appended to a local
+// write-ahead log in a directory, and the batcher tails these logs. The enrollment server and the
+// batcher must have access to the same directory.
+//
+// Each shard has a list of segment files and an offset file which contains the position of the last
+// acknowledged message of that shard. Acknowledged segments are deleted by the consumer.
+type WALBroker struct {
+	dir     string
+	layout  QueueLayout
+	writers []*walWriter
+	// Active consumers by their shard consumer name
+	consumers map[string]*walConsumer
+	mu        sync.Mutex
+}
+
+// NewWALBroker creates a write-ahead log broker in a directory
+func NewWALBroker(dir string, layout QueueLayout) (*WALBroker, error) {
+	if dir == "" {
+		return nil, errors.New("empty directory")
+	}
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create directory")
+	}
+	b := &WALBroker{
+		dir:       dir,
+		layout:    layout,
+		consumers: make(map[string]*walConsumer),
+	}
+	for _, shard := range layout.AllShards() {
+		writer, err := newWALWriter(dir, layout.QueueName(shard))
+		if err != nil {
+			_ = b.Close()
+			return nil, err
+		}
+		b.writers = append(b.writers, writer)
+	}
+	return b, nil
+}
+
+// ShardOf returns the shard of a department in the queue layout
+func (b *WALBroker) ShardOf(department course.DepartmentID) int {
+	return b.layout.ShardOf(department)
+}
+
+// ProcessDatabaseQuery will append a database query to the log of its department's shard.
+// If the message does not have an operation ID, a new one is assigned to it.
+func (b *WALBroker) ProcessDatabaseQuery(_ context.Context, department course.DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	if msg.OperationId == "" {
+		msg.OperationId = util.NewOperationID()
+	}
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal")
+	}
+	return b.writers[b.layout.ShardOf(department)].append(data)
+}
+
+// Consume will tail the logs of the given shards
+func (b *WALBroker) Consume(consumer string, prefetch int, shards []int) (<-chan Delivery, error) {
+	messageChannel := make(chan Delivery)
+	var wg sync.WaitGroup
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		name := shardConsumerName(consumer, shard)
+		if _, exists := b.consumers[name]; exists {
+			return nil, fmt.Errorf("consumer %s already exists", name)
+		}
+		c, err := newWALConsumer(b, shard, prefetch)
+		if err != nil {
+			return nil, err
+		}
+		b.consumers[name] = c
+		wg.Add(1)
+		go func() {
+			c.run(messageChannel)
+			wg.Done()
+		}()
+	}
+	// When all consumers are stopped, also close the outgoing channel
+	go func() {
+		wg.Wait()
+		close(messageChannel)
+	}()
+	return messageChannel, nil
+}
+
+// CancelConsumer stops tailing the logs. The offsets of the messages which are acknowledged
+// after this are still stored.
+func (b *WALBroker) CancelConsumer(consumer string, shards []int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, shard := range shards {
+		if c, exists := b.consumers[shardConsumerName(consumer, shard)]; exists {
+			c.cancel()
+		}
+	}
+	return nil
+}
+
+// Close stops all consumers, stores their offsets and closes the logs
+func (b *WALBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var result error
+	for name, c := range b.consumers {
+		c.cancel()
+		if err := c.close(); err != nil {
+			result = err
+		}
+		delete(b.consumers, name)
+	}
+	for _, writer := range b.writers {
+		if err := writer.close(); err != nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// walWriter appends records to the segments of a single queue.
+// Multiple processes can write to a queue at the same time.
+type walWriter struct {
+	dir   string
+	queue string
+	// A file which is locked while writing to synchronize the writers of different processes
+	lock *os.File
+	// The segment which we are appending to. It's nil until the first append.
+	file *os.File
+	seq  uint64
+	// The size of file which we know contains valid records
+	validSize int64
+	mu        sync.Mutex
+}
+
+// newWALWriter creates a writer for a queue
+func newWALWriter(dir, queue string) (*walWriter, error) {
+	lock, err := os.OpenFile(filepath.Join(dir, queue+".lock"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot open lock file")
+	}
+	return &walWriter{dir: dir, queue: queue, lock: lock}, nil
+}
+
+// append writes a record at the end of the log and syncs it to disk
+func (w *walWriter) append(payload []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := lockFile(w.lock); err != nil {
+		return errors.Wrap(err, "cannot lock log")
+	}
+	defer unlockFile(w.lock)
+	if err := w.syncSegment(); err != nil {
+		return err
+	}
+	record := encodeWALRecord(payload)
+	if _, err := w.file.WriteAt(record, w.validSize); err != nil {
+		return errors.Wrap(err, "cannot write record")
+	}
+	if err := w.file.Sync(); err != nil {
+		return errors.Wrap(err, "cannot sync log")
+	}
+	w.validSize += int64(len(record))
+	// Start a new segment if this one is big enough
+	if w.validSize >= walSegmentSize {
+		file, err := os.OpenFile(walSegmentPath(w.dir, w.queue, w.seq+1), os.O_CREATE|os.O_EXCL|os.O_RDWR, 0o644)
+		if err != nil {
+			return errors.Wrap(err, "cannot create segment")
+		}
+		_ = w.file.Close()
+		w.file, w.seq, w.validSize = file, w.seq+1, 0
+	}
+	return nil
+}
+
+// syncSegment makes sure that the writer is on the last segment and the tail of it only contains
+// valid records. Other processes might have written to the log or crashed in the middle of writing.
+// The lock must be held while calling this method.
+func (w *walWriter) syncSegment() error {
+	if w.file == nil {
+		segments, err := listWALSegments(w.dir, w.queue)
+		if err != nil {
+			return err
+		}
+		w.seq = 1
+		if len(segments) != 0 {
+			w.seq = segments[len(segments)-1]
+		}
+		w.file, err = os.OpenFile(walSegmentPath(w.dir, w.queue, w.seq), os.O_CREATE|os.O_RDWR, 0o644)
+		if err != nil {
+			return errors.Wrap(err, "cannot open segment")
+		}
+		w.validSize = 0
+	}
+	// Move to the segments which other processes have created
+	for {
+		file, err := os.OpenFile(walSegmentPath(w.dir, w.queue, w.seq+1), os.O_RDWR, 0o644)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "cannot open segment")
+		}
+		_ = w.file.Close()
+		w.file, w.seq, w.validSize = file, w.seq+1, 0
+	}
+	// Validate the records which other processes have written
+	info, err := w.file.Stat()
+	if err != nil {
+		return errors.Wrap(err, "cannot stat segment")
+	}
+	for w.validSize < info.Size() {
+		_, size, err := readWALRecord(w.file, w.validSize)
+		if errors.Is(err, errWALIncompleteRecord) || errors.Is(err, errWALCorruptRecord) {
+			// A writer has crashed in the middle of writing. Remove the torn record.
+			log.WithField("queue", w.queue).WithField("segment", w.seq).Warn("truncating torn record in write-ahead log")
+			if err = w.file.Truncate(w.validSize); err != nil {
+				return errors.Wrap(err, "cannot truncate segment")
+			}
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "cannot read segment")
+		}
+		w.validSize += size
+	}
+	return nil
+}
+
+// close closes the files of writer
+func (w *walWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file != nil {
+		_ = w.file.Close()
+		w.file = nil
+	}
+	return w.lock.Close()
+}
+
+// walPosition is a position in the log of a queue
+type walPosition struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+}
+
+// walConsumer tails the log of a single shard
+type walConsumer struct {
+	broker *WALBroker
+	shard  int
+	queue  string
+	// The records which are delivered but not acknowledged yet
+	window *ackWindow[walPosition]
+	// Closed when the consumer must stop reading
+	stop     chan struct{}
+	stopOnce sync.Once
+	// Closed when the consumer has stopped reading
+	done chan struct{}
+	// Closed when the offset flusher has stopped
+	flusherDone chan struct{}
+	// The position which everything before it is acknowledged
+	committed walPosition
+	// True if committed is not stored on disk yet
+	dirty bool
+	mu    sync.Mutex
+}
+
+// newWALConsumer creates a consumer for a shard and loads its committed offset
+func newWALConsumer(b *WALBroker, shard, prefetch int) (*walConsumer, error) {
+	c := &walConsumer{
+		broker:      b,
+		shard:       shard,
+		queue:       b.layout.QueueName(shard),
+		window:      newAckWindow[walPosition](prefetch),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		flusherDone: make(chan struct{}),
+	}
+	data, err := os.ReadFile(c.offsetPath())
+	switch {
+	case err == nil:
+		if err = json.Unmarshal(data, &c.committed); err != nil {
+			return nil, errors.Wrap(err, "cannot parse offset file")
+		}
+	case errors.Is(err, os.ErrNotExist):
+		// Start from the first segment
+	default:
+		return nil, errors.Wrap(err, "cannot read offset file")
+	}
+	go c.flushOffsets()
+	return c, nil
+}
+
+// run reads the records and sends them to out until the consumer is canceled
+func (c *walConsumer) run(out chan<- Delivery) {
+	defer close(c.done)
+	position := c.committed
+	var file *os.File
+	defer func() {
+		if file != nil {
+			_ = file.Close()
+		}
+	}()
+	// True if we know that a newer segment exists, so the current one is complete
+	segmentComplete := false
+	for {
+		if file == nil {
+			var err error
+			file, position, err = c.openSegment(position)
+			if err != nil {
+				log.WithError(err).WithField("queue", c.queue).Error("cannot open segment")
+			}
+			if file == nil {
+				if !c.sleep() {
+					return
+				}
+				continue
+			}
+			segmentComplete = false
+		}
+		payload, size, err := readWALRecord(file, position.Offset)
+		switch {
+		case err == nil:
+			end := walPosition{Segment: position.Segment, Offset: position.Offset + size}
+			if !c.deliver(out, payload, end) {
+				return
+			}
+			position = end
+		case errors.Is(err, errWALIncompleteRecord) || errors.Is(err, errWALCorruptRecord):
+			if segmentComplete {
+				if errors.Is(err, errWALCorruptRecord) {
+					log.WithField("queue", c.queue).WithField("segment", position.Segment).Error("skipping corrupt tail of segment")
+				}
+				// Move to next segment
+				_ = file.Close()
+				file = nil
+				position = walPosition{Segment: position.Segment + 1}
+				continue
+			}
+			if _, statErr := os.Stat(walSegmentPath(c.broker.dir, c.queue, position.Segment+1)); statErr == nil {
+				// Read once more because the record might have been written before the new segment
+				segmentComplete = true
+				continue
+			}
+			if !c.sleep() {
+				return
+			}
+		default:
+			log.WithError(err).WithField("queue", c.queue).Error("cannot read segment")
+			if !c.sleep() {
+				return
+			}
+		}
+	}
+}
+
+// openSegment opens the segment of position. If it does not exist but a later segment exists,
+// the first later segment is opened. It returns nil if there is no segment to open.
+func (c *walConsumer) openSegment(position walPosition) (*os.File, walPosition, error) {
+	segments, err := listWALSegments(c.broker.dir, c.queue)
+	if err != nil {
+		return nil, position, err
+	}
+	for _, segment := range segments {
+		if segment < position.Segment {
+			continue
+		}
+		if segment > position.Segment {
+			position = walPosition{Segment: segment}
+		}
+		file, err := os.Open(walSegmentPath(c.broker.dir, c.queue, segment))
+		if err != nil {
+			return nil, position, err
+		}
+		return file, position, nil
+	}
+	return nil, position, nil
+}
+
+// deliver sends a record to out. It returns false if the consumer is stopped before that.
+func (c *walConsumer) deliver(out chan<- Delivery, payload []byte, end walPosition) bool {
+	// Wait for the prefetch limit
+	record := c.window.add(c.stop, end)
+	if record == nil {
+		return false
+	}
+	acker := walAcknowledger{consumer: c, record: record, payload: payload}
+	message := new(proto.CourseDatabaseBatchMessage)
+	if err := protobuf.Unmarshal(payload, message); err != nil {
+		log.WithError(err).Warn("cannot parse proto buffer message")
+		err = c.broker.appendDeadLetter(c.shard, DeadLetter{
+			Body:     payload,
+			Error:    errors.Wrap(err, "cannot parse message").Error(),
+			FailedAt: time.Now(),
+		})
+		if err != nil {
+			log.WithError(err).Error("cannot dead letter unparseable message")
+		}
+		_ = acker.Ack()
+		return true
+	}
+	select {
+	case out <- Delivery{Message: message, Shard: c.shard, Body: payload, acknowledger: acker}:
+		return true
+	case <-c.stop:
+		return false
+	}
+}
+
+// ack marks a record as acknowledged and advances the committed position if possible.
+// The committed position is stored on disk by flushOffsets.
+func (c *walConsumer) ack(record *ackEntry[walPosition]) {
+	_ = c.window.ack(record, func(position walPosition) error {
+		c.mu.Lock()
+		c.committed, c.dirty = position, true
+		c.mu.Unlock()
+		return nil
+	})
+}
+
+// flushOffsets stores the committed offset on disk periodically until the consumer is closed
+func (c *walConsumer) flushOffsets() {
+	defer close(c.flusherDone)
+	ticker := time.NewTicker(walOffsetFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.storeOffset(); err != nil {
+				log.WithError(err).WithField("queue", c.queue).Error("cannot store offset")
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// storeOffset stores the committed offset on disk and removes the segments which are fully acknowledged
+func (c *walConsumer) storeOffset() error {
+	c.mu.Lock()
+	committed, dirty := c.committed, c.dirty
+	c.dirty = false
+	c.mu.Unlock()
+	if !dirty {
+		return nil
+	}
+	data, _ := json.Marshal(committed)
+	tempPath := c.offsetPath() + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
+		return errors.Wrap(err, "cannot write offset")
+	}
+	if err := os.Rename(tempPath, c.offsetPath()); err != nil {
+		return errors.Wrap(err, "cannot rename offset")
+	}
+	// Remove old segments
+	segments, err := listWALSegments(c.broker.dir, c.queue)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment < committed.Segment {
+			if err = os.Remove(walSegmentPath(c.broker.dir, c.queue, segment)); err != nil {
+				return errors.Wrap(err, "cannot remove segment")
+			}
+		}
+	}
+	return nil
+}
+
+// sleep waits for the poll interval. It returns false if the consumer is stopped meanwhile.
+func (c *walConsumer) sleep() bool {
+	select {
+	case <-time.After(walPollInterval):
+		return true
+	case <-c.stop:
+		return false
+	}
+}
+
+// cancel stops reading new records
+func (c *walConsumer) cancel() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// close waits for the consumer to stop and stores its offset
+func (c *walConsumer) close() error {
+	<-c.done
+	<-c.flusherDone
+	return c.storeOffset()
+}
+
+// offsetPath is the path of the offset file of consumer
+func (c *walConsumer) offsetPath() string {
+	return filepath.Join(c.broker.dir, c.queue+".offset")
+}
+
+// walAcknowledger acknowledges a record in the write-ahead log
+type walAcknowledger struct {
+	consumer *walConsumer
+	record   *ackEntry[walPosition]
+	payload  []byte
+}
+
+func (a walAcknowledger) Ack() error {
+	a.consumer.ack(a.record)
+	return nil
+}
+
+// Nack appends the record at the end of the log again if requeue is true
+func (a walAcknowledger) Nack(requeue bool) error {
+	if requeue {
+		if err := a.consumer.broker.writers[a.consumer.shard].append(a.payload); err != nil {
+			return err
+		}
+	}
+	a.consumer.ack(a.record)
+	return nil
+}
+
+// walSegmentPath returns the path of a segment of a queue
+func walSegmentPath(dir, queue string, segment uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.%020d.wal", queue, segment))
+}
+
+// listWALSegments returns the segments of a queue in sorted order
+func listWALSegments(dir, queue string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read directory")
+	}
+	var result []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, queue+".") || !strings.HasSuffix(name, ".wal") {
+			continue
+		}
+		segment, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, queue+"."), ".wal"), 10, 64)
+		if err != nil {
+			continue
+		}
+		result = append(result, segment)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result, nil
+}
@@ -0,0 +1,186 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-faster/errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// walDeadLetterRecord is the payload of each record in the dead letter log of a queue
+type walDeadLetterRecord struct {
+	OperationID string    `json:"operation_id"`
+	Error       string    `json:"error"`
+	Attempts    int32     `json:"attempts"`
+	FailedAt    time.Time `json:"failed_at"`
+	Body        []byte    `json:"body"`
+}
+
+// DeadLetter will append a delivery to the dead letter log of its shard with the reason and the number
+// of attempts attached to it. The delivery is acknowledged after it has been written.
+func (b *WALBroker) DeadLetter(_ context.Context, d Delivery, reason error, attempts int) error {
+	err := b.appendDeadLetter(d.Shard, DeadLetter{
+		OperationID: d.Message.OperationId,
+		Body:        d.Body,
+		Error:       reason.Error(),
+		Attempts:    int32(attempts),
+		FailedAt:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return d.Ack()
+}
+
+// ListDeadLetters returns all the messages in the dead letter logs of shards without removing them.
+func (b *WALBroker) ListDeadLetters(shards []int) ([]DeadLetter, error) {
+	var result []DeadLetter
+	for _, shard := range shards {
+		err := b.withDeadLetters(shard, func(letters []DeadLetter) ([]DeadLetter, error) {
+			result = append(result, letters...)
+			return letters, nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// RequeueDeadLetters moves the dead letters of shards which the filter returns true for back to
+// the log of their shard. The requeued messages are placed at the end of the log.
+// Returns the number of requeued messages.
+func (b *WALBroker) RequeueDeadLetters(_ context.Context, shards []int, filter func(DeadLetter) bool) (int, error) {
+	requeued := 0
+	for _, shard := range shards {
+		err := b.withDeadLetters(shard, func(letters []DeadLetter) ([]DeadLetter, error) {
+			kept := letters[:0:0]
+			for i, letter := range letters {
+				if !filter(letter) {
+					kept = append(kept, letter)
+					continue
+				}
+				if err := b.writers[shard].append(letter.Body); err != nil {
+					// Keep the rest of the letters
+					return append(kept, letters[i:]...), errors.Wrap(err, "cannot requeue message")
+				}
+				requeued++
+			}
+			return kept, nil
+		})
+		if err != nil {
+			return requeued, err
+		}
+	}
+	return requeued, nil
+}
+
+// appendDeadLetter writes a letter at the end of the dead letter log of a shard
+func (b *WALBroker) appendDeadLetter(shard int, letter DeadLetter) error {
+	return b.withDeadLetters(shard, func(letters []DeadLetter) ([]DeadLetter, error) {
+		return append(letters, letter), nil
+	})
+}
+
+// withDeadLetters reads the dead letter log of a shard and replaces it with the letters which
+// the callback returns. The log is locked while the callback runs. The log is only rewritten
+// if the callback changes the number of letters.
+func (b *WALBroker) withDeadLetters(shard int, callback func([]DeadLetter) ([]DeadLetter, error)) error {
+	path := filepath.Join(b.dir, deadLetterQueueName(b.layout.QueueName(shard))+".wal")
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "cannot open lock file")
+	}
+	defer lock.Close()
+	if err = lockFile(lock); err != nil {
+		return errors.Wrap(err, "cannot lock dead letters")
+	}
+	defer unlockFile(lock)
+	letters, err := readWALDeadLetters(path, shard)
+	if err != nil {
+		return err
+	}
+	newLetters, callbackErr := callback(letters)
+	if len(newLetters) == len(letters) {
+		return callbackErr
+	}
+	if err = writeWALDeadLetters(path, newLetters); err != nil {
+		return err
+	}
+	return callbackErr
+}
+
+// readWALDeadLetters reads every record in a dead letter log. A torn record at the end is ignored.
+func readWALDeadLetters(path string, shard int) ([]DeadLetter, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot open dead letters")
+	}
+	defer file.Close()
+	var result []DeadLetter
+	var offset int64
+	for {
+		payload, size, err := readWALRecord(file, offset)
+		if errors.Is(err, errWALIncompleteRecord) || errors.Is(err, errWALCorruptRecord) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read dead letters")
+		}
+		offset += size
+		var record walDeadLetterRecord
+		if err = json.Unmarshal(payload, &record); err != nil {
+			return nil, errors.Wrap(err, "cannot parse dead letter")
+		}
+		letter := DeadLetter{
+			Shard:       shard,
+			OperationID: record.OperationID,
+			Body:        record.Body,
+			Error:       record.Error,
+			Attempts:    record.Attempts,
+			FailedAt:    record.FailedAt,
+		}
+		parseDeadLetterBody(&letter)
+		result = append(result, letter)
+	}
+}
+
+// writeWALDeadLetters atomically replaces a dead letter log with the given letters
+func writeWALDeadLetters(path string, letters []DeadLetter) error {
+	var data []byte
+	for _, letter := range letters {
+		payload, err := json.Marshal(walDeadLetterRecord{
+			OperationID: letter.OperationID,
+			Error:       letter.Error,
+			Attempts:    letter.Attempts,
+			FailedAt:    letter.FailedAt,
+			Body:        letter.Body,
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot marshal dead letter")
+		}
+		data = append(data, encodeWALRecord(payload)...)
+	}
+	tempPath := path + ".tmp"
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return errors.Wrap(err, "cannot create dead letters")
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	_ = file.Close()
+	if err != nil {
+		return errors.Wrap(err, "cannot write dead letters")
+	}
+	if err = os.Rename(tempPath, path); err != nil {
+		return errors.Wrap(err, "cannot rename dead letters")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package broker
+
+import "os"
+
+// lockFile is a no-op on platforms without advisory locks. Only a single process
+// must write to the write-ahead log on these platforms.
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without advisory locks.
+func unlockFile(*os.File) error {
+	return nil
+}
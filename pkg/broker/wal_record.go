@@ -0,0 +1,61 @@
+package broker
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// walRecordHeaderSize is the size of the header of each record in a write-ahead log.
+// Each record is the length of the payload (4 bytes), the CRC32 of the payload (4 bytes)
+// and the payload itself. All numbers are big endian.
+const walRecordHeaderSize = 8
+
+// walMaxRecordSize is the maximum size of a payload. Anything bigger than this is considered corruption.
+const walMaxRecordSize = 16 * 1024 * 1024
+
+// errWALIncompleteRecord means that the record is not fully written yet
+var errWALIncompleteRecord = errors.New("incomplete record")
+
+// errWALCorruptRecord means that the record is corrupted
+var errWALCorruptRecord = errors.New("corrupt record")
+
+// encodeWALRecord encodes a payload as a record
+func encodeWALRecord(payload []byte) []byte {
+	record := make([]byte, walRecordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[walRecordHeaderSize:], payload)
+	return record
+}
+
+// readWALRecord reads a record at offset of r. It returns the payload and the total size of the record.
+// If the record is not fully written, errWALIncompleteRecord is returned. If the record is
+// corrupted, errWALCorruptRecord is returned.
+func readWALRecord(r io.ReaderAt, offset int64) ([]byte, int64, error) {
+	var header [walRecordHeaderSize]byte
+	n, err := r.ReadAt(header[:], offset)
+	if n < walRecordHeaderSize {
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil, 0, errWALIncompleteRecord
+		}
+		return nil, 0, err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > walMaxRecordSize {
+		return nil, 0, errWALCorruptRecord
+	}
+	payload := make([]byte, length)
+	n, err = r.ReadAt(payload, offset+walRecordHeaderSize)
+	if n < int(length) {
+		if err == nil || errors.Is(err, io.EOF) {
+			return nil, 0, errWALIncompleteRecord
+		}
+		return nil, 0, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, 0, errWALCorruptRecord
+	}
+	return payload, walRecordHeaderSize + int64(length), nil
+}
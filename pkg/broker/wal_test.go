@@ -0,0 +1,145 @@
+package broker
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// receiveWAL receives a delivery from a channel or fails the test after a timeout
+func receiveWAL(t *testing.T, messages <-chan Delivery) Delivery {
+	t.Helper()
+	select {
+	case d := <-messages:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+		return Delivery{}
+	}
+}
+
+func TestWALBrokerConsume(t *testing.T) {
+	dir := t.TempDir()
+	layout, err := ParseQueueLayout("queue", "1;2")
+	assert.NoError(t, err)
+	b, err := NewWALBroker(dir, layout)
+	assert.NoError(t, err)
+	for _, department := range []course.DepartmentID{1, 2, 3, 1} {
+		err = b.ProcessDatabaseQuery(context.Background(), department, &proto.CourseDatabaseBatchMessage{
+			Action: &proto.CourseDatabaseBatchMessage_Enroll{Enroll: &proto.CourseDatabaseBatchEnrollMessage{
+				StudentId: uint64(department),
+			}},
+		})
+		assert.NoError(t, err)
+	}
+	// Shard 1 gets department 1 in order
+	messages, err := b.Consume("test", 10, []int{1})
+	assert.NoError(t, err)
+	first := receiveWAL(t, messages)
+	second := receiveWAL(t, messages)
+	assert.Equal(t, 1, first.Shard)
+	assert.Equal(t, uint64(1), first.Message.GetEnroll().StudentId)
+	assert.Equal(t, uint64(1), second.Message.GetEnroll().StudentId)
+	assert.NotEqual(t, first.Message.OperationId, second.Message.OperationId)
+	// Only ack the first one and restart
+	assert.NoError(t, first.Ack())
+	assert.NoError(t, b.Close())
+	b, err = NewWALBroker(dir, layout)
+	assert.NoError(t, err)
+	defer b.Close()
+	messages, err = b.Consume("test", 10, []int{1})
+	assert.NoError(t, err)
+	redelivered := receiveWAL(t, messages)
+	assert.Equal(t, second.Message.OperationId, redelivered.Message.OperationId)
+	// Requeue puts the message at the end of the log
+	assert.NoError(t, redelivered.Nack(true))
+	redelivered = receiveWAL(t, messages)
+	assert.Equal(t, second.Message.OperationId, redelivered.Message.OperationId)
+	assert.NoError(t, redelivered.Ack())
+	assert.NoError(t, b.CancelConsumer("test", []int{1}))
+	_, open := <-messages
+	assert.False(t, open)
+	// Unrouted departments go to shard 0
+	messages, err = b.Consume("test0", 10, []int{0})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), receiveWAL(t, messages).Message.GetEnroll().StudentId)
+}
+
+func TestWALBrokerTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	layout := QueueLayout{Name: "queue"}
+	b, err := NewWALBroker(dir, layout)
+	assert.NoError(t, err)
+	defer b.Close()
+	assert.NoError(t, b.ProcessDatabaseQuery(context.Background(), 1, &proto.CourseDatabaseBatchMessage{OperationId: "first"}))
+	// Simulate a writer which crashed in the middle of a record
+	file, err := os.OpenFile(walSegmentPath(dir, "queue", 1), os.O_APPEND|os.O_WRONLY, 0)
+	assert.NoError(t, err)
+	_, err = file.Write(encodeWALRecord([]byte("torn record"))[:10])
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+	// The next writer removes the torn record
+	other, err := NewWALBroker(dir, layout)
+	assert.NoError(t, err)
+	defer other.Close()
+	assert.NoError(t, other.ProcessDatabaseQuery(context.Background(), 1, &proto.CourseDatabaseBatchMessage{OperationId: "second"}))
+	messages, err := b.Consume("test", 10, []int{0})
+	assert.NoError(t, err)
+	assert.Equal(t, "first", receiveWAL(t, messages).Message.OperationId)
+	assert.Equal(t, "second", receiveWAL(t, messages).Message.OperationId)
+}
+
+func TestWALBrokerDeadLetters(t *testing.T) {
+	dir := t.TempDir()
+	layout := QueueLayout{Name: "queue"}
+	b, err := NewWALBroker(dir, layout)
+	assert.NoError(t, err)
+	defer b.Close()
+	for _, id := range []string{"a", "b"} {
+		assert.NoError(t, b.ProcessDatabaseQuery(context.Background(), 1, &proto.CourseDatabaseBatchMessage{OperationId: id}))
+	}
+	messages, err := b.Consume("test", 10, []int{0})
+	assert.NoError(t, err)
+	for range 2 {
+		d := receiveWAL(t, messages)
+		assert.NoError(t, b.DeadLetter(context.Background(), d, errors.New("failed "+d.Message.OperationId), 3))
+	}
+	letters, err := b.ListDeadLetters([]int{0})
+	assert.NoError(t, err)
+	if assert.Len(t, letters, 2) {
+		assert.Equal(t, "a", letters[0].OperationID)
+		assert.Equal(t, "failed a", letters[0].Error)
+		assert.Equal(t, int32(3), letters[0].Attempts)
+		assert.Equal(t, "a", letters[0].Message.OperationId)
+		assert.False(t, letters[0].FailedAt.IsZero())
+	}
+	// Requeue one of them
+	requeued, err := b.RequeueDeadLetters(context.Background(), []int{0}, func(letter DeadLetter) bool {
+		return letter.OperationID == "b"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requeued)
+	assert.Equal(t, "b", receiveWAL(t, messages).Message.OperationId)
+	letters, err = b.ListDeadLetters([]int{0})
+	assert.NoError(t, err)
+	if assert.Len(t, letters, 1) {
+		assert.Equal(t, "a", letters[0].OperationID)
+	}
+}
+
+func TestListWALSegments(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"queue.00000000000000000002.wal", "queue.00000000000000000001.wal",
+		"queue-shard-1.00000000000000000003.wal", "queue-dead-letters.wal", "queue.lock", "queue.offset"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+	segments, err := listWALSegments(dir, "queue")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, segments)
+}
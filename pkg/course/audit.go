@@ -0,0 +1,223 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+)
+
+// ActorKind is the kind of who has made a change
+type ActorKind uint8
+
+const (
+	// ActorSystem is the enrollment server itself, like its periodic jobs
+	ActorSystem ActorKind = iota
+	ActorStudent
+	ActorStaff
+)
+
+func (k ActorKind) String() string {
+	switch k {
+	case ActorSystem:
+		return "system"
+	case ActorStudent:
+		return "student"
+	case ActorStaff:
+		return "staff"
+	default:
+		return "unknown"
+	}
+}
+
+// Audit is the context of a change which is written to the audit log alongside it
+type Audit struct {
+	ActorKind ActorKind
+	// Zero for the system
+	ActorID uint64
+	// When the change is made in unix milliseconds. It's set when the change is batched.
+	Time int64
+	// The RPC or the job which has made the change
+	Origin string
+	// The reason which the staff has entered. It can be empty.
+	Reason string
+}
+
+// ToProto converts the audit to its protobuf message
+func (a Audit) ToProto() *proto.CourseDatabaseBatchAudit {
+	return &proto.CourseDatabaseBatchAudit{
+		ActorKind: proto.AuditActorKind(a.ActorKind),
+		ActorId:   a.ActorID,
+		Time:      a.Time,
+		Origin:    a.Origin,
+		Reason:    a.Reason,
+	}
+}
+
+// NewAuditFromProto converts a protobuf message to an audit. Nil is the zero audit, which is
+// made by the system.
+func NewAuditFromProto(audit *proto.CourseDatabaseBatchAudit) Audit {
+	return Audit{
+		ActorKind: ActorKind(audit.GetActorKind()),
+		ActorID:   audit.GetActorId(),
+		Time:      audit.GetTime(),
+		Origin:    audit.GetOrigin(),
+		Reason:    audit.GetReason(),
+	}
+}
+
+// auditContextKey is the key of the audit in contexts
+type auditContextKey struct{}
+
+// WithAudit returns a context which the changes made with are audited with audit
+func WithAudit(ctx context.Context, audit Audit) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, audit)
+}
+
+// AuditFromContext returns the audit which is set with WithAudit. The zero audit is returned if
+// there is none.
+func AuditFromContext(ctx context.Context) Audit {
+	audit, _ := ctx.Value(auditContextKey{}).(Audit)
+	return audit
+}
+
+// AuditBatcher returns a batcher which sets the audit of every message from the context of the
+// change and then batches it with b. The time of the audit is the time of batching.
+func AuditBatcher(b Batcher) Batcher {
+	return auditBatcher{b}
+}
+
+// auditBatcher sets the audit of the messages which it batches
+type auditBatcher struct {
+	Batcher
+}
+
+func (b auditBatcher) ProcessDatabaseQuery(ctx context.Context, department DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	if msg.Audit == nil {
+		audit := AuditFromContext(ctx)
+		audit.Time = studentClock.Now().UnixMilli()
+		msg.Audit = audit.ToProto()
+	}
+	return b.Batcher.ProcessDatabaseQuery(ctx, department, msg)
+}
+
+func (b auditBatcher) ShardOf(department DepartmentID) int {
+	return ShardOf(b.Batcher, department)
+}
+
+// AuditAction is the kind of change in an AuditEntry
+type AuditAction uint8
+
+const (
+	// AuditEnroll means that the student is registered in the group
+	AuditEnroll AuditAction = iota + 1
+	// AuditReserve means that the student is added to the reserve queue of the group
+	AuditReserve
+	// AuditDisenroll means that the student has left the group
+	AuditDisenroll
+	// AuditChangeGroup means that the student has moved to the group from another group of the course
+	AuditChangeGroup
+	// AuditPromote means that the student is moved from the reserve queue of the group to its
+	// registered students because of another change
+	AuditPromote
+	// AuditConfirmPromotion means that the student has confirmed their promotion
+	AuditConfirmPromotion
+	// AuditUpdateCapacity means that the capacity of the group has changed
+	AuditUpdateCapacity
+	// AuditAddIntent means that the student wants to move to the group when it has a free seat
+	AuditAddIntent
+	// AuditRemoveIntent means that the student does not want to change their group anymore
+	AuditRemoveIntent
+)
+
+func (a AuditAction) String() string {
+	switch a {
+	case AuditEnroll:
+		return "enroll"
+	case AuditReserve:
+		return "reserve"
+	case AuditDisenroll:
+		return "disenroll"
+	case AuditChangeGroup:
+		return "change_group"
+	case AuditPromote:
+		return "promote"
+	case AuditConfirmPromotion:
+		return "confirm_promotion"
+	case AuditUpdateCapacity:
+		return "update_capacity"
+	case AuditAddIntent:
+		return "add_intent"
+	case AuditRemoveIntent:
+		return "remove_intent"
+	default:
+		return "unknown"
+	}
+}
+
+// AuditEntry is an enrollment change in the audit log
+type AuditEntry struct {
+	Audit
+	Action AuditAction
+	// Zero if the change is not about a single student, like capacity changes
+	StudentID StudentID
+	CourseID  CourseID
+	// Zero if the message of the change does not say
+	GroupID GroupID
+	// The capacity of the group after a capacity change
+	Capacity int
+}
+
+// NewAuditEntries returns the entries of the audit log of a batch message. The messages which
+// are not enrollment changes, like notifications or putting courses, do not have any.
+func NewAuditEntries(message *proto.CourseDatabaseBatchMessage) []AuditEntry {
+	return appendAuditEntries(nil, NewAuditFromProto(message.GetAudit()), message)
+}
+
+// appendAuditEntries appends the entries of a message to result. The inner messages of
+// transactions are audited with the audit of the transaction.
+func appendAuditEntries(result []AuditEntry, audit Audit, message *proto.CourseDatabaseBatchMessage) []AuditEntry {
+	add := func(action AuditAction, studentID uint64, courseID int32, groupID uint32) {
+		result = append(result, AuditEntry{
+			Audit:     audit,
+			Action:    action,
+			StudentID: StudentID(studentID),
+			CourseID:  CourseID(courseID),
+			GroupID:   GroupID(groupID),
+		})
+	}
+	switch action := message.GetAction().(type) {
+	case *proto.CourseDatabaseBatchMessage_Transaction:
+		for _, inner := range action.Transaction.Messages {
+			result = appendAuditEntries(result, audit, inner)
+		}
+	case *proto.CourseDatabaseBatchMessage_Enroll:
+		kind := AuditEnroll
+		if action.Enroll.Reserved {
+			kind = AuditReserve
+		}
+		add(kind, action.Enroll.StudentId, action.Enroll.CourseId, action.Enroll.GroupId)
+	case *proto.CourseDatabaseBatchMessage_Disenroll:
+		add(AuditDisenroll, action.Disenroll.StudentId, action.Disenroll.CourseId, action.Disenroll.GroupId)
+		if action.Disenroll.PromotedStudentId != 0 {
+			add(AuditPromote, action.Disenroll.PromotedStudentId, action.Disenroll.CourseId, action.Disenroll.GroupId)
+		}
+	case *proto.CourseDatabaseBatchMessage_ChangeGroup:
+		add(AuditChangeGroup, action.ChangeGroup.StudentId, action.ChangeGroup.CourseId, action.ChangeGroup.GroupId)
+		if action.ChangeGroup.PromotedStudentId != 0 {
+			// The message does not have the source group
+			add(AuditPromote, action.ChangeGroup.PromotedStudentId, action.ChangeGroup.CourseId, 0)
+		}
+	case *proto.CourseDatabaseBatchMessage_ConfirmPromotion:
+		add(AuditConfirmPromotion, action.ConfirmPromotion.StudentId, action.ConfirmPromotion.CourseId, 0)
+	case *proto.CourseDatabaseBatchMessage_UpdateCapacity:
+		add(AuditUpdateCapacity, 0, action.UpdateCapacity.CourseId, action.UpdateCapacity.GroupId)
+		result[len(result)-1].Capacity = int(action.UpdateCapacity.NewCapacity)
+		for _, id := range action.UpdateCapacity.MovedStudents {
+			add(AuditPromote, id, action.UpdateCapacity.CourseId, action.UpdateCapacity.GroupId)
+		}
+	case *proto.CourseDatabaseBatchMessage_AddIntent:
+		add(AuditAddIntent, action.AddIntent.StudentId, action.AddIntent.CourseId, action.AddIntent.GroupId)
+	case *proto.CourseDatabaseBatchMessage_RemoveIntent:
+		add(AuditRemoveIntent, action.RemoveIntent.StudentId, action.RemoveIntent.CourseId, 0)
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewAuditEntries(t *testing.T) {
+	staff := Audit{ActorKind: ActorStaff, ActorID: 100, Time: 1000, Origin: "ForceDisenroll", Reason: "duplicate"}
+	tests := []struct {
+		name     string
+		message  *proto.CourseDatabaseBatchMessage
+		expected []AuditEntry
+	}{
+		{
+			name: "reserve",
+			message: &proto.CourseDatabaseBatchMessage{
+				Audit: staff.ToProto(),
+				Action: &proto.CourseDatabaseBatchMessage_Enroll{Enroll: &proto.CourseDatabaseBatchEnrollMessage{
+					StudentId: 1, CourseId: 10, GroupId: 2, Reserved: true,
+				}},
+			},
+			expected: []AuditEntry{{Audit: staff, Action: AuditReserve, StudentID: 1, CourseID: 10, GroupID: 2}},
+		},
+		{
+			name: "disenroll with promotion",
+			message: &proto.CourseDatabaseBatchMessage{
+				Audit: staff.ToProto(),
+				Action: &proto.CourseDatabaseBatchMessage_Disenroll{Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{
+					StudentId: 1, CourseId: 10, GroupId: 2, PromotedStudentId: 3,
+				}},
+			},
+			expected: []AuditEntry{
+				{Audit: staff, Action: AuditDisenroll, StudentID: 1, CourseID: 10, GroupID: 2},
+				{Audit: staff, Action: AuditPromote, StudentID: 3, CourseID: 10, GroupID: 2},
+			},
+		},
+		{
+			name: "capacity",
+			message: &proto.CourseDatabaseBatchMessage{
+				Audit: staff.ToProto(),
+				Action: &proto.CourseDatabaseBatchMessage_UpdateCapacity{UpdateCapacity: &proto.CourseDatabaseBatchUpdateCapacity{
+					CourseId: 10, GroupId: 2, NewCapacity: 5, MovedStudents: []uint64{3, 4},
+				}},
+			},
+			expected: []AuditEntry{
+				{Audit: staff, Action: AuditUpdateCapacity, CourseID: 10, GroupID: 2, Capacity: 5},
+				{Audit: staff, Action: AuditPromote, StudentID: 3, CourseID: 10, GroupID: 2},
+				{Audit: staff, Action: AuditPromote, StudentID: 4, CourseID: 10, GroupID: 2},
+			},
+		},
+		{
+			name: "transaction",
+			message: &proto.CourseDatabaseBatchMessage{
+				Audit: staff.ToProto(),
+				Action: &proto.CourseDatabaseBatchMessage_Transaction{Transaction: &proto.CourseDatabaseBatchTransaction{
+					Messages: []*proto.CourseDatabaseBatchMessage{
+						{Action: &proto.CourseDatabaseBatchMessage_ChangeGroup{ChangeGroup: &proto.CourseDatabaseBatchChangeGroupMessage{
+							StudentId: 1, CourseId: 10, GroupId: 2, PromotedStudentId: 5,
+						}}},
+						{Action: &proto.CourseDatabaseBatchMessage_RemoveIntent{RemoveIntent: &proto.CourseDatabaseBatchRemoveIntent{
+							StudentId: 1, CourseId: 11,
+						}}},
+					},
+				}},
+			},
+			expected: []AuditEntry{
+				{Audit: staff, Action: AuditChangeGroup, StudentID: 1, CourseID: 10, GroupID: 2},
+				{Audit: staff, Action: AuditPromote, StudentID: 5, CourseID: 10},
+				{Audit: staff, Action: AuditRemoveIntent, StudentID: 1, CourseID: 11},
+			},
+		},
+		{
+			name: "not audited",
+			message: &proto.CourseDatabaseBatchMessage{
+				Action: &proto.CourseDatabaseBatchMessage_ConfirmPromotion{ConfirmPromotion: &proto.CourseDatabaseBatchConfirmPromotion{
+					StudentId: 1, CourseId: 10,
+				}},
+			},
+			expected: []AuditEntry{{Action: AuditConfirmPromotion, StudentID: 1, CourseID: 10}},
+		},
+		{
+			name: "not a change",
+			message: &proto.CourseDatabaseBatchMessage{
+				Audit:  staff.ToProto(),
+				Action: &proto.CourseDatabaseBatchMessage_PutStudent{PutStudent: &proto.CourseDatabaseBatchPutStudent{StudentId: 1}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, NewAuditEntries(test.message))
+		})
+	}
+}
+
+func TestAuditBatcher(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	batcher := new(inMemoryBatcher)
+	auditBatcher := AuditBatcher(batcher)
+	// From the context
+	ctx := WithAudit(context.Background(), Audit{ActorKind: ActorStudent, ActorID: 1, Origin: "StudentEnroll"})
+	assert.NoError(t, auditBatcher.ProcessDatabaseQuery(ctx, 1, new(proto.CourseDatabaseBatchMessage)))
+	assert.Equal(t, Audit{ActorKind: ActorStudent, ActorID: 1, Time: clk.Now().UnixMilli(), Origin: "StudentEnroll"},
+		NewAuditFromProto(batcher.messages[0].data.Audit))
+	// The system
+	assert.NoError(t, auditBatcher.ProcessDatabaseQuery(context.Background(), 1, new(proto.CourseDatabaseBatchMessage)))
+	assert.Equal(t, Audit{ActorKind: ActorSystem, Time: clk.Now().UnixMilli()}, NewAuditFromProto(batcher.messages[1].data.Audit))
+	// Already audited
+	audit := Audit{ActorKind: ActorStaff, ActorID: 100, Time: 10}
+	assert.NoError(t, auditBatcher.ProcessDatabaseQuery(ctx, 1, &proto.CourseDatabaseBatchMessage{Audit: audit.ToProto()}))
+	assert.Equal(t, audit, NewAuditFromProto(batcher.messages[2].data.Audit))
+}
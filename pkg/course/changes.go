@@ -0,0 +1,270 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Change is a single action of Student.ApplyChanges
+type Change struct {
+	// One of ActionEnroll, ActionDisenroll and ActionChangeGroup
+	Action   Action
+	CourseID CourseID
+	// The group to enroll in or change to. It's ignored when disenrolling.
+	GroupID GroupID
+}
+
+// plannedChange is a Change alongside the groups which it changes
+type plannedChange struct {
+	Change
+	// The group which the student leaves. Nil when enrolling.
+	source *Course
+	// The group which the student joins. Nil when disenrolling.
+	destination *Course
+}
+
+// ApplyChanges applies a list of enroll, disenroll and change group actions atomically; either all
+// of them are applied or none of them. Each course can be changed only once. Unlike doing the
+// actions one by one, the unit limit, the class and exam times and the requisites are checked
+// against the final courses of the student; so for example, a course can be replaced with another
+// course which is held at the same time. The errors of a single change are returned as ChangeErr.
+//
+// All changes are batched as a single transaction message in the queue of their departments; so all
+// changed courses must be in departments which the batcher sends to the same shard (see ShardOf),
+// otherwise MixedDepartmentsErr is returned. The queues are applied in the database independently,
+// so a transaction in one queue could not be ordered against the other messages of a course in
+// another queue. The changes in different shards must be applied with separate calls, which are not
+// atomic together. The changed groups are locked in the order of their course ID and group ID.
+func (s *Student) ApplyChanges(ctx context.Context, courses *Courses, changes []Change, batcher Batcher) error {
+	if batcher == nil {
+		panic("nil batcher")
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	// We check the schedule of every action at very first
+	var actions Action
+	for i, change := range changes {
+		if change.Action != ActionEnroll && change.Action != ActionDisenroll && change.Action != ActionChangeGroup {
+			return ChangeErr{Index: i, Err: InvalidChangeErr}
+		}
+		actions |= change.Action
+	}
+	for _, action := range []Action{ActionEnroll, ActionDisenroll, ActionChangeGroup} {
+		if actions&action != 0 {
+			if err := s.checkSchedule(courses, action); err != nil {
+				return err
+			}
+		}
+	}
+	// Lock the user to do stuff with them
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	planned, err := s.planChanges(courses, changes, batcher)
+	if err != nil {
+		return err
+	}
+	if err = s.applyPlannedChanges(ctx, planned, batcher); err != nil {
+		return err
+	}
+	// Update the student
+	for _, change := range planned {
+		if change.source != nil {
+			s.RegisteredUnits -= change.source.Units
+			s.RemainingActions--
+			delete(s.RegisteredCourses, change.CourseID)
+			s.threadUnsafeClearIntent(courses, change.CourseID)
+		}
+		if change.destination != nil {
+			s.RegisteredUnits += change.destination.Units
+			s.RegisteredCourses[change.CourseID] = change.GroupID
+		}
+	}
+	return nil
+}
+
+// planChanges finds the groups of the changes and checks everything except the capacity of the
+// groups against the final courses of the student. The student must be locked.
+func (s *Student) planChanges(courses *Courses, changes []Change, batcher Batcher) ([]plannedChange, error) {
+	planned := make([]plannedChange, len(changes))
+	registered := maps.Clone(s.RegisteredCourses)
+	units := int(s.RegisteredUnits)
+	usedActions := 0
+	for i, change := range changes {
+		planned[i].Change = change
+		if slices.ContainsFunc(changes[:i], func(other Change) bool { return other.CourseID == change.CourseID }) {
+			return nil, ChangeErr{Index: i, Err: DuplicateChangeErr}
+		}
+		// Find the source
+		groupID, enrolled := s.RegisteredCourses[change.CourseID]
+		if change.Action == ActionEnroll {
+			if enrolled {
+				return nil, ChangeErr{Index: i, Err: AlreadyRegisteredErr}
+			}
+		} else {
+			if !enrolled {
+				return nil, ChangeErr{Index: i, Err: NotExistsErr}
+			}
+			if change.Action == ActionChangeGroup && groupID == change.GroupID {
+				return nil, ChangeErr{Index: i, Err: PlayedYourselfErr}
+			}
+			planned[i].source = courses.GetCourse(change.CourseID, groupID)
+			if planned[i].source == nil {
+				panic(fmt.Sprintf("invalid registered lesson %d-%d for user %d", change.CourseID, groupID, s.ID))
+			}
+			units -= int(planned[i].source.Units)
+			usedActions++
+			delete(registered, change.CourseID)
+		}
+		// Find the destination
+		if change.Action != ActionDisenroll {
+			planned[i].destination = courses.GetCourse(change.CourseID, change.GroupID)
+			if planned[i].destination == nil {
+				return nil, ChangeErr{Index: i, Err: NotExistsErr}
+			}
+			if err := planned[i].destination.checkEligibility(s); err != nil {
+				return nil, ChangeErr{Index: i, Err: err}
+			}
+			units += int(planned[i].destination.Units)
+			registered[change.CourseID] = change.GroupID
+		}
+		// All messages must go to the same queue
+		if i > 0 && ShardOf(batcher, planned[i].department()) != ShardOf(batcher, planned[0].department()) {
+			return nil, MixedDepartmentsErr
+		}
+	}
+	if units > int(s.MaxUnits) {
+		return nil, UnitLimitReachedErr
+	}
+	if usedActions > int(s.RemainingActions) {
+		return nil, NoRemainingActionsErr
+	}
+	// Check the final courses
+	for i, change := range planned {
+		var err error
+		switch change.Action {
+		case ActionEnroll:
+			err = s.checkRequisites(courses, change.CourseID, registered)
+		case ActionDisenroll:
+			err = s.checkCorequisiteOf(courses, change.CourseID, registered)
+		}
+		if err == nil && change.destination != nil {
+			err = checkConflicts(courses, change.destination, registered)
+		}
+		if err != nil {
+			return nil, ChangeErr{Index: i, Err: err}
+		}
+	}
+	return planned, nil
+}
+
+// applyPlannedChanges locks the groups of the changes, checks their capacity and then batches and
+// applies the changes on them. The student must be locked.
+func (s *Student) applyPlannedChanges(ctx context.Context, planned []plannedChange, batcher Batcher) error {
+	// Lock the groups in order to avoid deadlocks. This is the same order which ChangeGroupOfStudent
+	// uses for the groups of a single course.
+	groups := make([]*Course, 0, len(planned)*2)
+	for _, change := range planned {
+		if change.source != nil {
+			groups = append(groups, change.source)
+		}
+		if change.destination != nil {
+			groups = append(groups, change.destination)
+		}
+	}
+	slices.SortFunc(groups, func(a, b *Course) int {
+		if a.ID != b.ID {
+			return cmp.Compare(a.ID, b.ID)
+		}
+		return cmp.Compare(a.GroupID, b.GroupID)
+	})
+	for _, group := range groups {
+		group.mu.Lock()
+	}
+	defer func() {
+		for _, group := range groups {
+			group.mu.Unlock()
+		}
+	}()
+	// Check the capacities and create the messages. The deadlines are the confirmation deadlines
+	// of the students which are promoted in the source groups.
+	messages := make([]*proto.CourseDatabaseBatchMessage, len(planned))
+	deadlines := make([]int64, len(planned))
+	for i, change := range planned {
+		var promoted StudentID
+		if change.source != nil {
+			promoted, deadlines[i] = change.source.threadUnsafePromotion(s.ID)
+		}
+		switch change.Action {
+		case ActionEnroll:
+			if !change.destination.threadUnsafeCanBeEnrolled() {
+				return ChangeErr{Index: i, Err: NoCapacityLeftErr}
+			}
+			messages[i] = &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Enroll{
+				Enroll: &proto.CourseDatabaseBatchEnrollMessage{
+					StudentId: uint64(s.ID),
+					CourseId:  int32(change.CourseID),
+					GroupId:   uint32(change.GroupID),
+					Reserved:  change.destination.threadUnsafeTakenSeats() >= change.destination.Capacity,
+				},
+			}}
+		case ActionDisenroll:
+			messages[i] = &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Disenroll{
+				Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{
+					StudentId:            uint64(s.ID),
+					CourseId:             int32(change.CourseID),
+					GroupId:              uint32(change.source.GroupID),
+					ConsumesAction:       true,
+					PromotedStudentId:    uint64(promoted),
+					ConfirmationDeadline: deadlines[i],
+				},
+			}}
+		case ActionChangeGroup:
+			if !change.destination.threadUnsafeCanBeEnrolled() {
+				return ChangeErr{Index: i, Err: NoCapacityLeftErr}
+			}
+			messages[i] = &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_ChangeGroup{
+				ChangeGroup: &proto.CourseDatabaseBatchChangeGroupMessage{
+					StudentId:            uint64(s.ID),
+					CourseId:             int32(change.CourseID),
+					GroupId:              uint32(change.GroupID),
+					Reserved:             change.destination.threadUnsafeTakenSeats() >= change.destination.Capacity,
+					ConsumesAction:       true,
+					PromotedStudentId:    uint64(promoted),
+					ConfirmationDeadline: deadlines[i],
+				},
+			}}
+		}
+	}
+	// Batch them as a single message
+	err := batcher.ProcessDatabaseQuery(ctx, planned[0].department(), &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_Transaction{
+			Transaction: &proto.CourseDatabaseBatchTransaction{Messages: messages},
+		},
+	})
+	if err != nil {
+		return BatchError{err}
+	}
+	// Apply them. Nothing can fail because everything is checked.
+	for i, change := range planned {
+		if change.destination != nil {
+			_, _ = change.destination.threadUnsafeEnrollStudent(ctx, s.ID, nil)
+		}
+		if change.source != nil {
+			change.source.threadUnsafeRemoveStudent(s.ID, deadlines[i])
+		}
+	}
+	return nil
+}
+
+// department returns the department of the course of the change
+func (c plannedChange) department() DepartmentID {
+	if c.source != nil {
+		return c.source.Department
+	}
+	return c.destination.Department
+}
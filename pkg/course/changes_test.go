@@ -0,0 +1,198 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/util"
+	"context"
+	"errors"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStudentApplyChanges(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	// newState creates course 1 with two groups, courses 2 and 3 which are held at the same time,
+	// course 4 which is full and course 5 in another department. Every course has 3 units. The
+	// student can take 9 units and is enrolled in course 1 group 1 and course 2.
+	newState := func() (*Courses, *Student) {
+		newCourse := func(id CourseID, groupID GroupID, department DepartmentID, capacity int) *Course {
+			return &Course{
+				ID:                 id,
+				GroupID:            groupID,
+				Department:         department,
+				Units:              3,
+				Capacity:           capacity,
+				RegisteredStudents: make(map[StudentID]struct{}),
+				ReserveQueue:       util.NewQueue[StudentID](),
+			}
+		}
+		coursesMap := map[CourseID][]*Course{
+			1: {newCourse(1, 1, 1, 5), newCourse(1, 2, 1, 5)},
+			2: {newCourse(2, 1, 1, 5)},
+			3: {newCourse(3, 1, 1, 5)},
+			4: {newCourse(4, 1, 1, 0)},
+			5: {newCourse(5, 1, 2, 5)},
+		}
+		coursesMap[2][0].ClassHeldTime = NewClassTime([]time.Weekday{time.Monday}, NewTimeOnly(600), NewTimeOnly(690))
+		coursesMap[3][0].ClassHeldTime = NewClassTime([]time.Weekday{time.Monday}, NewTimeOnly(600), NewTimeOnly(690))
+		courses := NewCourses(coursesMap)
+		student := &Student{
+			ID:                  1,
+			EnrollmentStartTime: clk.Now().Add(-time.Minute).UnixMilli(),
+			RemainingActions:    2,
+			MaxUnits:            9,
+			RegisteredCourses:   make(map[CourseID]GroupID),
+		}
+		require.NoError(t, student.EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		require.NoError(t, student.EnrollCourse(context.Background(), courses, 2, 1, noOpBatcher{}))
+		return courses, student
+	}
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			Name     string
+			Changes  []Change
+			Expected error
+		}{
+			{
+				Name:     "invalid action",
+				Changes:  []Change{{Action: ActionEnroll | ActionDisenroll, CourseID: 3, GroupID: 1}},
+				Expected: ChangeErr{Index: 0, Err: InvalidChangeErr},
+			},
+			{
+				Name:     "duplicate course",
+				Changes:  []Change{{Action: ActionDisenroll, CourseID: 2}, {Action: ActionEnroll, CourseID: 2, GroupID: 1}},
+				Expected: ChangeErr{Index: 1, Err: DuplicateChangeErr},
+			},
+			{
+				Name:     "not enrolled",
+				Changes:  []Change{{Action: ActionDisenroll, CourseID: 3}},
+				Expected: ChangeErr{Index: 0, Err: NotExistsErr},
+			},
+			{
+				Name:     "conflict with a kept course",
+				Changes:  []Change{{Action: ActionChangeGroup, CourseID: 1, GroupID: 2}, {Action: ActionEnroll, CourseID: 3, GroupID: 1}},
+				Expected: ChangeErr{Index: 1, Err: ClassTimeConflictErr{CourseID: 2, GroupID: 1}},
+			},
+			{
+				Name:     "mixed departments",
+				Changes:  []Change{{Action: ActionDisenroll, CourseID: 2}, {Action: ActionEnroll, CourseID: 3, GroupID: 1}, {Action: ActionEnroll, CourseID: 5, GroupID: 1}},
+				Expected: MixedDepartmentsErr,
+			},
+			{
+				Name:     "unit limit",
+				Changes:  []Change{{Action: ActionEnroll, CourseID: 3, GroupID: 1}, {Action: ActionEnroll, CourseID: 4, GroupID: 1}},
+				Expected: UnitLimitReachedErr,
+			},
+			{
+				Name:     "remaining actions",
+				Changes:  []Change{{Action: ActionDisenroll, CourseID: 1}, {Action: ActionDisenroll, CourseID: 2}, {Action: ActionEnroll, CourseID: 3, GroupID: 1}},
+				Expected: NoRemainingActionsErr,
+			},
+			{
+				Name:     "full course",
+				Changes:  []Change{{Action: ActionChangeGroup, CourseID: 1, GroupID: 2}, {Action: ActionEnroll, CourseID: 4, GroupID: 1}},
+				Expected: ChangeErr{Index: 1, Err: NoCapacityLeftErr},
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				courses, student := newState()
+				student.RemainingActions = 1
+				batcher := new(inMemoryBatcher)
+				assert.Equal(t, test.Expected, student.ApplyChanges(context.Background(), courses, test.Changes, batcher))
+				// Nothing is changed
+				assert.Empty(t, batcher.messages)
+				assert.Equal(t, map[CourseID]GroupID{1: 1, 2: 1}, student.RegisteredCourses)
+				assert.Equal(t, uint8(6), student.RegisteredUnits)
+				assert.Equal(t, uint8(1), student.RemainingActions)
+				assert.Contains(t, courses.GetCourse(1, 1).RegisteredStudents, student.ID)
+				assert.Empty(t, courses.GetCourse(1, 2).RegisteredStudents)
+			})
+		}
+	})
+	t.Run("apply", func(t *testing.T) {
+		courses, student := newState()
+		// Course 3 cannot be taken alone because it conflicts with course 2
+		assert.Equal(t, ClassTimeConflictErr{CourseID: 2, GroupID: 1}, student.EnrollCourse(context.Background(), courses, 3, 1, noOpBatcher{}))
+		// Replacing course 2 with course 3 works
+		batcher := new(inMemoryBatcher)
+		changes := []Change{
+			{Action: ActionDisenroll, CourseID: 2},
+			{Action: ActionEnroll, CourseID: 3, GroupID: 1},
+			{Action: ActionChangeGroup, CourseID: 1, GroupID: 2},
+		}
+		require.NoError(t, student.ApplyChanges(context.Background(), courses, changes, batcher))
+		assert.Equal(t, map[CourseID]GroupID{1: 2, 3: 1}, student.RegisteredCourses)
+		assert.Equal(t, uint8(6), student.RegisteredUnits)
+		assert.Equal(t, uint8(0), student.RemainingActions)
+		assert.Empty(t, courses.GetCourse(1, 1).RegisteredStudents)
+		assert.Empty(t, courses.GetCourse(2, 1).RegisteredStudents)
+		assert.Contains(t, courses.GetCourse(1, 2).RegisteredStudents, student.ID)
+		assert.Contains(t, courses.GetCourse(3, 1).RegisteredStudents, student.ID)
+		// Everything is batched as a single message
+		require.Len(t, batcher.messages, 1)
+		assert.Equal(t, DepartmentID(1), batcher.messages[0].dep)
+		transaction := batcher.messages[0].data.GetTransaction()
+		require.NotNil(t, transaction)
+		require.Len(t, transaction.Messages, 3)
+		assert.True(t, transaction.Messages[0].GetDisenroll().ConsumesAction)
+		assert.False(t, transaction.Messages[1].GetEnroll().Reserved)
+		assert.True(t, transaction.Messages[2].GetChangeGroup().ConsumesAction)
+		// Replaying it gives the same state
+		replayedCourses, replayedStudent := newState()
+		require.NoError(t, ReplayMessage(replayedCourses, map[StudentID]*Student{1: replayedStudent}, batcher.messages[0].data))
+		assert.Equal(t, student.RegisteredCourses, replayedStudent.RegisteredCourses)
+		assert.Equal(t, student.RegisteredUnits, replayedStudent.RegisteredUnits)
+		assert.Equal(t, student.RemainingActions, replayedStudent.RemainingActions)
+	})
+	t.Run("cross-department schedule", func(t *testing.T) {
+		courses, student := newState()
+		courses.GetCourse(5, 1).ClassHeldTime = NewClassTime([]time.Weekday{time.Monday}, NewTimeOnly(600), NewTimeOnly(690))
+		// Every department is in the default queue; so course 2 can be replaced with course 5 at once
+		batcher := new(shardedBatcher)
+		changes := []Change{{Action: ActionDisenroll, CourseID: 2}, {Action: ActionEnroll, CourseID: 5, GroupID: 1}}
+		require.NoError(t, student.ApplyChanges(context.Background(), courses, changes, AuditBatcher(batcher)))
+		assert.Equal(t, map[CourseID]GroupID{1: 1, 5: 1}, student.RegisteredCourses)
+		require.Len(t, batcher.messages, 1)
+		assert.Len(t, batcher.messages[0].data.GetTransaction().GetMessages(), 2)
+	})
+	t.Run("cross-shard schedule", func(t *testing.T) {
+		courses, student := newState()
+		courses.GetCourse(5, 1).ClassHeldTime = NewClassTime([]time.Weekday{time.Monday}, NewTimeOnly(600), NewTimeOnly(690))
+		batcher := &shardedBatcher{shards: map[DepartmentID]int{2: 1}}
+		// Course 2 cannot be replaced with course 5 at once because their departments are in different shards
+		changes := []Change{{Action: ActionDisenroll, CourseID: 2}, {Action: ActionEnroll, CourseID: 5, GroupID: 1}}
+		assert.ErrorIs(t, student.ApplyChanges(context.Background(), courses, changes, batcher), MixedDepartmentsErr)
+		assert.Equal(t, map[CourseID]GroupID{1: 1, 2: 1}, student.RegisteredCourses)
+		assert.Empty(t, batcher.messages)
+		// The changes of each department are applied separately
+		require.NoError(t, student.ApplyChanges(context.Background(), courses, changes[:1], batcher))
+		require.NoError(t, student.ApplyChanges(context.Background(), courses, changes[1:], batcher))
+		assert.Equal(t, map[CourseID]GroupID{1: 1, 5: 1}, student.RegisteredCourses)
+		require.Len(t, batcher.messages, 2)
+		assert.Equal(t, DepartmentID(1), batcher.messages[0].dep)
+		assert.Equal(t, DepartmentID(2), batcher.messages[1].dep)
+		// The changes in a department are still checked against the courses of other departments
+		changes = []Change{{Action: ActionEnroll, CourseID: 3, GroupID: 1}}
+		assert.Equal(t, ChangeErr{Index: 0, Err: ClassTimeConflictErr{CourseID: 5, GroupID: 1}},
+			student.ApplyChanges(context.Background(), courses, changes, batcher))
+		assert.Len(t, batcher.messages, 2)
+	})
+	t.Run("batch error", func(t *testing.T) {
+		courses, student := newState()
+		changes := []Change{{Action: ActionEnroll, CourseID: 3, GroupID: 1}, {Action: ActionDisenroll, CourseID: 2}}
+		assert.ErrorAs(t, student.ApplyChanges(context.Background(), courses, changes, errorBatcher{errors.New("broker is down")}), new(BatchError))
+		assert.Equal(t, map[CourseID]GroupID{1: 1, 2: 1}, student.RegisteredCourses)
+		assert.Empty(t, courses.GetCourse(3, 1).RegisteredStudents)
+	})
+}
+
+func TestChangeErr(t *testing.T) {
+	err := ChangeErr{Index: 1, Err: NoCapacityLeftErr}
+	assert.Equal(t, "change 2: this course's capacity is filled", err.Error())
+	assert.ErrorIs(t, err, NoCapacityLeftErr)
+}
@@ -95,6 +95,11 @@ func (t *ClassTime) Scan(value interface{}) error {
 	return nil
 }
 
+// Raw returns the internal value of ClassTime which is stored in database
+func (t *ClassTime) Raw() uint32 {
+	return t.data.Load()
+}
+
 // TimeOnly only and only holds a time between 00:00 and 24:00.
 //
 // Internal representation is basically a number between 0 and 1440 which is calculated with
@@ -5,8 +5,11 @@ import (
 	"CourseEnrollment/pkg/util"
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // CourseID is the type of the course's ID
@@ -19,6 +22,20 @@ type GroupID uint8
 type Courses struct {
 	courses map[CourseID][]*Course
 	mu      sync.RWMutex
+	// The enrollment schedule. Nil means the default schedule which is Student.IsEnrollTimeOK.
+	schedule atomic.Pointer[Schedule]
+	// The prerequisites and co-requisites of courses. Courses without any requisites are not in it.
+	requisites map[CourseID]Requisites
+	// The hooks of courses. It's shared with every course in courses.
+	hooks *courseHooks
+}
+
+// courseHooks are the listeners of the changes of courses
+type courseHooks struct {
+	// The watchers of the seats of courses
+	seats seatWatchers
+	// The sink of the events of students. Nil means that events are not emitted.
+	sink atomic.Pointer[EventSink]
 }
 
 // Course represents a single course
@@ -48,13 +65,36 @@ type Course struct {
 	ClassHeldTime ClassTime
 	// Does this class has a sex lock?
 	SexLock SexLock
+	// Who else can take this course? Nil means everyone.
+	Eligibility *Eligibility
+	// The students which want to move to this group from other groups of the course, in order.
+	// See Student.AddIntent.
+	Intents []StudentID
+	// The registered seats which are held for the students in Intents until FulfillIntents moves
+	// them here. A seat is held in the same lock which frees it, so nobody else can take it. Held
+	// seats are counted as registered students, and the reserve queue is closed while there are any.
+	HeldSeats map[StudentID]struct{}
+	// How long the students which are promoted from the reserve queue have to confirm their seat.
+	// Zero means that no confirmation is needed.
+	ConfirmationWindow time.Duration
+	// The promoted students which have not confirmed their seat yet, to their deadline in unix
+	// milliseconds. See Student.ConfirmPromotion.
+	ConfirmationDeadlines map[StudentID]int64
+	// The hooks of this course. Nil if the course is not in a Courses.
+	hooks *courseHooks
 	// The mutex to work with this course
 	mu sync.RWMutex
 }
 
 // NewCourses creates Courses from its map
 func NewCourses(courses map[CourseID][]*Course) *Courses {
-	return &Courses{courses: courses}
+	hooks := new(courseHooks)
+	for _, groups := range courses {
+		for _, course := range groups {
+			course.hooks = hooks
+		}
+	}
+	return &Courses{courses: courses, hooks: hooks}
 }
 
 // GetCourse will get the course based on group ID and course ID. If the course does not exist,
@@ -72,6 +112,50 @@ func (c *Courses) GetCourse(courseID CourseID, groupID GroupID) *Course {
 	return result
 }
 
+// All returns every group of every course in no specific order
+func (c *Courses) All() []*Course {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make([]*Course, 0, len(c.courses))
+	for _, groups := range c.courses {
+		result = append(result, groups...)
+	}
+	return result
+}
+
+// groupsOf returns a copy of the groups of a course
+func (c *Courses) groupsOf(courseID CourseID) []*Course {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.courses[courseID])
+}
+
+// DepartmentOf returns the department of the groups of a course. The second returned value is
+// false if the course has no groups.
+func (c *Courses) DepartmentOf(courseID CourseID) (DepartmentID, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if groups := c.courses[courseID]; len(groups) != 0 {
+		return groups[0].Department, true
+	}
+	return 0, false
+}
+
+// addCourse adds a new group to the courses. The group must not exist.
+func (c *Courses) addCourse(course *Course) {
+	course.hooks = c.hooks
+	c.mu.Lock()
+	if c.courses == nil {
+		c.courses = make(map[CourseID][]*Course)
+	}
+	c.courses[course.ID] = append(c.courses[course.ID], course)
+	c.mu.Unlock()
+	// The watchers of the whole course also watch the new group
+	course.mu.RLock()
+	course.threadUnsafeNotifyWatchers()
+	course.mu.RUnlock()
+}
+
 // GetDepartmentCoursesProto gets all courses in a department
 func (c *Courses) GetDepartmentCoursesProto(id DepartmentID) *proto.DepartmentCourses {
 	c.mu.RLock()
@@ -121,7 +205,7 @@ func (c *Course) threadUnsafeEnrollStudent(ctx context.Context, studentID Studen
 						StudentId: uint64(studentID),
 						CourseId:  int32(c.ID),
 						GroupId:   uint32(c.GroupID),
-						Reserved:  len(c.RegisteredStudents) == c.Capacity,
+						Reserved:  c.threadUnsafeTakenSeats() >= c.Capacity,
 					},
 				},
 			})
@@ -131,13 +215,15 @@ func (c *Course) threadUnsafeEnrollStudent(ctx context.Context, studentID Studen
 	}
 
 	// At first check the registered count
-	if len(c.RegisteredStudents) < c.Capacity {
+	if c.threadUnsafeTakenSeats() < c.Capacity {
 		c.RegisteredStudents[studentID] = struct{}{}
+		c.threadUnsafeNotifyWatchers()
 		return true, nil
 	}
 	// Next check the reserve queue
 	if c.ReserveQueue.Len() < c.ReserveCapacity {
 		c.ReserveQueue.Enqueue(studentID)
+		c.threadUnsafeNotifyWatchers()
 		return true, nil
 	}
 	// Should never happen because we checked before
@@ -160,6 +246,7 @@ func (c *Course) DisenrollStudent(ctx context.Context, studentID StudentID, batc
 // threadUnsafeDisenrollStudent is basically DisenrollStudent but without locking
 // the course
 func (c *Course) threadUnsafeDisenrollStudent(ctx context.Context, studentID StudentID, batcher Batcher) error {
+	promoted, deadline := c.threadUnsafePromotion(studentID)
 	if batcher != nil {
 		// Put data in batcher
 		err := batcher.ProcessDatabaseQuery(
@@ -168,8 +255,11 @@ func (c *Course) threadUnsafeDisenrollStudent(ctx context.Context, studentID Stu
 			&proto.CourseDatabaseBatchMessage{
 				Action: &proto.CourseDatabaseBatchMessage_Disenroll{
 					Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{
-						StudentId: uint64(studentID),
-						CourseId:  int32(c.ID),
+						StudentId:            uint64(studentID),
+						CourseId:             int32(c.ID),
+						GroupId:              uint32(c.GroupID),
+						PromotedStudentId:    uint64(promoted),
+						ConfirmationDeadline: deadline,
 					},
 				},
 			})
@@ -177,26 +267,80 @@ func (c *Course) threadUnsafeDisenrollStudent(ctx context.Context, studentID Stu
 			return BatchError{err}
 		}
 	}
+	c.threadUnsafeRemoveStudent(studentID, deadline)
+	return nil
+}
+
+// threadUnsafePromotion returns the student which is promoted from the reserve queue if the given
+// student leaves this course, alongside the deadline which they must confirm their seat before.
+// The student is zero if nobody is promoted, and the deadline is zero if no confirmation is needed.
+func (c *Course) threadUnsafePromotion(leaving StudentID) (StudentID, int64) {
+	if _, registered := c.RegisteredStudents[leaving]; !registered || c.ReserveQueue.Len() == 0 {
+		return 0, 0
+	}
+	return c.ReserveQueue.Peek(), c.threadUnsafeConfirmationDeadline()
+}
+
+// threadUnsafeConfirmationDeadline returns the deadline of the students which are promoted right
+// now in unix milliseconds. Zero means that no confirmation is needed.
+func (c *Course) threadUnsafeConfirmationDeadline() int64 {
+	if c.ConfirmationWindow == 0 {
+		return 0
+	}
+	return studentClock.Now().Add(c.ConfirmationWindow).UnixMilli()
+}
+
+// threadUnsafeSetConfirmationDeadline sets the confirmation deadline of a promoted student.
+// Zero deadline does nothing.
+func (c *Course) threadUnsafeSetConfirmationDeadline(studentID StudentID, deadline int64) {
+	if deadline == 0 {
+		return
+	}
+	if c.ConfirmationDeadlines == nil {
+		c.ConfirmationDeadlines = make(map[StudentID]int64)
+	}
+	c.ConfirmationDeadlines[studentID] = deadline
+}
+
+// threadUnsafeRemoveStudent removes the student from this course without batching anything. If
+// the student is registered, the first student in the reserve queue is promoted with the given
+// confirmation deadline.
+//
+// Will panic if the student is not enrolled in course.
+func (c *Course) threadUnsafeRemoveStudent(studentID StudentID, deadline int64) {
+	delete(c.ConfirmationDeadlines, studentID)
 	// Check registered list
 	if _, registered := c.RegisteredStudents[studentID]; registered {
 		delete(c.RegisteredStudents, studentID)
 		// Now put first person from reserve queue into registered users
 		// (if exists)
 		if c.ReserveQueue.Len() != 0 {
-			c.RegisteredStudents[c.ReserveQueue.Dequeue()] = struct{}{}
+			promoted := c.ReserveQueue.Dequeue()
+			c.RegisteredStudents[promoted] = struct{}{}
+			c.threadUnsafeSetConfirmationDeadline(promoted, deadline)
+			c.threadUnsafeEmit(EventPromoted, promoted, 0)
 		}
+		c.threadUnsafeHoldSeats(c.Intents)
+		c.threadUnsafeNotifyWatchers()
 		// Done
-		return nil
+		return
 	}
 	// Otherwise remove from queue
 	if !c.ReserveQueue.Remove(studentID) {
 		panic(fmt.Sprintf("user %d has lesson %d-%d in their registered courses but lesson map does not have this user", studentID, c.ID, c.GroupID))
 	}
-	return nil
+	c.threadUnsafeNotifyWatchers()
 }
 
 // ChangeGroupOfStudent tries to change the group of a student between two courses
 func (c *Course) ChangeGroupOfStudent(ctx context.Context, studentID StudentID, other *Course, batcher Batcher) (bool, error) {
+	return c.changeGroupOfStudent(ctx, studentID, other, true, batcher)
+}
+
+// changeGroupOfStudent is ChangeGroupOfStudent which only moves the student to the reserve queue of
+// the other course if toReserve is true. The seat which is held for the student in the other course
+// is taken if there is one.
+func (c *Course) changeGroupOfStudent(ctx context.Context, studentID StudentID, other *Course, toReserve bool, batcher Batcher) (bool, error) {
 	if batcher == nil {
 		panic("nil batcher")
 	}
@@ -225,19 +369,25 @@ func (c *Course) ChangeGroupOfStudent(ctx context.Context, studentID StudentID,
 		}
 	}
 	// Check the capacity
-	if !other.threadUnsafeCanBeEnrolled() {
+	_, held := other.HeldSeats[studentID]
+	reserved := !held && other.threadUnsafeTakenSeats() >= other.Capacity
+	if !held && (!other.threadUnsafeCanBeEnrolled() || reserved && !toReserve) {
 		return false, nil
 	}
 	// Send data in batcher
+	promoted, deadline := c.threadUnsafePromotion(studentID)
 	err := batcher.ProcessDatabaseQuery(
 		ctx,
 		c.Department,
 		&proto.CourseDatabaseBatchMessage{
 			Action: &proto.CourseDatabaseBatchMessage_ChangeGroup{
 				ChangeGroup: &proto.CourseDatabaseBatchChangeGroupMessage{
-					StudentId: uint64(studentID),
-					CourseId:  int32(c.ID),
-					GroupId:   uint32(other.GroupID),
+					StudentId:            uint64(studentID),
+					CourseId:             int32(c.ID),
+					GroupId:              uint32(other.GroupID),
+					Reserved:             reserved,
+					PromotedStudentId:    uint64(promoted),
+					ConfirmationDeadline: deadline,
 				},
 			},
 		})
@@ -245,11 +395,12 @@ func (c *Course) ChangeGroupOfStudent(ctx context.Context, studentID StudentID,
 		return false, BatchError{err}
 	}
 	// Now try to add it to other course
+	delete(other.HeldSeats, studentID)
 	if ok, _ := other.threadUnsafeEnrollStudent(ctx, studentID, nil); !ok {
 		panic("could not change group due to capacity and a is message in broker")
 	}
 	// Now remove the user from this course
-	_ = c.threadUnsafeDisenrollStudent(ctx, studentID, nil) // no error because no batcher
+	c.threadUnsafeRemoveStudent(studentID, deadline)
 	return true, nil
 }
 
@@ -264,7 +415,7 @@ func (c *Course) ForceEnroll(ctx context.Context, studentID StudentID, batcher B
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	// Check the capacity
-	if len(c.RegisteredStudents) == c.Capacity {
+	if c.threadUnsafeTakenSeats() >= c.Capacity {
 		// Update capacity
 		err := batcher.ProcessDatabaseQuery(
 			ctx,
@@ -301,13 +452,20 @@ func (c *Course) ForceEnroll(ctx context.Context, studentID StudentID, batcher B
 	}
 	// Add user
 	c.RegisteredStudents[studentID] = struct{}{}
+	c.threadUnsafeNotifyWatchers()
+	c.threadUnsafeEmit(EventForceEnrolled, studentID, 0)
 	return nil
 }
 
 // threadUnsafeCanBeEnrolled checks if one student can enroll in a course.
 // It doesn't lock anything, so it's thread unsafe.
 func (c *Course) threadUnsafeCanBeEnrolled() bool {
-	return len(c.RegisteredStudents) < c.Capacity || c.ReserveQueue.Len() < c.ReserveCapacity
+	return c.threadUnsafeTakenSeats() < c.Capacity || len(c.HeldSeats) == 0 && c.ReserveQueue.Len() < c.ReserveCapacity
+}
+
+// threadUnsafeTakenSeats returns the number of registered students and held seats
+func (c *Course) threadUnsafeTakenSeats() int {
+	return len(c.RegisteredStudents) + len(c.HeldSeats)
 }
 
 // GetStudentQueuePosition gets the position of a user in queue.
@@ -345,7 +503,7 @@ func (c *Course) threadUnsafeToProtoCourse() *proto.CourseData {
 		GroupId:         uint32(c.GroupID),
 		Units:           uint32(c.Units),
 		Capacity:        int32(c.Capacity),
-		RegisteredCount: uint32(len(c.RegisteredStudents)),
+		RegisteredCount: uint32(c.threadUnsafeTakenSeats()),
 		ExamTime:        c.ExamTime.Load(),
 		Lecturer:        c.Lecturer,
 	}
@@ -376,6 +534,7 @@ func (c *Course) ToStudentCourseDataProto(std StudentID) *proto.StudentCourseDat
 	result := &proto.StudentCourseData{
 		Course:               c.threadUnsafeToProtoCourse(),
 		ReserveQueuePosition: uint32(position),
+		ConfirmationDeadline: c.ConfirmationDeadlines[std],
 	}
 	c.mu.RUnlock()
 	return result
@@ -402,6 +561,14 @@ func (c *Course) ToStudentsOfCourseResponseProto() *proto.StudentsOfCourseRespon
 	return result
 }
 
+// ConfirmationDeadlinesOfStudents returns a copy of the confirmation deadlines of the promoted
+// students in milliseconds
+func (c *Course) ConfirmationDeadlinesOfStudents() map[StudentID]int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return maps.Clone(c.ConfirmationDeadlines)
+}
+
 // UpdateCapacity will update the courses
 func (c *Course) UpdateCapacity(ctx context.Context, newCapacity int, batcher Batcher) error {
 	if batcher == nil {
@@ -410,13 +577,34 @@ func (c *Course) UpdateCapacity(ctx context.Context, newCapacity int, batcher Ba
 	// Lock to update the course
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	update, err := c.threadUnsafeCapacityUpdate(newCapacity)
+	if err != nil || update == nil {
+		return err
+	}
+	// Batch it
+	err = batcher.ProcessDatabaseQuery(
+		ctx,
+		c.Department,
+		&proto.CourseDatabaseBatchMessage{
+			Action: &proto.CourseDatabaseBatchMessage_UpdateCapacity{UpdateCapacity: update},
+		})
+	if err != nil {
+		return BatchError{err}
+	}
+	c.threadUnsafeApplyCapacityUpdate(update)
+	return nil
+}
+
+// threadUnsafeCapacityUpdate creates the message which changes the capacity of the course to
+// newCapacity. Nil is returned if the capacity does not change.
+func (c *Course) threadUnsafeCapacityUpdate(newCapacity int) (*proto.CourseDatabaseBatchUpdateCapacity, error) {
 	// Check if new capacity is less than registered amount
-	if len(c.RegisteredStudents) > newCapacity {
-		return LowerCapacityThanRegistered
+	if c.threadUnsafeTakenSeats() > newCapacity {
+		return nil, LowerCapacityThanRegistered
 	}
 	// Check if new capacity is old capacity
 	if c.Capacity == newCapacity {
-		return nil // do nothing
+		return nil, nil // do nothing
 	}
 	// Get the users which are going to be moved from reserve queue to main registered users.
 	// We must add to registered users for Min(capacity difference, reserve queue len) times.
@@ -426,29 +614,36 @@ func (c *Course) UpdateCapacity(ctx context.Context, newCapacity int, batcher Ba
 	for i, v := range reservedMovedUsers {
 		reservedMovedUsersUint[i] = uint64(v)
 	}
-	// Batch it
-	err := batcher.ProcessDatabaseQuery(
-		ctx,
-		c.Department,
-		&proto.CourseDatabaseBatchMessage{
-			Action: &proto.CourseDatabaseBatchMessage_UpdateCapacity{
-				UpdateCapacity: &proto.CourseDatabaseBatchUpdateCapacity{
-					CourseId:      int32(c.ID),
-					GroupId:       uint32(c.GroupID),
-					NewCapacity:   int32(newCapacity),
-					MovedStudents: reservedMovedUsersUint,
-				},
-			},
-		})
-	if err != nil {
-		return BatchError{err}
-	}
+	var deadline int64
+	if len(reservedMovedUsers) != 0 {
+		deadline = c.threadUnsafeConfirmationDeadline()
+	}
+	return &proto.CourseDatabaseBatchUpdateCapacity{
+		CourseId:             int32(c.ID),
+		GroupId:              uint32(c.GroupID),
+		NewCapacity:          int32(newCapacity),
+		MovedStudents:        reservedMovedUsersUint,
+		ConfirmationDeadline: deadline,
+	}, nil
+}
+
+// threadUnsafeApplyCapacityUpdate applies a message of threadUnsafeCapacityUpdate after it's batched
+func (c *Course) threadUnsafeApplyCapacityUpdate(update *proto.CourseDatabaseBatchUpdateCapacity) {
 	// Remove from queue and add to main registered users.
-	for i := len(reservedMovedUsers); i > 0; i-- {
-		c.RegisteredStudents[c.ReserveQueue.Dequeue()] = struct{}{}
+	for range update.MovedStudents {
+		promoted := c.ReserveQueue.Dequeue()
+		c.RegisteredStudents[promoted] = struct{}{}
+		c.threadUnsafeSetConfirmationDeadline(promoted, update.ConfirmationDeadline)
 	}
 	// Update the capacity
-	c.Capacity = newCapacity
-	// Done
-	return nil
+	c.Capacity = int(update.NewCapacity)
+	c.threadUnsafeHoldSeats(c.Intents)
+	c.threadUnsafeNotifyWatchers()
+	// Tell the students in the reserve queue
+	for _, id := range update.MovedStudents {
+		c.threadUnsafeEmit(EventPromoted, StudentID(id), 0)
+	}
+	for i, id := range c.ReserveQueue.CopyAsArray() {
+		c.threadUnsafeEmit(EventCapacityChanged, id, i+1)
+	}
 }
@@ -190,8 +190,10 @@ func TestCourseUnrollStudent(t *testing.T) {
 				data: &proto.CourseDatabaseBatchMessage{
 					Action: &proto.CourseDatabaseBatchMessage_Disenroll{
 						Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{
-							StudentId: uint64(2),
-							CourseId:  1,
+							StudentId:         uint64(2),
+							CourseId:          1,
+							GroupId:           1,
+							PromotedStudentId: 3,
 						},
 					},
 				},
@@ -201,8 +203,10 @@ func TestCourseUnrollStudent(t *testing.T) {
 				data: &proto.CourseDatabaseBatchMessage{
 					Action: &proto.CourseDatabaseBatchMessage_Disenroll{
 						Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{
-							StudentId: uint64(1),
-							CourseId:  1,
+							StudentId:         uint64(1),
+							CourseId:          1,
+							GroupId:           1,
+							PromotedStudentId: 4,
 						},
 					},
 				},
@@ -380,9 +384,11 @@ func TestCourseChangeGroupOfStudent(t *testing.T) {
 				data: &proto.CourseDatabaseBatchMessage{
 					Action: &proto.CourseDatabaseBatchMessage_ChangeGroup{
 						ChangeGroup: &proto.CourseDatabaseBatchChangeGroupMessage{
-							StudentId: uint64(1),
-							CourseId:  1,
-							GroupId:   2,
+							StudentId:         uint64(1),
+							CourseId:          1,
+							GroupId:           2,
+							Reserved:          true,
+							PromotedStudentId: 2,
 						},
 					},
 				},
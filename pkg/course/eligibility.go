@@ -0,0 +1,103 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"slices"
+)
+
+// Eligibility is the set of rules which decide who can take a course group besides its sex lock.
+// Zero fields do not restrict anyone. It must not be changed after it's created.
+type Eligibility struct {
+	// The departments which can take the group
+	Departments []DepartmentID
+	// The range of entry years which can take the group. Both ends are inclusive.
+	MinEntryYear, MaxEntryYear int16
+	// If not empty, only these students can take the group
+	AllowedStudents map[StudentID]struct{}
+	// These students cannot take the group
+	DeniedStudents map[StudentID]struct{}
+}
+
+// check checks if a student can take a group with these rules. Nil eligibility means that
+// everyone can take the group.
+func (e *Eligibility) check(student *Student) error {
+	if e == nil {
+		return nil
+	}
+	if _, denied := e.DeniedStudents[student.ID]; denied {
+		return DeniedStudentErr
+	}
+	if len(e.AllowedStudents) != 0 {
+		if _, allowed := e.AllowedStudents[student.ID]; !allowed {
+			return NotAllowedStudentErr
+		}
+	}
+	if len(e.Departments) != 0 && !slices.Contains(e.Departments, student.Department) {
+		return DepartmentNotEligibleErr
+	}
+	if (e.MinEntryYear != 0 && student.EntryYear < e.MinEntryYear) || (e.MaxEntryYear != 0 && student.EntryYear > e.MaxEntryYear) {
+		return EntryYearNotEligibleErr
+	}
+	return nil
+}
+
+// checkEligibility checks the sex lock and the eligibility rules of this course for a student
+func (c *Course) checkEligibility(student *Student) error {
+	if !sexLockCompatible(c.SexLock, student.StudentSex) {
+		return SexLockErr
+	}
+	return c.Eligibility.check(student)
+}
+
+// NewEligibilityFromProto creates the eligibility rules from their protobuf message. Nil is
+// returned if the rules do not restrict anyone.
+func NewEligibilityFromProto(data *proto.CourseEligibility) *Eligibility {
+	if data == nil || (len(data.Departments) == 0 && data.MinEntryYear == 0 && data.MaxEntryYear == 0 &&
+		len(data.AllowedStudents) == 0 && len(data.DeniedStudents) == 0) {
+		return nil
+	}
+	result := &Eligibility{
+		MinEntryYear: int16(data.MinEntryYear),
+		MaxEntryYear: int16(data.MaxEntryYear),
+	}
+	for _, id := range data.Departments {
+		result.Departments = append(result.Departments, DepartmentID(id))
+	}
+	if len(data.AllowedStudents) != 0 {
+		result.AllowedStudents = make(map[StudentID]struct{}, len(data.AllowedStudents))
+		for _, id := range data.AllowedStudents {
+			result.AllowedStudents[StudentID(id)] = struct{}{}
+		}
+	}
+	if len(data.DeniedStudents) != 0 {
+		result.DeniedStudents = make(map[StudentID]struct{}, len(data.DeniedStudents))
+		for _, id := range data.DeniedStudents {
+			result.DeniedStudents[StudentID(id)] = struct{}{}
+		}
+	}
+	return result
+}
+
+// ToProto converts the eligibility rules to their protobuf message. Nil eligibility is converted
+// to nil.
+func (e *Eligibility) ToProto() *proto.CourseEligibility {
+	if e == nil {
+		return nil
+	}
+	result := &proto.CourseEligibility{
+		MinEntryYear: int32(e.MinEntryYear),
+		MaxEntryYear: int32(e.MaxEntryYear),
+	}
+	for _, id := range e.Departments {
+		result.Departments = append(result.Departments, uint32(id))
+	}
+	for id := range e.AllowedStudents {
+		result.AllowedStudents = append(result.AllowedStudents, uint64(id))
+	}
+	for id := range e.DeniedStudents {
+		result.DeniedStudents = append(result.DeniedStudents, uint64(id))
+	}
+	slices.Sort(result.AllowedStudents)
+	slices.Sort(result.DeniedStudents)
+	return result
+}
@@ -0,0 +1,114 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEligibilityCheck(t *testing.T) {
+	student := &Student{ID: 1, Department: 2, EntryYear: 1400, StudentSex: SexMale}
+	tests := []struct {
+		Name        string
+		Eligibility *Eligibility
+		Expected    error
+	}{
+		{
+			Name: "no rules",
+		},
+		{
+			Name:        "empty rules",
+			Eligibility: new(Eligibility),
+		},
+		{
+			Name:        "department",
+			Eligibility: &Eligibility{Departments: []DepartmentID{1, 2}},
+		},
+		{
+			Name:        "other department",
+			Eligibility: &Eligibility{Departments: []DepartmentID{1, 3}},
+			Expected:    DepartmentNotEligibleErr,
+		},
+		{
+			Name:        "entry year range",
+			Eligibility: &Eligibility{MinEntryYear: 1399, MaxEntryYear: 1400},
+		},
+		{
+			Name:        "before minimum entry year",
+			Eligibility: &Eligibility{MinEntryYear: 1401},
+			Expected:    EntryYearNotEligibleErr,
+		},
+		{
+			Name:        "after maximum entry year",
+			Eligibility: &Eligibility{MaxEntryYear: 1399},
+			Expected:    EntryYearNotEligibleErr,
+		},
+		{
+			Name:        "allow list",
+			Eligibility: &Eligibility{AllowedStudents: map[StudentID]struct{}{1: {}}},
+		},
+		{
+			Name:        "not in allow list",
+			Eligibility: &Eligibility{AllowedStudents: map[StudentID]struct{}{2: {}}},
+			Expected:    NotAllowedStudentErr,
+		},
+		{
+			Name:        "deny list",
+			Eligibility: &Eligibility{DeniedStudents: map[StudentID]struct{}{1: {}}},
+			Expected:    DeniedStudentErr,
+		},
+		{
+			Name:        "allow list does not bypass other rules",
+			Eligibility: &Eligibility{AllowedStudents: map[StudentID]struct{}{1: {}}, Departments: []DepartmentID{3}},
+			Expected:    DepartmentNotEligibleErr,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, test.Eligibility.check(student))
+			// The proto conversion must keep the rules
+			assert.Equal(t, test.Expected, NewEligibilityFromProto(test.Eligibility.ToProto()).check(student))
+		})
+	}
+	// The sex lock is checked alongside
+	course := &Course{SexLock: SexLockFemaleOnly}
+	assert.Equal(t, SexLockErr, course.checkEligibility(student))
+	assert.Nil(t, NewEligibilityFromProto(new(proto.CourseEligibility)))
+}
+
+func TestStudentEligibility(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	// Group 1 is for everyone and group 2 is only for department 2
+	groups := make([]*Course, 2)
+	for i := range groups {
+		groups[i] = &Course{
+			ID:                 1,
+			GroupID:            GroupID(i + 1),
+			Units:              3,
+			Capacity:           5,
+			RegisteredStudents: make(map[StudentID]struct{}),
+			ReserveQueue:       util.NewQueue[StudentID](),
+		}
+	}
+	groups[1].Eligibility = &Eligibility{Departments: []DepartmentID{2}}
+	courses := NewCourses(map[CourseID][]*Course{1: groups})
+	student := &Student{
+		ID:                  1,
+		EnrollmentStartTime: clk.Now().Add(-time.Minute).UnixMilli(),
+		RemainingActions:    5,
+		MaxUnits:            20,
+		Department:          1,
+		RegisteredCourses:   make(map[CourseID]GroupID),
+	}
+	assert.Equal(t, DepartmentNotEligibleErr, student.EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+	assert.NoError(t, student.EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+	assert.Equal(t, DepartmentNotEligibleErr, student.ChangeGroup(context.Background(), courses, 1, 2, noOpBatcher{}))
+	assert.Equal(t, GroupID(1), student.RegisteredCourses[1])
+	assert.Equal(t, uint8(5), student.RemainingActions)
+}
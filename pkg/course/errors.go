@@ -3,6 +3,7 @@ package course
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // NotExistsErr means that the requested course does not exist
@@ -15,6 +16,10 @@ var SexLockErr = errors.New("you cannot pick up this course due to sex lock")
 // time
 var NotEnrollmentTimeErr = errors.New("it's not your enrollment time")
 
+// ActionNotAllowedErr means that an enrollment phase is open for the student, but it does not
+// allow this action. For example, enrolling in a drop only phase.
+var ActionNotAllowedErr = errors.New("this action is not allowed in the current enrollment phase")
+
 // UnitLimitReachedErr is when user cannot register anymore because the unit limit has been
 // reached
 var UnitLimitReachedErr = errors.New("unit limit has been reached")
@@ -23,6 +28,18 @@ var UnitLimitReachedErr = errors.New("unit limit has been reached")
 // which they have already registered in
 var AlreadyRegisteredErr = errors.New("you are already registered in this course")
 
+// DepartmentNotEligibleErr means that the course group is reserved for other departments
+var DepartmentNotEligibleErr = errors.New("this group is not for your department")
+
+// EntryYearNotEligibleErr means that the course group is reserved for other entry years
+var EntryYearNotEligibleErr = errors.New("this group is not for your entry year")
+
+// NotAllowedStudentErr means that the course group has an allow list which the student is not in
+var NotAllowedStudentErr = errors.New("you are not in the allowed students of this group")
+
+// DeniedStudentErr means that the student is in the deny list of the course group
+var DeniedStudentErr = errors.New("you are not allowed to take this group")
+
 // ExamConflictErr is returned when the courses of user have exam conflicts
 type ExamConflictErr struct {
 	CourseID CourseID
@@ -56,3 +73,128 @@ var PlayedYourselfErr = errors.New("source and destination group ID is same")
 // LowerCapacityThanRegistered means that the new capacity which admin wants is less than the
 // registered count of the course. This cannot be applied because we need to remove users from course.
 var LowerCapacityThanRegistered = errors.New("new capacity cannot be less than registered count")
+
+// NoPasswordErr means that a new student is being created without a password
+var NoPasswordErr = errors.New("new students must have a password")
+
+// InvalidSexErr means that the sex or the sex lock is not a valid value
+var InvalidSexErr = errors.New("invalid sex")
+
+// DepartmentChangeErr means that the department of a course is being changed. All groups of a course
+// must be in the same department, and it cannot change because it decides the queue of its messages.
+var DepartmentChangeErr = errors.New("department of a course cannot be changed")
+
+// LowerReserveCapacityThanQueue means that the new reserve capacity of a course is less than its
+// reserve queue. This cannot be applied because we need to remove users from the queue.
+var LowerReserveCapacityThanQueue = errors.New("new reserve capacity cannot be less than reserve queue length")
+
+// InvalidEntryYearRangeErr means that the minimum entry year of a course group is more than its
+// maximum entry year
+var InvalidEntryYearRangeErr = errors.New("minimum entry year cannot be more than maximum entry year")
+
+// NegativeCapacityErr means that the capacity or the reserve capacity of a course is negative
+var NegativeCapacityErr = errors.New("capacity cannot be negative")
+
+// NoIntentErr means that the student does not have an intent to change their group in a course
+var NoIntentErr = errors.New("there is no intent to change the group of this course")
+
+// DuplicateChangeErr means that a course is changed more than once in Student.ApplyChanges
+var DuplicateChangeErr = errors.New("each course can be only changed once")
+
+// InvalidChangeErr means that the action of a change is not enroll, disenroll or change group
+var InvalidChangeErr = errors.New("invalid change action")
+
+// MixedDepartmentsErr means that the changes of Student.ApplyChanges are in courses of departments
+// which are sent to different shards. They cannot be batched together because the queues of the
+// shards are applied in the database independently of each other.
+var MixedDepartmentsErr = errors.New("all changed courses must be in departments of the same shard")
+
+// NotLotteryTimeErr means that no lottery phase is open for the student to submit a wishlist
+var NotLotteryTimeErr = errors.New("no lottery phase is open for you")
+
+// TooManyWishesErr means that a wishlist has more than MaxWishlistLength groups
+var TooManyWishesErr = fmt.Errorf("a wishlist cannot have more than %d groups", MaxWishlistLength)
+
+// DuplicateWishErr means that a group appears more than once in a wishlist
+var DuplicateWishErr = errors.New("each group can be only wished once")
+
+// NoLotteryPhaseErr means that the lottery phase does not exist in the schedule
+var NoLotteryPhaseErr = errors.New("lottery phase does not exist")
+
+// LotteryPhaseOpenErr means that the lottery cannot run because some windows of its phase are
+// still open
+var LotteryPhaseOpenErr = errors.New("lottery phase is not closed yet")
+
+// NoWatchedGroupsErr means that a watcher is created without any groups
+var NoWatchedGroupsErr = errors.New("at least one group must be watched")
+
+// TooManyWatchedGroupsErr means that a watcher is created with more than MaxWatchedGroups groups
+var TooManyWatchedGroupsErr = fmt.Errorf("cannot watch more than %d groups", MaxWatchedGroups)
+
+// NegativeConfirmationWindowErr is returned when the confirmation window of a course is negative
+var NegativeConfirmationWindowErr = errors.New("confirmation window cannot be negative")
+
+// NoPromotionErr is returned when the student confirms a course which they have not been promoted
+// in, or have already confirmed
+var NoPromotionErr = errors.New("there is no promotion to confirm in this course")
+
+// PromotionExpiredErr is returned when the student confirms their promotion after its deadline
+var PromotionExpiredErr = errors.New("the deadline of confirming this course has passed")
+
+// ChangeErr is returned when a single change of Student.ApplyChanges fails
+type ChangeErr struct {
+	// The index of the change
+	Index int
+	Err   error
+}
+
+func (e ChangeErr) Error() string {
+	return fmt.Sprintf("change %d: %s", e.Index+1, e.Err)
+}
+
+func (e ChangeErr) Unwrap() error {
+	return e.Err
+}
+
+// WishErr is returned when a single wish of Student.PutWishlist is invalid
+type WishErr struct {
+	// The index of the wish
+	Index int
+	Err   error
+}
+
+func (e WishErr) Error() string {
+	return fmt.Sprintf("wish %d: %s", e.Index+1, e.Err)
+}
+
+func (e WishErr) Unwrap() error {
+	return e.Err
+}
+
+// PrerequisiteMissingErr is returned when the student has not passed the prerequisites of a course
+// or is not enrolled in its co-requisites
+type PrerequisiteMissingErr struct {
+	Prerequisites []CourseID
+	Corequisites  []CourseID
+}
+
+func (e PrerequisiteMissingErr) Error() string {
+	var missing []string
+	if len(e.Prerequisites) != 0 {
+		missing = append(missing, fmt.Sprintf("prerequisites %v", e.Prerequisites))
+	}
+	if len(e.Corequisites) != 0 {
+		missing = append(missing, fmt.Sprintf("co-requisites %v", e.Corequisites))
+	}
+	return "missing " + strings.Join(missing, " and ")
+}
+
+// CorequisiteRequiredErr is returned when the student wants to disenroll from a course which is a
+// co-requisite of another course they are enrolled in
+type CorequisiteRequiredErr struct {
+	CourseID CourseID
+}
+
+func (e CorequisiteRequiredErr) Error() string {
+	return fmt.Sprintf("this course is a co-requisite of course %d", e.CourseID)
+}
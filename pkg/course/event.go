@@ -0,0 +1,125 @@
+package course
+
+import "CourseEnrollment/pkg/proto"
+
+// EventKind is the kind of Event
+type EventKind uint8
+
+const (
+	// EventPromoted means that the student is moved from the reserve queue of the group to its
+	// registered students
+	EventPromoted EventKind = iota + 1
+	// EventForceEnrolled means that a staff has enrolled the student in the group
+	EventForceEnrolled
+	// EventForceDisenrolled means that a staff has dropped the student from the group
+	EventForceDisenrolled
+	// EventCapacityChanged means that the capacity of the group has changed while the student is
+	// in its reserve queue
+	EventCapacityChanged
+	// EventPromotionExpired means that the student has not confirmed their promotion in time and
+	// is dropped from the group
+	EventPromotionExpired
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventPromoted:
+		return "promoted"
+	case EventForceEnrolled:
+		return "force_enrolled"
+	case EventForceDisenrolled:
+		return "force_disenrolled"
+	case EventCapacityChanged:
+		return "capacity_changed"
+	case EventPromotionExpired:
+		return "promotion_expired"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is something which has happened to a student that they should be told about
+type Event struct {
+	Kind      EventKind
+	StudentID StudentID
+	CourseID  CourseID
+	GroupID   GroupID
+	// The department of the course
+	Department DepartmentID
+	// The capacity of the group after the event
+	Capacity int
+	// The position of the student in the reserve queue after the event (1-indexed).
+	// Zero means that the student is registered or not in the group anymore.
+	ReservePosition int
+	// When the event has happened in unix milliseconds
+	Time int64
+	// If not zero, the student must confirm their seat before this time in unix milliseconds
+	ConfirmationDeadline int64
+}
+
+// EventSink receives the events of students. Events are emitted while the courses and students
+// are locked; so Emit must not block or call this package.
+type EventSink interface {
+	Emit(event Event)
+}
+
+// SetEventSink sets the sink of the events of students. Nil stops emitting events. It must be set
+// after the state is loaded; otherwise, replaying the old messages emits their events again.
+func (c *Courses) SetEventSink(sink EventSink) {
+	if sink == nil {
+		c.hooks.sink.Store(nil)
+	} else {
+		c.hooks.sink.Store(&sink)
+	}
+}
+
+// threadUnsafeEmit emits an event of a student in this course. The course must be locked for
+// reading at least.
+func (c *Course) threadUnsafeEmit(kind EventKind, studentID StudentID, reservePosition int) {
+	if c.hooks == nil {
+		return
+	}
+	sink := c.hooks.sink.Load()
+	if sink == nil {
+		return
+	}
+	(*sink).Emit(Event{
+		Kind:                 kind,
+		StudentID:            studentID,
+		CourseID:             c.ID,
+		GroupID:              c.GroupID,
+		Department:           c.Department,
+		Capacity:             c.Capacity,
+		ReservePosition:      reservePosition,
+		Time:                 studentClock.Now().UnixMilli(),
+		ConfirmationDeadline: c.ConfirmationDeadlines[studentID],
+	})
+}
+
+// NewEventFromProto creates an event from its protobuf message. The department is not in the message.
+func NewEventFromProto(data *proto.StudentNotification) Event {
+	return Event{
+		Kind:                 EventKind(data.Kind),
+		StudentID:            StudentID(data.StudentId),
+		CourseID:             CourseID(data.CourseId),
+		GroupID:              GroupID(data.GroupId),
+		Capacity:             int(data.Capacity),
+		ReservePosition:      int(data.ReservePosition),
+		Time:                 data.Time,
+		ConfirmationDeadline: data.ConfirmationDeadline,
+	}
+}
+
+// ToProto converts the event to its protobuf message
+func (e Event) ToProto() *proto.StudentNotification {
+	return &proto.StudentNotification{
+		Kind:                 proto.StudentNotification_Kind(e.Kind),
+		StudentId:            uint64(e.StudentID),
+		CourseId:             int32(e.CourseID),
+		GroupId:              uint32(e.GroupID),
+		Capacity:             int32(e.Capacity),
+		ReservePosition:      uint32(e.ReservePosition),
+		Time:                 e.Time,
+		ConfirmationDeadline: e.ConfirmationDeadline,
+	}
+}
@@ -0,0 +1,93 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/util"
+	"context"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// recordingEventSink records the emitted events
+type recordingEventSink struct {
+	events []Event
+}
+
+func (s *recordingEventSink) Emit(event Event) {
+	s.events = append(s.events, event)
+}
+
+func TestEvents(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	now := clk.Now().UnixMilli()
+	// newState creates group 1 of course 1 with a seat and three reserve seats, alongside four
+	// students which are enrolled in it in order
+	newState := func() (*Courses, map[StudentID]*Student, *recordingEventSink) {
+		courses := NewCourses(map[CourseID][]*Course{1: {{
+			ID:                 1,
+			GroupID:            1,
+			Department:         2,
+			Units:              1,
+			Capacity:           1,
+			RegisteredStudents: make(map[StudentID]struct{}),
+			ReserveCapacity:    3,
+			ReserveQueue:       util.NewQueue[StudentID](),
+		}}})
+		students := make(map[StudentID]*Student)
+		for id := StudentID(1); id <= 4; id++ {
+			students[id] = &Student{
+				ID:                  id,
+				EnrollmentStartTime: clk.Now().Add(-time.Minute).UnixMilli(),
+				RemainingActions:    2,
+				MaxUnits:            20,
+				RegisteredCourses:   make(map[CourseID]GroupID),
+			}
+			require.NoError(t, students[id].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		}
+		sink := new(recordingEventSink)
+		courses.SetEventSink(sink)
+		return courses, students, sink
+	}
+	t.Run("promote", func(t *testing.T) {
+		courses, students, sink := newState()
+		require.NoError(t, students[1].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Equal(t, []Event{{Kind: EventPromoted, StudentID: 2, CourseID: 1, GroupID: 1, Department: 2, Capacity: 1, Time: now}}, sink.events)
+	})
+	t.Run("force", func(t *testing.T) {
+		courses, students, sink := newState()
+		require.NoError(t, students[1].ForceDisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		require.NoError(t, courses.GetCourse(1, 1).ForceEnroll(context.Background(), 1, noOpBatcher{}))
+		kinds := make([]EventKind, len(sink.events))
+		for i, event := range sink.events {
+			kinds[i] = event.Kind
+		}
+		assert.Equal(t, []EventKind{EventPromoted, EventForceDisenrolled, EventForceEnrolled}, kinds)
+		assert.Equal(t, StudentID(2), sink.events[0].StudentID)
+		assert.Equal(t, StudentID(1), sink.events[1].StudentID)
+		assert.Equal(t, StudentID(1), sink.events[2].StudentID)
+	})
+	t.Run("capacity", func(t *testing.T) {
+		courses, _, sink := newState()
+		require.NoError(t, courses.GetCourse(1, 1).UpdateCapacity(context.Background(), 2, noOpBatcher{}))
+		assert.Equal(t, []Event{
+			{Kind: EventPromoted, StudentID: 2, CourseID: 1, GroupID: 1, Department: 2, Capacity: 2, Time: now},
+			{Kind: EventCapacityChanged, StudentID: 3, CourseID: 1, GroupID: 1, Department: 2, Capacity: 2, ReservePosition: 1, Time: now},
+			{Kind: EventCapacityChanged, StudentID: 4, CourseID: 1, GroupID: 1, Department: 2, Capacity: 2, ReservePosition: 2, Time: now},
+		}, sink.events)
+	})
+	t.Run("no sink", func(t *testing.T) {
+		courses, students, sink := newState()
+		courses.SetEventSink(nil)
+		require.NoError(t, students[1].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Empty(t, sink.events)
+	})
+	t.Run("proto", func(t *testing.T) {
+		event := Event{Kind: EventCapacityChanged, StudentID: 3, CourseID: 1, GroupID: 1, Capacity: 2, ReservePosition: 1, Time: now}
+		assert.Equal(t, event, NewEventFromProto(event.ToProto()))
+		assert.Equal(t, "capacity_changed", event.Kind.String())
+	})
+}
@@ -0,0 +1,261 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"errors"
+	"slices"
+)
+
+// AddIntent tries to change the group of the student in a course like ChangeGroup. If the
+// destination group has no free registered seat, an intent is registered instead, and the group is
+// changed later by FulfillIntents when a seat frees in the destination group. The student is never
+// moved to the reserve queue of the destination group, so they keep their seat until then.
+// Fulfilling the intent uses one of the remaining actions of the student like ChangeGroup. A
+// student can have only one intent in each course; a new intent replaces the old one and puts the
+// student at the end of the intents of the destination group.
+//
+// The first returned value is true if the group was changed right away.
+func (s *Student) AddIntent(ctx context.Context, courses *Courses, courseID CourseID, destinationGroupID GroupID, batcher Batcher) (bool, error) {
+	// We check the schedule at very first
+	if err := s.checkSchedule(courses, ActionChangeGroup); err != nil {
+		return false, err
+	}
+	// Lock the user to do stuff with them
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Try to change the group right now
+	err := s.threadUnsafeChangeGroup(ctx, courses, courseID, destinationGroupID, false, batcher)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, NoCapacityLeftErr) {
+		return false, err
+	}
+	// Nothing changes if the student already waits for this group
+	if groupID, exists := s.Intents[courseID]; exists && groupID == destinationGroupID {
+		return false, nil
+	}
+	department, _ := courses.DepartmentOf(courseID) // it exists because the student is enrolled in it
+	err = batcher.ProcessDatabaseQuery(ctx, department, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_AddIntent{
+			AddIntent: &proto.CourseDatabaseBatchAddIntent{
+				StudentId: uint64(s.ID),
+				CourseId:  int32(courseID),
+				GroupId:   uint32(destinationGroupID),
+			},
+		},
+	})
+	if err != nil {
+		return false, BatchError{err}
+	}
+	s.threadUnsafeSetIntent(courses, courseID, destinationGroupID)
+	return false, nil
+}
+
+// CancelIntent removes the intent of the student to change their group in a course.
+// NoIntentErr is returned if there is no intent.
+func (s *Student) CancelIntent(ctx context.Context, courses *Courses, courseID CourseID, batcher Batcher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.Intents[courseID]; !exists {
+		return NoIntentErr
+	}
+	return s.threadUnsafeRemoveIntent(ctx, courses, courseID, batcher)
+}
+
+// GetIntentsProto gets the pending intents of the student alongside their positions
+func (s *Student) GetIntentsProto(courses *Courses) *proto.GroupChangeIntents {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := &proto.GroupChangeIntents{
+		Intents: make([]*proto.GroupChangeIntent, 0, len(s.Intents)),
+	}
+	for courseID, groupID := range s.Intents {
+		intent := &proto.GroupChangeIntent{
+			CourseId:           int32(courseID),
+			SourceGroupId:      uint32(s.RegisteredCourses[courseID]),
+			DestinationGroupId: uint32(groupID),
+		}
+		if course := courses.GetCourse(courseID, groupID); course != nil {
+			course.mu.RLock()
+			intent.Position = uint32(slices.Index(course.Intents, s.ID) + 1)
+			course.mu.RUnlock()
+		}
+		result.Intents = append(result.Intents, intent)
+	}
+	slices.SortFunc(result.Intents, func(a, b *proto.GroupChangeIntent) int {
+		return int(a.CourseId - b.CourseId)
+	})
+	return result
+}
+
+// FulfillIntents moves the students which have a seat held for them in the groups of a course to
+// those groups. The seats are held for the intents in order in the same lock which frees them (see
+// Course.HeldSeats), so a normal enrollment cannot take them; the students themselves are moved
+// here because they must be locked before the courses. This must be called after a seat is freed
+// in a group. Moving a student frees a seat in another group, so this is repeated until nothing
+// changes. Intents which cannot be fulfilled anymore (for example, the student has no remaining
+// actions or the class time conflicts) are removed and their seat is held for the next intents.
+// Intents of students which are not in their enrollment schedule are kept, but their seat is held
+// for the next intents as well. On batch errors, the intent is kept but its seat is released like
+// above, so a failed batch never leaves a seat held; the first batch error is returned after all
+// the held seats are handled.
+func FulfillIntents(ctx context.Context, courses *Courses, students map[StudentID]*Student, courseID CourseID, batcher Batcher) error {
+	if batcher == nil {
+		panic("nil batcher")
+	}
+	var batchErr error
+	for changed := true; changed; {
+		changed = false
+		for _, group := range courses.groupsOf(courseID) {
+			group.mu.RLock()
+			held := group.threadUnsafeHeldStudents()
+			group.mu.RUnlock()
+			for _, studentID := range held {
+				student, exists := students[studentID]
+				if !exists {
+					group.mu.Lock()
+					group.threadUnsafeReleaseSeat(studentID)
+					group.mu.Unlock()
+					changed = true
+					continue
+				}
+				fulfilled, err := student.fulfillIntent(ctx, courses, group, batcher)
+				if err != nil && batchErr == nil {
+					batchErr = err
+				}
+				changed = changed || fulfilled
+			}
+		}
+	}
+	return batchErr
+}
+
+// fulfillIntent moves the student to the destination group if a seat is held for them in it. The
+// seat is released if the student is not in their enrollment schedule or batching fails, and the
+// intent is removed if any other error than a batch error happens while changing the group. It
+// returns true if the seat is taken or released. Only batch errors are returned.
+func (s *Student) fulfillIntent(ctx context.Context, courses *Courses, destination *Course, batcher Batcher) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The seat might have been taken or released meanwhile
+	destination.mu.RLock()
+	_, held := destination.HeldSeats[s.ID]
+	destination.mu.RUnlock()
+	if !held {
+		return false, nil
+	}
+	if s.checkSchedule(courses, ActionChangeGroup) != nil {
+		destination.mu.Lock()
+		destination.threadUnsafeReleaseSeat(s.ID)
+		destination.mu.Unlock()
+		return true, nil
+	}
+	err := s.threadUnsafeChangeGroup(ctx, courses, destination.ID, destination.GroupID, false, batcher)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.As(err, new(BatchError)) {
+		err = s.threadUnsafeRemoveIntent(ctx, courses, destination.ID, batcher)
+	}
+	if err != nil {
+		// Release the seat to not keep the reserve queue closed because of a failed batch
+		destination.mu.Lock()
+		destination.threadUnsafeReleaseSeat(s.ID)
+		destination.mu.Unlock()
+	}
+	return true, err
+}
+
+// threadUnsafeHoldSeats holds the free registered seats of this course for the given students of
+// Intents in order. It must be called in the same lock which frees the seats.
+func (c *Course) threadUnsafeHoldSeats(candidates []StudentID) {
+	for _, id := range candidates {
+		if c.threadUnsafeTakenSeats() >= c.Capacity {
+			return
+		}
+		if _, held := c.HeldSeats[id]; held {
+			continue
+		}
+		if c.HeldSeats == nil {
+			c.HeldSeats = make(map[StudentID]struct{})
+		}
+		c.HeldSeats[id] = struct{}{}
+	}
+}
+
+// threadUnsafeReleaseSeat releases the seat which is held for a student, if any, and holds it for
+// the intents after the student. The reserve queue is closed while the seat is held, so it never
+// has anyone to promote to the released seat.
+func (c *Course) threadUnsafeReleaseSeat(studentID StudentID) {
+	if _, held := c.HeldSeats[studentID]; !held {
+		return
+	}
+	delete(c.HeldSeats, studentID)
+	c.threadUnsafeHoldSeats(c.Intents[slices.Index(c.Intents, studentID)+1:])
+	c.threadUnsafeNotifyWatchers()
+}
+
+// threadUnsafeHeldStudents returns the students which a seat is held for in the order of Intents
+func (c *Course) threadUnsafeHeldStudents() []StudentID {
+	result := make([]StudentID, 0, len(c.HeldSeats))
+	for _, id := range c.Intents {
+		if _, held := c.HeldSeats[id]; held {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// threadUnsafeRemoveIntent batches the removal of the intent of the student in a course and
+// removes it. The student must be locked.
+func (s *Student) threadUnsafeRemoveIntent(ctx context.Context, courses *Courses, courseID CourseID, batcher Batcher) error {
+	department, _ := courses.DepartmentOf(courseID)
+	err := batcher.ProcessDatabaseQuery(ctx, department, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_RemoveIntent{
+			RemoveIntent: &proto.CourseDatabaseBatchRemoveIntent{
+				StudentId: uint64(s.ID),
+				CourseId:  int32(courseID),
+			},
+		},
+	})
+	if err != nil {
+		return BatchError{err}
+	}
+	s.threadUnsafeClearIntent(courses, courseID)
+	return nil
+}
+
+// threadUnsafeSetIntent replaces the intent of the student in a course without batching it.
+// The student must be locked and the courses must not be locked.
+func (s *Student) threadUnsafeSetIntent(courses *Courses, courseID CourseID, destinationGroupID GroupID) {
+	s.threadUnsafeClearIntent(courses, courseID)
+	if s.Intents == nil {
+		s.Intents = make(map[CourseID]GroupID)
+	}
+	s.Intents[courseID] = destinationGroupID
+	if course := courses.GetCourse(courseID, destinationGroupID); course != nil {
+		course.mu.Lock()
+		course.Intents = append(course.Intents, s.ID)
+		course.mu.Unlock()
+	}
+}
+
+// threadUnsafeClearIntent removes the intent of the student in a course without batching it.
+// The student must be locked and the courses must not be locked.
+func (s *Student) threadUnsafeClearIntent(courses *Courses, courseID CourseID) {
+	groupID, exists := s.Intents[courseID]
+	if !exists {
+		return
+	}
+	delete(s.Intents, courseID)
+	if course := courses.GetCourse(courseID, groupID); course != nil {
+		course.mu.Lock()
+		if index := slices.Index(course.Intents, s.ID); index != -1 {
+			course.Intents = slices.Delete(course.Intents, index, index+1)
+		}
+		course.threadUnsafeReleaseSeat(s.ID)
+		course.mu.Unlock()
+	}
+}
@@ -0,0 +1,240 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"errors"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStudentIntents(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	// newState creates course 1 with the given number of groups which each has a single seat,
+	// alongside students 1 to 5 with two remaining actions.
+	newState := func(groups int) (*Courses, map[StudentID]*Student) {
+		var courseGroups []*Course
+		for id := 1; id <= groups; id++ {
+			courseGroups = append(courseGroups, &Course{
+				ID:                 1,
+				GroupID:            GroupID(id),
+				Department:         2,
+				Units:              1,
+				Capacity:           1,
+				RegisteredStudents: make(map[StudentID]struct{}),
+				ReserveQueue:       util.NewQueue[StudentID](),
+			})
+		}
+		students := make(map[StudentID]*Student)
+		for id := StudentID(1); id <= 5; id++ {
+			students[id] = &Student{
+				ID:                  id,
+				EnrollmentStartTime: clk.Now().Add(-time.Minute).UnixMilli(),
+				RemainingActions:    2,
+				MaxUnits:            20,
+				RegisteredCourses:   make(map[CourseID]GroupID),
+			}
+		}
+		return NewCourses(map[CourseID][]*Course{1: courseGroups}), students
+	}
+	t.Run("fulfill in order", func(t *testing.T) {
+		courses, students := newState(3)
+		batcher := new(inMemoryBatcher)
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, batcher))
+		require.NoError(t, students[2].EnrollCourse(context.Background(), courses, 1, 2, batcher))
+		require.NoError(t, students[3].EnrollCourse(context.Background(), courses, 1, 3, batcher))
+		// Group 3 is full, so the intents are pending
+		changed, err := students[1].AddIntent(context.Background(), courses, 1, 3, batcher)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		changed, err = students[2].AddIntent(context.Background(), courses, 1, 3, batcher)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, []*proto.GroupChangeIntent{{CourseId: 1, SourceGroupId: 2, DestinationGroupId: 3, Position: 2}},
+			students[2].GetIntentsProto(courses).Intents)
+		// Adding the same intent again keeps the position
+		changed, err = students[1].AddIntent(context.Background(), courses, 1, 3, batcher)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, []StudentID{1, 2}, courses.GetCourse(1, 3).Intents)
+		// The first student moves when the seat frees
+		require.NoError(t, students[3].DisenrollCourse(context.Background(), courses, 1, batcher))
+		require.NoError(t, FulfillIntents(context.Background(), courses, students, 1, batcher))
+		assert.Equal(t, GroupID(3), students[1].RegisteredCourses[1])
+		assert.Equal(t, uint8(1), students[1].RemainingActions)
+		assert.Empty(t, students[1].Intents)
+		assert.Equal(t, []*proto.GroupChangeIntent{{CourseId: 1, SourceGroupId: 2, DestinationGroupId: 3, Position: 1}},
+			students[2].GetIntentsProto(courses).Intents)
+		// Intents are replayed and kept in snapshots
+		replayedCourses, replayedStudents := newState(3)
+		for _, message := range batcher.messages {
+			require.NoError(t, ReplayMessage(replayedCourses, replayedStudents, message.data))
+		}
+		assert.Equal(t, []StudentID{2}, replayedCourses.GetCourse(1, 3).Intents)
+		assert.Equal(t, map[CourseID]GroupID{1: 3}, replayedStudents[2].Intents)
+		snapshotCourses, snapshotStudents, err := NewStateFromSnapshotProto(NewSnapshotProto(courses, students))
+		require.NoError(t, err)
+		assert.Equal(t, []StudentID{2}, snapshotCourses.GetCourse(1, 3).Intents)
+		assert.Equal(t, map[CourseID]GroupID{1: 3}, snapshotStudents[2].Intents)
+		// Cancel the intent
+		require.NoError(t, students[2].CancelIntent(context.Background(), courses, 1, batcher))
+		assert.Empty(t, courses.GetCourse(1, 3).Intents)
+		assert.Empty(t, students[2].GetIntentsProto(courses).Intents)
+		assert.ErrorIs(t, students[2].CancelIntent(context.Background(), courses, 1, batcher), NoIntentErr)
+	})
+	t.Run("change right away", func(t *testing.T) {
+		courses, students := newState(2)
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		changed, err := students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, GroupID(2), students[1].RegisteredCourses[1])
+		assert.Empty(t, courses.GetCourse(1, 2).Intents)
+		// Other errors of changing group are returned
+		_, err = students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		assert.ErrorIs(t, err, PlayedYourselfErr)
+		_, err = students[2].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		assert.ErrorIs(t, err, NotExistsErr)
+	})
+	t.Run("cascade", func(t *testing.T) {
+		// Student 1 waits for group 2 and student 2 waits for group 3
+		courses, students := newState(3)
+		for id := StudentID(1); id <= 3; id++ {
+			require.NoError(t, students[id].EnrollCourse(context.Background(), courses, 1, GroupID(id), noOpBatcher{}))
+		}
+		_, err := students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		require.NoError(t, err)
+		_, err = students[2].AddIntent(context.Background(), courses, 1, 3, noOpBatcher{})
+		require.NoError(t, err)
+		// Freeing group 3 moves both of them
+		require.NoError(t, students[3].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		require.NoError(t, FulfillIntents(context.Background(), courses, students, 1, noOpBatcher{}))
+		assert.Equal(t, GroupID(2), students[1].RegisteredCourses[1])
+		assert.Equal(t, GroupID(3), students[2].RegisteredCourses[1])
+		assert.Equal(t, map[StudentID]struct{}{}, courses.GetCourse(1, 1).RegisteredStudents)
+	})
+	t.Run("unfulfillable", func(t *testing.T) {
+		courses, students := newState(2)
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		require.NoError(t, students[2].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		_, err := students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		require.NoError(t, err)
+		// Student has no remaining actions when the seat frees
+		students[1].RemainingActions = 0
+		require.NoError(t, students[2].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		batcher := new(inMemoryBatcher)
+		require.NoError(t, FulfillIntents(context.Background(), courses, students, 1, batcher))
+		assert.Equal(t, GroupID(1), students[1].RegisteredCourses[1])
+		assert.Empty(t, students[1].Intents)
+		assert.Empty(t, courses.GetCourse(1, 2).Intents)
+		if assert.Len(t, batcher.messages, 1) {
+			assert.Equal(t, DepartmentID(2), batcher.messages[0].dep)
+			assert.NotNil(t, batcher.messages[0].data.GetRemoveIntent())
+		}
+		// The seat is not held anymore
+		assert.Empty(t, courses.GetCourse(1, 2).HeldSeats)
+		require.NoError(t, students[3].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+	})
+	t.Run("reserve queue", func(t *testing.T) {
+		// Group 2 is full but has room in its reserve queue
+		courses, students := newState(2)
+		courses.GetCourse(1, 2).ReserveCapacity = 1
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		require.NoError(t, students[2].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		changed, err := students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		require.NoError(t, err)
+		assert.False(t, changed)
+		require.NoError(t, FulfillIntents(context.Background(), courses, students, 1, noOpBatcher{}))
+		assert.Equal(t, GroupID(1), students[1].RegisteredCourses[1])
+		assert.Equal(t, map[StudentID]struct{}{1: {}}, courses.GetCourse(1, 1).RegisteredStudents)
+		assert.Zero(t, courses.GetCourse(1, 2).ReserveQueue.Len())
+		// The reserve queue is promoted before the intents
+		require.NoError(t, students[3].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		require.NoError(t, students[2].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		require.NoError(t, FulfillIntents(context.Background(), courses, students, 1, noOpBatcher{}))
+		assert.Equal(t, GroupID(1), students[1].RegisteredCourses[1])
+		assert.Equal(t, map[StudentID]struct{}{3: {}}, courses.GetCourse(1, 2).RegisteredStudents)
+		assert.Equal(t, map[CourseID]GroupID{1: 2}, students[1].Intents)
+	})
+	t.Run("held seat", func(t *testing.T) {
+		courses, students := newState(2)
+		courses.GetCourse(1, 2).ReserveCapacity = 1
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		require.NoError(t, students[2].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		_, err := students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		require.NoError(t, err)
+		// The freed seat is held for the intent before anyone else can take it, and the reserve
+		// queue is closed meanwhile
+		require.NoError(t, students[2].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Equal(t, map[StudentID]struct{}{1: {}}, courses.GetCourse(1, 2).HeldSeats)
+		assert.ErrorIs(t, students[3].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}), NoCapacityLeftErr)
+		assert.Equal(t, uint32(1), courses.GetCourse(1, 2).ToProtoCourse().RegisteredCount)
+		require.NoError(t, FulfillIntents(context.Background(), courses, students, 1, noOpBatcher{}))
+		assert.Equal(t, GroupID(2), students[1].RegisteredCourses[1])
+		assert.Empty(t, courses.GetCourse(1, 2).HeldSeats)
+		assert.Equal(t, map[StudentID]struct{}{1: {}}, courses.GetCourse(1, 2).RegisteredStudents)
+	})
+	t.Run("out of schedule", func(t *testing.T) {
+		// Students 2 and 3 wait for group 1 in order
+		courses, students := newState(3)
+		for id := StudentID(1); id <= 3; id++ {
+			require.NoError(t, students[id].EnrollCourse(context.Background(), courses, 1, GroupID(id), noOpBatcher{}))
+		}
+		_, err := students[2].AddIntent(context.Background(), courses, 1, 1, noOpBatcher{})
+		require.NoError(t, err)
+		_, err = students[3].AddIntent(context.Background(), courses, 1, 1, noOpBatcher{})
+		require.NoError(t, err)
+		// The intent of student 2 is kept but the seat goes to the next one
+		students[2].EnrollmentStartTime = clk.Now().Add(-2 * time.Hour).UnixMilli()
+		require.NoError(t, students[1].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Equal(t, map[StudentID]struct{}{2: {}}, courses.GetCourse(1, 1).HeldSeats)
+		require.NoError(t, FulfillIntents(context.Background(), courses, students, 1, noOpBatcher{}))
+		assert.Equal(t, GroupID(2), students[2].RegisteredCourses[1])
+		assert.Equal(t, map[CourseID]GroupID{1: 1}, students[2].Intents)
+		assert.Equal(t, GroupID(1), students[3].RegisteredCourses[1])
+		assert.Empty(t, courses.GetCourse(1, 1).HeldSeats)
+	})
+	t.Run("disenroll removes intent", func(t *testing.T) {
+		courses, students := newState(2)
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		require.NoError(t, students[2].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		_, err := students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		require.NoError(t, err)
+		require.NoError(t, students[1].ForceDisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Empty(t, students[1].Intents)
+		assert.Empty(t, courses.GetCourse(1, 2).Intents)
+	})
+	t.Run("batch error", func(t *testing.T) {
+		courses, students := newState(2)
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		require.NoError(t, students[2].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		_, err := students[1].AddIntent(context.Background(), courses, 1, 2, errorBatcher{errors.New("broker is down")})
+		assert.ErrorAs(t, err, new(BatchError))
+		assert.Empty(t, students[1].Intents)
+		assert.Empty(t, courses.GetCourse(1, 2).Intents)
+	})
+	t.Run("fulfill batch error", func(t *testing.T) {
+		courses, students := newState(2)
+		courses.GetCourse(1, 2).ReserveCapacity = 1
+		require.NoError(t, students[1].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		require.NoError(t, students[2].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		_, err := students[1].AddIntent(context.Background(), courses, 1, 2, noOpBatcher{})
+		require.NoError(t, err)
+		require.NoError(t, students[2].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Equal(t, map[StudentID]struct{}{1: {}}, courses.GetCourse(1, 2).HeldSeats)
+		// The intent is kept, but the seat is not held anymore
+		err = FulfillIntents(context.Background(), courses, students, 1, errorBatcher{errors.New("broker is down")})
+		assert.ErrorAs(t, err, new(BatchError))
+		assert.Equal(t, GroupID(1), students[1].RegisteredCourses[1])
+		assert.Equal(t, map[CourseID]GroupID{1: 2}, students[1].Intents)
+		assert.Empty(t, courses.GetCourse(1, 2).HeldSeats)
+		require.NoError(t, students[3].EnrollCourse(context.Background(), courses, 1, 2, noOpBatcher{}))
+		assert.Equal(t, map[StudentID]struct{}{3: {}}, courses.GetCourse(1, 2).RegisteredStudents)
+	})
+}
@@ -0,0 +1,310 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"cmp"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+)
+
+// MaxWishlistLength is the maximum number of groups in a wishlist
+const MaxWishlistLength = 20
+
+// Priority is a criterion which orders the students of a lottery phase
+type Priority uint8
+
+const (
+	// PrioritySeniority puts the students with earlier entry years first. Students without an
+	// entry year are the last ones.
+	PrioritySeniority Priority = iota
+	// PriorityGPABand puts the students with higher GPA bands first
+	PriorityGPABand
+)
+
+// Wish is a single course group in a wishlist
+type Wish struct {
+	CourseID CourseID
+	GroupID  GroupID
+}
+
+// Wishlist is the ranked list of course groups which a student wants in a lottery phase.
+// It must not be changed after it's created.
+type Wishlist struct {
+	// The lottery phase which this wishlist is submitted in
+	Phase string
+	// The first wish is the most wanted one
+	Wishes []Wish
+}
+
+// LotteryResult is the result of RunLottery
+type LotteryResult struct {
+	// The students which had a wishlist, in the order which they were given seats
+	Order []StudentID
+	// How many wishes got a seat
+	Enrolled int
+	// How many wishes got a place in a reserve queue
+	Reserved int
+}
+
+// PutWishlist replaces the wishlist of the student in the lottery phase which is open for them.
+// Each wish must be a group which the student can take. Empty wishes removes the wishlist.
+// The errors of a single wish are returned as WishErr.
+func (s *Student) PutWishlist(ctx context.Context, courses *Courses, wishes []Wish, batcher Batcher) error {
+	if batcher == nil {
+		panic("nil batcher")
+	}
+	// Find the phase
+	var phase *Phase
+	if schedule := courses.Schedule(); schedule != nil {
+		phase = schedule.openLotteryPhase(s, studentClock.Now().UnixMilli())
+	}
+	if phase == nil {
+		return NotLotteryTimeErr
+	}
+	// Check the wishes. Eligibility rules do not change, so we can check them without lock.
+	if len(wishes) > MaxWishlistLength {
+		return TooManyWishesErr
+	}
+	for i, wish := range wishes {
+		if slices.Contains(wishes[:i], wish) {
+			return WishErr{Index: i, Err: DuplicateWishErr}
+		}
+		course := courses.GetCourse(wish.CourseID, wish.GroupID)
+		if course == nil {
+			return WishErr{Index: i, Err: NotExistsErr}
+		}
+		if err := course.checkEligibility(s); err != nil {
+			return WishErr{Index: i, Err: err}
+		}
+	}
+	var wishlist *Wishlist
+	if len(wishes) != 0 {
+		wishlist = &Wishlist{Phase: phase.Name, Wishes: slices.Clone(wishes)}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.threadUnsafeSetWishlist(ctx, wishlist, batcher)
+}
+
+// GetWishlistProto gets the wishlist of the student. The result is empty if the student does
+// not have a wishlist.
+func (s *Student) GetWishlistProto() *proto.Wishlist {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Wishlist == nil {
+		return new(proto.Wishlist)
+	}
+	return s.Wishlist.ToProto()
+}
+
+// RunLottery allocates the seats of a closed lottery phase based on the wishlists which are
+// submitted in it. The students are ordered by the priorities of the phase and the ties are
+// broken randomly with the seed of the phase; so the same state and seed always gives the same
+// result. Then, in each round, every student in order is enrolled in their most wanted group which
+// still has a free seat. When there are no free seats left in the wished groups, the reserve
+// queues are filled in the same order. Wishes which cannot be taken (for example, because of a
+// class time conflict or the unit limit) are skipped. At last, the wishlists are removed.
+//
+// The enrollments are batched like normal enrollments; so if a batch error happens midway, the
+// seats which are already allocated stay and running the lottery again continues the allocation.
+// The caller must make sure that nothing else changes the courses and students meanwhile.
+func RunLottery(ctx context.Context, courses *Courses, students map[StudentID]*Student, phaseName string, batcher Batcher) (LotteryResult, error) {
+	if batcher == nil {
+		panic("nil batcher")
+	}
+	var phase *Phase
+	if schedule := courses.Schedule(); schedule != nil {
+		phase = schedule.phase(phaseName)
+	}
+	if phase == nil || !phase.Lottery {
+		return LotteryResult{}, NoLotteryPhaseErr
+	}
+	if !phase.isClosed(studentClock.Now().UnixMilli()) {
+		return LotteryResult{}, LotteryPhaseOpenErr
+	}
+	order := phase.lotteryOrder(students)
+	result := LotteryResult{Order: make([]StudentID, len(order))}
+	for i, student := range order {
+		result.Order[i] = student.ID
+	}
+	// Give each student a seat in each round
+	next := make([]int, len(order))
+	for remaining := true; remaining; {
+		remaining = false
+		for i, student := range order {
+			wishes := student.Wishlist.Wishes
+			for next[i] < len(wishes) {
+				wish := wishes[next[i]]
+				next[i]++
+				enrolled, err := student.allocateWish(ctx, courses, wish, false, batcher)
+				if err != nil {
+					return result, err
+				}
+				if enrolled {
+					result.Enrolled++
+					break
+				}
+			}
+			remaining = remaining || next[i] < len(wishes)
+		}
+	}
+	// Fill the reserve queues
+	for _, student := range order {
+		for _, wish := range student.Wishlist.Wishes {
+			reserved, err := student.allocateWish(ctx, courses, wish, true, batcher)
+			if err != nil {
+				return result, err
+			}
+			if reserved {
+				result.Reserved++
+			}
+		}
+	}
+	// Remove the wishlists
+	for _, student := range order {
+		student.mu.Lock()
+		err := student.threadUnsafeSetWishlist(ctx, nil, batcher)
+		student.mu.Unlock()
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// PendingLotteries returns the names of the closed lottery phases which still have wishlists.
+// RunLottery must be called for each of them.
+func PendingLotteries(courses *Courses, students map[StudentID]*Student) []string {
+	schedule := courses.Schedule()
+	if schedule == nil {
+		return nil
+	}
+	now := studentClock.Now().UnixMilli()
+	pending := make(map[string]struct{})
+	for _, student := range students {
+		student.mu.RLock()
+		if student.Wishlist != nil {
+			pending[student.Wishlist.Phase] = struct{}{}
+		}
+		student.mu.RUnlock()
+	}
+	var result []string
+	for _, phase := range schedule.Phases {
+		if _, exists := pending[phase.Name]; exists && phase.Lottery && phase.isClosed(now) {
+			result = append(result, phase.Name)
+		}
+	}
+	return result
+}
+
+// lotteryOrder returns the students which have a wishlist in this phase, ordered by the
+// priorities of the phase. The ties are broken randomly based on the seed of the phase.
+func (p *Phase) lotteryOrder(students map[StudentID]*Student) []*Student {
+	var result []*Student
+	for _, student := range students {
+		if student.Wishlist != nil && student.Wishlist.Phase == p.Name {
+			result = append(result, student)
+		}
+	}
+	// Map iteration is random; so the students are sorted before shuffling them
+	slices.SortFunc(result, func(a, b *Student) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	random := rand.New(rand.NewPCG(uint64(p.Seed), 0))
+	random.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+	slices.SortStableFunc(result, func(a, b *Student) int {
+		for _, priority := range p.Priorities {
+			if c := priority.compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return result
+}
+
+// compare returns a negative number if student a comes before student b
+func (p Priority) compare(a, b *Student) int {
+	switch p {
+	case PrioritySeniority:
+		if (a.EntryYear == 0) != (b.EntryYear == 0) {
+			if a.EntryYear == 0 {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.EntryYear, b.EntryYear)
+	case PriorityGPABand:
+		return cmp.Compare(b.GPABand, a.GPABand)
+	default:
+		return 0
+	}
+}
+
+// allocateWish enrolls the student in a wished group. If reserve is false, it's only done when the
+// group has a free seat. Otherwise, it's only done when the group is full, which puts the student
+// in its reserve queue. Only batch errors are returned; other errors mean that the wish is skipped.
+func (s *Student) allocateWish(ctx context.Context, courses *Courses, wish Wish, reserve bool, batcher Batcher) (bool, error) {
+	course := courses.GetCourse(wish.CourseID, wish.GroupID)
+	if course == nil {
+		return false, nil
+	}
+	course.mu.RLock()
+	full := course.threadUnsafeTakenSeats() >= course.Capacity
+	course.mu.RUnlock()
+	if full != reserve {
+		return false, nil
+	}
+	err := s.enrollCourse(ctx, courses, wish.CourseID, wish.GroupID, batcher)
+	if errors.As(err, new(BatchError)) {
+		return false, err
+	}
+	return err == nil, nil
+}
+
+// threadUnsafeSetWishlist batches and replaces the wishlist of the student. Nil removes the
+// wishlist. The student must be locked.
+func (s *Student) threadUnsafeSetWishlist(ctx context.Context, wishlist *Wishlist, batcher Batcher) error {
+	err := batcher.ProcessDatabaseQuery(ctx, s.Department, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_PutWishlist{
+			PutWishlist: &proto.CourseDatabaseBatchPutWishlist{
+				StudentId: uint64(s.ID),
+				Wishlist:  wishlist.ToProto(),
+			},
+		},
+	})
+	if err != nil {
+		return BatchError{err}
+	}
+	s.Wishlist = wishlist
+	return nil
+}
+
+// NewWishlistFromProto creates a wishlist from its protobuf message.
+// Nil is returned if the message does not have any entries.
+func NewWishlistFromProto(data *proto.Wishlist) *Wishlist {
+	if len(data.GetEntries()) == 0 {
+		return nil
+	}
+	result := &Wishlist{Phase: data.Phase, Wishes: make([]Wish, len(data.Entries))}
+	for i, entry := range data.Entries {
+		result.Wishes[i] = Wish{CourseID: CourseID(entry.CourseId), GroupID: GroupID(entry.GroupId)}
+	}
+	return result
+}
+
+// ToProto converts the wishlist to its protobuf message. Nil wishlist gives nil.
+func (w *Wishlist) ToProto() *proto.Wishlist {
+	if w == nil {
+		return nil
+	}
+	result := &proto.Wishlist{Phase: w.Phase, Entries: make([]*proto.WishlistEntry, len(w.Wishes))}
+	for i, wish := range w.Wishes {
+		result.Entries[i] = &proto.WishlistEntry{CourseId: int32(wish.CourseID), GroupId: uint32(wish.GroupID)}
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/util"
+	"context"
+	"errors"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestLottery(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	start, end := clk.Now().UnixMilli(), clk.Now().Add(time.Hour).UnixMilli()
+	// newState creates course 1 with two groups which each has a single seat and a single reserve
+	// seat, and course 2 with two seats which is held at the same time of course 1 group 2. Students
+	// 1 to 5 are in GPA bands 1 to 5 and have wished group 1, group 2 and course 2 in order.
+	newState := func(priorities []Priority, seed int64) (*Courses, map[StudentID]*Student) {
+		newCourse := func(id CourseID, groupID GroupID, capacity int) *Course {
+			return &Course{
+				ID:                 id,
+				GroupID:            groupID,
+				Department:         1,
+				Units:              3,
+				Capacity:           capacity,
+				RegisteredStudents: make(map[StudentID]struct{}),
+				ReserveCapacity:    1,
+				ReserveQueue:       util.NewQueue[StudentID](),
+			}
+		}
+		coursesMap := map[CourseID][]*Course{
+			1: {newCourse(1, 1, 1), newCourse(1, 2, 1)},
+			2: {newCourse(2, 1, 2)},
+		}
+		coursesMap[1][1].ClassHeldTime = NewClassTime([]time.Weekday{time.Monday}, NewTimeOnly(600), NewTimeOnly(690))
+		coursesMap[2][0].ClassHeldTime = NewClassTime([]time.Weekday{time.Monday}, NewTimeOnly(600), NewTimeOnly(690))
+		courses := NewCourses(coursesMap)
+		courses.SetSchedule(&Schedule{Phases: []Phase{{
+			Name:       "lottery",
+			Windows:    []Window{{Start: start, End: end}},
+			Lottery:    true,
+			Priorities: priorities,
+			Seed:       seed,
+		}}})
+		students := make(map[StudentID]*Student)
+		for id := StudentID(1); id <= 5; id++ {
+			students[id] = &Student{
+				ID:                id,
+				MaxUnits:          20,
+				EntryYear:         1400,
+				GPABand:           uint8(id),
+				RegisteredCourses: make(map[CourseID]GroupID),
+				Wishlist:          &Wishlist{Phase: "lottery", Wishes: []Wish{{1, 1}, {1, 2}, {2, 1}}},
+			}
+		}
+		return courses, students
+	}
+	t.Run("wishlist", func(t *testing.T) {
+		courses, students := newState(nil, 0)
+		student := students[1]
+		student.Wishlist = nil
+		// Students cannot enroll directly in a lottery phase
+		assert.ErrorIs(t, student.EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}), NotEnrollmentTimeErr)
+		// Invalid wishlists
+		tooMany := make([]Wish, MaxWishlistLength+1)
+		assert.ErrorIs(t, student.PutWishlist(context.Background(), courses, tooMany, noOpBatcher{}), TooManyWishesErr)
+		assert.Equal(t, WishErr{Index: 1, Err: DuplicateWishErr}, student.PutWishlist(context.Background(), courses, []Wish{{1, 1}, {1, 1}}, noOpBatcher{}))
+		assert.Equal(t, WishErr{Index: 0, Err: NotExistsErr}, student.PutWishlist(context.Background(), courses, []Wish{{3, 1}}, noOpBatcher{}))
+		assert.ErrorAs(t, student.PutWishlist(context.Background(), courses, []Wish{{1, 1}}, errorBatcher{errors.New("broker is down")}), new(BatchError))
+		assert.Nil(t, student.Wishlist)
+		// Put it
+		batcher := new(inMemoryBatcher)
+		require.NoError(t, student.PutWishlist(context.Background(), courses, []Wish{{1, 2}, {1, 1}}, batcher))
+		assert.Equal(t, &Wishlist{Phase: "lottery", Wishes: []Wish{{1, 2}, {1, 1}}}, student.Wishlist)
+		assert.Equal(t, "lottery", student.GetWishlistProto().Phase)
+		if assert.Len(t, batcher.messages, 1) {
+			replayed := &Student{ID: 1}
+			require.NoError(t, ReplayMessage(courses, map[StudentID]*Student{1: replayed}, batcher.messages[0].data))
+			assert.Equal(t, student.Wishlist, replayed.Wishlist)
+		}
+		// Empty wishlist removes it
+		require.NoError(t, student.PutWishlist(context.Background(), courses, nil, batcher))
+		assert.Nil(t, student.Wishlist)
+		assert.Empty(t, student.GetWishlistProto().Entries)
+		// Wishlists cannot be submitted after the phase
+		clk.Add(time.Hour)
+		defer clk.Add(-time.Hour)
+		assert.ErrorIs(t, student.PutWishlist(context.Background(), courses, []Wish{{1, 1}}, noOpBatcher{}), NotLotteryTimeErr)
+	})
+	t.Run("run", func(t *testing.T) {
+		courses, students := newState([]Priority{PriorityGPABand}, 0)
+		// The lottery cannot run before the phase is closed
+		_, err := RunLottery(context.Background(), courses, students, "lottery", noOpBatcher{})
+		assert.ErrorIs(t, err, LotteryPhaseOpenErr)
+		clk.Add(time.Hour)
+		defer clk.Add(-time.Hour)
+		_, err = RunLottery(context.Background(), courses, students, "main", noOpBatcher{})
+		assert.ErrorIs(t, err, NoLotteryPhaseErr)
+		assert.Equal(t, []string{"lottery"}, PendingLotteries(courses, students))
+		// Run it
+		batcher := new(inMemoryBatcher)
+		result, err := RunLottery(context.Background(), courses, students, "lottery", batcher)
+		require.NoError(t, err)
+		assert.Equal(t, LotteryResult{Order: []StudentID{5, 4, 3, 2, 1}, Enrolled: 4, Reserved: 3}, result)
+		// Student 5 gets group 1, student 4 gets group 2 and students 3 and 2 get course 2. Then,
+		// the reserve seats go to the remaining wishes which do not conflict in the same order.
+		assert.Equal(t, map[CourseID]GroupID{1: 1, 2: 1}, students[5].RegisteredCourses)
+		assert.Equal(t, map[CourseID]GroupID{1: 2}, students[4].RegisteredCourses)
+		assert.Equal(t, map[CourseID]GroupID{1: 1, 2: 1}, students[3].RegisteredCourses)
+		assert.Equal(t, map[CourseID]GroupID{2: 1}, students[2].RegisteredCourses)
+		assert.Equal(t, map[CourseID]GroupID{1: 2}, students[1].RegisteredCourses)
+		assert.Equal(t, map[StudentID]struct{}{5: {}}, courses.GetCourse(1, 1).RegisteredStudents)
+		assert.Equal(t, []StudentID{3}, courses.GetCourse(1, 1).ReserveQueue.CopyAsArray())
+		assert.Equal(t, []StudentID{1}, courses.GetCourse(1, 2).ReserveQueue.CopyAsArray())
+		assert.Equal(t, []StudentID{5}, courses.GetCourse(2, 1).ReserveQueue.CopyAsArray())
+		// The wishlists are removed
+		for _, student := range students {
+			assert.Nil(t, student.Wishlist)
+		}
+		assert.Empty(t, PendingLotteries(courses, students))
+		// Replaying the messages gives the same state
+		replayedCourses, replayedStudents := newState([]Priority{PriorityGPABand}, 0)
+		for _, message := range batcher.messages {
+			require.NoError(t, ReplayMessage(replayedCourses, replayedStudents, message.data))
+		}
+		for id, student := range students {
+			assert.Equal(t, student.RegisteredCourses, replayedStudents[id].RegisteredCourses)
+			assert.Nil(t, replayedStudents[id].Wishlist)
+		}
+	})
+	t.Run("reproducible", func(t *testing.T) {
+		clk.Add(time.Hour)
+		defer clk.Add(-time.Hour)
+		// The same seed gives the same result
+		run := func(seed int64) (LotteryResult, map[StudentID]*Student) {
+			courses, students := newState(nil, seed)
+			result, err := RunLottery(context.Background(), courses, students, "lottery", noOpBatcher{})
+			require.NoError(t, err)
+			return result, students
+		}
+		result, students := run(42)
+		for range 3 {
+			otherResult, otherStudents := run(42)
+			assert.Equal(t, result, otherResult)
+			for id, student := range students {
+				assert.Equal(t, student.RegisteredCourses, otherStudents[id].RegisteredCourses)
+			}
+		}
+		// Another seed gives another order at some point
+		changed := false
+		for seed := int64(1); seed <= 10 && !changed; seed++ {
+			otherResult, _ := run(seed)
+			changed = !assert.ObjectsAreEqual(result.Order, otherResult.Order)
+		}
+		assert.True(t, changed)
+	})
+	t.Run("priorities", func(t *testing.T) {
+		a := &Student{EntryYear: 1399, GPABand: 1}
+		b := &Student{EntryYear: 1401, GPABand: 3}
+		unknown := &Student{}
+		assert.Negative(t, PrioritySeniority.compare(a, b))
+		assert.Negative(t, PrioritySeniority.compare(b, unknown))
+		assert.Positive(t, PrioritySeniority.compare(unknown, a))
+		assert.Positive(t, PriorityGPABand.compare(a, b))
+		assert.Zero(t, PriorityGPABand.compare(a, a))
+	})
+	t.Run("batch error", func(t *testing.T) {
+		courses, students := newState(nil, 0)
+		clk.Add(time.Hour)
+		defer clk.Add(-time.Hour)
+		_, err := RunLottery(context.Background(), courses, students, "lottery", errorBatcher{errors.New("broker is down")})
+		assert.ErrorAs(t, err, new(BatchError))
+		assert.Empty(t, courses.GetCourse(1, 1).RegisteredStudents)
+		assert.Equal(t, []string{"lottery"}, PendingLotteries(courses, students))
+	})
+}
@@ -11,6 +11,24 @@ type Batcher interface {
 	ProcessDatabaseQuery(context.Context, DepartmentID, *proto.CourseDatabaseBatchMessage) error
 }
 
+// ShardedBatcher is a Batcher which sends the messages of several departments to the same queue.
+// The messages of a queue are applied in order; so the messages of departments of the same shard
+// can be batched together.
+type ShardedBatcher interface {
+	Batcher
+	// ShardOf must return the shard which the messages of a department are sent to
+	ShardOf(DepartmentID) int
+}
+
+// ShardOf returns the shard which a batcher sends the messages of a department to. If the batcher is
+// not a ShardedBatcher, each department is assumed to be in a shard of its own.
+func ShardOf(batcher Batcher, department DepartmentID) int {
+	if sharded, ok := batcher.(ShardedBatcher); ok {
+		return sharded.ShardOf(department)
+	}
+	return int(department)
+}
+
 // BatchError is an error which Batcher.ProcessDatabaseQuery can return
 type BatchError struct {
 	err error
@@ -38,3 +38,14 @@ type errorBatcher struct {
 func (b errorBatcher) ProcessDatabaseQuery(context.Context, DepartmentID, *proto.CourseDatabaseBatchMessage) error {
 	return b.err
 }
+
+// shardedBatcher is an inMemoryBatcher which sends the departments to the shards in a map. The
+// departments which are not in the map are in shard zero.
+type shardedBatcher struct {
+	inMemoryBatcher
+	shards map[DepartmentID]int
+}
+
+func (b *shardedBatcher) ShardOf(department DepartmentID) int {
+	return b.shards[department]
+}
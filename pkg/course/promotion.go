@@ -0,0 +1,135 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ExpiredPromotion is a student which has not confirmed their seat in a course before its deadline
+type ExpiredPromotion struct {
+	StudentID StudentID
+	CourseID  CourseID
+	GroupID   GroupID
+}
+
+// ConfirmPromotion confirms the seat of the student in a course which they have been promoted to
+// from its reserve queue. The courses which have a confirmation window take the seats back from
+// the students which do not confirm them in time; see ExpirePromotions. NoPromotionErr is returned
+// if there is nothing to confirm, and PromotionExpiredErr is returned if the deadline has passed.
+//
+// Confirming does not use the remaining actions of the student and is not restricted by the schedule.
+func (s *Student) ConfirmPromotion(ctx context.Context, courses *Courses, courseID CourseID, batcher Batcher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Get the course
+	groupID, exists := s.RegisteredCourses[courseID]
+	if !exists {
+		return NotExistsErr
+	}
+	course := courses.GetCourse(courseID, groupID)
+	if course == nil {
+		panic(fmt.Sprintf("invalid registered lesson %d-%d for user %d", courseID, groupID, s.ID))
+	}
+	course.mu.Lock()
+	defer course.mu.Unlock()
+	deadline, exists := course.ConfirmationDeadlines[s.ID]
+	if !exists {
+		return NoPromotionErr
+	}
+	if deadline <= studentClock.Now().UnixMilli() {
+		return PromotionExpiredErr
+	}
+	err := batcher.ProcessDatabaseQuery(ctx, course.Department, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_ConfirmPromotion{
+			ConfirmPromotion: &proto.CourseDatabaseBatchConfirmPromotion{
+				StudentId: uint64(s.ID),
+				CourseId:  int32(courseID),
+			},
+		},
+	})
+	if err != nil {
+		return BatchError{err}
+	}
+	delete(course.ConfirmationDeadlines, s.ID)
+	return nil
+}
+
+// ExpirePromotions drops the promoted students which have not confirmed their seat before their
+// deadline from their courses. Each freed seat goes to the next student in the reserve queue of the
+// course, which must confirm it too. The dropped students are returned in the order of their deadline.
+// It stops at the first error; the students which are dropped until then are returned.
+func ExpirePromotions(ctx context.Context, courses *Courses, students map[StudentID]*Student, batcher Batcher) ([]ExpiredPromotion, error) {
+	now := studentClock.Now().UnixMilli()
+	// Find the expired ones at first because the students must be locked before the courses
+	type candidate struct {
+		ExpiredPromotion
+		deadline int64
+	}
+	var candidates []candidate
+	for _, course := range courses.All() {
+		course.mu.RLock()
+		for id, deadline := range course.ConfirmationDeadlines {
+			if deadline <= now {
+				candidates = append(candidates, candidate{ExpiredPromotion{id, course.ID, course.GroupID}, deadline})
+			}
+		}
+		course.mu.RUnlock()
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.deadline, b.deadline), cmp.Compare(a.StudentID, b.StudentID))
+	})
+	// Drop them
+	var result []ExpiredPromotion
+	for _, c := range candidates {
+		student, exists := students[c.StudentID]
+		if !exists {
+			continue
+		}
+		expired, err := student.expirePromotion(ctx, courses, c.CourseID, now, batcher)
+		if err != nil {
+			return result, err
+		}
+		if expired {
+			result = append(result, c.ExpiredPromotion)
+		}
+	}
+	return result, nil
+}
+
+// expirePromotion drops the student from a course if they have not confirmed their seat in it
+// before now. The first returned value is false if the seat is confirmed or the student has left the
+// course meanwhile.
+func (s *Student) expirePromotion(ctx context.Context, courses *Courses, courseID CourseID, now int64, batcher Batcher) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groupID, exists := s.RegisteredCourses[courseID]
+	if !exists {
+		return false, nil
+	}
+	course := courses.GetCourse(courseID, groupID)
+	if course == nil {
+		panic(fmt.Sprintf("invalid registered lesson %d-%d for user %d", courseID, groupID, s.ID))
+	}
+	course.mu.Lock()
+	deadline, exists := course.ConfirmationDeadlines[s.ID]
+	if !exists || deadline > now {
+		course.mu.Unlock()
+		return false, nil
+	}
+	err := course.threadUnsafeDisenrollStudent(ctx, s.ID, batcher)
+	if err == nil {
+		course.threadUnsafeEmit(EventPromotionExpired, s.ID, 0)
+	}
+	course.mu.Unlock()
+	if err != nil {
+		return false, err
+	}
+	// Remove from map
+	delete(s.RegisteredCourses, courseID)
+	s.RegisteredUnits -= course.Units
+	s.threadUnsafeClearIntent(courses, courseID)
+	return true, nil
+}
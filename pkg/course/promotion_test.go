@@ -0,0 +1,120 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPromotionConfirmation(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	// newState creates group 1 of course 1 with a seat, three reserve seats and a confirmation
+	// window of one hour, alongside four students which are enrolled in it in order
+	newState := func() (*Courses, map[StudentID]*Student) {
+		courses := NewCourses(map[CourseID][]*Course{1: {{
+			ID:                 1,
+			GroupID:            1,
+			Department:         2,
+			Units:              3,
+			Capacity:           1,
+			RegisteredStudents: make(map[StudentID]struct{}),
+			ReserveCapacity:    3,
+			ReserveQueue:       util.NewQueue[StudentID](),
+			ConfirmationWindow: time.Hour,
+		}}})
+		students := make(map[StudentID]*Student)
+		for id := StudentID(1); id <= 4; id++ {
+			students[id] = &Student{
+				ID:                  id,
+				EnrollmentStartTime: clk.Now().Add(-time.Minute).UnixMilli(),
+				RemainingActions:    2,
+				MaxUnits:            20,
+				RegisteredCourses:   make(map[CourseID]GroupID),
+			}
+			require.NoError(t, students[id].EnrollCourse(context.Background(), courses, 1, 1, noOpBatcher{}))
+		}
+		return courses, students
+	}
+	t.Run("confirm", func(t *testing.T) {
+		courses, students := newState()
+		course := courses.GetCourse(1, 1)
+		batcher := new(inMemoryBatcher)
+		assert.ErrorIs(t, students[1].ConfirmPromotion(context.Background(), courses, 1, batcher), NoPromotionErr)
+		assert.ErrorIs(t, students[1].ConfirmPromotion(context.Background(), courses, 2, batcher), NotExistsErr)
+		require.NoError(t, students[1].DisenrollCourse(context.Background(), courses, 1, batcher))
+		deadline := clk.Now().Add(time.Hour).UnixMilli()
+		assert.Equal(t, map[StudentID]int64{2: deadline}, course.ConfirmationDeadlines)
+		assert.Equal(t, deadline, course.ToStudentCourseDataProto(2).ConfirmationDeadline)
+		assert.Equal(t, uint64(2), batcher.messages[0].data.GetDisenroll().PromotedStudentId)
+		assert.Equal(t, deadline, batcher.messages[0].data.GetDisenroll().ConfirmationDeadline)
+		// Confirm it
+		clk.Add(30 * time.Minute)
+		require.NoError(t, students[2].ConfirmPromotion(context.Background(), courses, 1, batcher))
+		assert.Empty(t, course.ConfirmationDeadlines)
+		assert.Equal(t, &proto.CourseDatabaseBatchConfirmPromotion{StudentId: 2, CourseId: 1}, batcher.messages[1].data.GetConfirmPromotion())
+		// Nothing expires anymore
+		clk.Add(time.Hour)
+		expired, err := ExpirePromotions(context.Background(), courses, students, batcher)
+		require.NoError(t, err)
+		assert.Empty(t, expired)
+		assert.Contains(t, course.RegisteredStudents, StudentID(2))
+	})
+	t.Run("expire", func(t *testing.T) {
+		courses, students := newState()
+		course := courses.GetCourse(1, 1)
+		sink := new(recordingEventSink)
+		courses.SetEventSink(sink)
+		require.NoError(t, students[1].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		// Not yet
+		clk.Add(59 * time.Minute)
+		expired, err := ExpirePromotions(context.Background(), courses, students, noOpBatcher{})
+		require.NoError(t, err)
+		assert.Empty(t, expired)
+		// The seat goes to the next student
+		clk.Add(time.Minute)
+		assert.ErrorIs(t, students[2].ConfirmPromotion(context.Background(), courses, 1, noOpBatcher{}), PromotionExpiredErr)
+		expired, err = ExpirePromotions(context.Background(), courses, students, noOpBatcher{})
+		require.NoError(t, err)
+		assert.Equal(t, []ExpiredPromotion{{StudentID: 2, CourseID: 1, GroupID: 1}}, expired)
+		assert.Equal(t, map[StudentID]struct{}{3: {}}, course.RegisteredStudents)
+		assert.Equal(t, []StudentID{4}, course.ReserveQueue.CopyAsArray())
+		assert.Equal(t, map[StudentID]int64{3: clk.Now().Add(time.Hour).UnixMilli()}, course.ConfirmationDeadlines)
+		assert.NotContains(t, students[2].RegisteredCourses, CourseID(1))
+		assert.Zero(t, students[2].RegisteredUnits)
+		kinds := make([]EventKind, len(sink.events))
+		for i, event := range sink.events {
+			kinds[i] = event.Kind
+		}
+		assert.Equal(t, []EventKind{EventPromoted, EventPromoted, EventPromotionExpired}, kinds)
+		assert.Equal(t, StudentID(3), sink.events[1].StudentID)
+		assert.Equal(t, clk.Now().Add(time.Hour).UnixMilli(), sink.events[1].ConfirmationDeadline)
+		assert.Equal(t, StudentID(2), sink.events[2].StudentID)
+		// Dropping a promoted student does not leave their deadline behind
+		require.NoError(t, students[3].ForceDisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Equal(t, map[StudentID]int64{4: clk.Now().Add(time.Hour).UnixMilli()}, course.ConfirmationDeadlines)
+	})
+	t.Run("capacity", func(t *testing.T) {
+		courses, _ := newState()
+		course := courses.GetCourse(1, 1)
+		batcher := new(inMemoryBatcher)
+		require.NoError(t, course.UpdateCapacity(context.Background(), 3, batcher))
+		deadline := clk.Now().Add(time.Hour).UnixMilli()
+		assert.Equal(t, map[StudentID]int64{2: deadline, 3: deadline}, course.ConfirmationDeadlines)
+		assert.Equal(t, deadline, batcher.messages[0].data.GetUpdateCapacity().ConfirmationDeadline)
+	})
+	t.Run("no window", func(t *testing.T) {
+		courses, students := newState()
+		course := courses.GetCourse(1, 1)
+		course.ConfirmationWindow = 0
+		require.NoError(t, students[1].DisenrollCourse(context.Background(), courses, 1, noOpBatcher{}))
+		assert.Empty(t, course.ConfirmationDeadlines)
+		assert.ErrorIs(t, students[2].ConfirmPromotion(context.Background(), courses, 1, noOpBatcher{}), NoPromotionErr)
+	})
+}
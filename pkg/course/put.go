@@ -0,0 +1,193 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"time"
+)
+
+// PutStudent creates a student or replaces the data of an existing one. The enrolled and passed
+// courses of an existing student are kept.
+//
+// The caller must make sure that nothing else uses students meanwhile, because a new student
+// is added to the map.
+func PutStudent(ctx context.Context, students map[StudentID]*Student, data *proto.CourseDatabaseBatchPutStudent, batcher Batcher) error {
+	if batcher == nil {
+		panic("nil batcher")
+	}
+	// Check the data
+	if sex := Sex(data.Sex); sex != SexMale && sex != SexFemale {
+		return InvalidSexErr
+	}
+	if _, exists := students[StudentID(data.StudentId)]; !exists && data.PasswordHash == "" {
+		return NoPasswordErr
+	}
+	// Batch it
+	err := batcher.ProcessDatabaseQuery(ctx, DepartmentID(data.DepartmentId), &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_PutStudent{PutStudent: data},
+	})
+	if err != nil {
+		return BatchError{err}
+	}
+	putStudent(students, data)
+	return nil
+}
+
+// putStudent creates or updates a student without any checks
+func putStudent(students map[StudentID]*Student, data *proto.CourseDatabaseBatchPutStudent) {
+	student, exists := students[StudentID(data.StudentId)]
+	if !exists {
+		student = &Student{
+			ID:                StudentID(data.StudentId),
+			RegisteredCourses: make(map[CourseID]GroupID),
+		}
+		students[student.ID] = student
+	}
+	student.mu.Lock()
+	student.EnrollmentStartTime = data.EnrollmentStartTime
+	student.RemainingActions = uint8(data.RemainingActions)
+	student.MaxUnits = uint8(data.MaxUnits)
+	student.StudentSex = Sex(data.Sex)
+	student.Department = DepartmentID(data.DepartmentId)
+	student.EntryYear = int16(data.EntryYear)
+	student.GPABand = uint8(data.GpaBand)
+	student.mu.Unlock()
+}
+
+// PutCourse creates a course group or replaces the data of an existing one. The students of an
+// existing group are kept; so its capacity cannot be less than its registered students, and its
+// reserve capacity cannot be less than its reserve queue. If the capacity is increased, students are
+// moved from the reserve queue like UpdateCapacity; the capacity update and the new data are
+// batched in a single transaction message, so either both are applied or none. If the units
+// change, the registered units of the students of the group are updated.
+//
+// The caller must make sure that nothing else changes the courses and students meanwhile.
+func PutCourse(ctx context.Context, courses *Courses, students map[StudentID]*Student, data *proto.CourseDatabaseBatchPutCourse, batcher Batcher) error {
+	if batcher == nil {
+		panic("nil batcher")
+	}
+	// Check the data
+	if SexLock(data.SexLock) > SexLockFemaleOnly {
+		return InvalidSexErr
+	}
+	if data.Capacity < 0 || data.ReserveCapacity < 0 {
+		return NegativeCapacityErr
+	}
+	if data.ConfirmationWindow < 0 {
+		return NegativeConfirmationWindowErr
+	}
+	if eligibility := data.GetEligibility(); eligibility != nil && eligibility.MinEntryYear != 0 && eligibility.MaxEntryYear != 0 &&
+		eligibility.MinEntryYear > eligibility.MaxEntryYear {
+		return InvalidEntryYearRangeErr
+	}
+	if department, exists := courses.DepartmentOf(CourseID(data.CourseId)); exists && department != DepartmentID(data.DepartmentId) {
+		return DepartmentChangeErr
+	}
+	// Batch it
+	if err := batchPutCourse(ctx, courses.GetCourse(CourseID(data.CourseId), GroupID(data.GroupId)), data, batcher); err != nil {
+		return err
+	}
+	putCourse(courses, students, data)
+	return nil
+}
+
+// batchPutCourse batches the message of PutCourse. If the group exists, its capacities are checked
+// and its capacity is changed in the same message to move the reserved students.
+func batchPutCourse(ctx context.Context, course *Course, data *proto.CourseDatabaseBatchPutCourse, batcher Batcher) error {
+	message := &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_PutCourse{PutCourse: data},
+	}
+	if course == nil {
+		if err := batcher.ProcessDatabaseQuery(ctx, DepartmentID(data.DepartmentId), message); err != nil {
+			return BatchError{err}
+		}
+		return nil
+	}
+	course.mu.Lock()
+	defer course.mu.Unlock()
+	// Check the capacities
+	registered, queued := course.threadUnsafeTakenSeats(), course.ReserveQueue.Len()
+	if registered > int(data.Capacity) {
+		return LowerCapacityThanRegistered
+	}
+	moved := util.Max(util.Min(int(data.Capacity)-course.Capacity, queued), 0)
+	if queued-moved > int(data.ReserveCapacity) {
+		return LowerReserveCapacityThanQueue
+	}
+	update, err := course.threadUnsafeCapacityUpdate(int(data.Capacity))
+	if err != nil {
+		return err
+	}
+	if update != nil {
+		message = &proto.CourseDatabaseBatchMessage{
+			Action: &proto.CourseDatabaseBatchMessage_Transaction{
+				Transaction: &proto.CourseDatabaseBatchTransaction{Messages: []*proto.CourseDatabaseBatchMessage{
+					{Action: &proto.CourseDatabaseBatchMessage_UpdateCapacity{UpdateCapacity: update}},
+					message,
+				}},
+			},
+		}
+	}
+	if err = batcher.ProcessDatabaseQuery(ctx, DepartmentID(data.DepartmentId), message); err != nil {
+		return BatchError{err}
+	}
+	if update != nil {
+		course.threadUnsafeApplyCapacityUpdate(update)
+	}
+	return nil
+}
+
+// putCourse creates or updates a course group without any checks
+func putCourse(courses *Courses, students map[StudentID]*Student, data *proto.CourseDatabaseBatchPutCourse) {
+	course := courses.GetCourse(CourseID(data.CourseId), GroupID(data.GroupId))
+	if course == nil {
+		course = &Course{
+			ID:                 CourseID(data.CourseId),
+			GroupID:            GroupID(data.GroupId),
+			Department:         DepartmentID(data.DepartmentId),
+			Lecturer:           data.Lecturer,
+			Units:              uint8(data.Units),
+			Capacity:           int(data.Capacity),
+			RegisteredStudents: make(map[StudentID]struct{}, data.Capacity),
+			ReserveCapacity:    int(data.ReserveCapacity),
+			ReserveQueue:       util.NewQueue[StudentID](),
+			SexLock:            SexLock(data.SexLock),
+			Eligibility:        NewEligibilityFromProto(data.Eligibility),
+			ConfirmationWindow: time.Duration(data.ConfirmationWindow) * time.Second,
+		}
+		course.ExamTime.Store(data.ExamTime)
+		course.ClassHeldTime.data.Store(data.ClassTime)
+		courses.addCourse(course)
+		return
+	}
+	// Update the course and get its students to fix their units
+	course.mu.Lock()
+	oldUnits := course.Units
+	course.Lecturer = data.Lecturer
+	course.Units = uint8(data.Units)
+	course.Capacity = int(data.Capacity)
+	course.ReserveCapacity = int(data.ReserveCapacity)
+	course.SexLock = SexLock(data.SexLock)
+	course.Eligibility = NewEligibilityFromProto(data.Eligibility)
+	course.ConfirmationWindow = time.Duration(data.ConfirmationWindow) * time.Second
+	course.ExamTime.Store(data.ExamTime)
+	course.ClassHeldTime.data.Store(data.ClassTime)
+	course.threadUnsafeNotifyWatchers()
+	var enrolled []StudentID
+	if oldUnits != course.Units {
+		for id := range course.RegisteredStudents {
+			enrolled = append(enrolled, id)
+		}
+		enrolled = append(enrolled, course.ReserveQueue.CopyAsArray()...)
+	}
+	course.mu.Unlock()
+	// Students are locked after the course is unlocked because they are locked before courses
+	for _, id := range enrolled {
+		if student, exists := students[id]; exists {
+			student.mu.Lock()
+			student.RegisteredUnits = student.RegisteredUnits - oldUnits + uint8(data.Units)
+			student.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPutStudent(t *testing.T) {
+	students := map[StudentID]*Student{
+		1: {ID: 1, MaxUnits: 20, RegisteredUnits: 3, StudentSex: SexMale, RegisteredCourses: map[CourseID]GroupID{10: 1}},
+	}
+	batcher := new(inMemoryBatcher)
+	// Invalid data
+	assert.ErrorIs(t, PutStudent(context.Background(), students, &proto.CourseDatabaseBatchPutStudent{StudentId: 2, PasswordHash: "hash"}, batcher), InvalidSexErr)
+	assert.ErrorIs(t, PutStudent(context.Background(), students, &proto.CourseDatabaseBatchPutStudent{StudentId: 2, Sex: uint32(SexMale)}, batcher), NoPasswordErr)
+	assert.Empty(t, batcher.messages)
+	// Create a student
+	require.NoError(t, PutStudent(context.Background(), students, &proto.CourseDatabaseBatchPutStudent{
+		StudentId:           2,
+		PasswordHash:        "hash",
+		EnrollmentStartTime: 1000,
+		MaxUnits:            24,
+		RemainingActions:    3,
+		DepartmentId:        5,
+		EntryYear:           1401,
+		Sex:                 uint32(SexFemale),
+		GpaBand:             3,
+	}, batcher))
+	if assert.Contains(t, students, StudentID(2)) {
+		assert.Equal(t, &Student{ID: 2, EnrollmentStartTime: 1000, RemainingActions: 3, MaxUnits: 24, StudentSex: SexFemale, Department: 5, EntryYear: 1401, GPABand: 3, RegisteredCourses: map[CourseID]GroupID{}}, students[2])
+	}
+	if assert.Len(t, batcher.messages, 1) {
+		assert.Equal(t, DepartmentID(5), batcher.messages[0].dep)
+		assert.Equal(t, uint64(2), batcher.messages[0].data.GetPutStudent().StudentId)
+	}
+	// Update a student without a password and keep its courses
+	require.NoError(t, PutStudent(context.Background(), students, &proto.CourseDatabaseBatchPutStudent{StudentId: 1, MaxUnits: 12, Sex: uint32(SexFemale)}, batcher))
+	assert.Equal(t, uint8(12), students[1].MaxUnits)
+	assert.Equal(t, SexFemale, students[1].StudentSex)
+	assert.Equal(t, uint8(3), students[1].RegisteredUnits)
+	assert.Equal(t, map[CourseID]GroupID{10: 1}, students[1].RegisteredCourses)
+	// Batch errors do not change anything
+	assert.ErrorAs(t, PutStudent(context.Background(), students, &proto.CourseDatabaseBatchPutStudent{StudentId: 3, PasswordHash: "hash", Sex: uint32(SexMale)}, errorBatcher{errors.New("broker is down")}), new(BatchError))
+	assert.NotContains(t, students, StudentID(3))
+}
+
+func TestPutCourse(t *testing.T) {
+	// newState creates a course with a registered student and two reserved students
+	newState := func() (*Courses, map[StudentID]*Student) {
+		course := &Course{
+			ID:                 10,
+			GroupID:            1,
+			Department:         2,
+			Units:              3,
+			Capacity:           1,
+			RegisteredStudents: map[StudentID]struct{}{1: {}},
+			ReserveCapacity:    2,
+			ReserveQueue:       util.NewQueue[StudentID](),
+		}
+		course.ReserveQueue.Enqueue(2)
+		course.ReserveQueue.Enqueue(3)
+		students := make(map[StudentID]*Student)
+		for id := StudentID(1); id <= 3; id++ {
+			students[id] = &Student{ID: id, MaxUnits: 20, RegisteredUnits: 3, RegisteredCourses: map[CourseID]GroupID{10: 1}}
+		}
+		return NewCourses(map[CourseID][]*Course{10: {course}}), students
+	}
+	tests := []struct {
+		Name     string
+		Data     *proto.CourseDatabaseBatchPutCourse
+		Expected error
+	}{
+		{
+			Name:     "invalid sex lock",
+			Data:     &proto.CourseDatabaseBatchPutCourse{CourseId: 11, GroupId: 1, DepartmentId: 2, SexLock: 3},
+			Expected: InvalidSexErr,
+		},
+		{
+			Name:     "negative capacity",
+			Data:     &proto.CourseDatabaseBatchPutCourse{CourseId: 11, GroupId: 1, DepartmentId: 2, Capacity: -1},
+			Expected: NegativeCapacityErr,
+		},
+		{
+			Name:     "invalid entry year range",
+			Data:     &proto.CourseDatabaseBatchPutCourse{CourseId: 11, GroupId: 1, DepartmentId: 2, Eligibility: &proto.CourseEligibility{MinEntryYear: 1401, MaxEntryYear: 1400}},
+			Expected: InvalidEntryYearRangeErr,
+		},
+		{
+			Name:     "other department",
+			Data:     &proto.CourseDatabaseBatchPutCourse{CourseId: 10, GroupId: 2, DepartmentId: 3, Capacity: 1},
+			Expected: DepartmentChangeErr,
+		},
+		{
+			Name:     "lower capacity",
+			Data:     &proto.CourseDatabaseBatchPutCourse{CourseId: 10, GroupId: 1, DepartmentId: 2, Capacity: 0, ReserveCapacity: 2},
+			Expected: LowerCapacityThanRegistered,
+		},
+		{
+			Name:     "lower reserve capacity",
+			Data:     &proto.CourseDatabaseBatchPutCourse{CourseId: 10, GroupId: 1, DepartmentId: 2, Capacity: 1, ReserveCapacity: 1},
+			Expected: LowerReserveCapacityThanQueue,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			courses, students := newState()
+			batcher := new(inMemoryBatcher)
+			assert.ErrorIs(t, PutCourse(context.Background(), courses, students, test.Data, batcher), test.Expected)
+			assert.Empty(t, batcher.messages)
+		})
+	}
+	t.Run("new group", func(t *testing.T) {
+		courses, students := newState()
+		batcher := new(inMemoryBatcher)
+		require.NoError(t, PutCourse(context.Background(), courses, students, &proto.CourseDatabaseBatchPutCourse{
+			CourseId: 10, GroupId: 2, DepartmentId: 2, Lecturer: "Lecturer", Units: 3, Capacity: 5, ReserveCapacity: 1, ExamTime: 1000, SexLock: uint32(SexLockMaleOnly),
+			Eligibility: &proto.CourseEligibility{Departments: []uint32{2}},
+		}, batcher))
+		course := courses.GetCourse(10, 2)
+		if assert.NotNil(t, course) {
+			assert.Equal(t, "Lecturer", course.Lecturer)
+			assert.Equal(t, 5, course.Capacity)
+			assert.Equal(t, int64(1000), course.ExamTime.Load())
+			assert.Equal(t, SexLockMaleOnly, course.SexLock)
+			assert.Equal(t, &Eligibility{Departments: []DepartmentID{2}}, course.Eligibility)
+		}
+		assert.Len(t, batcher.messages, 1)
+		assert.Len(t, courses.GetDepartmentCoursesProto(2).Courses, 2)
+	})
+	t.Run("update group", func(t *testing.T) {
+		courses, students := newState()
+		batcher := new(inMemoryBatcher)
+		require.NoError(t, PutCourse(context.Background(), courses, students, &proto.CourseDatabaseBatchPutCourse{
+			CourseId: 10, GroupId: 1, DepartmentId: 2, Units: 4, Capacity: 2, ReserveCapacity: 1,
+		}, batcher))
+		course := courses.GetCourse(10, 1)
+		assert.Equal(t, map[StudentID]struct{}{1: {}, 2: {}}, course.RegisteredStudents)
+		assert.Equal(t, []StudentID{3}, course.ReserveQueue.CopyAsArray())
+		assert.Equal(t, uint8(4), course.Units)
+		for id := StudentID(1); id <= 3; id++ {
+			assert.Equal(t, uint8(4), students[id].RegisteredUnits)
+		}
+		// The capacity is updated before the course in the same transaction
+		if assert.Len(t, batcher.messages, 1) {
+			messages := batcher.messages[0].data.GetTransaction().GetMessages()
+			if assert.Len(t, messages, 2) {
+				assert.Equal(t, []uint64{2}, messages[0].GetUpdateCapacity().MovedStudents)
+				assert.NotNil(t, messages[1].GetPutCourse())
+			}
+		}
+		// Replaying the messages gives the same state
+		replayedCourses, replayedStudents := newState()
+		for _, message := range batcher.messages {
+			require.NoError(t, ReplayMessage(replayedCourses, replayedStudents, message.data))
+		}
+		assert.Equal(t, course.RegisteredStudents, replayedCourses.GetCourse(10, 1).RegisteredStudents)
+		assert.Equal(t, uint8(4), replayedStudents[3].RegisteredUnits)
+	})
+	t.Run("batch error", func(t *testing.T) {
+		courses, students := newState()
+		err := PutCourse(context.Background(), courses, students, &proto.CourseDatabaseBatchPutCourse{
+			CourseId: 10, GroupId: 1, DepartmentId: 2, Units: 4, Capacity: 2, ReserveCapacity: 1,
+		}, errorBatcher{errors.New("broker is down")})
+		assert.ErrorAs(t, err, new(BatchError))
+		// Neither the capacity nor the course is changed
+		course := courses.GetCourse(10, 1)
+		assert.Equal(t, 1, course.Capacity)
+		assert.Equal(t, map[StudentID]struct{}{1: {}}, course.RegisteredStudents)
+		assert.Equal(t, []StudentID{2, 3}, course.ReserveQueue.CopyAsArray())
+		assert.Equal(t, uint8(3), course.Units)
+	})
+}
@@ -0,0 +1,99 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/util"
+	"fmt"
+	"slices"
+)
+
+// CourseStudents is the list of students of a course group
+type CourseStudents struct {
+	Course *Course
+	// Registered students in no specific order
+	Registered []StudentID
+	// The reserve queue in order
+	Reserved []StudentID
+}
+
+// ReplaceStudents replaces the registered students and the reserve queue of courses and updates the
+// enrolled courses of the students to match them. Nothing like the capacity is checked. It's used to
+// repair the state when it has drifted from the database.
+//
+// The caller must make sure that no other change happens to the courses and students meanwhile.
+func ReplaceStudents(courses *Courses, students map[StudentID]*Student, replacements []CourseStudents) error {
+	// Check the students at first to not change anything on error
+	for _, replacement := range replacements {
+		for _, id := range slices.Concat(replacement.Registered, replacement.Reserved) {
+			if _, exists := students[id]; !exists {
+				return fmt.Errorf("student %d does not exist", id)
+			}
+		}
+	}
+	// Replace the students of courses
+	removed := make(map[*Course][]StudentID, len(replacements))
+	touched := make(map[StudentID]struct{})
+	for _, replacement := range replacements {
+		c := replacement.Course
+		c.mu.Lock()
+		for id := range c.RegisteredStudents {
+			removed[c] = append(removed[c], id)
+		}
+		removed[c] = append(removed[c], c.ReserveQueue.CopyAsArray()...)
+		c.RegisteredStudents = make(map[StudentID]struct{}, max(c.Capacity, len(replacement.Registered)))
+		for _, id := range replacement.Registered {
+			c.RegisteredStudents[id] = struct{}{}
+		}
+		c.ReserveQueue = util.NewQueue[StudentID]()
+		for _, id := range replacement.Reserved {
+			c.ReserveQueue.Enqueue(id)
+		}
+		// The held seats are not in the database
+		c.HeldSeats = nil
+		// Only the registered students can have a promotion to confirm
+		for id := range c.ConfirmationDeadlines {
+			if _, registered := c.RegisteredStudents[id]; !registered {
+				delete(c.ConfirmationDeadlines, id)
+			}
+		}
+		c.threadUnsafeNotifyWatchers()
+		c.mu.Unlock()
+	}
+	// Remove the old students and then add the new ones. A student might move between
+	// two groups which are both replaced.
+	for c, ids := range removed {
+		for _, id := range ids {
+			student, exists := students[id]
+			if !exists {
+				continue
+			}
+			student.mu.Lock()
+			if groupID, enrolled := student.RegisteredCourses[c.ID]; enrolled && groupID == c.GroupID {
+				delete(student.RegisteredCourses, c.ID)
+			}
+			student.mu.Unlock()
+			touched[id] = struct{}{}
+		}
+	}
+	for _, replacement := range replacements {
+		for _, id := range slices.Concat(replacement.Registered, replacement.Reserved) {
+			student := students[id]
+			student.mu.Lock()
+			student.RegisteredCourses[replacement.Course.ID] = replacement.Course.GroupID
+			student.mu.Unlock()
+			touched[id] = struct{}{}
+		}
+	}
+	// Fix the units
+	for id := range touched {
+		student := students[id]
+		student.mu.Lock()
+		student.RegisteredUnits = 0
+		for courseID, groupID := range student.RegisteredCourses {
+			if c := courses.GetCourse(courseID, groupID); c != nil {
+				student.RegisteredUnits += c.Units
+			}
+		}
+		student.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"fmt"
+)
+
+// ReplayMessage applies a message which has been batched before on the courses and students.
+// The same change which happened when the message was batched is done again, so nothing like
+// the capacity or enrollment time is checked. An error is returned if the message does not match
+// the state; for example, if the student is not enrolled in the course which it's disenrolling from.
+//
+// This function is not thread safe and must be only used while loading the state.
+func ReplayMessage(courses *Courses, students map[StudentID]*Student, msg *proto.CourseDatabaseBatchMessage) error {
+	switch action := msg.GetAction().(type) {
+	case *proto.CourseDatabaseBatchMessage_Enroll:
+		student, course, err := replayTarget(courses, students, action.Enroll.StudentId, action.Enroll.CourseId, action.Enroll.GroupId)
+		if err != nil {
+			return err
+		}
+		if _, exists := student.RegisteredCourses[course.ID]; exists {
+			return fmt.Errorf("student %d is already enrolled in course %d", student.ID, course.ID)
+		}
+		if action.Enroll.Reserved {
+			course.ReserveQueue.Enqueue(student.ID)
+		} else {
+			course.RegisteredStudents[student.ID] = struct{}{}
+		}
+		student.RegisteredCourses[course.ID] = course.GroupID
+		student.RegisteredUnits += course.Units
+	case *proto.CourseDatabaseBatchMessage_Disenroll:
+		student, ok := students[StudentID(action.Disenroll.StudentId)]
+		if !ok {
+			return fmt.Errorf("student %d does not exist", action.Disenroll.StudentId)
+		}
+		course, err := replayEnrolledCourse(courses, student, CourseID(action.Disenroll.CourseId))
+		if err != nil {
+			return err
+		}
+		// The deadline of the message is used instead of the replay time
+		course.threadUnsafeRemoveStudent(student.ID, action.Disenroll.ConfirmationDeadline)
+		// The held seats are not in the messages; the moves of intents are replayed as group changes
+		course.HeldSeats = nil
+		delete(student.RegisteredCourses, course.ID)
+		student.RegisteredUnits -= course.Units
+		student.threadUnsafeClearIntent(courses, course.ID)
+		if action.Disenroll.ConsumesAction && student.RemainingActions != 0 {
+			student.RemainingActions--
+		}
+	case *proto.CourseDatabaseBatchMessage_ChangeGroup:
+		student, destination, err := replayTarget(courses, students, action.ChangeGroup.StudentId, action.ChangeGroup.CourseId, action.ChangeGroup.GroupId)
+		if err != nil {
+			return err
+		}
+		source, err := replayEnrolledCourse(courses, student, destination.ID)
+		if err != nil {
+			return err
+		}
+		if source == destination {
+			return fmt.Errorf("student %d is already in course %d-%d", student.ID, destination.ID, destination.GroupID)
+		}
+		if ok, _ := destination.threadUnsafeEnrollStudent(context.Background(), student.ID, nil); !ok {
+			return fmt.Errorf("course %d-%d is full", destination.ID, destination.GroupID)
+		}
+		source.threadUnsafeRemoveStudent(student.ID, action.ChangeGroup.ConfirmationDeadline)
+		source.HeldSeats = nil
+		student.RegisteredCourses[destination.ID] = destination.GroupID
+		student.threadUnsafeClearIntent(courses, destination.ID)
+		if action.ChangeGroup.ConsumesAction && student.RemainingActions != 0 {
+			student.RemainingActions--
+		}
+	case *proto.CourseDatabaseBatchMessage_UpdateCapacity:
+		course := courses.GetCourse(CourseID(action.UpdateCapacity.CourseId), GroupID(action.UpdateCapacity.GroupId))
+		if course == nil {
+			return fmt.Errorf("course %d-%d does not exist", action.UpdateCapacity.CourseId, action.UpdateCapacity.GroupId)
+		}
+		for _, id := range action.UpdateCapacity.MovedStudents {
+			if !course.ReserveQueue.Remove(StudentID(id)) {
+				return fmt.Errorf("student %d is not in the reserve queue of course %d-%d", id, course.ID, course.GroupID)
+			}
+			course.RegisteredStudents[StudentID(id)] = struct{}{}
+			course.threadUnsafeSetConfirmationDeadline(StudentID(id), action.UpdateCapacity.ConfirmationDeadline)
+		}
+		course.Capacity = int(action.UpdateCapacity.NewCapacity)
+	case *proto.CourseDatabaseBatchMessage_AddIntent:
+		student, destination, err := replayTarget(courses, students, action.AddIntent.StudentId, action.AddIntent.CourseId, action.AddIntent.GroupId)
+		if err != nil {
+			return err
+		}
+		if _, err := replayEnrolledCourse(courses, student, destination.ID); err != nil {
+			return err
+		}
+		student.threadUnsafeSetIntent(courses, destination.ID, destination.GroupID)
+	case *proto.CourseDatabaseBatchMessage_RemoveIntent:
+		student, ok := students[StudentID(action.RemoveIntent.StudentId)]
+		if !ok {
+			return fmt.Errorf("student %d does not exist", action.RemoveIntent.StudentId)
+		}
+		if _, exists := student.Intents[CourseID(action.RemoveIntent.CourseId)]; !exists {
+			return fmt.Errorf("student %d has no intent in course %d", student.ID, action.RemoveIntent.CourseId)
+		}
+		student.threadUnsafeClearIntent(courses, CourseID(action.RemoveIntent.CourseId))
+	case *proto.CourseDatabaseBatchMessage_Transaction:
+		for _, message := range action.Transaction.Messages {
+			if err := ReplayMessage(courses, students, message); err != nil {
+				return err
+			}
+		}
+	case *proto.CourseDatabaseBatchMessage_PutWishlist:
+		student, ok := students[StudentID(action.PutWishlist.StudentId)]
+		if !ok {
+			return fmt.Errorf("student %d does not exist", action.PutWishlist.StudentId)
+		}
+		student.Wishlist = NewWishlistFromProto(action.PutWishlist.Wishlist)
+	case *proto.CourseDatabaseBatchMessage_PutStudent:
+		putStudent(students, action.PutStudent)
+	case *proto.CourseDatabaseBatchMessage_PutCourse:
+		putCourse(courses, students, action.PutCourse)
+	case *proto.CourseDatabaseBatchMessage_PutSchedule:
+		schedule, err := NewScheduleFromProto(action.PutSchedule)
+		if err != nil {
+			return fmt.Errorf("invalid schedule: %w", err)
+		}
+		courses.SetSchedule(schedule)
+	case *proto.CourseDatabaseBatchMessage_ConfirmPromotion:
+		student, ok := students[StudentID(action.ConfirmPromotion.StudentId)]
+		if !ok {
+			return fmt.Errorf("student %d does not exist", action.ConfirmPromotion.StudentId)
+		}
+		course, err := replayEnrolledCourse(courses, student, CourseID(action.ConfirmPromotion.CourseId))
+		if err != nil {
+			return err
+		}
+		if _, exists := course.ConfirmationDeadlines[student.ID]; !exists {
+			return fmt.Errorf("student %d has no promotion to confirm in course %d-%d", student.ID, course.ID, course.GroupID)
+		}
+		delete(course.ConfirmationDeadlines, student.ID)
+	case *proto.CourseDatabaseBatchMessage_AddNotification:
+		// Notifications are not a part of the state
+	default:
+		return fmt.Errorf("invalid action: %v", msg)
+	}
+	return nil
+}
+
+// replayTarget gets the student and the course group of a message
+func replayTarget(courses *Courses, students map[StudentID]*Student, studentID uint64, courseID int32, groupID uint32) (*Student, *Course, error) {
+	student, ok := students[StudentID(studentID)]
+	if !ok {
+		return nil, nil, fmt.Errorf("student %d does not exist", studentID)
+	}
+	course := courses.GetCourse(CourseID(courseID), GroupID(groupID))
+	if course == nil {
+		return nil, nil, fmt.Errorf("course %d-%d does not exist", courseID, groupID)
+	}
+	return student, course, nil
+}
+
+// replayEnrolledCourse gets the group of a course which the student is enrolled in
+func replayEnrolledCourse(courses *Courses, student *Student, courseID CourseID) (*Course, error) {
+	groupID, exists := student.RegisteredCourses[courseID]
+	if !exists {
+		return nil, fmt.Errorf("student %d is not enrolled in course %d", student.ID, courseID)
+	}
+	course := courses.GetCourse(courseID, groupID)
+	if course == nil {
+		return nil, fmt.Errorf("course %d-%d does not exist", courseID, groupID)
+	}
+	return course, nil
+}
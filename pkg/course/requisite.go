@@ -0,0 +1,62 @@
+package course
+
+import "slices"
+
+// Requisites are the courses which a student must have passed or must take alongside a course
+type Requisites struct {
+	// The courses which must be passed before enrolling in the course
+	Prerequisites []CourseID
+	// The courses which must be passed before, or must be enrolled in the same enrollment session
+	Corequisites []CourseID
+}
+
+// Requisites returns the requisites of a course. The returned value must not be changed.
+func (c *Courses) Requisites(courseID CourseID) Requisites {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.requisites[courseID]
+}
+
+// SetRequisites replaces the requisites of all courses. The map must not be changed afterward.
+func (c *Courses) SetRequisites(requisites map[CourseID]Requisites) {
+	c.mu.Lock()
+	c.requisites = requisites
+	c.mu.Unlock()
+}
+
+// checkRequisites checks if the student can enroll in a course based on its requisites while
+// they are enrolled in the registered courses. The student must be locked.
+func (s *Student) checkRequisites(courses *Courses, courseID CourseID, registered map[CourseID]GroupID) error {
+	requisites := courses.Requisites(courseID)
+	var missing PrerequisiteMissingErr
+	for _, id := range requisites.Prerequisites {
+		if _, passed := s.PassedCourses[id]; !passed {
+			missing.Prerequisites = append(missing.Prerequisites, id)
+		}
+	}
+	for _, id := range requisites.Corequisites {
+		_, passed := s.PassedCourses[id]
+		_, enrolled := registered[id]
+		if !passed && !enrolled {
+			missing.Corequisites = append(missing.Corequisites, id)
+		}
+	}
+	if len(missing.Prerequisites) != 0 || len(missing.Corequisites) != 0 {
+		return missing
+	}
+	return nil
+}
+
+// checkCorequisiteOf checks if the student can disenroll from a course while they are enrolled in
+// the registered courses which might need it as a co-requisite. The student must be locked.
+func (s *Student) checkCorequisiteOf(courses *Courses, courseID CourseID, registered map[CourseID]GroupID) error {
+	if _, passed := s.PassedCourses[courseID]; passed {
+		return nil
+	}
+	for registeredCourseID := range registered {
+		if slices.Contains(courses.Requisites(registeredCourseID).Corequisites, courseID) {
+			return CorequisiteRequiredErr{CourseID: registeredCourseID}
+		}
+	}
+	return nil
+}
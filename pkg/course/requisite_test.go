@@ -0,0 +1,134 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/util"
+	"context"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStudentRequisites(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	// newCourses creates courses 1 to 4 with a single group. Course 3 needs course 1 passed
+	// and course 2 passed or taken. Course 4 needs course 1 passed and course 3 taken.
+	newCourses := func() *Courses {
+		courses := make(map[CourseID][]*Course)
+		for id := CourseID(1); id <= 4; id++ {
+			courses[id] = []*Course{{
+				ID:                 id,
+				GroupID:            1,
+				Units:              1,
+				Capacity:           5,
+				RegisteredStudents: make(map[StudentID]struct{}),
+				ReserveQueue:       util.NewQueue[StudentID](),
+			}}
+		}
+		result := NewCourses(courses)
+		result.SetRequisites(map[CourseID]Requisites{
+			3: {Prerequisites: []CourseID{1}, Corequisites: []CourseID{2}},
+			4: {Prerequisites: []CourseID{1}, Corequisites: []CourseID{3}},
+		})
+		return result
+	}
+	newStudent := func(passed []CourseID, registered []CourseID) *Student {
+		student := &Student{
+			ID:                  1,
+			EnrollmentStartTime: clk.Now().Add(-time.Minute).UnixMilli(),
+			RemainingActions:    5,
+			MaxUnits:            20,
+			RegisteredCourses:   make(map[CourseID]GroupID),
+			PassedCourses:       make(map[CourseID]struct{}),
+		}
+		for _, id := range passed {
+			student.PassedCourses[id] = struct{}{}
+		}
+		for _, id := range registered {
+			student.RegisteredCourses[id] = 1
+		}
+		return student
+	}
+	t.Run("enroll", func(t *testing.T) {
+		tests := []struct {
+			Name       string
+			Passed     []CourseID
+			Registered []CourseID
+			Course     CourseID
+			Expected   error
+		}{
+			{
+				Name:   "no requisites",
+				Course: 1,
+			},
+			{
+				Name:     "nothing passed",
+				Course:   3,
+				Expected: PrerequisiteMissingErr{Prerequisites: []CourseID{1}, Corequisites: []CourseID{2}},
+			},
+			{
+				Name:     "co-requisite missing",
+				Passed:   []CourseID{1},
+				Course:   3,
+				Expected: PrerequisiteMissingErr{Corequisites: []CourseID{2}},
+			},
+			{
+				Name:   "co-requisite passed",
+				Passed: []CourseID{1, 2},
+				Course: 3,
+			},
+			{
+				Name:       "co-requisite taken",
+				Passed:     []CourseID{1},
+				Registered: []CourseID{2},
+				Course:     3,
+			},
+			{
+				Name:       "prerequisite taken but not passed",
+				Registered: []CourseID{1, 2},
+				Course:     3,
+				Expected:   PrerequisiteMissingErr{Prerequisites: []CourseID{1}},
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				student := newStudent(test.Passed, test.Registered)
+				err := student.EnrollCourse(context.Background(), newCourses(), test.Course, 1, noOpBatcher{})
+				if test.Expected == nil {
+					assert.NoError(t, err)
+				} else {
+					assert.Equal(t, test.Expected, err)
+					assert.NotContains(t, student.RegisteredCourses, test.Course)
+				}
+			})
+		}
+	})
+	t.Run("disenroll", func(t *testing.T) {
+		courses := newCourses()
+		// A co-requisite of a taken course cannot be dropped
+		student := newStudent([]CourseID{1}, nil)
+		assert.NoError(t, student.EnrollCourse(context.Background(), courses, 2, 1, noOpBatcher{}))
+		assert.NoError(t, student.EnrollCourse(context.Background(), courses, 3, 1, noOpBatcher{}))
+		assert.NoError(t, student.EnrollCourse(context.Background(), courses, 4, 1, noOpBatcher{}))
+		assert.Equal(t, CorequisiteRequiredErr{CourseID: 3}, student.DisenrollCourse(context.Background(), courses, 2, noOpBatcher{}))
+		assert.Equal(t, CorequisiteRequiredErr{CourseID: 4}, student.DisenrollCourse(context.Background(), courses, 3, noOpBatcher{}))
+		// They can be dropped in reverse order
+		assert.NoError(t, student.DisenrollCourse(context.Background(), courses, 4, noOpBatcher{}))
+		assert.NoError(t, student.DisenrollCourse(context.Background(), courses, 3, noOpBatcher{}))
+		assert.NoError(t, student.DisenrollCourse(context.Background(), courses, 2, noOpBatcher{}))
+		// Passed co-requisites do not matter
+		courses = newCourses()
+		student = newStudent([]CourseID{1, 2}, nil)
+		assert.NoError(t, student.EnrollCourse(context.Background(), courses, 2, 1, noOpBatcher{}))
+		assert.NoError(t, student.EnrollCourse(context.Background(), courses, 3, 1, noOpBatcher{}))
+		assert.NoError(t, student.DisenrollCourse(context.Background(), courses, 2, noOpBatcher{}))
+	})
+}
+
+func TestPrerequisiteMissingErr(t *testing.T) {
+	assert.Equal(t, "missing prerequisites [1 2]", PrerequisiteMissingErr{Prerequisites: []CourseID{1, 2}}.Error())
+	assert.Equal(t, "missing co-requisites [3]", PrerequisiteMissingErr{Corequisites: []CourseID{3}}.Error())
+	assert.Equal(t, "missing prerequisites [1] and co-requisites [3]", PrerequisiteMissingErr{Prerequisites: []CourseID{1}, Corequisites: []CourseID{3}}.Error())
+}
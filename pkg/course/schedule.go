@@ -0,0 +1,247 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Action is a set of the actions which students can do with their courses
+type Action uint8
+
+const (
+	ActionEnroll Action = 1 << iota
+	ActionDisenroll
+	ActionChangeGroup
+)
+
+// Schedule is the list of enrollment phases. It must not be changed after it's created.
+type Schedule struct {
+	Phases []Phase
+}
+
+// Phase is a period of enrollment like main registration, add/drop or late registration
+type Phase struct {
+	// A unique name for this phase
+	Name string
+	// The actions which are allowed in this phase
+	Actions Action
+	// If not zero, each student can only act for this duration after their
+	// Student.EnrollmentStartTime in this phase
+	StudentDuration time.Duration
+	// Students can act when they are in any window which matches their cohort
+	Windows []Window
+	// In a lottery phase, students submit wishlists in the windows instead of acting directly,
+	// and the seats are allocated by RunLottery when all windows are closed. A lottery phase
+	// does not allow any action.
+	Lottery bool
+	// The criteria which order the students of a lottery phase. The ties are broken randomly.
+	Priorities []Priority
+	// The seed of the random tie-breaker of a lottery phase. The same seed gives the same result.
+	Seed int64
+}
+
+// Window is a time range which a cohort can act in a phase
+type Window struct {
+	// The department of the cohort. Zero matches every department.
+	Department DepartmentID
+	// The entry year of the cohort. Zero matches every entry year.
+	EntryYear int16
+	// In unix milliseconds. The start is inclusive and the end is exclusive.
+	Start, End int64
+}
+
+// Schedule returns the enrollment schedule. Nil or a schedule without phases means that there is
+// no schedule and the students can only act in the hour after their Student.EnrollmentStartTime.
+func (c *Courses) Schedule() *Schedule {
+	return c.schedule.Load()
+}
+
+// SetSchedule replaces the enrollment schedule. The schedule must not be changed afterward.
+func (c *Courses) SetSchedule(schedule *Schedule) {
+	c.schedule.Store(schedule)
+}
+
+// PutSchedule replaces the enrollment schedule after batching it
+func PutSchedule(ctx context.Context, courses *Courses, schedule *Schedule, batcher Batcher) error {
+	if batcher == nil {
+		panic("nil batcher")
+	}
+	// The schedule is not for a department, so its messages go in the queue of department zero
+	err := batcher.ProcessDatabaseQuery(ctx, 0, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_PutSchedule{PutSchedule: schedule.ToProto()},
+	})
+	if err != nil {
+		return BatchError{err}
+	}
+	courses.SetSchedule(schedule)
+	return nil
+}
+
+// checkSchedule checks if the student can do an action right now based on the schedule
+func (s *Student) checkSchedule(courses *Courses, action Action) error {
+	schedule := courses.Schedule()
+	if schedule == nil || len(schedule.Phases) == 0 {
+		if !s.IsEnrollTimeOK() {
+			return NotEnrollmentTimeErr
+		}
+		return nil
+	}
+	allowed, open := schedule.allowedActions(s, studentClock.Now().UnixMilli())
+	if !open {
+		return NotEnrollmentTimeErr
+	}
+	if allowed&action == 0 {
+		return ActionNotAllowedErr
+	}
+	return nil
+}
+
+// allowedActions returns the actions which a student can do at now (in unix milliseconds).
+// The second returned value is false if no phase is open for the student.
+func (s *Schedule) allowedActions(student *Student, now int64) (Action, bool) {
+	var result Action
+	open := false
+	for _, phase := range s.Phases {
+		if !phase.Lottery && phase.isOpenFor(student, now) {
+			result |= phase.Actions
+			open = true
+		}
+	}
+	return result, open
+}
+
+// openLotteryPhase returns the lottery phase which is open for a student at now (in unix
+// milliseconds). Nil is returned if there is no such phase.
+func (s *Schedule) openLotteryPhase(student *Student, now int64) *Phase {
+	for i := range s.Phases {
+		if s.Phases[i].Lottery && s.Phases[i].isOpenFor(student, now) {
+			return &s.Phases[i]
+		}
+	}
+	return nil
+}
+
+// phase returns the phase with the given name or nil if it does not exist
+func (s *Schedule) phase(name string) *Phase {
+	for i := range s.Phases {
+		if s.Phases[i].Name == name {
+			return &s.Phases[i]
+		}
+	}
+	return nil
+}
+
+// isOpenFor checks if the phase is open for a student at now (in unix milliseconds)
+func (p *Phase) isOpenFor(student *Student, now int64) bool {
+	if p.StudentDuration != 0 && (now <= student.EnrollmentStartTime || now >= student.EnrollmentStartTime+p.StudentDuration.Milliseconds()) {
+		return false
+	}
+	for _, window := range p.Windows {
+		if window.matches(student) && window.Start <= now && now < window.End {
+			return true
+		}
+	}
+	return false
+}
+
+// isClosed checks if all windows of the phase are ended at now (in unix milliseconds)
+func (p *Phase) isClosed(now int64) bool {
+	for _, window := range p.Windows {
+		if now < window.End {
+			return false
+		}
+	}
+	return true
+}
+
+// matches checks if a student is in the cohort of this window
+func (w Window) matches(student *Student) bool {
+	return (w.Department == 0 || w.Department == student.Department) && (w.EntryYear == 0 || w.EntryYear == student.EntryYear)
+}
+
+// NewScheduleFromProto creates a schedule from its protobuf message. The phases must have unique
+// names, the windows must not end before they start and the lottery phases must not allow any
+// action.
+func NewScheduleFromProto(data *proto.EnrollmentSchedule) (*Schedule, error) {
+	result := &Schedule{Phases: make([]Phase, len(data.GetPhases()))}
+	names := make(map[string]struct{}, len(data.GetPhases()))
+	for i, phaseData := range data.GetPhases() {
+		if _, duplicate := names[phaseData.Name]; duplicate {
+			return nil, fmt.Errorf("duplicate phase %q", phaseData.Name)
+		}
+		names[phaseData.Name] = struct{}{}
+		if phaseData.StudentDuration < 0 {
+			return nil, fmt.Errorf("negative student duration in phase %q", phaseData.Name)
+		}
+		phase := Phase{
+			Name:            phaseData.Name,
+			StudentDuration: time.Duration(phaseData.StudentDuration) * time.Millisecond,
+			Windows:         make([]Window, len(phaseData.Windows)),
+			Lottery:         phaseData.Lottery,
+			Seed:            phaseData.Seed,
+		}
+		for _, priority := range phaseData.Priorities {
+			if priority != proto.EnrollmentPhase_SENIORITY && priority != proto.EnrollmentPhase_GPA_BAND {
+				return nil, fmt.Errorf("invalid priority %d in phase %q", priority, phaseData.Name)
+			}
+			phase.Priorities = append(phase.Priorities, Priority(priority))
+		}
+		if phaseData.AllowEnroll {
+			phase.Actions |= ActionEnroll
+		}
+		if phaseData.AllowDisenroll {
+			phase.Actions |= ActionDisenroll
+		}
+		if phaseData.AllowChangeGroup {
+			phase.Actions |= ActionChangeGroup
+		}
+		if phase.Lottery && phase.Actions != 0 {
+			return nil, fmt.Errorf("lottery phase %q cannot allow actions", phaseData.Name)
+		}
+		for j, windowData := range phaseData.Windows {
+			if windowData.End < windowData.Start {
+				return nil, fmt.Errorf("window %d of phase %q ends before it starts", j, phaseData.Name)
+			}
+			phase.Windows[j] = Window{
+				Department: DepartmentID(windowData.DepartmentId),
+				EntryYear:  int16(windowData.EntryYear),
+				Start:      windowData.Start,
+				End:        windowData.End,
+			}
+		}
+		result.Phases[i] = phase
+	}
+	return result, nil
+}
+
+// ToProto converts the schedule to its protobuf message
+func (s *Schedule) ToProto() *proto.EnrollmentSchedule {
+	result := &proto.EnrollmentSchedule{Phases: make([]*proto.EnrollmentPhase, len(s.Phases))}
+	for i, phase := range s.Phases {
+		phaseData := &proto.EnrollmentPhase{
+			Name:             phase.Name,
+			AllowEnroll:      phase.Actions&ActionEnroll != 0,
+			AllowDisenroll:   phase.Actions&ActionDisenroll != 0,
+			AllowChangeGroup: phase.Actions&ActionChangeGroup != 0,
+			StudentDuration:  phase.StudentDuration.Milliseconds(),
+			Windows:          make([]*proto.EnrollmentWindow, len(phase.Windows)),
+			Lottery:          phase.Lottery,
+			Seed:             phase.Seed,
+		}
+		for _, priority := range phase.Priorities {
+			phaseData.Priorities = append(phaseData.Priorities, proto.EnrollmentPhase_Priority(priority))
+		}
+		for j, window := range phase.Windows {
+			phaseData.Windows[j] = &proto.EnrollmentWindow{
+				DepartmentId: uint32(window.Department),
+				EntryYear:    int32(window.EntryYear),
+				Start:        window.Start,
+				End:          window.End,
+			}
+		}
+		result.Phases[i] = phaseData
+	}
+	return result
+}
@@ -0,0 +1,214 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"errors"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStudentCheckSchedule(t *testing.T) {
+	clk := clock.NewMock()
+	studentClock = clk
+	day := func(hour int) int64 {
+		return time.Date(2022, 9, 12, hour, 0, 0, 0, time.UTC).UnixMilli()
+	}
+	// Main registration for the freshmen of department 1 from 8 to 10, then for everyone
+	// from 10 to 12. Drop only from 12 to 14. Late registration for the first hour of each
+	// student from 14 to 18.
+	schedule := &Schedule{Phases: []Phase{
+		{
+			Name:    "main",
+			Actions: ActionEnroll | ActionDisenroll | ActionChangeGroup,
+			Windows: []Window{
+				{Department: 1, EntryYear: 1401, Start: day(8), End: day(10)},
+				{Start: day(10), End: day(12)},
+			},
+		},
+		{
+			Name:    "drop",
+			Actions: ActionDisenroll,
+			Windows: []Window{{Start: day(12), End: day(14)}},
+		},
+		{
+			Name:            "late",
+			Actions:         ActionEnroll,
+			StudentDuration: time.Hour,
+			Windows:         []Window{{Start: day(14), End: day(18)}},
+		},
+	}}
+	freshman := &Student{Department: 1, EntryYear: 1401, EnrollmentStartTime: day(15)}
+	senior := &Student{Department: 1, EntryYear: 1398, EnrollmentStartTime: day(16)}
+	tests := []struct {
+		Name     string
+		Student  *Student
+		Now      int64
+		Action   Action
+		Expected error
+	}{
+		{
+			Name:     "before every phase",
+			Student:  freshman,
+			Now:      day(7),
+			Action:   ActionEnroll,
+			Expected: NotEnrollmentTimeErr,
+		},
+		{
+			Name:    "cohort window",
+			Student: freshman,
+			Now:     day(8),
+			Action:  ActionEnroll,
+		},
+		{
+			Name:     "other cohort window",
+			Student:  senior,
+			Now:      day(8),
+			Action:   ActionEnroll,
+			Expected: NotEnrollmentTimeErr,
+		},
+		{
+			Name:    "window of everyone",
+			Student: senior,
+			Now:     day(10),
+			Action:  ActionChangeGroup,
+		},
+		{
+			Name:    "drop phase",
+			Student: senior,
+			Now:     day(12),
+			Action:  ActionDisenroll,
+		},
+		{
+			Name:     "enroll in drop phase",
+			Student:  senior,
+			Now:      day(12),
+			Action:   ActionEnroll,
+			Expected: ActionNotAllowedErr,
+		},
+		{
+			Name:     "late phase before student time",
+			Student:  freshman,
+			Now:      day(14),
+			Action:   ActionEnroll,
+			Expected: NotEnrollmentTimeErr,
+		},
+		{
+			Name:    "late phase in student time",
+			Student: freshman,
+			Now:     day(15) + 1,
+			Action:  ActionEnroll,
+		},
+		{
+			Name:     "late phase after student time",
+			Student:  freshman,
+			Now:      day(16),
+			Action:   ActionEnroll,
+			Expected: NotEnrollmentTimeErr,
+		},
+		{
+			Name:     "after every phase",
+			Student:  senior,
+			Now:      day(18),
+			Action:   ActionDisenroll,
+			Expected: NotEnrollmentTimeErr,
+		},
+	}
+	courses := NewCourses(nil)
+	courses.SetSchedule(schedule)
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			clk.Set(time.UnixMilli(test.Now))
+			assert.Equal(t, test.Expected, test.Student.checkSchedule(courses, test.Action))
+		})
+	}
+	t.Run("default schedule", func(t *testing.T) {
+		courses := NewCourses(nil)
+		clk.Set(time.UnixMilli(day(15) + 1))
+		assert.NoError(t, freshman.checkSchedule(courses, ActionEnroll))
+		courses.SetSchedule(new(Schedule))
+		assert.NoError(t, freshman.checkSchedule(courses, ActionEnroll))
+		clk.Set(time.UnixMilli(day(16)))
+		assert.ErrorIs(t, freshman.checkSchedule(courses, ActionEnroll), NotEnrollmentTimeErr)
+	})
+}
+
+func TestNewScheduleFromProto(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Data    *proto.EnrollmentSchedule
+		IsValid bool
+	}{
+		{
+			Name:    "empty",
+			Data:    new(proto.EnrollmentSchedule),
+			IsValid: true,
+		},
+		{
+			Name: "valid",
+			Data: &proto.EnrollmentSchedule{Phases: []*proto.EnrollmentPhase{
+				{Name: "main", AllowEnroll: true, AllowChangeGroup: true, StudentDuration: 3600000, Windows: []*proto.EnrollmentWindow{{DepartmentId: 1, EntryYear: 1401, Start: 1000, End: 2000}}},
+				{Name: "drop", AllowDisenroll: true, Windows: []*proto.EnrollmentWindow{{Start: 2000, End: 3000}}},
+				{Name: "lottery", Lottery: true, Priorities: []proto.EnrollmentPhase_Priority{proto.EnrollmentPhase_GPA_BAND, proto.EnrollmentPhase_SENIORITY}, Seed: 42},
+			}},
+			IsValid: true,
+		},
+		{
+			Name: "duplicate name",
+			Data: &proto.EnrollmentSchedule{Phases: []*proto.EnrollmentPhase{{Name: "main"}, {Name: "main"}}},
+		},
+		{
+			Name: "negative student duration",
+			Data: &proto.EnrollmentSchedule{Phases: []*proto.EnrollmentPhase{{Name: "main", StudentDuration: -1}}},
+		},
+		{
+			Name: "window ends before start",
+			Data: &proto.EnrollmentSchedule{Phases: []*proto.EnrollmentPhase{{Name: "main", Windows: []*proto.EnrollmentWindow{{Start: 2000, End: 1000}}}}},
+		},
+		{
+			Name: "lottery with actions",
+			Data: &proto.EnrollmentSchedule{Phases: []*proto.EnrollmentPhase{{Name: "main", Lottery: true, AllowEnroll: true}}},
+		},
+		{
+			Name: "invalid priority",
+			Data: &proto.EnrollmentSchedule{Phases: []*proto.EnrollmentPhase{{Name: "main", Lottery: true, Priorities: []proto.EnrollmentPhase_Priority{5}}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			schedule, err := NewScheduleFromProto(test.Data)
+			if !test.IsValid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// The conversion must be lossless
+			assert.Equal(t, len(test.Data.Phases), len(schedule.ToProto().Phases))
+			for i, phase := range schedule.ToProto().Phases {
+				assert.Equal(t, test.Data.Phases[i].String(), phase.String())
+			}
+		})
+	}
+}
+
+func TestPutSchedule(t *testing.T) {
+	schedule := &Schedule{Phases: []Phase{{Name: "main", Actions: ActionEnroll, Windows: []Window{{Start: 1000, End: 2000}}}}}
+	// Batch errors do not change anything
+	courses := NewCourses(nil)
+	assert.ErrorAs(t, PutSchedule(context.Background(), courses, schedule, errorBatcher{errors.New("broker is down")}), new(BatchError))
+	assert.Nil(t, courses.Schedule())
+	// Put it
+	batcher := new(inMemoryBatcher)
+	require.NoError(t, PutSchedule(context.Background(), courses, schedule, batcher))
+	assert.Same(t, schedule, courses.Schedule())
+	if assert.Len(t, batcher.messages, 1) {
+		assert.Equal(t, DepartmentID(0), batcher.messages[0].dep)
+		// Replaying the message gives the same schedule
+		replayed := NewCourses(nil)
+		require.NoError(t, ReplayMessage(replayed, nil, batcher.messages[0].data))
+		assert.Equal(t, schedule, replayed.Schedule())
+	}
+}
@@ -0,0 +1,215 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"fmt"
+	"time"
+)
+
+// NewSnapshotProto creates a snapshot of all courses and students.
+//
+// This method does not stop the courses and students from being changed. The caller must
+// make sure that nothing changes them while the snapshot is being taken.
+func NewSnapshotProto(courses *Courses, students map[StudentID]*Student) *proto.EnrollmentSnapshot {
+	result := &proto.EnrollmentSnapshot{
+		Courses:  make([]*proto.CourseSnapshot, 0, len(courses.courses)),
+		Students: make([]*proto.StudentSnapshot, 0, len(students)),
+	}
+	courses.mu.RLock()
+	for _, groups := range courses.courses {
+		for _, course := range groups {
+			result.Courses = append(result.Courses, course.toSnapshotProto())
+		}
+	}
+	for courseID, requisites := range courses.requisites {
+		data := &proto.CourseRequisites{
+			CourseId:      int32(courseID),
+			Prerequisites: make([]int32, len(requisites.Prerequisites)),
+			Corequisites:  make([]int32, len(requisites.Corequisites)),
+		}
+		for i, id := range requisites.Prerequisites {
+			data.Prerequisites[i] = int32(id)
+		}
+		for i, id := range requisites.Corequisites {
+			data.Corequisites[i] = int32(id)
+		}
+		result.Requisites = append(result.Requisites, data)
+	}
+	courses.mu.RUnlock()
+	if schedule := courses.Schedule(); schedule != nil {
+		result.Schedule = schedule.ToProto()
+	}
+	for _, student := range students {
+		result.Students = append(result.Students, student.toSnapshotProto())
+	}
+	return result
+}
+
+// NewStateFromSnapshotProto creates the courses and students from a snapshot
+func NewStateFromSnapshotProto(snapshot *proto.EnrollmentSnapshot) (*Courses, map[StudentID]*Student, error) {
+	courses := make(map[CourseID][]*Course)
+	for _, data := range snapshot.Courses {
+		course := &Course{
+			ID:                 CourseID(data.CourseId),
+			GroupID:            GroupID(data.GroupId),
+			Department:         DepartmentID(data.DepartmentId),
+			Lecturer:           data.Lecturer,
+			Units:              uint8(data.Units),
+			Capacity:           int(data.Capacity),
+			RegisteredStudents: make(map[StudentID]struct{}, data.Capacity),
+			ReserveCapacity:    int(data.ReserveCapacity),
+			ReserveQueue:       util.NewQueue[StudentID](),
+			SexLock:            SexLock(data.SexLock),
+			Eligibility:        NewEligibilityFromProto(data.Eligibility),
+			ConfirmationWindow: time.Duration(data.ConfirmationWindow) * time.Second,
+		}
+		course.ExamTime.Store(data.ExamTime)
+		course.ClassHeldTime.data.Store(data.ClassTime)
+		for _, id := range data.RegisteredStudents {
+			course.RegisteredStudents[StudentID(id)] = struct{}{}
+		}
+		for _, id := range data.ReserveQueue {
+			course.ReserveQueue.Enqueue(StudentID(id))
+		}
+		for _, id := range data.Intents {
+			course.Intents = append(course.Intents, StudentID(id))
+		}
+		for id, deadline := range data.ConfirmationDeadlines {
+			if _, registered := course.RegisteredStudents[StudentID(id)]; !registered {
+				return nil, nil, fmt.Errorf("student %d must confirm course %d-%d but is not registered in it", id, course.ID, course.GroupID)
+			}
+			course.threadUnsafeSetConfirmationDeadline(StudentID(id), deadline)
+		}
+		courses[course.ID] = append(courses[course.ID], course)
+	}
+	result := NewCourses(courses)
+	if snapshot.Schedule != nil {
+		schedule, err := NewScheduleFromProto(snapshot.Schedule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid schedule: %w", err)
+		}
+		result.SetSchedule(schedule)
+	}
+	if len(snapshot.Requisites) != 0 {
+		requisites := make(map[CourseID]Requisites, len(snapshot.Requisites))
+		for _, data := range snapshot.Requisites {
+			var courseRequisites Requisites
+			for _, id := range data.Prerequisites {
+				courseRequisites.Prerequisites = append(courseRequisites.Prerequisites, CourseID(id))
+			}
+			for _, id := range data.Corequisites {
+				courseRequisites.Corequisites = append(courseRequisites.Corequisites, CourseID(id))
+			}
+			requisites[CourseID(data.CourseId)] = courseRequisites
+		}
+		result.SetRequisites(requisites)
+	}
+	students := make(map[StudentID]*Student, len(snapshot.Students))
+	for _, data := range snapshot.Students {
+		student := &Student{
+			ID:                  StudentID(data.StudentId),
+			EnrollmentStartTime: data.EnrollmentStartTime,
+			RemainingActions:    uint8(data.RemainingActions),
+			MaxUnits:            uint8(data.MaxUnits),
+			RegisteredUnits:     uint8(data.RegisteredUnits),
+			StudentSex:          Sex(data.Sex),
+			Department:          DepartmentID(data.DepartmentId),
+			EntryYear:           int16(data.EntryYear),
+			GPABand:             uint8(data.GpaBand),
+			RegisteredCourses:   make(map[CourseID]GroupID, len(data.RegisteredCourses)),
+			PassedCourses:       make(map[CourseID]struct{}, len(data.PassedCourses)),
+			Wishlist:            NewWishlistFromProto(data.Wishlist),
+		}
+		for _, courseID := range data.PassedCourses {
+			student.PassedCourses[CourseID(courseID)] = struct{}{}
+		}
+		for courseID, groupID := range data.RegisteredCourses {
+			if result.GetCourse(CourseID(courseID), GroupID(groupID)) == nil {
+				return nil, nil, fmt.Errorf("student %d is registered in course %d-%d which does not exist", student.ID, courseID, groupID)
+			}
+			student.RegisteredCourses[CourseID(courseID)] = GroupID(groupID)
+		}
+		students[student.ID] = student
+	}
+	// The intents of the students are in their destination groups
+	for _, groups := range courses {
+		for _, course := range groups {
+			for _, id := range course.Intents {
+				student, exists := students[id]
+				if !exists {
+					return nil, nil, fmt.Errorf("student %d has an intent in course %d-%d but does not exist", id, course.ID, course.GroupID)
+				}
+				if student.Intents == nil {
+					student.Intents = make(map[CourseID]GroupID)
+				}
+				student.Intents[course.ID] = course.GroupID
+			}
+		}
+	}
+	return result, students, nil
+}
+
+// toSnapshotProto converts this course to its snapshot
+func (c *Course) toSnapshotProto() *proto.CourseSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := &proto.CourseSnapshot{
+		CourseId:           int32(c.ID),
+		GroupId:            uint32(c.GroupID),
+		DepartmentId:       uint32(c.Department),
+		Lecturer:           c.Lecturer,
+		Units:              uint32(c.Units),
+		Capacity:           int32(c.Capacity),
+		ReserveCapacity:    int32(c.ReserveCapacity),
+		ExamTime:           c.ExamTime.Load(),
+		ClassTime:          c.ClassHeldTime.data.Load(),
+		SexLock:            uint32(c.SexLock),
+		Eligibility:        c.Eligibility.ToProto(),
+		RegisteredStudents: make([]uint64, 0, len(c.RegisteredStudents)),
+		ReserveQueue:       make([]uint64, 0, c.ReserveQueue.Len()),
+		ConfirmationWindow: int64(c.ConfirmationWindow / time.Second),
+	}
+	for id := range c.RegisteredStudents {
+		result.RegisteredStudents = append(result.RegisteredStudents, uint64(id))
+	}
+	for _, id := range c.ReserveQueue.CopyAsArray() {
+		result.ReserveQueue = append(result.ReserveQueue, uint64(id))
+	}
+	for _, id := range c.Intents {
+		result.Intents = append(result.Intents, uint64(id))
+	}
+	if len(c.ConfirmationDeadlines) != 0 {
+		result.ConfirmationDeadlines = make(map[uint64]int64, len(c.ConfirmationDeadlines))
+		for id, deadline := range c.ConfirmationDeadlines {
+			result.ConfirmationDeadlines[uint64(id)] = deadline
+		}
+	}
+	return result
+}
+
+// toSnapshotProto converts this student to its snapshot
+func (s *Student) toSnapshotProto() *proto.StudentSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := &proto.StudentSnapshot{
+		StudentId:           uint64(s.ID),
+		EnrollmentStartTime: s.EnrollmentStartTime,
+		RemainingActions:    uint32(s.RemainingActions),
+		MaxUnits:            uint32(s.MaxUnits),
+		RegisteredUnits:     uint32(s.RegisteredUnits),
+		Sex:                 uint32(s.StudentSex),
+		RegisteredCourses:   make(map[int32]uint32, len(s.RegisteredCourses)),
+		DepartmentId:        uint32(s.Department),
+		EntryYear:           int32(s.EntryYear),
+		GpaBand:             uint32(s.GPABand),
+		Wishlist:            s.Wishlist.ToProto(),
+	}
+	for courseID, groupID := range s.RegisteredCourses {
+		result.RegisteredCourses[int32(courseID)] = uint32(groupID)
+	}
+	for courseID := range s.PassedCourses {
+		result.PassedCourses = append(result.PassedCourses, int32(courseID))
+	}
+	return result
+}
@@ -0,0 +1,215 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"CourseEnrollment/pkg/util"
+	"context"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	protobuf "google.golang.org/protobuf/proto"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+// sortSnapshot sorts the lists of a snapshot which are filled from maps
+func sortSnapshot(snapshot *proto.EnrollmentSnapshot) {
+	slices.SortFunc(snapshot.Courses, func(a, b *proto.CourseSnapshot) int {
+		if a.CourseId != b.CourseId {
+			return int(a.CourseId - b.CourseId)
+		}
+		return int(a.GroupId) - int(b.GroupId)
+	})
+	for _, c := range snapshot.Courses {
+		slices.Sort(c.RegisteredStudents)
+	}
+	slices.SortFunc(snapshot.Students, func(a, b *proto.StudentSnapshot) int {
+		return int(a.StudentId) - int(b.StudentId)
+	})
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	course := &Course{
+		ID:                    10,
+		GroupID:               2,
+		Department:            3,
+		Lecturer:              "Lecturer",
+		Units:                 3,
+		Capacity:              1,
+		RegisteredStudents:    map[StudentID]struct{}{1: {}},
+		ReserveCapacity:       2,
+		ReserveQueue:          util.NewQueue[StudentID](),
+		ClassHeldTime:         NewClassTime([]time.Weekday{time.Monday}, NewTimeOnly(600), NewTimeOnly(690)),
+		SexLock:               SexLockFemaleOnly,
+		Eligibility:           &Eligibility{MinEntryYear: 1400, DeniedStudents: map[StudentID]struct{}{4: {}}},
+		ConfirmationWindow:    time.Hour,
+		ConfirmationDeadlines: map[StudentID]int64{1: 5000},
+	}
+	course.ReserveQueue.Enqueue(3)
+	course.ReserveQueue.Enqueue(2)
+	course.ExamTime.Store(1000)
+	courses := NewCourses(map[CourseID][]*Course{10: {course}})
+	schedule := &Schedule{Phases: []Phase{{Name: "main", Actions: ActionEnroll | ActionDisenroll, StudentDuration: time.Hour, Windows: []Window{{Department: 3, Start: 1000, End: 2000}}}}}
+	courses.SetSchedule(schedule)
+	requisites := map[CourseID]Requisites{10: {Prerequisites: []CourseID{7}, Corequisites: []CourseID{8, 9}}}
+	courses.SetRequisites(requisites)
+	students := map[StudentID]*Student{
+		1: {ID: 1, EnrollmentStartTime: 5, RemainingActions: 2, MaxUnits: 20, RegisteredUnits: 3, StudentSex: SexFemale, Department: 3, EntryYear: 1401, GPABand: 2, RegisteredCourses: map[CourseID]GroupID{10: 2}, PassedCourses: map[CourseID]struct{}{7: {}},
+			Wishlist: &Wishlist{Phase: "main", Wishes: []Wish{{CourseID: 10, GroupID: 2}}}},
+		2: {ID: 2, RegisteredCourses: map[CourseID]GroupID{10: 2}},
+		3: {ID: 3, RegisteredCourses: map[CourseID]GroupID{10: 2}},
+	}
+	snapshot := NewSnapshotProto(courses, students)
+	loadedCourses, loadedStudents, err := NewStateFromSnapshotProto(snapshot)
+	assert.NoError(t, err)
+	loaded := loadedCourses.GetCourse(10, 2)
+	if assert.NotNil(t, loaded) {
+		assert.Equal(t, []StudentID{3, 2}, loaded.ReserveQueue.CopyAsArray())
+		assert.Equal(t, course.RegisteredStudents, loaded.RegisteredStudents)
+		assert.Equal(t, int64(1000), loaded.ExamTime.Load())
+		assert.Equal(t, course.ClassHeldTime.data.Load(), loaded.ClassHeldTime.data.Load())
+		assert.Equal(t, SexLockFemaleOnly, loaded.SexLock)
+		assert.Equal(t, "Lecturer", loaded.Lecturer)
+		assert.Equal(t, course.Eligibility, loaded.Eligibility)
+		assert.Equal(t, time.Hour, loaded.ConfirmationWindow)
+		assert.Equal(t, course.ConfirmationDeadlines, loaded.ConfirmationDeadlines)
+	}
+	assert.Equal(t, students[1], loadedStudents[1])
+	assert.Equal(t, schedule, loadedCourses.Schedule())
+	assert.Equal(t, requisites[10], loadedCourses.Requisites(10))
+	// Unknown courses are rejected
+	snapshot.Students[0].RegisteredCourses[11] = 1
+	_, _, err = NewStateFromSnapshotProto(snapshot)
+	assert.Error(t, err)
+}
+
+func TestSnapshotReplay(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Date(2022, 9, 12, 9, 0, 0, 0, time.UTC))
+	studentClock = clk
+	const numberOfStudents = 30
+	const numberOfCourses = 4
+	const numberOfGroups = 3
+	// Create the state
+	courses := make(map[CourseID][]*Course)
+	for i := 0; i < numberOfCourses; i++ {
+		for j := 0; j < numberOfGroups; j++ {
+			courses[CourseID(i)] = append(courses[CourseID(i)], &Course{
+				ID:                 CourseID(i),
+				GroupID:            GroupID(j),
+				Department:         DepartmentID(i % 2),
+				Units:              3,
+				Capacity:           3,
+				RegisteredStudents: make(map[StudentID]struct{}),
+				ReserveCapacity:    3,
+				ReserveQueue:       util.NewQueue[StudentID](),
+				// Some groups need confirmation of the promotions
+				ConfirmationWindow: time.Duration(j) * time.Minute,
+			})
+		}
+	}
+	students := make(map[StudentID]*Student)
+	for i := 0; i < numberOfStudents; i++ {
+		students[StudentID(i)] = &Student{
+			ID:                  StudentID(i),
+			EnrollmentStartTime: clk.Now().Add(-time.Minute).UnixMilli(),
+			RemainingActions:    4,
+			MaxUnits:            20,
+			RegisteredCourses:   make(map[CourseID]GroupID),
+		}
+	}
+	state := NewCourses(courses)
+	before := NewSnapshotProto(state, students)
+	// Do random operations and record the messages
+	batcher := new(inMemoryBatcher)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		student := students[StudentID(rng.Intn(numberOfStudents))]
+		courseID, groupID := CourseID(rng.Intn(numberOfCourses)), GroupID(rng.Intn(numberOfGroups))
+		switch rng.Intn(8) {
+		case 0, 1:
+			_ = student.EnrollCourse(context.Background(), state, courseID, groupID, batcher)
+		case 2:
+			_ = student.DisenrollCourse(context.Background(), state, courseID, batcher)
+		case 3:
+			_ = student.ChangeGroup(context.Background(), state, courseID, groupID, batcher)
+		case 4:
+			if rng.Intn(2) == 0 {
+				_ = student.ForceEnrollCourse(context.Background(), state, courseID, groupID, batcher)
+			} else {
+				_ = student.ForceDisenrollCourse(context.Background(), state, courseID, batcher)
+			}
+		case 5:
+			_ = state.GetCourse(courseID, groupID).UpdateCapacity(context.Background(), rng.Intn(6), batcher)
+		case 6:
+			for id := range CourseID(numberOfCourses) {
+				_ = student.ConfirmPromotion(context.Background(), state, id, batcher)
+			}
+		case 7:
+			clk.Add(10 * time.Second)
+			_, _ = ExpirePromotions(context.Background(), state, students, batcher)
+		}
+	}
+	assert.Greater(t, len(batcher.messages), 100)
+	after := NewSnapshotProto(state, students)
+	// Replay the messages on the first snapshot
+	replayedCourses, replayedStudents, err := NewStateFromSnapshotProto(before)
+	assert.NoError(t, err)
+	for _, message := range batcher.messages {
+		assert.NoError(t, ReplayMessage(replayedCourses, replayedStudents, message.data))
+	}
+	replayed := NewSnapshotProto(replayedCourses, replayedStudents)
+	sortSnapshot(after)
+	sortSnapshot(replayed)
+	assert.True(t, protobuf.Equal(after, replayed), "replayed state is different")
+}
+
+func TestReplayMessageErrors(t *testing.T) {
+	courses := NewCourses(map[CourseID][]*Course{1: {{
+		ID:                 1,
+		GroupID:            1,
+		Capacity:           1,
+		RegisteredStudents: make(map[StudentID]struct{}),
+		ReserveQueue:       util.NewQueue[StudentID](),
+	}}})
+	students := map[StudentID]*Student{1: {ID: 1, RegisteredCourses: make(map[CourseID]GroupID)}}
+	tests := []struct {
+		Name    string
+		Message *proto.CourseDatabaseBatchMessage
+	}{
+		{
+			Name: "unknown student",
+			Message: &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Enroll{
+				Enroll: &proto.CourseDatabaseBatchEnrollMessage{StudentId: 2, CourseId: 1, GroupId: 1},
+			}},
+		},
+		{
+			Name: "unknown course",
+			Message: &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Enroll{
+				Enroll: &proto.CourseDatabaseBatchEnrollMessage{StudentId: 1, CourseId: 2, GroupId: 1},
+			}},
+		},
+		{
+			Name: "not enrolled",
+			Message: &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_Disenroll{
+				Disenroll: &proto.CourseDatabaseBatchDisenrollMessage{StudentId: 1, CourseId: 1},
+			}},
+		},
+		{
+			Name: "not in reserve queue",
+			Message: &proto.CourseDatabaseBatchMessage{Action: &proto.CourseDatabaseBatchMessage_UpdateCapacity{
+				UpdateCapacity: &proto.CourseDatabaseBatchUpdateCapacity{CourseId: 1, GroupId: 1, NewCapacity: 2, MovedStudents: []uint64{1}},
+			}},
+		},
+		{
+			Name:    "no action",
+			Message: &proto.CourseDatabaseBatchMessage{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Error(t, ReplayMessage(courses, students, test.Message))
+		})
+	}
+}
@@ -29,9 +29,24 @@ type Student struct {
 	// How many units user has registered in
 	RegisteredUnits uint8
 	StudentSex      Sex
+	// The department and the entry year of the student. They decide the enrollment windows of
+	// the student in the schedule.
+	Department DepartmentID
+	EntryYear  int16
+	// The GPA band of the student. Higher bands get seats earlier in the lottery phases which are
+	// ordered by PriorityGPABand.
+	GPABand uint8
 	// List of courses which the student has enrolled in. The key is the course ID and the value is
 	// the group ID
 	RegisteredCourses map[CourseID]GroupID
+	// The courses which the student has passed before. This does not change while the server runs.
+	PassedCourses map[CourseID]struct{}
+	// The groups which the student wants to move to when they have a free seat. The key is the
+	// course ID and the value is the destination group ID. See AddIntent.
+	Intents map[CourseID]GroupID
+	// The wishlist which the student has submitted in a lottery phase. Nil if there is none.
+	// See PutWishlist.
+	Wishlist *Wishlist
 	// A simple locker for this user
 	mu sync.RWMutex
 }
@@ -39,18 +54,23 @@ type Student struct {
 // EnrollCourse tries to enroll the student in a course.
 // It does all the checks and then enrolls the student if possible.
 func (s *Student) EnrollCourse(ctx context.Context, courses *Courses, courseID CourseID, groupID GroupID, batcher Batcher) error {
-	// We check the start time at very first
-	if !s.IsEnrollTimeOK() {
-		return NotEnrollmentTimeErr
+	// We check the schedule at very first
+	if err := s.checkSchedule(courses, ActionEnroll); err != nil {
+		return err
 	}
+	return s.enrollCourse(ctx, courses, courseID, groupID, batcher)
+}
+
+// enrollCourse does everything which EnrollCourse does except checking the schedule
+func (s *Student) enrollCourse(ctx context.Context, courses *Courses, courseID CourseID, groupID GroupID, batcher Batcher) error {
 	// We get the course which is basically lock-free. (we are all reading from this map)
 	course := courses.GetCourse(courseID, groupID)
 	if course == nil {
 		return NotExistsErr
 	}
-	// We check the sex without lock because it doesn't change!
-	if !sexLockCompatible(course.SexLock, s.StudentSex) {
-		return SexLockErr
+	// We check the sex lock and other rules without lock because they don't change!
+	if err := course.checkEligibility(s); err != nil {
+		return err
 	}
 	// Then we lock the user to do stuff with him/her
 	s.mu.Lock()
@@ -63,27 +83,13 @@ func (s *Student) EnrollCourse(ctx context.Context, courses *Courses, courseID C
 	if _, alreadyRegistered := s.RegisteredCourses[courseID]; alreadyRegistered {
 		return AlreadyRegisteredErr
 	}
+	// Check the prerequisites and co-requisites
+	if err := s.checkRequisites(courses, courseID, s.RegisteredCourses); err != nil {
+		return err
+	}
 	// Check the time of the course with registered courses
-	for registeredCourseID, registeredGroupID := range s.RegisteredCourses {
-		// Get the course
-		registeredCourse := courses.GetCourse(registeredCourseID, registeredGroupID)
-		if registeredCourse == nil {
-			panic(fmt.Sprintf("inconsistent user state: course %d group %d is registered but not found", registeredCourseID, registeredGroupID))
-		}
-		// Check exam time
-		if examTimesIntersect(registeredCourse.ExamTime.Load(), course.ExamTime.Load()) {
-			return ExamConflictErr{
-				CourseID: registeredCourse.ID,
-				GroupID:  registeredCourse.GroupID,
-			}
-		}
-		// Check time
-		if registeredCourse.ClassHeldTime.Intersects(&course.ClassHeldTime) {
-			return ClassTimeConflictErr{
-				CourseID: registeredCourse.ID,
-				GroupID:  registeredCourse.GroupID,
-			}
-		}
+	if err := checkConflicts(courses, course, s.RegisteredCourses); err != nil {
+		return err
 	}
 	// At last, we register the course
 	registered, err := course.EnrollStudent(ctx, s.ID, batcher)
@@ -101,9 +107,9 @@ func (s *Student) EnrollCourse(ctx context.Context, courses *Courses, courseID C
 
 // DisenrollCourse will remove student from a course
 func (s *Student) DisenrollCourse(ctx context.Context, courses *Courses, courseID CourseID, batcher Batcher) error {
-	// We check the start time at very first
-	if !s.IsEnrollTimeOK() {
-		return NotEnrollmentTimeErr
+	// We check the schedule at very first
+	if err := s.checkSchedule(courses, ActionDisenroll); err != nil {
+		return err
 	}
 	// Lock the user to do stuff with them
 	s.mu.Lock()
@@ -121,27 +127,39 @@ func (s *Student) DisenrollCourse(ctx context.Context, courses *Courses, courseI
 	if course == nil {
 		panic(fmt.Sprintf("invalid registered lesson %d-%d for user %d", courseID, groupID, s.ID))
 	}
+	// Other courses might need this one
+	if err := s.checkCorequisiteOf(courses, courseID, s.RegisteredCourses); err != nil {
+		return err
+	}
 	// Disenroll
-	err := course.DisenrollStudent(ctx, s.ID, batcher)
+	err := course.DisenrollStudent(ctx, s.ID, actionBatcher{batcher})
 	if err != nil {
 		return err
 	}
 	// Remove from map
 	delete(s.RegisteredCourses, courseID)
 	s.RegisteredUnits -= course.Units
+	s.threadUnsafeClearIntent(courses, courseID)
 	s.RemainingActions--
 	return nil
 }
 
 // ChangeGroup will atomically change group of a user in a course
 func (s *Student) ChangeGroup(ctx context.Context, courses *Courses, courseID CourseID, destinationGroupID GroupID, batcher Batcher) error {
-	// We check the start time at very first
-	if !s.IsEnrollTimeOK() {
-		return NotEnrollmentTimeErr
+	// We check the schedule at very first
+	if err := s.checkSchedule(courses, ActionChangeGroup); err != nil {
+		return err
 	}
 	// Lock the user to do stuff with them
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.threadUnsafeChangeGroup(ctx, courses, courseID, destinationGroupID, true, batcher)
+}
+
+// threadUnsafeChangeGroup is ChangeGroup without checking the schedule and locking the student.
+// The student is only moved to the reserve queue of the destination group if toReserve is true.
+// The intent of the student in the course is removed if the group is changed.
+func (s *Student) threadUnsafeChangeGroup(ctx context.Context, courses *Courses, courseID CourseID, destinationGroupID GroupID, toReserve bool, batcher Batcher) error {
 	// Check the actions
 	if s.RemainingActions == 0 {
 		return NoRemainingActionsErr
@@ -164,34 +182,16 @@ func (s *Student) ChangeGroup(ctx context.Context, courses *Courses, courseID Co
 	if destinationCourse == nil {
 		return NotExistsErr
 	}
+	// Groups of a course might be for different students
+	if err := destinationCourse.checkEligibility(s); err != nil {
+		return err
+	}
 	// Check the time of the course with registered courses (except the source)
-	for registeredCourseID, registeredGroupID := range s.RegisteredCourses {
-		// We are going to remove this, so we don't check it
-		if registeredCourseID == courseID {
-			continue
-		}
-		// Get the course
-		registeredCourse := courses.GetCourse(registeredCourseID, registeredGroupID)
-		if registeredCourse == nil {
-			panic(fmt.Sprintf("inconsistent user state: course %d group %d is registered but not found", registeredCourseID, registeredGroupID))
-		}
-		// Check exam time
-		if examTimesIntersect(registeredCourse.ExamTime.Load(), destinationCourse.ExamTime.Load()) {
-			return ExamConflictErr{
-				CourseID: registeredCourse.ID,
-				GroupID:  registeredCourse.GroupID,
-			}
-		}
-		// Check time
-		if registeredCourse.ClassHeldTime.Intersects(&destinationCourse.ClassHeldTime) {
-			return ClassTimeConflictErr{
-				CourseID: registeredCourse.ID,
-				GroupID:  registeredCourse.GroupID,
-			}
-		}
+	if err := checkConflicts(courses, destinationCourse, s.RegisteredCourses); err != nil {
+		return err
 	}
 	// Change the group
-	changed, err := sourceCourse.ChangeGroupOfStudent(ctx, s.ID, destinationCourse, batcher)
+	changed, err := sourceCourse.changeGroupOfStudent(ctx, s.ID, destinationCourse, toReserve, actionBatcher{batcher})
 	if err != nil {
 		return err
 	}
@@ -201,6 +201,7 @@ func (s *Student) ChangeGroup(ctx context.Context, courses *Courses, courseID Co
 	// Done!
 	s.RemainingActions--
 	s.RegisteredCourses[courseID] = destinationGroupID
+	s.threadUnsafeClearIntent(courses, courseID)
 	return nil
 }
 
@@ -252,9 +253,13 @@ func (s *Student) ForceDisenrollCourse(ctx context.Context, courses *Courses, co
 	if err != nil {
 		return err
 	}
+	course.mu.RLock()
+	course.threadUnsafeEmit(EventForceDisenrolled, s.ID, 0)
+	course.mu.RUnlock()
 	// Remove from map
 	delete(s.RegisteredCourses, courseID)
 	s.RegisteredUnits -= course.Units
+	s.threadUnsafeClearIntent(courses, courseID)
 	return nil
 }
 
@@ -286,6 +291,57 @@ func (s *Student) GetEnrolledCoursesProto(courses *Courses) *proto.StudentCourse
 	return result
 }
 
+// actionBatcher marks the disenroll and change group messages which it batches as messages
+// which have used one of the remaining actions of student
+type actionBatcher struct {
+	Batcher
+}
+
+func (b actionBatcher) ProcessDatabaseQuery(ctx context.Context, department DepartmentID, msg *proto.CourseDatabaseBatchMessage) error {
+	switch action := msg.GetAction().(type) {
+	case *proto.CourseDatabaseBatchMessage_Disenroll:
+		action.Disenroll.ConsumesAction = true
+	case *proto.CourseDatabaseBatchMessage_ChangeGroup:
+		action.ChangeGroup.ConsumesAction = true
+	}
+	return b.Batcher.ProcessDatabaseQuery(ctx, department, msg)
+}
+
+func (b actionBatcher) ShardOf(department DepartmentID) int {
+	return ShardOf(b.Batcher, department)
+}
+
+// checkConflicts checks the exam and class time of a course group with the registered groups
+// of other courses
+func checkConflicts(courses *Courses, course *Course, registered map[CourseID]GroupID) error {
+	for registeredCourseID, registeredGroupID := range registered {
+		// The same course is going to be replaced, so we don't check it
+		if registeredCourseID == course.ID {
+			continue
+		}
+		// Get the course
+		registeredCourse := courses.GetCourse(registeredCourseID, registeredGroupID)
+		if registeredCourse == nil {
+			panic(fmt.Sprintf("inconsistent user state: course %d group %d is registered but not found", registeredCourseID, registeredGroupID))
+		}
+		// Check exam time
+		if examTimesIntersect(registeredCourse.ExamTime.Load(), course.ExamTime.Load()) {
+			return ExamConflictErr{
+				CourseID: registeredCourse.ID,
+				GroupID:  registeredCourse.GroupID,
+			}
+		}
+		// Check time
+		if registeredCourse.ClassHeldTime.Intersects(&course.ClassHeldTime) {
+			return ClassTimeConflictErr{
+				CourseID: registeredCourse.ID,
+				GroupID:  registeredCourse.GroupID,
+			}
+		}
+	}
+	return nil
+}
+
 // examTimesIntersect checks if two exam times intersect.
 // As a side note that why this is a separate function, 0 as time means no exam.
 func examTimesIntersect(a, b int64) bool {
@@ -0,0 +1,204 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/proto"
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// MaxWatchedGroups is the maximum number of groups which a single watcher can watch
+const MaxWatchedGroups = 100
+
+// WatchedGroup is a course group which is watched. Zero group ID watches every group of the
+// course, including the groups which are added later.
+type WatchedGroup struct {
+	CourseID CourseID
+	GroupID  GroupID
+}
+
+// Seats is the seat availability of a course group
+type Seats struct {
+	CourseID           CourseID
+	GroupID            GroupID
+	Capacity           int
+	RegisteredCount    int
+	ReserveCapacity    int
+	ReserveQueueLength int
+}
+
+// SeatWatcher receives the seats of some course groups whenever they change. See Courses.WatchSeats.
+type SeatWatcher struct {
+	watchers *seatWatchers
+	groups   []WatchedGroup
+	// The latest seats of the groups which have changed since the last call to Next
+	pending map[WatchedGroup]Seats
+	mu      sync.Mutex
+	// Has a value when pending is not empty
+	signal chan struct{}
+}
+
+// seatWatchers is the list of watchers of some courses
+type seatWatchers struct {
+	// The watchers of each course
+	watchers map[CourseID]map[*SeatWatcher]struct{}
+	mu       sync.RWMutex
+}
+
+// WatchSeats creates a watcher of some course groups. The current seats of every watched group
+// are available in the first call to SeatWatcher.Next. After that, a group is returned again
+// whenever its registered students, reserve queue or capacities change. The watcher must be
+// closed after use.
+func (c *Courses) WatchSeats(groups []WatchedGroup) (*SeatWatcher, error) {
+	if len(groups) == 0 {
+		return nil, NoWatchedGroupsErr
+	}
+	if len(groups) > MaxWatchedGroups {
+		return nil, TooManyWatchedGroupsErr
+	}
+	// Find the courses at first
+	var courses []*Course
+	for _, group := range groups {
+		if group.GroupID == 0 {
+			groupsOfCourse := c.groupsOf(group.CourseID)
+			if len(groupsOfCourse) == 0 {
+				return nil, NotExistsErr
+			}
+			courses = append(courses, groupsOfCourse...)
+			continue
+		}
+		course := c.GetCourse(group.CourseID, group.GroupID)
+		if course == nil {
+			return nil, NotExistsErr
+		}
+		courses = append(courses, course)
+	}
+	// Register the watcher before reading the seats; so no change is missed
+	w := &SeatWatcher{
+		watchers: &c.hooks.seats,
+		groups:   slices.Clone(groups),
+		pending:  make(map[WatchedGroup]Seats),
+		signal:   make(chan struct{}, 1),
+	}
+	c.hooks.seats.add(w)
+	for _, course := range courses {
+		course.mu.RLock()
+		w.push(course.threadUnsafeSeats())
+		course.mu.RUnlock()
+	}
+	return w, nil
+}
+
+// Next waits until some watched groups change and returns their latest seats, ordered by course
+// and group IDs. The changes which happen between two calls are merged; so a slow reader only
+// misses the intermediate seats, not the latest ones.
+func (w *SeatWatcher) Next(ctx context.Context) ([]Seats, error) {
+	for {
+		w.mu.Lock()
+		if len(w.pending) != 0 {
+			result := slices.SortedFunc(maps.Values(w.pending), func(a, b Seats) int {
+				return cmp.Or(cmp.Compare(a.CourseID, b.CourseID), cmp.Compare(a.GroupID, b.GroupID))
+			})
+			clear(w.pending)
+			w.mu.Unlock()
+			return result, nil
+		}
+		w.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-w.signal:
+		}
+	}
+}
+
+// Close stops the watcher
+func (w *SeatWatcher) Close() {
+	w.watchers.remove(w)
+}
+
+// push adds the seats of a group to the pending seats if the group is watched
+func (w *SeatWatcher) push(seats Seats) {
+	if !slices.Contains(w.groups, WatchedGroup{CourseID: seats.CourseID}) &&
+		!slices.Contains(w.groups, WatchedGroup{CourseID: seats.CourseID, GroupID: seats.GroupID}) {
+		return
+	}
+	w.mu.Lock()
+	w.pending[WatchedGroup{CourseID: seats.CourseID, GroupID: seats.GroupID}] = seats
+	w.mu.Unlock()
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+// add registers a watcher for its courses
+func (s *seatWatchers) add(w *SeatWatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchers == nil {
+		s.watchers = make(map[CourseID]map[*SeatWatcher]struct{})
+	}
+	for _, group := range w.groups {
+		if s.watchers[group.CourseID] == nil {
+			s.watchers[group.CourseID] = make(map[*SeatWatcher]struct{})
+		}
+		s.watchers[group.CourseID][w] = struct{}{}
+	}
+}
+
+// remove unregisters a watcher
+func (s *seatWatchers) remove(w *SeatWatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, group := range w.groups {
+		delete(s.watchers[group.CourseID], w)
+		if len(s.watchers[group.CourseID]) == 0 {
+			delete(s.watchers, group.CourseID)
+		}
+	}
+}
+
+// threadUnsafeNotifyWatchers sends the seats of this course to its watchers. It never blocks, so
+// it can be called while the course is locked. The course must be locked for reading at least.
+func (c *Course) threadUnsafeNotifyWatchers() {
+	if c.hooks == nil {
+		return
+	}
+	c.hooks.seats.mu.RLock()
+	defer c.hooks.seats.mu.RUnlock()
+	watchers := c.hooks.seats.watchers[c.ID]
+	if len(watchers) == 0 {
+		return
+	}
+	seats := c.threadUnsafeSeats()
+	for w := range watchers {
+		w.push(seats)
+	}
+}
+
+// threadUnsafeSeats returns the seats of this course without locking it
+func (c *Course) threadUnsafeSeats() Seats {
+	return Seats{
+		CourseID:           c.ID,
+		GroupID:            c.GroupID,
+		Capacity:           c.Capacity,
+		RegisteredCount:    c.threadUnsafeTakenSeats(),
+		ReserveCapacity:    c.ReserveCapacity,
+		ReserveQueueLength: c.ReserveQueue.Len(),
+	}
+}
+
+// ToProto converts the seats to its protobuf message
+func (s Seats) ToProto() *proto.CourseSeats {
+	return &proto.CourseSeats{
+		CourseId:           int32(s.CourseID),
+		GroupId:            uint32(s.GroupID),
+		Capacity:           int32(s.Capacity),
+		RegisteredCount:    uint32(s.RegisteredCount),
+		ReserveCapacity:    int32(s.ReserveCapacity),
+		ReserveQueueLength: uint32(s.ReserveQueueLength),
+	}
+}
@@ -0,0 +1,93 @@
+package course
+
+import (
+	"CourseEnrollment/pkg/util"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestWatchSeats(t *testing.T) {
+	newCourse := func(id CourseID, groupID GroupID) *Course {
+		return &Course{
+			ID:                 id,
+			GroupID:            groupID,
+			Capacity:           1,
+			RegisteredStudents: make(map[StudentID]struct{}),
+			ReserveCapacity:    1,
+			ReserveQueue:       util.NewQueue[StudentID](),
+		}
+	}
+	// next returns the next seats or nil if nothing changes soon
+	next := func(t *testing.T, w *SeatWatcher) []Seats {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		seats, err := w.Next(ctx)
+		if err != nil {
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			return nil
+		}
+		return seats
+	}
+	courses := NewCourses(map[CourseID][]*Course{
+		1: {newCourse(1, 1), newCourse(1, 2)},
+		2: {newCourse(2, 1)},
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := courses.WatchSeats(nil)
+		assert.ErrorIs(t, err, NoWatchedGroupsErr)
+		_, err = courses.WatchSeats(make([]WatchedGroup, MaxWatchedGroups+1))
+		assert.ErrorIs(t, err, TooManyWatchedGroupsErr)
+		_, err = courses.WatchSeats([]WatchedGroup{{1, 3}})
+		assert.ErrorIs(t, err, NotExistsErr)
+		_, err = courses.WatchSeats([]WatchedGroup{{3, 0}})
+		assert.ErrorIs(t, err, NotExistsErr)
+	})
+	t.Run("group", func(t *testing.T) {
+		w, err := courses.WatchSeats([]WatchedGroup{{1, 1}})
+		require.NoError(t, err)
+		defer w.Close()
+		assert.Equal(t, []Seats{{CourseID: 1, GroupID: 1, Capacity: 1, ReserveCapacity: 1}}, next(t, w))
+		// Changes are merged
+		course := courses.GetCourse(1, 1)
+		for id := StudentID(1); id <= 2; id++ {
+			ok, err := course.EnrollStudent(context.Background(), id, noOpBatcher{})
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+		assert.Equal(t, []Seats{{CourseID: 1, GroupID: 1, Capacity: 1, RegisteredCount: 1, ReserveCapacity: 1, ReserveQueueLength: 1}}, next(t, w))
+		require.NoError(t, course.UpdateCapacity(context.Background(), 2, noOpBatcher{}))
+		assert.Equal(t, []Seats{{CourseID: 1, GroupID: 1, Capacity: 2, RegisteredCount: 2, ReserveCapacity: 1}}, next(t, w))
+		require.NoError(t, course.DisenrollStudent(context.Background(), 1, noOpBatcher{}))
+		assert.Equal(t, []Seats{{CourseID: 1, GroupID: 1, Capacity: 2, RegisteredCount: 1, ReserveCapacity: 1}}, next(t, w))
+		// Other groups are not watched
+		_, err = courses.GetCourse(1, 2).EnrollStudent(context.Background(), 3, noOpBatcher{})
+		require.NoError(t, err)
+		assert.Nil(t, next(t, w))
+	})
+	t.Run("course", func(t *testing.T) {
+		w, err := courses.WatchSeats([]WatchedGroup{{1, 0}})
+		require.NoError(t, err)
+		assert.Len(t, next(t, w), 2)
+		// Changing the group updates both groups
+		ok, err := courses.GetCourse(1, 1).ChangeGroupOfStudent(context.Background(), 2, courses.GetCourse(1, 2), noOpBatcher{})
+		require.NoError(t, err)
+		require.True(t, ok)
+		seats := next(t, w)
+		if assert.Len(t, seats, 2) {
+			assert.Equal(t, 0, seats[0].RegisteredCount)
+			assert.Equal(t, 1, seats[1].RegisteredCount)
+			assert.Equal(t, 1, seats[1].ReserveQueueLength)
+		}
+		// New groups are watched too
+		courses.addCourse(newCourse(1, 3))
+		assert.Equal(t, []Seats{{CourseID: 1, GroupID: 3, Capacity: 1, ReserveCapacity: 1}}, next(t, w))
+		// Nothing is sent after closing
+		w.Close()
+		assert.Empty(t, courses.hooks.seats.watchers)
+		require.NoError(t, courses.GetCourse(1, 3).ForceEnroll(context.Background(), 4, noOpBatcher{}))
+		assert.Nil(t, next(t, w))
+	})
+}
@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+)
+
+// InboxSink adds the events to the inbox of students. The events are batched like the other
+// changes; so the batcher writes them in the notifications table, which the auth core reads.
+type InboxSink struct {
+	// The message broker. The journal of snapshots is not needed because the notifications are
+	// not a part of the state.
+	Batcher course.Batcher
+}
+
+// Send will batch the event to the queue of the department of its course
+func (s InboxSink) Send(ctx context.Context, event course.Event) error {
+	return s.Batcher.ProcessDatabaseQuery(ctx, event.Department, &proto.CourseDatabaseBatchMessage{
+		Action: &proto.CourseDatabaseBatchMessage_AddNotification{AddNotification: event.ToProto()},
+	})
+}
@@ -0,0 +1,114 @@
+// Package notification delivers the events of students, like being promoted from a reserve queue,
+// to them through email, webhooks or their inbox.
+package notification
+
+import (
+	"CourseEnrollment/pkg/course"
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// sendTimeout is the maximum time which a sink can take to send a single event
+const sendTimeout = 10 * time.Second
+
+// Sink delivers the events to students
+type Sink interface {
+	// Send must deliver a single event. It's only called from a single goroutine.
+	Send(ctx context.Context, event course.Event) error
+}
+
+// Dispatcher is a course.EventSink which sends every event to some sinks in the background. Each
+// sink has its own queue; so a slow sink does not delay the others. Events are dropped if the queue
+// of a sink is full, and failed events are only logged.
+type Dispatcher struct {
+	sinks  []Sink
+	queues []chan course.Event
+	closed bool
+	// Emit holds this for reading and Close holds it for writing
+	mu sync.RWMutex
+	wg sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher which queues at most queueSize events for each sink
+func NewDispatcher(queueSize int, sinks ...Sink) *Dispatcher {
+	d := &Dispatcher{sinks: sinks, queues: make([]chan course.Event, len(sinks))}
+	for i, sink := range sinks {
+		d.queues[i] = make(chan course.Event, queueSize)
+		d.wg.Add(1)
+		go d.run(sink, d.queues[i])
+	}
+	return d
+}
+
+// Emit queues the event for every sink. It never blocks.
+func (d *Dispatcher) Emit(event course.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	for i, queue := range d.queues {
+		select {
+		case queue <- event:
+		default:
+			log.WithField("sink", fmt.Sprintf("%T", d.sinks[i])).WithField("student id", event.StudentID).
+				Warn("notification queue is full; dropping the event")
+		}
+	}
+}
+
+// Close stops accepting events and waits until the queued events are sent
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// run sends the events of a queue to its sink until the queue is closed
+func (d *Dispatcher) run(sink Sink, queue <-chan course.Event) {
+	defer d.wg.Done()
+	for event := range queue {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := sink.Send(ctx, event); err != nil {
+			log.WithError(err).WithField("sink", fmt.Sprintf("%T", sink)).WithField("student id", event.StudentID).
+				Error("cannot send notification")
+		}
+		cancel()
+	}
+}
+
+// Message returns the human-readable subject and body of an event
+func Message(event course.Event) (string, string) {
+	switch event.Kind {
+	case course.EventPromoted:
+		body := fmt.Sprintf("A seat is freed in course %d group %d and you are moved from its reserve queue to its registered students.", event.CourseID, event.GroupID)
+		if event.ConfirmationDeadline != 0 {
+			body += fmt.Sprintf(" You must confirm the seat before %s or it goes to the next student in the queue.",
+				time.UnixMilli(event.ConfirmationDeadline).UTC().Format(time.RFC1123))
+		}
+		return fmt.Sprintf("You are enrolled in course %d-%d", event.CourseID, event.GroupID), body
+	case course.EventPromotionExpired:
+		return fmt.Sprintf("You are dropped from course %d-%d", event.CourseID, event.GroupID),
+			fmt.Sprintf("You have not confirmed your seat in course %d group %d in time, so it is given to the next student in its reserve queue.", event.CourseID, event.GroupID)
+	case course.EventForceEnrolled:
+		return fmt.Sprintf("You are enrolled in course %d-%d", event.CourseID, event.GroupID),
+			fmt.Sprintf("A staff member has enrolled you in course %d group %d.", event.CourseID, event.GroupID)
+	case course.EventForceDisenrolled:
+		return fmt.Sprintf("You are dropped from course %d-%d", event.CourseID, event.GroupID),
+			fmt.Sprintf("A staff member has dropped you from course %d group %d.", event.CourseID, event.GroupID)
+	case course.EventCapacityChanged:
+		return fmt.Sprintf("The capacity of course %d-%d has changed", event.CourseID, event.GroupID),
+			fmt.Sprintf("The capacity of course %d group %d is now %d. You are number %d in its reserve queue.", event.CourseID, event.GroupID, event.Capacity, event.ReservePosition)
+	default:
+		return "Enrollment notification", fmt.Sprintf("Something has happened in course %d group %d.", event.CourseID, event.GroupID)
+	}
+}
@@ -0,0 +1,223 @@
+package notification
+
+import (
+	"CourseEnrollment/pkg/course"
+	"CourseEnrollment/pkg/proto"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingSink records the sent events
+type recordingSink struct {
+	mu     sync.Mutex
+	events []course.Event
+	// If not nil, Send blocks until it's closed
+	block chan struct{}
+}
+
+func (s *recordingSink) Send(_ context.Context, event course.Event) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	s.mu.Unlock()
+	return nil
+}
+
+// failingSink always fails
+type failingSink struct{}
+
+func (failingSink) Send(context.Context, course.Event) error {
+	return errors.New("failed")
+}
+
+// recordingBatcher records the batched messages
+type recordingBatcher struct {
+	department course.DepartmentID
+	messages   []*proto.CourseDatabaseBatchMessage
+}
+
+func (b *recordingBatcher) ProcessDatabaseQuery(_ context.Context, department course.DepartmentID, message *proto.CourseDatabaseBatchMessage) error {
+	b.department = department
+	b.messages = append(b.messages, message)
+	return nil
+}
+
+var testEvent = course.Event{
+	Kind:            course.EventCapacityChanged,
+	StudentID:       400243059,
+	CourseID:        40101,
+	GroupID:         2,
+	Department:      40,
+	Capacity:        30,
+	ReservePosition: 3,
+	Time:            1700000000000,
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("deliver", func(t *testing.T) {
+		first, second := new(recordingSink), new(recordingSink)
+		d := NewDispatcher(10, first, failingSink{}, second)
+		d.Emit(testEvent)
+		d.Emit(course.Event{Kind: course.EventPromoted, StudentID: 1})
+		d.Close()
+		for _, sink := range []*recordingSink{first, second} {
+			if assert.Len(t, sink.events, 2) {
+				assert.Equal(t, testEvent, sink.events[0])
+				assert.Equal(t, course.StudentID(1), sink.events[1].StudentID)
+			}
+		}
+		// Closed dispatchers ignore the events
+		d.Emit(testEvent)
+		d.Close()
+		assert.Len(t, first.events, 2)
+	})
+	t.Run("full queue", func(t *testing.T) {
+		sink := &recordingSink{block: make(chan struct{})}
+		d := NewDispatcher(1, sink)
+		// At most one event is being sent and one is queued; so some of these are dropped
+		for i := 0; i < 5; i++ {
+			d.Emit(course.Event{StudentID: course.StudentID(i)})
+		}
+		close(sink.block)
+		d.Close()
+		assert.GreaterOrEqual(t, len(sink.events), 1)
+		assert.LessOrEqual(t, len(sink.events), 2)
+		assert.Equal(t, course.StudentID(0), sink.events[0].StudentID)
+	})
+}
+
+func TestInboxSink(t *testing.T) {
+	batcher := new(recordingBatcher)
+	require.NoError(t, InboxSink{Batcher: batcher}.Send(context.Background(), testEvent))
+	assert.Equal(t, course.DepartmentID(40), batcher.department)
+	require.Len(t, batcher.messages, 1)
+	notification := batcher.messages[0].GetAddNotification()
+	require.NotNil(t, notification)
+	event := testEvent
+	event.Department = 0
+	assert.Equal(t, event, course.NewEventFromProto(notification))
+}
+
+func TestWebhookSink(t *testing.T) {
+	const secret = "secret"
+	var body []byte
+	var signature string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := WebhookSink{URL: server.URL, Secret: secret}
+	require.NoError(t, sink.Send(context.Background(), testEvent))
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "capacity_changed", payload["kind"])
+	assert.EqualValues(t, 400243059, payload["student_id"])
+	assert.EqualValues(t, 40101, payload["course_id"])
+	assert.EqualValues(t, 2, payload["group_id"])
+	assert.EqualValues(t, 30, payload["capacity"])
+	assert.EqualValues(t, 3, payload["reserve_position"])
+	assert.Equal(t, "2023-11-14T22:13:20Z", payload["time"])
+	assert.NotContains(t, payload, "confirmation_deadline")
+	_, message := Message(testEvent)
+	assert.Equal(t, message, payload["message"])
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+
+	// No secret means no signature
+	sink.Secret = ""
+	require.NoError(t, sink.Send(context.Background(), testEvent))
+	assert.Empty(t, signature)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, sink.Send(context.Background(), testEvent))
+}
+
+func TestSMTPSink(t *testing.T) {
+	_, err := NewSMTPSink("localhost", "", "", "from@example.edu", "{id}@example.edu")
+	assert.Error(t, err)
+	_, err = NewSMTPSink("localhost:25", "", "", "from@example.edu", "student@example.edu")
+	assert.Error(t, err)
+
+	sink, err := NewSMTPSink("localhost:25", "user", "pass", "from@example.edu", "{id}@students.example.edu")
+	require.NoError(t, err)
+	assert.NotNil(t, sink.auth)
+	var sentAddress, sentFrom string
+	var sentTo []string
+	var sentMessage string
+	sink.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentAddress, sentFrom, sentTo, sentMessage = addr, from, to, string(msg)
+		return nil
+	}
+	require.NoError(t, sink.Send(context.Background(), testEvent))
+	assert.Equal(t, "localhost:25", sentAddress)
+	assert.Equal(t, "from@example.edu", sentFrom)
+	assert.Equal(t, []string{"400243059@students.example.edu"}, sentTo)
+	subject, body := Message(testEvent)
+	assert.True(t, strings.HasPrefix(sentMessage, "From: from@example.edu\r\nTo: 400243059@students.example.edu\r\nSubject: "+subject+"\r\n"))
+	assert.True(t, strings.HasSuffix(sentMessage, "\r\n\r\n"+body+"\r\n"))
+
+	sink.sendMail = func(string, smtp.Auth, string, []string, []byte) error {
+		return errors.New("failed")
+	}
+	assert.Error(t, sink.Send(context.Background(), testEvent))
+
+	sink, err = NewSMTPSink("localhost:25", "", "", "from@example.edu", "{id}@students.example.edu")
+	require.NoError(t, err)
+	assert.Nil(t, sink.auth)
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		kind    course.EventKind
+		subject string
+	}{
+		{course.EventPromoted, "You are enrolled in course 40101-2"},
+		{course.EventForceEnrolled, "You are enrolled in course 40101-2"},
+		{course.EventForceDisenrolled, "You are dropped from course 40101-2"},
+		{course.EventCapacityChanged, "The capacity of course 40101-2 has changed"},
+		{course.EventPromotionExpired, "You are dropped from course 40101-2"},
+		{0, "Enrollment notification"},
+	}
+	for _, test := range tests {
+		t.Run(test.kind.String(), func(t *testing.T) {
+			event := testEvent
+			event.Kind = test.kind
+			subject, body := Message(event)
+			assert.Equal(t, test.subject, subject)
+			assert.Contains(t, body, "course 40101 group 2")
+		})
+	}
+	_, body := Message(testEvent)
+	assert.Contains(t, body, "is now 30")
+	assert.Contains(t, body, "number 3")
+	// The promoted students are told about the deadline
+	event := testEvent
+	event.Kind = course.EventPromoted
+	_, body = Message(event)
+	assert.NotContains(t, body, "confirm")
+	event.ConfirmationDeadline = 1700003600000
+	_, body = Message(event)
+	assert.Contains(t, body, "confirm the seat before Tue, 14 Nov 2023 23:13:20 UTC")
+}
@@ -0,0 +1,62 @@
+package notification
+
+import (
+	"CourseEnrollment/pkg/course"
+	"context"
+	"fmt"
+	"github.com/go-faster/errors"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// StudentIDPlaceholder is replaced with the student ID in the address of students
+const StudentIDPlaceholder = "{id}"
+
+// SMTPSink emails every event to its student
+type SMTPSink struct {
+	// The host:port of the SMTP server
+	address string
+	// Nil means no authentication
+	auth smtp.Auth
+	from string
+	// The address of students. StudentIDPlaceholder is replaced with the student ID.
+	to string
+	// It's smtp.SendMail; tests replace it
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSink creates a sink which sends emails through the SMTP server at address. If username is
+// not empty, PLAIN authentication is used. Every student must have an email address which can be
+// made from the to template, like "{id}@students.example.edu".
+func NewSMTPSink(address, username, password, from, to string) (*SMTPSink, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid address")
+	}
+	if !strings.Contains(to, StudentIDPlaceholder) {
+		return nil, fmt.Errorf("the address of students must contain %s", StudentIDPlaceholder)
+	}
+	sink := &SMTPSink{address: address, from: from, to: to, sendMail: smtp.SendMail}
+	if username != "" {
+		sink.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sink, nil
+}
+
+// Send will email the event. The context is not used because net/smtp does not support it.
+func (s *SMTPSink) Send(_ context.Context, event course.Event) error {
+	to := strings.ReplaceAll(s.to, StudentIDPlaceholder, strconv.FormatUint(uint64(event.StudentID), 10))
+	subject, body := Message(event)
+	message := "From: " + s.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		body + "\r\n"
+	if err := s.sendMail(s.address, s.auth, s.from, []string{to}, []byte(message)); err != nil {
+		return errors.Wrap(err, "cannot send mail")
+	}
+	return nil
+}
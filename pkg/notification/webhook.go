@@ -0,0 +1,93 @@
+package notification
+
+import (
+	"CourseEnrollment/pkg/course"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/go-faster/errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader is the header which contains the signature of webhook requests
+const SignatureHeader = "X-Signature-256"
+
+// WebhookSink posts every event as JSON to a URL
+type WebhookSink struct {
+	URL string
+	// If not empty, the body is signed with HMAC-SHA256 and sent in SignatureHeader as
+	// "sha256=" followed by the hex of the signature.
+	Secret string
+	// Nil means http.DefaultClient
+	Client *http.Client
+}
+
+// webhookPayload is the body of webhook requests
+type webhookPayload struct {
+	Kind            string           `json:"kind"`
+	StudentID       course.StudentID `json:"student_id"`
+	CourseID        course.CourseID  `json:"course_id"`
+	GroupID         course.GroupID   `json:"group_id"`
+	Capacity        int              `json:"capacity"`
+	ReservePosition int              `json:"reserve_position"`
+	Time            time.Time        `json:"time"`
+	// Nil if the seat does not need confirmation
+	ConfirmationDeadline *time.Time `json:"confirmation_deadline,omitempty"`
+	Subject              string     `json:"subject"`
+	Message              string     `json:"message"`
+}
+
+// Send will post the event. Any status other than 2xx is an error.
+func (s WebhookSink) Send(ctx context.Context, event course.Event) error {
+	subject, message := Message(event)
+	var deadline *time.Time
+	if event.ConfirmationDeadline != 0 {
+		t := time.UnixMilli(event.ConfirmationDeadline).UTC()
+		deadline = &t
+	}
+	body, err := json.Marshal(webhookPayload{
+		Kind:                 event.Kind.String(),
+		StudentID:            event.StudentID,
+		CourseID:             event.CourseID,
+		GroupID:              event.GroupID,
+		Capacity:             event.Capacity,
+		ReservePosition:      event.ReservePosition,
+		Time:                 time.UnixMilli(event.Time).UTC(),
+		ConfirmationDeadline: deadline,
+		Subject:              subject,
+		Message:              message,
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal event")
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "cannot create request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if s.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.Secret))
+		mac.Write(body)
+		request.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "cannot send request")
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return nil
+}
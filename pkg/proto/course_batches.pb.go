@@ -20,6 +20,57 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// AuditActorKind is the kind of who has made a change
+type AuditActorKind int32
+
+const (
+	// The enrollment server itself, like the periodic jobs
+	AuditActorKind_AUDIT_ACTOR_SYSTEM  AuditActorKind = 0
+	AuditActorKind_AUDIT_ACTOR_STUDENT AuditActorKind = 1
+	AuditActorKind_AUDIT_ACTOR_STAFF   AuditActorKind = 2
+)
+
+// Enum value maps for AuditActorKind.
+var (
+	AuditActorKind_name = map[int32]string{
+		0: "AUDIT_ACTOR_SYSTEM",
+		1: "AUDIT_ACTOR_STUDENT",
+		2: "AUDIT_ACTOR_STAFF",
+	}
+	AuditActorKind_value = map[string]int32{
+		"AUDIT_ACTOR_SYSTEM":  0,
+		"AUDIT_ACTOR_STUDENT": 1,
+		"AUDIT_ACTOR_STAFF":   2,
+	}
+)
+
+func (x AuditActorKind) Enum() *AuditActorKind {
+	p := new(AuditActorKind)
+	*p = x
+	return p
+}
+
+func (x AuditActorKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuditActorKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_course_batches_proto_enumTypes[0].Descriptor()
+}
+
+func (AuditActorKind) Type() protoreflect.EnumType {
+	return &file_pkg_proto_course_batches_proto_enumTypes[0]
+}
+
+func (x AuditActorKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuditActorKind.Descriptor instead.
+func (AuditActorKind) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{0}
+}
+
 type CourseDatabaseBatchMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -31,7 +82,21 @@ type CourseDatabaseBatchMessage struct {
 	//	*CourseDatabaseBatchMessage_Disenroll
 	//	*CourseDatabaseBatchMessage_ChangeGroup
 	//	*CourseDatabaseBatchMessage_UpdateCapacity
+	//	*CourseDatabaseBatchMessage_PutStudent
+	//	*CourseDatabaseBatchMessage_PutCourse
+	//	*CourseDatabaseBatchMessage_PutSchedule
+	//	*CourseDatabaseBatchMessage_AddIntent
+	//	*CourseDatabaseBatchMessage_RemoveIntent
+	//	*CourseDatabaseBatchMessage_Transaction
+	//	*CourseDatabaseBatchMessage_PutWishlist
+	//	*CourseDatabaseBatchMessage_AddNotification
+	//	*CourseDatabaseBatchMessage_ConfirmPromotion
 	Action isCourseDatabaseBatchMessage_Action `protobuf_oneof:"action"`
+	// A unique ID for this operation. The batcher records the applied IDs so
+	// applying a message more than once is a no-op.
+	OperationId string `protobuf:"bytes,5,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// Who has made this change and why. The inner messages of transactions do not have it.
+	Audit *CourseDatabaseBatchAudit `protobuf:"bytes,15,opt,name=audit,proto3" json:"audit,omitempty"`
 }
 
 func (x *CourseDatabaseBatchMessage) Reset() {
@@ -101,6 +166,83 @@ func (x *CourseDatabaseBatchMessage) GetUpdateCapacity() *CourseDatabaseBatchUpd
 	return nil
 }
 
+func (x *CourseDatabaseBatchMessage) GetPutStudent() *CourseDatabaseBatchPutStudent {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_PutStudent); ok {
+		return x.PutStudent
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetPutCourse() *CourseDatabaseBatchPutCourse {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_PutCourse); ok {
+		return x.PutCourse
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetPutSchedule() *EnrollmentSchedule {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_PutSchedule); ok {
+		return x.PutSchedule
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetAddIntent() *CourseDatabaseBatchAddIntent {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_AddIntent); ok {
+		return x.AddIntent
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetRemoveIntent() *CourseDatabaseBatchRemoveIntent {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_RemoveIntent); ok {
+		return x.RemoveIntent
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetTransaction() *CourseDatabaseBatchTransaction {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_Transaction); ok {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetPutWishlist() *CourseDatabaseBatchPutWishlist {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_PutWishlist); ok {
+		return x.PutWishlist
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetAddNotification() *StudentNotification {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_AddNotification); ok {
+		return x.AddNotification
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetConfirmPromotion() *CourseDatabaseBatchConfirmPromotion {
+	if x, ok := x.GetAction().(*CourseDatabaseBatchMessage_ConfirmPromotion); ok {
+		return x.ConfirmPromotion
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchMessage) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *CourseDatabaseBatchMessage) GetAudit() *CourseDatabaseBatchAudit {
+	if x != nil {
+		return x.Audit
+	}
+	return nil
+}
+
 type isCourseDatabaseBatchMessage_Action interface {
 	isCourseDatabaseBatchMessage_Action()
 }
@@ -121,6 +263,43 @@ type CourseDatabaseBatchMessage_UpdateCapacity struct {
 	UpdateCapacity *CourseDatabaseBatchUpdateCapacity `protobuf:"bytes,4,opt,name=update_capacity,json=updateCapacity,proto3,oneof"`
 }
 
+type CourseDatabaseBatchMessage_PutStudent struct {
+	PutStudent *CourseDatabaseBatchPutStudent `protobuf:"bytes,6,opt,name=put_student,json=putStudent,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_PutCourse struct {
+	PutCourse *CourseDatabaseBatchPutCourse `protobuf:"bytes,7,opt,name=put_course,json=putCourse,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_PutSchedule struct {
+	PutSchedule *EnrollmentSchedule `protobuf:"bytes,8,opt,name=put_schedule,json=putSchedule,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_AddIntent struct {
+	AddIntent *CourseDatabaseBatchAddIntent `protobuf:"bytes,9,opt,name=add_intent,json=addIntent,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_RemoveIntent struct {
+	RemoveIntent *CourseDatabaseBatchRemoveIntent `protobuf:"bytes,10,opt,name=remove_intent,json=removeIntent,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_Transaction struct {
+	Transaction *CourseDatabaseBatchTransaction `protobuf:"bytes,11,opt,name=transaction,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_PutWishlist struct {
+	PutWishlist *CourseDatabaseBatchPutWishlist `protobuf:"bytes,12,opt,name=put_wishlist,json=putWishlist,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_AddNotification struct {
+	// Adds the notification to the inbox of its student
+	AddNotification *StudentNotification `protobuf:"bytes,13,opt,name=add_notification,json=addNotification,proto3,oneof"`
+}
+
+type CourseDatabaseBatchMessage_ConfirmPromotion struct {
+	ConfirmPromotion *CourseDatabaseBatchConfirmPromotion `protobuf:"bytes,14,opt,name=confirm_promotion,json=confirmPromotion,proto3,oneof"`
+}
+
 func (*CourseDatabaseBatchMessage_Enroll) isCourseDatabaseBatchMessage_Action() {}
 
 func (*CourseDatabaseBatchMessage_Disenroll) isCourseDatabaseBatchMessage_Action() {}
@@ -129,23 +308,43 @@ func (*CourseDatabaseBatchMessage_ChangeGroup) isCourseDatabaseBatchMessage_Acti
 
 func (*CourseDatabaseBatchMessage_UpdateCapacity) isCourseDatabaseBatchMessage_Action() {}
 
-type CourseDatabaseBatchEnrollMessage struct {
+func (*CourseDatabaseBatchMessage_PutStudent) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_PutCourse) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_PutSchedule) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_AddIntent) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_RemoveIntent) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_Transaction) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_PutWishlist) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_AddNotification) isCourseDatabaseBatchMessage_Action() {}
+
+func (*CourseDatabaseBatchMessage_ConfirmPromotion) isCourseDatabaseBatchMessage_Action() {}
+
+// CourseDatabaseBatchAudit is the context of a change which is written to the audit log
+type CourseDatabaseBatchAudit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The student ID which this message is for.
-	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
-	// The course which user is trying to enroll in.
-	CourseId int32 `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
-	// The group ID which user is trying to enroll in.
-	GroupId uint32 `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
-	// True if user is in reserved queue
-	Reserved bool `protobuf:"varint,4,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	ActorKind AuditActorKind `protobuf:"varint,1,opt,name=actor_kind,json=actorKind,proto3,enum=proto.AuditActorKind" json:"actor_kind,omitempty"`
+	// The ID of the student or the staff. Zero for the system.
+	ActorId uint64 `protobuf:"varint,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	// When the change is made in unix milliseconds
+	Time int64 `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	// The RPC or the job which has made the change
+	Origin string `protobuf:"bytes,4,opt,name=origin,proto3" json:"origin,omitempty"`
+	// The reason which the staff has entered. It can be empty.
+	Reason string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (x *CourseDatabaseBatchEnrollMessage) Reset() {
-	*x = CourseDatabaseBatchEnrollMessage{}
+func (x *CourseDatabaseBatchAudit) Reset() {
+	*x = CourseDatabaseBatchAudit{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_course_batches_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -153,13 +352,13 @@ func (x *CourseDatabaseBatchEnrollMessage) Reset() {
 	}
 }
 
-func (x *CourseDatabaseBatchEnrollMessage) String() string {
+func (x *CourseDatabaseBatchAudit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CourseDatabaseBatchEnrollMessage) ProtoMessage() {}
+func (*CourseDatabaseBatchAudit) ProtoMessage() {}
 
-func (x *CourseDatabaseBatchEnrollMessage) ProtoReflect() protoreflect.Message {
+func (x *CourseDatabaseBatchAudit) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_course_batches_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -171,52 +370,58 @@ func (x *CourseDatabaseBatchEnrollMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CourseDatabaseBatchEnrollMessage.ProtoReflect.Descriptor instead.
-func (*CourseDatabaseBatchEnrollMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use CourseDatabaseBatchAudit.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchAudit) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *CourseDatabaseBatchEnrollMessage) GetStudentId() uint64 {
+func (x *CourseDatabaseBatchAudit) GetActorKind() AuditActorKind {
 	if x != nil {
-		return x.StudentId
+		return x.ActorKind
 	}
-	return 0
+	return AuditActorKind_AUDIT_ACTOR_SYSTEM
 }
 
-func (x *CourseDatabaseBatchEnrollMessage) GetCourseId() int32 {
+func (x *CourseDatabaseBatchAudit) GetActorId() uint64 {
 	if x != nil {
-		return x.CourseId
+		return x.ActorId
 	}
 	return 0
 }
 
-func (x *CourseDatabaseBatchEnrollMessage) GetGroupId() uint32 {
+func (x *CourseDatabaseBatchAudit) GetTime() int64 {
 	if x != nil {
-		return x.GroupId
+		return x.Time
 	}
 	return 0
 }
 
-func (x *CourseDatabaseBatchEnrollMessage) GetReserved() bool {
+func (x *CourseDatabaseBatchAudit) GetOrigin() string {
 	if x != nil {
-		return x.Reserved
+		return x.Origin
 	}
-	return false
+	return ""
 }
 
-type CourseDatabaseBatchDisenrollMessage struct {
+func (x *CourseDatabaseBatchAudit) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// CourseDatabaseBatchTransaction contains the enroll, disenroll and change group messages of a
+// student which must be applied all together. The inner messages do not have operation IDs.
+type CourseDatabaseBatchTransaction struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The student ID which this message is for.
-	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
-	// The course which user is trying to disenroll from.
-	CourseId int32 `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	Messages []*CourseDatabaseBatchMessage `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
 }
 
-func (x *CourseDatabaseBatchDisenrollMessage) Reset() {
-	*x = CourseDatabaseBatchDisenrollMessage{}
+func (x *CourseDatabaseBatchTransaction) Reset() {
+	*x = CourseDatabaseBatchTransaction{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_course_batches_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -224,13 +429,13 @@ func (x *CourseDatabaseBatchDisenrollMessage) Reset() {
 	}
 }
 
-func (x *CourseDatabaseBatchDisenrollMessage) String() string {
+func (x *CourseDatabaseBatchTransaction) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CourseDatabaseBatchDisenrollMessage) ProtoMessage() {}
+func (*CourseDatabaseBatchTransaction) ProtoMessage() {}
 
-func (x *CourseDatabaseBatchDisenrollMessage) ProtoReflect() protoreflect.Message {
+func (x *CourseDatabaseBatchTransaction) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_course_batches_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -242,42 +447,35 @@ func (x *CourseDatabaseBatchDisenrollMessage) ProtoReflect() protoreflect.Messag
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CourseDatabaseBatchDisenrollMessage.ProtoReflect.Descriptor instead.
-func (*CourseDatabaseBatchDisenrollMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use CourseDatabaseBatchTransaction.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchTransaction) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *CourseDatabaseBatchDisenrollMessage) GetStudentId() uint64 {
-	if x != nil {
-		return x.StudentId
-	}
-	return 0
-}
-
-func (x *CourseDatabaseBatchDisenrollMessage) GetCourseId() int32 {
+func (x *CourseDatabaseBatchTransaction) GetMessages() []*CourseDatabaseBatchMessage {
 	if x != nil {
-		return x.CourseId
+		return x.Messages
 	}
-	return 0
+	return nil
 }
 
-type CourseDatabaseBatchChangeGroupMessage struct {
+type CourseDatabaseBatchEnrollMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// The student ID which this message is for.
 	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
-	// The course which user is trying to change group in.
+	// The course which user is trying to enroll in.
 	CourseId int32 `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
-	// The group ID which user is trying to change group to.
+	// The group ID which user is trying to enroll in.
 	GroupId uint32 `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
 	// True if user is in reserved queue
 	Reserved bool `protobuf:"varint,4,opt,name=reserved,proto3" json:"reserved,omitempty"`
 }
 
-func (x *CourseDatabaseBatchChangeGroupMessage) Reset() {
-	*x = CourseDatabaseBatchChangeGroupMessage{}
+func (x *CourseDatabaseBatchEnrollMessage) Reset() {
+	*x = CourseDatabaseBatchEnrollMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_course_batches_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -285,13 +483,13 @@ func (x *CourseDatabaseBatchChangeGroupMessage) Reset() {
 	}
 }
 
-func (x *CourseDatabaseBatchChangeGroupMessage) String() string {
+func (x *CourseDatabaseBatchEnrollMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CourseDatabaseBatchChangeGroupMessage) ProtoMessage() {}
+func (*CourseDatabaseBatchEnrollMessage) ProtoMessage() {}
 
-func (x *CourseDatabaseBatchChangeGroupMessage) ProtoReflect() protoreflect.Message {
+func (x *CourseDatabaseBatchEnrollMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_course_batches_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -303,56 +501,61 @@ func (x *CourseDatabaseBatchChangeGroupMessage) ProtoReflect() protoreflect.Mess
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CourseDatabaseBatchChangeGroupMessage.ProtoReflect.Descriptor instead.
-func (*CourseDatabaseBatchChangeGroupMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use CourseDatabaseBatchEnrollMessage.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchEnrollMessage) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *CourseDatabaseBatchChangeGroupMessage) GetStudentId() uint64 {
+func (x *CourseDatabaseBatchEnrollMessage) GetStudentId() uint64 {
 	if x != nil {
 		return x.StudentId
 	}
 	return 0
 }
 
-func (x *CourseDatabaseBatchChangeGroupMessage) GetCourseId() int32 {
+func (x *CourseDatabaseBatchEnrollMessage) GetCourseId() int32 {
 	if x != nil {
 		return x.CourseId
 	}
 	return 0
 }
 
-func (x *CourseDatabaseBatchChangeGroupMessage) GetGroupId() uint32 {
+func (x *CourseDatabaseBatchEnrollMessage) GetGroupId() uint32 {
 	if x != nil {
 		return x.GroupId
 	}
 	return 0
 }
 
-func (x *CourseDatabaseBatchChangeGroupMessage) GetReserved() bool {
+func (x *CourseDatabaseBatchEnrollMessage) GetReserved() bool {
 	if x != nil {
 		return x.Reserved
 	}
 	return false
 }
 
-type CourseDatabaseBatchUpdateCapacity struct {
+type CourseDatabaseBatchDisenrollMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The course ID to change its capacity
-	CourseId int32 `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
-	// The group ID of the course to change its capacity
-	GroupId uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
-	// The new capacity of this course
-	NewCapacity int32 `protobuf:"varint,3,opt,name=new_capacity,json=newCapacity,proto3" json:"new_capacity,omitempty"`
-	// Users which are moved from reserve queue to main registered users
-	MovedStudents []uint64 `protobuf:"varint,4,rep,packed,name=moved_students,json=movedStudents,proto3" json:"moved_students,omitempty"`
+	// The student ID which this message is for.
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	// The course which user is trying to disenroll from.
+	CourseId int32 `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// True if this disenrollment has used one of the remaining actions of student
+	ConsumesAction bool `protobuf:"varint,3,opt,name=consumes_action,json=consumesAction,proto3" json:"consumes_action,omitempty"`
+	// The student which is moved from the reserve queue to the freed seat. Zero means nobody.
+	PromotedStudentId uint64 `protobuf:"varint,4,opt,name=promoted_student_id,json=promotedStudentId,proto3" json:"promoted_student_id,omitempty"`
+	// Until when the promoted student must confirm their seat in unix milliseconds. Zero means
+	// that the course does not need confirmations.
+	ConfirmationDeadline int64 `protobuf:"varint,5,opt,name=confirmation_deadline,json=confirmationDeadline,proto3" json:"confirmation_deadline,omitempty"`
+	// The group which the student has left. It's only used in the audit log.
+	GroupId uint32 `protobuf:"varint,6,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
 }
 
-func (x *CourseDatabaseBatchUpdateCapacity) Reset() {
-	*x = CourseDatabaseBatchUpdateCapacity{}
+func (x *CourseDatabaseBatchDisenrollMessage) Reset() {
+	*x = CourseDatabaseBatchDisenrollMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_course_batches_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -360,13 +563,13 @@ func (x *CourseDatabaseBatchUpdateCapacity) Reset() {
 	}
 }
 
-func (x *CourseDatabaseBatchUpdateCapacity) String() string {
+func (x *CourseDatabaseBatchDisenrollMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CourseDatabaseBatchUpdateCapacity) ProtoMessage() {}
+func (*CourseDatabaseBatchDisenrollMessage) ProtoMessage() {}
 
-func (x *CourseDatabaseBatchUpdateCapacity) ProtoReflect() protoreflect.Message {
+func (x *CourseDatabaseBatchDisenrollMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_course_batches_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -378,137 +581,1058 @@ func (x *CourseDatabaseBatchUpdateCapacity) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CourseDatabaseBatchUpdateCapacity.ProtoReflect.Descriptor instead.
-func (*CourseDatabaseBatchUpdateCapacity) Descriptor() ([]byte, []int) {
+// Deprecated: Use CourseDatabaseBatchDisenrollMessage.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchDisenrollMessage) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CourseDatabaseBatchUpdateCapacity) GetCourseId() int32 {
+func (x *CourseDatabaseBatchDisenrollMessage) GetStudentId() uint64 {
 	if x != nil {
-		return x.CourseId
+		return x.StudentId
 	}
 	return 0
 }
 
-func (x *CourseDatabaseBatchUpdateCapacity) GetGroupId() uint32 {
+func (x *CourseDatabaseBatchDisenrollMessage) GetCourseId() int32 {
 	if x != nil {
-		return x.GroupId
+		return x.CourseId
 	}
 	return 0
 }
 
-func (x *CourseDatabaseBatchUpdateCapacity) GetNewCapacity() int32 {
+func (x *CourseDatabaseBatchDisenrollMessage) GetConsumesAction() bool {
 	if x != nil {
-		return x.NewCapacity
+		return x.ConsumesAction
 	}
-	return 0
+	return false
 }
 
-func (x *CourseDatabaseBatchUpdateCapacity) GetMovedStudents() []uint64 {
+func (x *CourseDatabaseBatchDisenrollMessage) GetPromotedStudentId() uint64 {
 	if x != nil {
-		return x.MovedStudents
+		return x.PromotedStudentId
 	}
-	return nil
+	return 0
 }
 
-var File_pkg_proto_course_batches_proto protoreflect.FileDescriptor
+func (x *CourseDatabaseBatchDisenrollMessage) GetConfirmationDeadline() int64 {
+	if x != nil {
+		return x.ConfirmationDeadline
+	}
+	return 0
+}
 
-var file_pkg_proto_course_batches_proto_rawDesc = []byte{
-	0x0a, 0x1e, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x75, 0x72,
-	0x73, 0x65, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdd, 0x02, 0x0a, 0x1a, 0x43, 0x6f, 0x75, 0x72,
-	0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x41, 0x0a, 0x06, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
-	0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74,
-	0x63, 0x68, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48,
-	0x00, 0x52, 0x06, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x4a, 0x0a, 0x09, 0x64, 0x69, 0x73,
-	0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62,
-	0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c,
-	0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x09, 0x64, 0x69, 0x73, 0x65,
-	0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x51, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f,
-	0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
-	0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x63, 0x68, 0x61,
-	0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x53, 0x0a, 0x0f, 0x75, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65,
-	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0e, 0x75,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x42, 0x08, 0x0a,
-	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x95, 0x01, 0x0a, 0x20, 0x43, 0x6f, 0x75, 0x72,
-	0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45,
-	0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
-	0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63,
-	0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
-	0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x22,
-	0x61, 0x0a, 0x23, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
-	0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
-	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64,
-	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
-	0x49, 0x64, 0x22, 0x9a, 0x01, 0x0a, 0x25, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74,
-	0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
-	0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63,
-	0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
-	0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x22,
-	0xa5, 0x01, 0x0a, 0x21, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
-	0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61, 0x70,
-	0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
-	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x21, 0x0a,
-	0x0c, 0x6e, 0x65, 0x77, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0b, 0x6e, 0x65, 0x77, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
-	0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
-	0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0d, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x53,
-	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x1c, 0x5a, 0x1a, 0x43, 0x6f, 0x75, 0x72, 0x73,
-	0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *CourseDatabaseBatchDisenrollMessage) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
 }
 
-var (
-	file_pkg_proto_course_batches_proto_rawDescOnce sync.Once
-	file_pkg_proto_course_batches_proto_rawDescData = file_pkg_proto_course_batches_proto_rawDesc
-)
+// CourseDatabaseBatchAddIntent registers the intent of a student to change their group in a course
+// when the destination group has a free seat. It replaces the previous intent of the student in the
+// course. Changing the group or disenrolling from the course removes the intent.
+type CourseDatabaseBatchAddIntent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func file_pkg_proto_course_batches_proto_rawDescGZIP() []byte {
-	file_pkg_proto_course_batches_proto_rawDescOnce.Do(func() {
-		file_pkg_proto_course_batches_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_course_batches_proto_rawDescData)
-	})
-	return file_pkg_proto_course_batches_proto_rawDescData
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	CourseId  int32  `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// The destination group
+	GroupId uint32 `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
 }
 
-var file_pkg_proto_course_batches_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_pkg_proto_course_batches_proto_goTypes = []interface{}{
-	(*CourseDatabaseBatchMessage)(nil),            // 0: proto.CourseDatabaseBatchMessage
-	(*CourseDatabaseBatchEnrollMessage)(nil),      // 1: proto.CourseDatabaseBatchEnrollMessage
-	(*CourseDatabaseBatchDisenrollMessage)(nil),   // 2: proto.CourseDatabaseBatchDisenrollMessage
-	(*CourseDatabaseBatchChangeGroupMessage)(nil), // 3: proto.CourseDatabaseBatchChangeGroupMessage
-	(*CourseDatabaseBatchUpdateCapacity)(nil),     // 4: proto.CourseDatabaseBatchUpdateCapacity
-}
+func (x *CourseDatabaseBatchAddIntent) Reset() {
+	*x = CourseDatabaseBatchAddIntent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchAddIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchAddIntent) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchAddIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchAddIntent.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchAddIntent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CourseDatabaseBatchAddIntent) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchAddIntent) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchAddIntent) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// CourseDatabaseBatchRemoveIntent removes the intent of a student to change their group in a course
+type CourseDatabaseBatchRemoveIntent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	CourseId  int32  `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+}
+
+func (x *CourseDatabaseBatchRemoveIntent) Reset() {
+	*x = CourseDatabaseBatchRemoveIntent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchRemoveIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchRemoveIntent) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchRemoveIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchRemoveIntent.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchRemoveIntent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CourseDatabaseBatchRemoveIntent) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchRemoveIntent) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+type CourseDatabaseBatchChangeGroupMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The student ID which this message is for.
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	// The course which user is trying to change group in.
+	CourseId int32 `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// The group ID which user is trying to change group to.
+	GroupId uint32 `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// True if user is in reserved queue
+	Reserved bool `protobuf:"varint,4,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	// True if this change has used one of the remaining actions of student
+	ConsumesAction bool `protobuf:"varint,5,opt,name=consumes_action,json=consumesAction,proto3" json:"consumes_action,omitempty"`
+	// The student which is moved from the reserve queue of the source group to the freed seat.
+	// Zero means nobody.
+	PromotedStudentId uint64 `protobuf:"varint,6,opt,name=promoted_student_id,json=promotedStudentId,proto3" json:"promoted_student_id,omitempty"`
+	// Until when the promoted student must confirm their seat in unix milliseconds. Zero means
+	// that the course does not need confirmations.
+	ConfirmationDeadline int64 `protobuf:"varint,7,opt,name=confirmation_deadline,json=confirmationDeadline,proto3" json:"confirmation_deadline,omitempty"`
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) Reset() {
+	*x = CourseDatabaseBatchChangeGroupMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchChangeGroupMessage) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchChangeGroupMessage.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchChangeGroupMessage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) GetReserved() bool {
+	if x != nil {
+		return x.Reserved
+	}
+	return false
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) GetConsumesAction() bool {
+	if x != nil {
+		return x.ConsumesAction
+	}
+	return false
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) GetPromotedStudentId() uint64 {
+	if x != nil {
+		return x.PromotedStudentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchChangeGroupMessage) GetConfirmationDeadline() int64 {
+	if x != nil {
+		return x.ConfirmationDeadline
+	}
+	return 0
+}
+
+// CourseDatabaseBatchConfirmPromotion confirms the seat of a student which is promoted from the
+// reserve queue of a course
+type CourseDatabaseBatchConfirmPromotion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	CourseId  int32  `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+}
+
+func (x *CourseDatabaseBatchConfirmPromotion) Reset() {
+	*x = CourseDatabaseBatchConfirmPromotion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchConfirmPromotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchConfirmPromotion) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchConfirmPromotion) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchConfirmPromotion.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchConfirmPromotion) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CourseDatabaseBatchConfirmPromotion) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchConfirmPromotion) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+type CourseDatabaseBatchUpdateCapacity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The course ID to change its capacity
+	CourseId int32 `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// The group ID of the course to change its capacity
+	GroupId uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// The new capacity of this course
+	NewCapacity int32 `protobuf:"varint,3,opt,name=new_capacity,json=newCapacity,proto3" json:"new_capacity,omitempty"`
+	// Users which are moved from reserve queue to main registered users
+	MovedStudents []uint64 `protobuf:"varint,4,rep,packed,name=moved_students,json=movedStudents,proto3" json:"moved_students,omitempty"`
+	// Until when the moved students must confirm their seat in unix milliseconds. Zero means that
+	// the course does not need confirmations.
+	ConfirmationDeadline int64 `protobuf:"varint,5,opt,name=confirmation_deadline,json=confirmationDeadline,proto3" json:"confirmation_deadline,omitempty"`
+}
+
+func (x *CourseDatabaseBatchUpdateCapacity) Reset() {
+	*x = CourseDatabaseBatchUpdateCapacity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchUpdateCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchUpdateCapacity) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchUpdateCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchUpdateCapacity.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchUpdateCapacity) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CourseDatabaseBatchUpdateCapacity) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchUpdateCapacity) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchUpdateCapacity) GetNewCapacity() int32 {
+	if x != nil {
+		return x.NewCapacity
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchUpdateCapacity) GetMovedStudents() []uint64 {
+	if x != nil {
+		return x.MovedStudents
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchUpdateCapacity) GetConfirmationDeadline() int64 {
+	if x != nil {
+		return x.ConfirmationDeadline
+	}
+	return 0
+}
+
+type CourseDatabaseBatchPutStudent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The student ID which is created or updated
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	// The hash of the password. Empty keeps the password of an existing student.
+	PasswordHash string `protobuf:"bytes,2,opt,name=password_hash,json=passwordHash,proto3" json:"password_hash,omitempty"`
+	// When the enrollment of this student starts in unix milliseconds
+	EnrollmentStartTime int64  `protobuf:"varint,3,opt,name=enrollment_start_time,json=enrollmentStartTime,proto3" json:"enrollment_start_time,omitempty"`
+	MaxUnits            uint32 `protobuf:"varint,4,opt,name=max_units,json=maxUnits,proto3" json:"max_units,omitempty"`
+	RemainingActions    uint32 `protobuf:"varint,5,opt,name=remaining_actions,json=remainingActions,proto3" json:"remaining_actions,omitempty"`
+	DepartmentId        uint32 `protobuf:"varint,6,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	EntryYear           int32  `protobuf:"varint,7,opt,name=entry_year,json=entryYear,proto3" json:"entry_year,omitempty"`
+	// Same as course.Sex
+	Sex uint32 `protobuf:"varint,8,opt,name=sex,proto3" json:"sex,omitempty"`
+	// Higher bands get seats earlier in the lottery phases which are ordered by GPA band
+	GpaBand uint32 `protobuf:"varint,9,opt,name=gpa_band,json=gpaBand,proto3" json:"gpa_band,omitempty"`
+}
+
+func (x *CourseDatabaseBatchPutStudent) Reset() {
+	*x = CourseDatabaseBatchPutStudent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchPutStudent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchPutStudent) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchPutStudent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchPutStudent.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchPutStudent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetPasswordHash() string {
+	if x != nil {
+		return x.PasswordHash
+	}
+	return ""
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetEnrollmentStartTime() int64 {
+	if x != nil {
+		return x.EnrollmentStartTime
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetMaxUnits() uint32 {
+	if x != nil {
+		return x.MaxUnits
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetRemainingActions() uint32 {
+	if x != nil {
+		return x.RemainingActions
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetDepartmentId() uint32 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetEntryYear() int32 {
+	if x != nil {
+		return x.EntryYear
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetSex() uint32 {
+	if x != nil {
+		return x.Sex
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutStudent) GetGpaBand() uint32 {
+	if x != nil {
+		return x.GpaBand
+	}
+	return 0
+}
+
+// CourseDatabaseBatchPutWishlist replaces the wishlist of a student. A wishlist without entries
+// removes the wishlist of the student.
+type CourseDatabaseBatchPutWishlist struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId uint64    `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Wishlist  *Wishlist `protobuf:"bytes,2,opt,name=wishlist,proto3" json:"wishlist,omitempty"`
+}
+
+func (x *CourseDatabaseBatchPutWishlist) Reset() {
+	*x = CourseDatabaseBatchPutWishlist{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchPutWishlist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchPutWishlist) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchPutWishlist) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchPutWishlist.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchPutWishlist) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CourseDatabaseBatchPutWishlist) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutWishlist) GetWishlist() *Wishlist {
+	if x != nil {
+		return x.Wishlist
+	}
+	return nil
+}
+
+type CourseDatabaseBatchPutCourse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The course ID which is created or updated
+	CourseId int32 `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// The group ID which is created or updated
+	GroupId         uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	DepartmentId    uint32 `protobuf:"varint,3,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Name            string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Lecturer        string `protobuf:"bytes,5,opt,name=lecturer,proto3" json:"lecturer,omitempty"`
+	Units           uint32 `protobuf:"varint,6,opt,name=units,proto3" json:"units,omitempty"`
+	Capacity        int32  `protobuf:"varint,7,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	ReserveCapacity int32  `protobuf:"varint,8,opt,name=reserve_capacity,json=reserveCapacity,proto3" json:"reserve_capacity,omitempty"`
+	// The exam time in unix epoch (seconds). Zero means no exam.
+	ExamTime int64 `protobuf:"varint,9,opt,name=exam_time,json=examTime,proto3" json:"exam_time,omitempty"`
+	// The raw value of course.ClassTime
+	ClassTime uint32 `protobuf:"varint,10,opt,name=class_time,json=classTime,proto3" json:"class_time,omitempty"`
+	// Same as course.SexLock
+	SexLock uint32 `protobuf:"varint,11,opt,name=sex_lock,json=sexLock,proto3" json:"sex_lock,omitempty"`
+	Notes   string `protobuf:"bytes,12,opt,name=notes,proto3" json:"notes,omitempty"`
+	// Nil means that everyone can take the group
+	Eligibility *CourseEligibility `protobuf:"bytes,13,opt,name=eligibility,proto3" json:"eligibility,omitempty"`
+	// How many seconds the students which are promoted from the reserve queue have to confirm
+	// their seat. Zero means that no confirmation is needed.
+	ConfirmationWindow int64 `protobuf:"varint,14,opt,name=confirmation_window,json=confirmationWindow,proto3" json:"confirmation_window,omitempty"`
+}
+
+func (x *CourseDatabaseBatchPutCourse) Reset() {
+	*x = CourseDatabaseBatchPutCourse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_course_batches_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseDatabaseBatchPutCourse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseDatabaseBatchPutCourse) ProtoMessage() {}
+
+func (x *CourseDatabaseBatchPutCourse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_course_batches_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseDatabaseBatchPutCourse.ProtoReflect.Descriptor instead.
+func (*CourseDatabaseBatchPutCourse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_course_batches_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetDepartmentId() uint32 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetLecturer() string {
+	if x != nil {
+		return x.Lecturer
+	}
+	return ""
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetUnits() uint32 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetReserveCapacity() int32 {
+	if x != nil {
+		return x.ReserveCapacity
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetExamTime() int64 {
+	if x != nil {
+		return x.ExamTime
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetClassTime() uint32 {
+	if x != nil {
+		return x.ClassTime
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetSexLock() uint32 {
+	if x != nil {
+		return x.SexLock
+	}
+	return 0
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetEligibility() *CourseEligibility {
+	if x != nil {
+		return x.Eligibility
+	}
+	return nil
+}
+
+func (x *CourseDatabaseBatchPutCourse) GetConfirmationWindow() int64 {
+	if x != nil {
+		return x.ConfirmationWindow
+	}
+	return 0
+}
+
+var File_pkg_proto_course_batches_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_course_batches_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1b, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x6c, 0x69,
+	0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17,
+	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6c, 0x6f, 0x74, 0x74, 0x65, 0x72,
+	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd6, 0x08, 0x0a, 0x1a, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x41, 0x0a, 0x06, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75,
+	0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52,
+	0x06, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x4a, 0x0a, 0x09, 0x64, 0x69, 0x73, 0x65, 0x6e,
+	0x72, 0x6f, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x09, 0x64, 0x69, 0x73, 0x65, 0x6e, 0x72,
+	0x6f, 0x6c, 0x6c, 0x12, 0x51, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x53, 0x0a, 0x0f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52, 0x0e, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x47, 0x0a, 0x0b, 0x70,
+	0x75, 0x74, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x75, 0x74, 0x53,
+	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x75, 0x74, 0x53, 0x74, 0x75,
+	0x64, 0x65, 0x6e, 0x74, 0x12, 0x44, 0x0a, 0x0a, 0x70, 0x75, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x50, 0x75, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x48, 0x00, 0x52,
+	0x09, 0x70, 0x75, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x70, 0x75,
+	0x74, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d,
+	0x65, 0x6e, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x70,
+	0x75, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x44, 0x0a, 0x0a, 0x61, 0x64,
+	0x64, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x61, 0x64, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x12, 0x4d, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x48,
+	0x00, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12,
+	0x49, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75,
+	0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4a, 0x0a, 0x0c, 0x70, 0x75,
+	0x74, 0x5f, 0x77, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x75, 0x74, 0x57,
+	0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x70, 0x75, 0x74, 0x57, 0x69,
+	0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x47, 0x0a, 0x10, 0x61, 0x64, 0x64, 0x5f, 0x6e, 0x6f,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
+	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0f,
+	0x61, 0x64, 0x64, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x59, 0x0a, 0x11, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x5f, 0x70, 0x72, 0x6f, 0x6d, 0x6f,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x72, 0x6f,
+	0x6d, 0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72,
+	0x6d, 0x50, 0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x35, 0x0a,
+	0x05, 0x61, 0x75, 0x64, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x75, 0x64, 0x69, 0x74, 0x52, 0x05, 0x61,
+	0x75, 0x64, 0x69, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xaf,
+	0x01, 0x0a, 0x18, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x75, 0x64, 0x69, 0x74, 0x12, 0x34, 0x0a, 0x0a, 0x61,
+	0x63, 0x74, 0x6f, 0x72, 0x5f, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x41, 0x63, 0x74,
+	0x6f, 0x72, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x09, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x07, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x22, 0x5f, 0x0a, 0x1e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75,
+	0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x73, 0x22, 0x95, 0x01, 0x0a, 0x20, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61,
+	0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x22, 0x8a, 0x02, 0x0a, 0x23, 0x43, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x27, 0x0a,
+	0x0f, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x73, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x73,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x13, 0x70, 0x72, 0x6f, 0x6d, 0x6f, 0x74,
+	0x65, 0x64, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x11, 0x70, 0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x64, 0x53, 0x74, 0x75,
+	0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x33, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72,
+	0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0x75, 0x0a, 0x1c, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x41, 0x64, 0x64,
+	0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0x5d, 0x0a,
+	0x1f, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x22, 0xa8, 0x02, 0x0a,
+	0x25, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e,
+	0x73, 0x75, 0x6d, 0x65, 0x73, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x73, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x13, 0x70, 0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x64, 0x5f, 0x73,
+	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x11, 0x70, 0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x65, 0x64, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x33, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x61, 0x0a, 0x23, 0x43, 0x6f, 0x75, 0x72, 0x73,
+	0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x72, 0x6f, 0x6d, 0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x22, 0xda, 0x01, 0x0a, 0x21, 0x43,
+	0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a,
+	0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x65, 0x77, 0x5f,
+	0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
+	0x6e, 0x65, 0x77, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x04, 0x52, 0x0d, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x33, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0xd2, 0x02, 0x0a, 0x1d, 0x43, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50,
+	0x75, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75,
+	0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73,
+	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x32, 0x0a,
+	0x15, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x65, 0x6e,
+	0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x2b,
+	0x0a, 0x11, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x72, 0x65, 0x6d, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x64,
+	0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x59, 0x65, 0x61, 0x72, 0x12,
+	0x10, 0x0a, 0x03, 0x73, 0x65, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x73, 0x65,
+	0x78, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x70, 0x61, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x70, 0x61, 0x42, 0x61, 0x6e, 0x64, 0x22, 0x6c, 0x0a, 0x1e,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x50, 0x75, 0x74, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2b, 0x0a,
+	0x08, 0x77, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74,
+	0x52, 0x08, 0x77, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x22, 0xe2, 0x03, 0x0a, 0x1c, 0x43,
+	0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x50, 0x75, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63,
+	0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61,
+	0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x6c, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6c, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x43, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x61, 0x6d, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x65, 0x78, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x73, 0x65, 0x78, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x74,
+	0x65, 0x73, 0x12, 0x3a, 0x0a, 0x0b, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x52, 0x0b, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x2f,
+	0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x2a,
+	0x58, 0x0a, 0x0e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x41, 0x63, 0x74, 0x6f, 0x72, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x4f, 0x52,
+	0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x55, 0x44,
+	0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x4f, 0x52, 0x5f, 0x53, 0x54, 0x55, 0x44, 0x45, 0x4e, 0x54,
+	0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x41, 0x43, 0x54, 0x4f,
+	0x52, 0x5f, 0x53, 0x54, 0x41, 0x46, 0x46, 0x10, 0x02, 0x42, 0x1c, 0x5a, 0x1a, 0x43, 0x6f, 0x75,
+	0x72, 0x73, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_course_batches_proto_rawDescOnce sync.Once
+	file_pkg_proto_course_batches_proto_rawDescData = file_pkg_proto_course_batches_proto_rawDesc
+)
+
+func file_pkg_proto_course_batches_proto_rawDescGZIP() []byte {
+	file_pkg_proto_course_batches_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_course_batches_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_course_batches_proto_rawDescData)
+	})
+	return file_pkg_proto_course_batches_proto_rawDescData
+}
+
+var file_pkg_proto_course_batches_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_proto_course_batches_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_pkg_proto_course_batches_proto_goTypes = []interface{}{
+	(AuditActorKind)(0),                           // 0: proto.AuditActorKind
+	(*CourseDatabaseBatchMessage)(nil),            // 1: proto.CourseDatabaseBatchMessage
+	(*CourseDatabaseBatchAudit)(nil),              // 2: proto.CourseDatabaseBatchAudit
+	(*CourseDatabaseBatchTransaction)(nil),        // 3: proto.CourseDatabaseBatchTransaction
+	(*CourseDatabaseBatchEnrollMessage)(nil),      // 4: proto.CourseDatabaseBatchEnrollMessage
+	(*CourseDatabaseBatchDisenrollMessage)(nil),   // 5: proto.CourseDatabaseBatchDisenrollMessage
+	(*CourseDatabaseBatchAddIntent)(nil),          // 6: proto.CourseDatabaseBatchAddIntent
+	(*CourseDatabaseBatchRemoveIntent)(nil),       // 7: proto.CourseDatabaseBatchRemoveIntent
+	(*CourseDatabaseBatchChangeGroupMessage)(nil), // 8: proto.CourseDatabaseBatchChangeGroupMessage
+	(*CourseDatabaseBatchConfirmPromotion)(nil),   // 9: proto.CourseDatabaseBatchConfirmPromotion
+	(*CourseDatabaseBatchUpdateCapacity)(nil),     // 10: proto.CourseDatabaseBatchUpdateCapacity
+	(*CourseDatabaseBatchPutStudent)(nil),         // 11: proto.CourseDatabaseBatchPutStudent
+	(*CourseDatabaseBatchPutWishlist)(nil),        // 12: proto.CourseDatabaseBatchPutWishlist
+	(*CourseDatabaseBatchPutCourse)(nil),          // 13: proto.CourseDatabaseBatchPutCourse
+	(*EnrollmentSchedule)(nil),                    // 14: proto.EnrollmentSchedule
+	(*StudentNotification)(nil),                   // 15: proto.StudentNotification
+	(*Wishlist)(nil),                              // 16: proto.Wishlist
+	(*CourseEligibility)(nil),                     // 17: proto.CourseEligibility
+}
 var file_pkg_proto_course_batches_proto_depIdxs = []int32{
-	1, // 0: proto.CourseDatabaseBatchMessage.enroll:type_name -> proto.CourseDatabaseBatchEnrollMessage
-	2, // 1: proto.CourseDatabaseBatchMessage.disenroll:type_name -> proto.CourseDatabaseBatchDisenrollMessage
-	3, // 2: proto.CourseDatabaseBatchMessage.change_group:type_name -> proto.CourseDatabaseBatchChangeGroupMessage
-	4, // 3: proto.CourseDatabaseBatchMessage.update_capacity:type_name -> proto.CourseDatabaseBatchUpdateCapacity
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	4,  // 0: proto.CourseDatabaseBatchMessage.enroll:type_name -> proto.CourseDatabaseBatchEnrollMessage
+	5,  // 1: proto.CourseDatabaseBatchMessage.disenroll:type_name -> proto.CourseDatabaseBatchDisenrollMessage
+	8,  // 2: proto.CourseDatabaseBatchMessage.change_group:type_name -> proto.CourseDatabaseBatchChangeGroupMessage
+	10, // 3: proto.CourseDatabaseBatchMessage.update_capacity:type_name -> proto.CourseDatabaseBatchUpdateCapacity
+	11, // 4: proto.CourseDatabaseBatchMessage.put_student:type_name -> proto.CourseDatabaseBatchPutStudent
+	13, // 5: proto.CourseDatabaseBatchMessage.put_course:type_name -> proto.CourseDatabaseBatchPutCourse
+	14, // 6: proto.CourseDatabaseBatchMessage.put_schedule:type_name -> proto.EnrollmentSchedule
+	6,  // 7: proto.CourseDatabaseBatchMessage.add_intent:type_name -> proto.CourseDatabaseBatchAddIntent
+	7,  // 8: proto.CourseDatabaseBatchMessage.remove_intent:type_name -> proto.CourseDatabaseBatchRemoveIntent
+	3,  // 9: proto.CourseDatabaseBatchMessage.transaction:type_name -> proto.CourseDatabaseBatchTransaction
+	12, // 10: proto.CourseDatabaseBatchMessage.put_wishlist:type_name -> proto.CourseDatabaseBatchPutWishlist
+	15, // 11: proto.CourseDatabaseBatchMessage.add_notification:type_name -> proto.StudentNotification
+	9,  // 12: proto.CourseDatabaseBatchMessage.confirm_promotion:type_name -> proto.CourseDatabaseBatchConfirmPromotion
+	2,  // 13: proto.CourseDatabaseBatchMessage.audit:type_name -> proto.CourseDatabaseBatchAudit
+	0,  // 14: proto.CourseDatabaseBatchAudit.actor_kind:type_name -> proto.AuditActorKind
+	1,  // 15: proto.CourseDatabaseBatchTransaction.messages:type_name -> proto.CourseDatabaseBatchMessage
+	16, // 16: proto.CourseDatabaseBatchPutWishlist.wishlist:type_name -> proto.Wishlist
+	17, // 17: proto.CourseDatabaseBatchPutCourse.eligibility:type_name -> proto.CourseEligibility
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_course_batches_proto_init() }
@@ -516,6 +1640,10 @@ func file_pkg_proto_course_batches_proto_init() {
 	if File_pkg_proto_course_batches_proto != nil {
 		return
 	}
+	file_pkg_proto_schedule_proto_init()
+	file_pkg_proto_eligibility_proto_init()
+	file_pkg_proto_lottery_proto_init()
+	file_pkg_proto_notification_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_pkg_proto_course_batches_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CourseDatabaseBatchMessage); i {
@@ -530,7 +1658,7 @@ func file_pkg_proto_course_batches_proto_init() {
 			}
 		}
 		file_pkg_proto_course_batches_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CourseDatabaseBatchEnrollMessage); i {
+			switch v := v.(*CourseDatabaseBatchAudit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -542,7 +1670,7 @@ func file_pkg_proto_course_batches_proto_init() {
 			}
 		}
 		file_pkg_proto_course_batches_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CourseDatabaseBatchDisenrollMessage); i {
+			switch v := v.(*CourseDatabaseBatchTransaction); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -554,7 +1682,7 @@ func file_pkg_proto_course_batches_proto_init() {
 			}
 		}
 		file_pkg_proto_course_batches_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CourseDatabaseBatchChangeGroupMessage); i {
+			switch v := v.(*CourseDatabaseBatchEnrollMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -566,6 +1694,66 @@ func file_pkg_proto_course_batches_proto_init() {
 			}
 		}
 		file_pkg_proto_course_batches_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchDisenrollMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_course_batches_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchAddIntent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_course_batches_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchRemoveIntent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_course_batches_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchChangeGroupMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_course_batches_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchConfirmPromotion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_course_batches_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CourseDatabaseBatchUpdateCapacity); i {
 			case 0:
 				return &v.state
@@ -577,25 +1765,71 @@ func file_pkg_proto_course_batches_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_proto_course_batches_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchPutStudent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_course_batches_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchPutWishlist); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_course_batches_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseDatabaseBatchPutCourse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_pkg_proto_course_batches_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*CourseDatabaseBatchMessage_Enroll)(nil),
 		(*CourseDatabaseBatchMessage_Disenroll)(nil),
 		(*CourseDatabaseBatchMessage_ChangeGroup)(nil),
 		(*CourseDatabaseBatchMessage_UpdateCapacity)(nil),
+		(*CourseDatabaseBatchMessage_PutStudent)(nil),
+		(*CourseDatabaseBatchMessage_PutCourse)(nil),
+		(*CourseDatabaseBatchMessage_PutSchedule)(nil),
+		(*CourseDatabaseBatchMessage_AddIntent)(nil),
+		(*CourseDatabaseBatchMessage_RemoveIntent)(nil),
+		(*CourseDatabaseBatchMessage_Transaction)(nil),
+		(*CourseDatabaseBatchMessage_PutWishlist)(nil),
+		(*CourseDatabaseBatchMessage_AddNotification)(nil),
+		(*CourseDatabaseBatchMessage_ConfirmPromotion)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_course_batches_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_pkg_proto_course_batches_proto_goTypes,
 		DependencyIndexes: file_pkg_proto_course_batches_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_course_batches_proto_enumTypes,
 		MessageInfos:      file_pkg_proto_course_batches_proto_msgTypes,
 	}.Build()
 	File_pkg_proto_course_batches_proto = out.File
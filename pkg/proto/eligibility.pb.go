@@ -0,0 +1,193 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.9
+// source: pkg/proto/eligibility.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CourseEligibility is the set of rules which decide who can take a course group besides its
+// sex lock. Empty fields do not restrict anyone.
+type CourseEligibility struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The departments which can take the group
+	Departments []uint32 `protobuf:"varint,1,rep,packed,name=departments,proto3" json:"departments,omitempty"`
+	// The range of entry years which can take the group. Both ends are inclusive.
+	MinEntryYear int32 `protobuf:"varint,2,opt,name=min_entry_year,json=minEntryYear,proto3" json:"min_entry_year,omitempty"`
+	MaxEntryYear int32 `protobuf:"varint,3,opt,name=max_entry_year,json=maxEntryYear,proto3" json:"max_entry_year,omitempty"`
+	// If not empty, only these students can take the group
+	AllowedStudents []uint64 `protobuf:"varint,4,rep,packed,name=allowed_students,json=allowedStudents,proto3" json:"allowed_students,omitempty"`
+	// These students cannot take the group
+	DeniedStudents []uint64 `protobuf:"varint,5,rep,packed,name=denied_students,json=deniedStudents,proto3" json:"denied_students,omitempty"`
+}
+
+func (x *CourseEligibility) Reset() {
+	*x = CourseEligibility{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_eligibility_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseEligibility) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseEligibility) ProtoMessage() {}
+
+func (x *CourseEligibility) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_eligibility_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseEligibility.ProtoReflect.Descriptor instead.
+func (*CourseEligibility) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_eligibility_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CourseEligibility) GetDepartments() []uint32 {
+	if x != nil {
+		return x.Departments
+	}
+	return nil
+}
+
+func (x *CourseEligibility) GetMinEntryYear() int32 {
+	if x != nil {
+		return x.MinEntryYear
+	}
+	return 0
+}
+
+func (x *CourseEligibility) GetMaxEntryYear() int32 {
+	if x != nil {
+		return x.MaxEntryYear
+	}
+	return 0
+}
+
+func (x *CourseEligibility) GetAllowedStudents() []uint64 {
+	if x != nil {
+		return x.AllowedStudents
+	}
+	return nil
+}
+
+func (x *CourseEligibility) GetDeniedStudents() []uint64 {
+	if x != nil {
+		return x.DeniedStudents
+	}
+	return nil
+}
+
+var File_pkg_proto_eligibility_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_eligibility_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x6c, 0x69, 0x67,
+	0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd5, 0x01, 0x0a, 0x11, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45,
+	0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52,
+	0x0b, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0e,
+	0x6d, 0x69, 0x6e, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x59, 0x65,
+	0x61, 0x72, 0x12, 0x24, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f,
+	0x79, 0x65, 0x61, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x59, 0x65, 0x61, 0x72, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x04, 0x52, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x53, 0x74, 0x75, 0x64, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x5f, 0x73, 0x74,
+	0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0e, 0x64, 0x65,
+	0x6e, 0x69, 0x65, 0x64, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x1c, 0x5a, 0x1a,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_eligibility_proto_rawDescOnce sync.Once
+	file_pkg_proto_eligibility_proto_rawDescData = file_pkg_proto_eligibility_proto_rawDesc
+)
+
+func file_pkg_proto_eligibility_proto_rawDescGZIP() []byte {
+	file_pkg_proto_eligibility_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_eligibility_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_eligibility_proto_rawDescData)
+	})
+	return file_pkg_proto_eligibility_proto_rawDescData
+}
+
+var file_pkg_proto_eligibility_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_proto_eligibility_proto_goTypes = []interface{}{
+	(*CourseEligibility)(nil), // 0: proto.CourseEligibility
+}
+var file_pkg_proto_eligibility_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_eligibility_proto_init() }
+func file_pkg_proto_eligibility_proto_init() {
+	if File_pkg_proto_eligibility_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_eligibility_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseEligibility); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_eligibility_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_eligibility_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_eligibility_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_eligibility_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_eligibility_proto = out.File
+	file_pkg_proto_eligibility_proto_rawDesc = nil
+	file_pkg_proto_eligibility_proto_goTypes = nil
+	file_pkg_proto_eligibility_proto_depIdxs = nil
+}
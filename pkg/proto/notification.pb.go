@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.9
+// source: pkg/proto/notification.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Same as course.EventKind
+type StudentNotification_Kind int32
+
+const (
+	StudentNotification_UNKNOWN StudentNotification_Kind = 0
+	// The student is moved from the reserve queue of the group to its registered students
+	StudentNotification_PROMOTED StudentNotification_Kind = 1
+	// A staff has enrolled the student in the group
+	StudentNotification_FORCE_ENROLLED StudentNotification_Kind = 2
+	// A staff has dropped the student from the group
+	StudentNotification_FORCE_DISENROLLED StudentNotification_Kind = 3
+	// The capacity of the group has changed while the student is in its reserve queue
+	StudentNotification_CAPACITY_CHANGED StudentNotification_Kind = 4
+	// The student did not confirm their promotion in time and is dropped from the group
+	StudentNotification_PROMOTION_EXPIRED StudentNotification_Kind = 5
+)
+
+// Enum value maps for StudentNotification_Kind.
+var (
+	StudentNotification_Kind_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "PROMOTED",
+		2: "FORCE_ENROLLED",
+		3: "FORCE_DISENROLLED",
+		4: "CAPACITY_CHANGED",
+		5: "PROMOTION_EXPIRED",
+	}
+	StudentNotification_Kind_value = map[string]int32{
+		"UNKNOWN":           0,
+		"PROMOTED":          1,
+		"FORCE_ENROLLED":    2,
+		"FORCE_DISENROLLED": 3,
+		"CAPACITY_CHANGED":  4,
+		"PROMOTION_EXPIRED": 5,
+	}
+)
+
+func (x StudentNotification_Kind) Enum() *StudentNotification_Kind {
+	p := new(StudentNotification_Kind)
+	*p = x
+	return p
+}
+
+func (x StudentNotification_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StudentNotification_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_notification_proto_enumTypes[0].Descriptor()
+}
+
+func (StudentNotification_Kind) Type() protoreflect.EnumType {
+	return &file_pkg_proto_notification_proto_enumTypes[0]
+}
+
+func (x StudentNotification_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StudentNotification_Kind.Descriptor instead.
+func (StudentNotification_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_notification_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// StudentNotification is something which has happened to a student that they should be told about
+type StudentNotification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind      StudentNotification_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=proto.StudentNotification_Kind" json:"kind,omitempty"`
+	StudentId uint64                   `protobuf:"varint,2,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	CourseId  int32                    `protobuf:"varint,3,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId   uint32                   `protobuf:"varint,4,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// The capacity of the group after the event
+	Capacity int32 `protobuf:"varint,5,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// The position of the student in the reserve queue after the event. Zero means registered.
+	ReservePosition uint32 `protobuf:"varint,6,opt,name=reserve_position,json=reservePosition,proto3" json:"reserve_position,omitempty"`
+	// When the event has happened in unix milliseconds
+	Time int64 `protobuf:"varint,7,opt,name=time,proto3" json:"time,omitempty"`
+	// If not zero, the student must confirm their seat before this time in unix milliseconds
+	ConfirmationDeadline int64 `protobuf:"varint,8,opt,name=confirmation_deadline,json=confirmationDeadline,proto3" json:"confirmation_deadline,omitempty"`
+}
+
+func (x *StudentNotification) Reset() {
+	*x = StudentNotification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_notification_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentNotification) ProtoMessage() {}
+
+func (x *StudentNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_notification_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentNotification.ProtoReflect.Descriptor instead.
+func (*StudentNotification) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_notification_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StudentNotification) GetKind() StudentNotification_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return StudentNotification_UNKNOWN
+}
+
+func (x *StudentNotification) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *StudentNotification) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *StudentNotification) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *StudentNotification) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *StudentNotification) GetReservePosition() uint32 {
+	if x != nil {
+		return x.ReservePosition
+	}
+	return 0
+}
+
+func (x *StudentNotification) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *StudentNotification) GetConfirmationDeadline() int64 {
+	if x != nil {
+		return x.ConfirmationDeadline
+	}
+	return 0
+}
+
+var File_pkg_proto_notification_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_notification_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x6f, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xac, 0x03, 0x0a, 0x13, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x4e, 0x6f, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69,
+	0x6e, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19,
+	0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x79, 0x0a, 0x04, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0c,
+	0x0a, 0x08, 0x50, 0x52, 0x4f, 0x4d, 0x4f, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e,
+	0x46, 0x4f, 0x52, 0x43, 0x45, 0x5f, 0x45, 0x4e, 0x52, 0x4f, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x02,
+	0x12, 0x15, 0x0a, 0x11, 0x46, 0x4f, 0x52, 0x43, 0x45, 0x5f, 0x44, 0x49, 0x53, 0x45, 0x4e, 0x52,
+	0x4f, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x41, 0x50, 0x41, 0x43,
+	0x49, 0x54, 0x59, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x04, 0x12, 0x15, 0x0a,
+	0x11, 0x50, 0x52, 0x4f, 0x4d, 0x4f, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52,
+	0x45, 0x44, 0x10, 0x05, 0x42, 0x1c, 0x5a, 0x1a, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6e,
+	0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_notification_proto_rawDescOnce sync.Once
+	file_pkg_proto_notification_proto_rawDescData = file_pkg_proto_notification_proto_rawDesc
+)
+
+func file_pkg_proto_notification_proto_rawDescGZIP() []byte {
+	file_pkg_proto_notification_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_notification_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_notification_proto_rawDescData)
+	})
+	return file_pkg_proto_notification_proto_rawDescData
+}
+
+var file_pkg_proto_notification_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_proto_notification_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_proto_notification_proto_goTypes = []interface{}{
+	(StudentNotification_Kind)(0), // 0: proto.StudentNotification.Kind
+	(*StudentNotification)(nil),   // 1: proto.StudentNotification
+}
+var file_pkg_proto_notification_proto_depIdxs = []int32{
+	0, // 0: proto.StudentNotification.kind:type_name -> proto.StudentNotification.Kind
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_notification_proto_init() }
+func file_pkg_proto_notification_proto_init() {
+	if File_pkg_proto_notification_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_notification_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudentNotification); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_notification_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_notification_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_notification_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_notification_proto_enumTypes,
+		MessageInfos:      file_pkg_proto_notification_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_notification_proto = out.File
+	file_pkg_proto_notification_proto_rawDesc = nil
+	file_pkg_proto_notification_proto_goTypes = nil
+	file_pkg_proto_notification_proto_depIdxs = nil
+}
@@ -0,0 +1,452 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.9
+// source: pkg/proto/reconcile.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The side which is changed to match the other one while reconciling
+type ReconcileRepair int32
+
+const (
+	// Only report the differences
+	ReconcileRepair_REPAIR_NONE ReconcileRepair = 0
+	// Change the database to match the in-memory state
+	ReconcileRepair_REPAIR_DATABASE ReconcileRepair = 1
+	// Change the in-memory state to match the database
+	ReconcileRepair_REPAIR_MEMORY ReconcileRepair = 2
+)
+
+// Enum value maps for ReconcileRepair.
+var (
+	ReconcileRepair_name = map[int32]string{
+		0: "REPAIR_NONE",
+		1: "REPAIR_DATABASE",
+		2: "REPAIR_MEMORY",
+	}
+	ReconcileRepair_value = map[string]int32{
+		"REPAIR_NONE":     0,
+		"REPAIR_DATABASE": 1,
+		"REPAIR_MEMORY":   2,
+	}
+)
+
+func (x ReconcileRepair) Enum() *ReconcileRepair {
+	p := new(ReconcileRepair)
+	*p = x
+	return p
+}
+
+func (x ReconcileRepair) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReconcileRepair) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_reconcile_proto_enumTypes[0].Descriptor()
+}
+
+func (ReconcileRepair) Type() protoreflect.EnumType {
+	return &file_pkg_proto_reconcile_proto_enumTypes[0]
+}
+
+func (x ReconcileRepair) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReconcileRepair.Descriptor instead.
+func (ReconcileRepair) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_reconcile_proto_rawDescGZIP(), []int{0}
+}
+
+// The kind of a difference between the in-memory state and the database
+type ReconcileDifferenceKind int32
+
+const (
+	// The student is in the course in memory but not in the database
+	ReconcileDifferenceKind_MISSING_ROW ReconcileDifferenceKind = 0
+	// The student is in the course in the database but not in memory
+	ReconcileDifferenceKind_EXTRA_ROW ReconcileDifferenceKind = 1
+	// The reserved flag of the row does not match the in-memory state
+	ReconcileDifferenceKind_WRONG_RESERVED ReconcileDifferenceKind = 2
+	// The order of the reserved rows by their id differs from the reserve queue
+	ReconcileDifferenceKind_RESERVE_ORDER ReconcileDifferenceKind = 3
+	// The student has more than one row in the course in the database
+	ReconcileDifferenceKind_DUPLICATE_ROW ReconcileDifferenceKind = 4
+)
+
+// Enum value maps for ReconcileDifferenceKind.
+var (
+	ReconcileDifferenceKind_name = map[int32]string{
+		0: "MISSING_ROW",
+		1: "EXTRA_ROW",
+		2: "WRONG_RESERVED",
+		3: "RESERVE_ORDER",
+		4: "DUPLICATE_ROW",
+	}
+	ReconcileDifferenceKind_value = map[string]int32{
+		"MISSING_ROW":    0,
+		"EXTRA_ROW":      1,
+		"WRONG_RESERVED": 2,
+		"RESERVE_ORDER":  3,
+		"DUPLICATE_ROW":  4,
+	}
+)
+
+func (x ReconcileDifferenceKind) Enum() *ReconcileDifferenceKind {
+	p := new(ReconcileDifferenceKind)
+	*p = x
+	return p
+}
+
+func (x ReconcileDifferenceKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReconcileDifferenceKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_reconcile_proto_enumTypes[1].Descriptor()
+}
+
+func (ReconcileDifferenceKind) Type() protoreflect.EnumType {
+	return &file_pkg_proto_reconcile_proto_enumTypes[1]
+}
+
+func (x ReconcileDifferenceKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReconcileDifferenceKind.Descriptor instead.
+func (ReconcileDifferenceKind) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_reconcile_proto_rawDescGZIP(), []int{1}
+}
+
+// The request to compare the in-memory state with the database
+type ReconcileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Repair ReconcileRepair `protobuf:"varint,1,opt,name=repair,proto3,enum=proto.ReconcileRepair" json:"repair,omitempty"`
+}
+
+func (x *ReconcileRequest) Reset() {
+	*x = ReconcileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_reconcile_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconcileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileRequest) ProtoMessage() {}
+
+func (x *ReconcileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_reconcile_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileRequest.ProtoReflect.Descriptor instead.
+func (*ReconcileRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_reconcile_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReconcileRequest) GetRepair() ReconcileRepair {
+	if x != nil {
+		return x.Repair
+	}
+	return ReconcileRepair_REPAIR_NONE
+}
+
+// A single difference between the in-memory state and the database
+type ReconcileDifference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CourseId int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId  uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// Zero for RESERVE_ORDER
+	StudentId uint64                  `protobuf:"varint,3,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	Kind      ReconcileDifferenceKind `protobuf:"varint,4,opt,name=kind,proto3,enum=proto.ReconcileDifferenceKind" json:"kind,omitempty"`
+}
+
+func (x *ReconcileDifference) Reset() {
+	*x = ReconcileDifference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_reconcile_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconcileDifference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileDifference) ProtoMessage() {}
+
+func (x *ReconcileDifference) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_reconcile_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileDifference.ProtoReflect.Descriptor instead.
+func (*ReconcileDifference) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_reconcile_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReconcileDifference) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *ReconcileDifference) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *ReconcileDifference) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *ReconcileDifference) GetKind() ReconcileDifferenceKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ReconcileDifferenceKind_MISSING_ROW
+}
+
+// The result of reconciliation
+type ReconcileResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Differences []*ReconcileDifference `protobuf:"bytes,1,rep,name=differences,proto3" json:"differences,omitempty"`
+	// True if the differences are repaired
+	Repaired bool `protobuf:"varint,2,opt,name=repaired,proto3" json:"repaired,omitempty"`
+}
+
+func (x *ReconcileResponse) Reset() {
+	*x = ReconcileResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_reconcile_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconcileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileResponse) ProtoMessage() {}
+
+func (x *ReconcileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_reconcile_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileResponse.ProtoReflect.Descriptor instead.
+func (*ReconcileResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_reconcile_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReconcileResponse) GetDifferences() []*ReconcileDifference {
+	if x != nil {
+		return x.Differences
+	}
+	return nil
+}
+
+func (x *ReconcileResponse) GetRepaired() bool {
+	if x != nil {
+		return x.Repaired
+	}
+	return false
+}
+
+var File_pkg_proto_reconcile_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_reconcile_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x63, 0x6f,
+	0x6e, 0x63, 0x69, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x42, 0x0a, 0x10, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x61, 0x69, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x52, 0x06,
+	0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x22, 0xa0, 0x01, 0x0a, 0x13, 0x52, 0x65, 0x63, 0x6f, 0x6e,
+	0x63, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x63, 0x6f,
+	0x6e, 0x63, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x4b,
+	0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x6d, 0x0a, 0x11, 0x52, 0x65, 0x63,
+	0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c,
+	0x0a, 0x0b, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x63, 0x6f,
+	0x6e, 0x63, 0x69, 0x6c, 0x65, 0x44, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52,
+	0x0b, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x65, 0x64, 0x2a, 0x4a, 0x0a, 0x0f, 0x52, 0x65, 0x63, 0x6f,
+	0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x12, 0x0f, 0x0a, 0x0b, 0x52,
+	0x45, 0x50, 0x41, 0x49, 0x52, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f,
+	0x52, 0x45, 0x50, 0x41, 0x49, 0x52, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x42, 0x41, 0x53, 0x45, 0x10,
+	0x01, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x45, 0x50, 0x41, 0x49, 0x52, 0x5f, 0x4d, 0x45, 0x4d, 0x4f,
+	0x52, 0x59, 0x10, 0x02, 0x2a, 0x73, 0x0a, 0x17, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c,
+	0x65, 0x44, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x0f, 0x0a, 0x0b, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x4f, 0x57, 0x10, 0x00,
+	0x12, 0x0d, 0x0a, 0x09, 0x45, 0x58, 0x54, 0x52, 0x41, 0x5f, 0x52, 0x4f, 0x57, 0x10, 0x01, 0x12,
+	0x12, 0x0a, 0x0e, 0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45,
+	0x44, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x5f, 0x4f,
+	0x52, 0x44, 0x45, 0x52, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x44, 0x55, 0x50, 0x4c, 0x49, 0x43,
+	0x41, 0x54, 0x45, 0x5f, 0x52, 0x4f, 0x57, 0x10, 0x04, 0x42, 0x1c, 0x5a, 0x1a, 0x43, 0x6f, 0x75,
+	0x72, 0x73, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_reconcile_proto_rawDescOnce sync.Once
+	file_pkg_proto_reconcile_proto_rawDescData = file_pkg_proto_reconcile_proto_rawDesc
+)
+
+func file_pkg_proto_reconcile_proto_rawDescGZIP() []byte {
+	file_pkg_proto_reconcile_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_reconcile_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_reconcile_proto_rawDescData)
+	})
+	return file_pkg_proto_reconcile_proto_rawDescData
+}
+
+var file_pkg_proto_reconcile_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pkg_proto_reconcile_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_proto_reconcile_proto_goTypes = []interface{}{
+	(ReconcileRepair)(0),         // 0: proto.ReconcileRepair
+	(ReconcileDifferenceKind)(0), // 1: proto.ReconcileDifferenceKind
+	(*ReconcileRequest)(nil),     // 2: proto.ReconcileRequest
+	(*ReconcileDifference)(nil),  // 3: proto.ReconcileDifference
+	(*ReconcileResponse)(nil),    // 4: proto.ReconcileResponse
+}
+var file_pkg_proto_reconcile_proto_depIdxs = []int32{
+	0, // 0: proto.ReconcileRequest.repair:type_name -> proto.ReconcileRepair
+	1, // 1: proto.ReconcileDifference.kind:type_name -> proto.ReconcileDifferenceKind
+	3, // 2: proto.ReconcileResponse.differences:type_name -> proto.ReconcileDifference
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_reconcile_proto_init() }
+func file_pkg_proto_reconcile_proto_init() {
+	if File_pkg_proto_reconcile_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_reconcile_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconcileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_reconcile_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconcileDifference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_reconcile_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconcileResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_reconcile_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_reconcile_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_reconcile_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_reconcile_proto_enumTypes,
+		MessageInfos:      file_pkg_proto_reconcile_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_reconcile_proto = out.File
+	file_pkg_proto_reconcile_proto_rawDesc = nil
+	file_pkg_proto_reconcile_proto_goTypes = nil
+	file_pkg_proto_reconcile_proto_depIdxs = nil
+}
@@ -0,0 +1,459 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.9
+// source: pkg/proto/schedule.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The criteria which order the students of a lottery phase
+type EnrollmentPhase_Priority int32
+
+const (
+	// Earlier entry years first
+	EnrollmentPhase_SENIORITY EnrollmentPhase_Priority = 0
+	// Higher GPA bands first
+	EnrollmentPhase_GPA_BAND EnrollmentPhase_Priority = 1
+)
+
+// Enum value maps for EnrollmentPhase_Priority.
+var (
+	EnrollmentPhase_Priority_name = map[int32]string{
+		0: "SENIORITY",
+		1: "GPA_BAND",
+	}
+	EnrollmentPhase_Priority_value = map[string]int32{
+		"SENIORITY": 0,
+		"GPA_BAND":  1,
+	}
+)
+
+func (x EnrollmentPhase_Priority) Enum() *EnrollmentPhase_Priority {
+	p := new(EnrollmentPhase_Priority)
+	*p = x
+	return p
+}
+
+func (x EnrollmentPhase_Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EnrollmentPhase_Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_schedule_proto_enumTypes[0].Descriptor()
+}
+
+func (EnrollmentPhase_Priority) Type() protoreflect.EnumType {
+	return &file_pkg_proto_schedule_proto_enumTypes[0]
+}
+
+func (x EnrollmentPhase_Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EnrollmentPhase_Priority.Descriptor instead.
+func (EnrollmentPhase_Priority) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_schedule_proto_rawDescGZIP(), []int{1, 0}
+}
+
+// EnrollmentSchedule is the list of enrollment phases. If there is no phase, each student can
+// do everything for an hour after their enrollment start time.
+type EnrollmentSchedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Phases []*EnrollmentPhase `protobuf:"bytes,1,rep,name=phases,proto3" json:"phases,omitempty"`
+}
+
+func (x *EnrollmentSchedule) Reset() {
+	*x = EnrollmentSchedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_schedule_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrollmentSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollmentSchedule) ProtoMessage() {}
+
+func (x *EnrollmentSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_schedule_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollmentSchedule.ProtoReflect.Descriptor instead.
+func (*EnrollmentSchedule) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EnrollmentSchedule) GetPhases() []*EnrollmentPhase {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+// EnrollmentPhase is a period of enrollment like main registration, add/drop or late registration
+type EnrollmentPhase struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique name for this phase
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The actions which students can do in this phase
+	AllowEnroll      bool `protobuf:"varint,2,opt,name=allow_enroll,json=allowEnroll,proto3" json:"allow_enroll,omitempty"`
+	AllowDisenroll   bool `protobuf:"varint,3,opt,name=allow_disenroll,json=allowDisenroll,proto3" json:"allow_disenroll,omitempty"`
+	AllowChangeGroup bool `protobuf:"varint,4,opt,name=allow_change_group,json=allowChangeGroup,proto3" json:"allow_change_group,omitempty"`
+	// If not zero, each student can only act for this many milliseconds after their enrollment start
+	// time in this phase.
+	StudentDuration int64 `protobuf:"varint,5,opt,name=student_duration,json=studentDuration,proto3" json:"student_duration,omitempty"`
+	// The windows of cohorts in this phase. Students can act when they are in any window which
+	// matches their cohort.
+	Windows []*EnrollmentWindow `protobuf:"bytes,6,rep,name=windows,proto3" json:"windows,omitempty"`
+	// In a lottery phase, students submit wishlists in the windows instead of acting directly, and
+	// the seats are allocated when all windows are closed. A lottery phase cannot allow actions.
+	Lottery bool `protobuf:"varint,7,opt,name=lottery,proto3" json:"lottery,omitempty"`
+	// The students of a lottery phase are ordered by these criteria in order. The ties are broken
+	// randomly based on the seed.
+	Priorities []EnrollmentPhase_Priority `protobuf:"varint,8,rep,packed,name=priorities,proto3,enum=proto.EnrollmentPhase_Priority" json:"priorities,omitempty"`
+	Seed       int64                      `protobuf:"varint,9,opt,name=seed,proto3" json:"seed,omitempty"`
+}
+
+func (x *EnrollmentPhase) Reset() {
+	*x = EnrollmentPhase{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_schedule_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrollmentPhase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollmentPhase) ProtoMessage() {}
+
+func (x *EnrollmentPhase) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_schedule_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollmentPhase.ProtoReflect.Descriptor instead.
+func (*EnrollmentPhase) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_schedule_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EnrollmentPhase) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EnrollmentPhase) GetAllowEnroll() bool {
+	if x != nil {
+		return x.AllowEnroll
+	}
+	return false
+}
+
+func (x *EnrollmentPhase) GetAllowDisenroll() bool {
+	if x != nil {
+		return x.AllowDisenroll
+	}
+	return false
+}
+
+func (x *EnrollmentPhase) GetAllowChangeGroup() bool {
+	if x != nil {
+		return x.AllowChangeGroup
+	}
+	return false
+}
+
+func (x *EnrollmentPhase) GetStudentDuration() int64 {
+	if x != nil {
+		return x.StudentDuration
+	}
+	return 0
+}
+
+func (x *EnrollmentPhase) GetWindows() []*EnrollmentWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+func (x *EnrollmentPhase) GetLottery() bool {
+	if x != nil {
+		return x.Lottery
+	}
+	return false
+}
+
+func (x *EnrollmentPhase) GetPriorities() []EnrollmentPhase_Priority {
+	if x != nil {
+		return x.Priorities
+	}
+	return nil
+}
+
+func (x *EnrollmentPhase) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+// EnrollmentWindow is a time range which a cohort can act in a phase
+type EnrollmentWindow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The department of the cohort. Zero matches every department.
+	DepartmentId uint32 `protobuf:"varint,1,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	// The entry year of the cohort. Zero matches every entry year.
+	EntryYear int32 `protobuf:"varint,2,opt,name=entry_year,json=entryYear,proto3" json:"entry_year,omitempty"`
+	// In unix epoch (milliseconds). The start is inclusive and the end is exclusive.
+	Start int64 `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	End   int64 `protobuf:"varint,4,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *EnrollmentWindow) Reset() {
+	*x = EnrollmentWindow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_schedule_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrollmentWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollmentWindow) ProtoMessage() {}
+
+func (x *EnrollmentWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_schedule_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollmentWindow.ProtoReflect.Descriptor instead.
+func (*EnrollmentWindow) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_schedule_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EnrollmentWindow) GetDepartmentId() uint32 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *EnrollmentWindow) GetEntryYear() int32 {
+	if x != nil {
+		return x.EntryYear
+	}
+	return 0
+}
+
+func (x *EnrollmentWindow) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *EnrollmentWindow) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+var File_pkg_proto_schedule_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_schedule_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x44, 0x0a, 0x12, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x68, 0x61, 0x73, 0x65, 0x52,
+	0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x22, 0x95, 0x03, 0x0a, 0x0f, 0x45, 0x6e, 0x72, 0x6f,
+	0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x45, 0x6e, 0x72, 0x6f,
+	0x6c, 0x6c, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x64, 0x69, 0x73, 0x65,
+	0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x2c, 0x0a, 0x12, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x74, 0x75,
+	0x64, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x07, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e,
+	0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x07,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x6f, 0x74, 0x74, 0x65,
+	0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6c, 0x6f, 0x74, 0x74, 0x65, 0x72,
+	0x79, 0x12, 0x3f, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e,
+	0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x72,
+	0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x22, 0x27, 0x0a, 0x08, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x45, 0x4e, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x10,
+	0x00, 0x12, 0x0c, 0x0a, 0x08, 0x47, 0x50, 0x41, 0x5f, 0x42, 0x41, 0x4e, 0x44, 0x10, 0x01, 0x22,
+	0x7e, 0x0a, 0x10, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x57, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61,
+	0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6e, 0x74, 0x72,
+	0x79, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x59, 0x65, 0x61, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x42,
+	0x1c, 0x5a, 0x1a, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d,
+	0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_schedule_proto_rawDescOnce sync.Once
+	file_pkg_proto_schedule_proto_rawDescData = file_pkg_proto_schedule_proto_rawDesc
+)
+
+func file_pkg_proto_schedule_proto_rawDescGZIP() []byte {
+	file_pkg_proto_schedule_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_schedule_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_schedule_proto_rawDescData)
+	})
+	return file_pkg_proto_schedule_proto_rawDescData
+}
+
+var file_pkg_proto_schedule_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_proto_schedule_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_proto_schedule_proto_goTypes = []interface{}{
+	(EnrollmentPhase_Priority)(0), // 0: proto.EnrollmentPhase.Priority
+	(*EnrollmentSchedule)(nil),    // 1: proto.EnrollmentSchedule
+	(*EnrollmentPhase)(nil),       // 2: proto.EnrollmentPhase
+	(*EnrollmentWindow)(nil),      // 3: proto.EnrollmentWindow
+}
+var file_pkg_proto_schedule_proto_depIdxs = []int32{
+	2, // 0: proto.EnrollmentSchedule.phases:type_name -> proto.EnrollmentPhase
+	3, // 1: proto.EnrollmentPhase.windows:type_name -> proto.EnrollmentWindow
+	0, // 2: proto.EnrollmentPhase.priorities:type_name -> proto.EnrollmentPhase.Priority
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_schedule_proto_init() }
+func file_pkg_proto_schedule_proto_init() {
+	if File_pkg_proto_schedule_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_schedule_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnrollmentSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_schedule_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnrollmentPhase); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_schedule_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnrollmentWindow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_schedule_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_schedule_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_schedule_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_schedule_proto_enumTypes,
+		MessageInfos:      file_pkg_proto_schedule_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_schedule_proto = out.File
+	file_pkg_proto_schedule_proto_rawDesc = nil
+	file_pkg_proto_schedule_proto_goTypes = nil
+	file_pkg_proto_schedule_proto_depIdxs = nil
+}
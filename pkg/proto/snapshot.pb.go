@@ -0,0 +1,729 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.9
+// source: pkg/proto/snapshot.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EnrollmentSnapshot is the whole state of the enrollment server at a point of time.
+type EnrollmentSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The generation of the journal which contains the messages that are published after this snapshot
+	Generation uint64 `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	// When was this snapshot taken. In unix epoch (milliseconds)
+	CreatedAt  int64               `protobuf:"varint,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Courses    []*CourseSnapshot   `protobuf:"bytes,3,rep,name=courses,proto3" json:"courses,omitempty"`
+	Students   []*StudentSnapshot  `protobuf:"bytes,4,rep,name=students,proto3" json:"students,omitempty"`
+	Schedule   *EnrollmentSchedule `protobuf:"bytes,5,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Requisites []*CourseRequisites `protobuf:"bytes,6,rep,name=requisites,proto3" json:"requisites,omitempty"`
+}
+
+func (x *EnrollmentSnapshot) Reset() {
+	*x = EnrollmentSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_snapshot_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnrollmentSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollmentSnapshot) ProtoMessage() {}
+
+func (x *EnrollmentSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snapshot_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollmentSnapshot.ProtoReflect.Descriptor instead.
+func (*EnrollmentSnapshot) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snapshot_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EnrollmentSnapshot) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *EnrollmentSnapshot) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *EnrollmentSnapshot) GetCourses() []*CourseSnapshot {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
+func (x *EnrollmentSnapshot) GetStudents() []*StudentSnapshot {
+	if x != nil {
+		return x.Students
+	}
+	return nil
+}
+
+func (x *EnrollmentSnapshot) GetSchedule() *EnrollmentSchedule {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *EnrollmentSnapshot) GetRequisites() []*CourseRequisites {
+	if x != nil {
+		return x.Requisites
+	}
+	return nil
+}
+
+// CourseRequisites is the prerequisites and co-requisites of a course
+type CourseRequisites struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CourseId      int32   `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	Prerequisites []int32 `protobuf:"varint,2,rep,packed,name=prerequisites,proto3" json:"prerequisites,omitempty"`
+	Corequisites  []int32 `protobuf:"varint,3,rep,packed,name=corequisites,proto3" json:"corequisites,omitempty"`
+}
+
+func (x *CourseRequisites) Reset() {
+	*x = CourseRequisites{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_snapshot_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseRequisites) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseRequisites) ProtoMessage() {}
+
+func (x *CourseRequisites) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snapshot_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseRequisites.ProtoReflect.Descriptor instead.
+func (*CourseRequisites) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snapshot_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CourseRequisites) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *CourseRequisites) GetPrerequisites() []int32 {
+	if x != nil {
+		return x.Prerequisites
+	}
+	return nil
+}
+
+func (x *CourseRequisites) GetCorequisites() []int32 {
+	if x != nil {
+		return x.Corequisites
+	}
+	return nil
+}
+
+// CourseSnapshot is the state of a single group of a course
+type CourseSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CourseId        int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId         uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	DepartmentId    uint32 `protobuf:"varint,3,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Lecturer        string `protobuf:"bytes,4,opt,name=lecturer,proto3" json:"lecturer,omitempty"`
+	Units           uint32 `protobuf:"varint,5,opt,name=units,proto3" json:"units,omitempty"`
+	Capacity        int32  `protobuf:"varint,6,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	ReserveCapacity int32  `protobuf:"varint,7,opt,name=reserve_capacity,json=reserveCapacity,proto3" json:"reserve_capacity,omitempty"`
+	// In unix epoch (seconds). Zero means no exam.
+	ExamTime int64 `protobuf:"varint,8,opt,name=exam_time,json=examTime,proto3" json:"exam_time,omitempty"`
+	// The raw value of the class time
+	ClassTime          uint32   `protobuf:"varint,9,opt,name=class_time,json=classTime,proto3" json:"class_time,omitempty"`
+	SexLock            uint32   `protobuf:"varint,10,opt,name=sex_lock,json=sexLock,proto3" json:"sex_lock,omitempty"`
+	RegisteredStudents []uint64 `protobuf:"varint,11,rep,packed,name=registered_students,json=registeredStudents,proto3" json:"registered_students,omitempty"`
+	// The reserve queue in order
+	ReserveQueue []uint64           `protobuf:"varint,12,rep,packed,name=reserve_queue,json=reserveQueue,proto3" json:"reserve_queue,omitempty"`
+	Eligibility  *CourseEligibility `protobuf:"bytes,13,opt,name=eligibility,proto3" json:"eligibility,omitempty"`
+	// The students which want to change their group to this group, in order
+	Intents []uint64 `protobuf:"varint,14,rep,packed,name=intents,proto3" json:"intents,omitempty"`
+	// In seconds. Zero means that promoted students do not need to confirm their seat.
+	ConfirmationWindow int64 `protobuf:"varint,15,opt,name=confirmation_window,json=confirmationWindow,proto3" json:"confirmation_window,omitempty"`
+	// The promoted students which have not confirmed their seat to their deadline in unix milliseconds
+	ConfirmationDeadlines map[uint64]int64 `protobuf:"bytes,16,rep,name=confirmation_deadlines,json=confirmationDeadlines,proto3" json:"confirmation_deadlines,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *CourseSnapshot) Reset() {
+	*x = CourseSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_snapshot_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CourseSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseSnapshot) ProtoMessage() {}
+
+func (x *CourseSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snapshot_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseSnapshot.ProtoReflect.Descriptor instead.
+func (*CourseSnapshot) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snapshot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CourseSnapshot) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetDepartmentId() uint32 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetLecturer() string {
+	if x != nil {
+		return x.Lecturer
+	}
+	return ""
+}
+
+func (x *CourseSnapshot) GetUnits() uint32 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetReserveCapacity() int32 {
+	if x != nil {
+		return x.ReserveCapacity
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetExamTime() int64 {
+	if x != nil {
+		return x.ExamTime
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetClassTime() uint32 {
+	if x != nil {
+		return x.ClassTime
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetSexLock() uint32 {
+	if x != nil {
+		return x.SexLock
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetRegisteredStudents() []uint64 {
+	if x != nil {
+		return x.RegisteredStudents
+	}
+	return nil
+}
+
+func (x *CourseSnapshot) GetReserveQueue() []uint64 {
+	if x != nil {
+		return x.ReserveQueue
+	}
+	return nil
+}
+
+func (x *CourseSnapshot) GetEligibility() *CourseEligibility {
+	if x != nil {
+		return x.Eligibility
+	}
+	return nil
+}
+
+func (x *CourseSnapshot) GetIntents() []uint64 {
+	if x != nil {
+		return x.Intents
+	}
+	return nil
+}
+
+func (x *CourseSnapshot) GetConfirmationWindow() int64 {
+	if x != nil {
+		return x.ConfirmationWindow
+	}
+	return 0
+}
+
+func (x *CourseSnapshot) GetConfirmationDeadlines() map[uint64]int64 {
+	if x != nil {
+		return x.ConfirmationDeadlines
+	}
+	return nil
+}
+
+// StudentSnapshot is the state of a single student
+type StudentSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	// In unix epoch (milliseconds)
+	EnrollmentStartTime int64  `protobuf:"varint,2,opt,name=enrollment_start_time,json=enrollmentStartTime,proto3" json:"enrollment_start_time,omitempty"`
+	RemainingActions    uint32 `protobuf:"varint,3,opt,name=remaining_actions,json=remainingActions,proto3" json:"remaining_actions,omitempty"`
+	MaxUnits            uint32 `protobuf:"varint,4,opt,name=max_units,json=maxUnits,proto3" json:"max_units,omitempty"`
+	RegisteredUnits     uint32 `protobuf:"varint,5,opt,name=registered_units,json=registeredUnits,proto3" json:"registered_units,omitempty"`
+	Sex                 uint32 `protobuf:"varint,6,opt,name=sex,proto3" json:"sex,omitempty"`
+	// Course ID to group ID
+	RegisteredCourses map[int32]uint32 `protobuf:"bytes,7,rep,name=registered_courses,json=registeredCourses,proto3" json:"registered_courses,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	DepartmentId      uint32           `protobuf:"varint,8,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	EntryYear         int32            `protobuf:"varint,9,opt,name=entry_year,json=entryYear,proto3" json:"entry_year,omitempty"`
+	PassedCourses     []int32          `protobuf:"varint,10,rep,packed,name=passed_courses,json=passedCourses,proto3" json:"passed_courses,omitempty"`
+	GpaBand           uint32           `protobuf:"varint,11,opt,name=gpa_band,json=gpaBand,proto3" json:"gpa_band,omitempty"`
+	// Nil if the student has not submitted a wishlist
+	Wishlist *Wishlist `protobuf:"bytes,12,opt,name=wishlist,proto3" json:"wishlist,omitempty"`
+}
+
+func (x *StudentSnapshot) Reset() {
+	*x = StudentSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_snapshot_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentSnapshot) ProtoMessage() {}
+
+func (x *StudentSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_snapshot_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentSnapshot.ProtoReflect.Descriptor instead.
+func (*StudentSnapshot) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_snapshot_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StudentSnapshot) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetEnrollmentStartTime() int64 {
+	if x != nil {
+		return x.EnrollmentStartTime
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetRemainingActions() uint32 {
+	if x != nil {
+		return x.RemainingActions
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetMaxUnits() uint32 {
+	if x != nil {
+		return x.MaxUnits
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetRegisteredUnits() uint32 {
+	if x != nil {
+		return x.RegisteredUnits
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetSex() uint32 {
+	if x != nil {
+		return x.Sex
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetRegisteredCourses() map[int32]uint32 {
+	if x != nil {
+		return x.RegisteredCourses
+	}
+	return nil
+}
+
+func (x *StudentSnapshot) GetDepartmentId() uint32 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetEntryYear() int32 {
+	if x != nil {
+		return x.EntryYear
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetPassedCourses() []int32 {
+	if x != nil {
+		return x.PassedCourses
+	}
+	return nil
+}
+
+func (x *StudentSnapshot) GetGpaBand() uint32 {
+	if x != nil {
+		return x.GpaBand
+	}
+	return 0
+}
+
+func (x *StudentSnapshot) GetWishlist() *Wishlist {
+	if x != nil {
+		return x.Wishlist
+	}
+	return nil
+}
+
+var File_pkg_proto_snapshot_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_snapshot_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x18, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x70, 0x6b, 0x67,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x6c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xa8, 0x02, 0x0a, 0x12, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x67, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x2f, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x72, 0x73,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52,
+	0x07, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x08, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x35, 0x0a, 0x08,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e,
+	0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x65, 0x73,
+	0x52, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x65, 0x73, 0x22, 0x79, 0x0a, 0x10,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x65, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x24, 0x0a,
+	0x0d, 0x70, 0x72, 0x65, 0x72, 0x65, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x72, 0x65, 0x71, 0x75, 0x69, 0x73, 0x69,
+	0x74, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x73, 0x69,
+	0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0c, 0x63, 0x6f, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x73, 0x69, 0x74, 0x65, 0x73, 0x22, 0xcd, 0x05, 0x0a, 0x0e, 0x43, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63,
+	0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61, 0x72,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75,
+	0x72, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75,
+	0x72, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x61, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08,
+	0x73, 0x65, 0x78, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
+	0x73, 0x65, 0x78, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x2f, 0x0a, 0x13, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x0b,
+	0x20, 0x03, 0x28, 0x04, 0x52, 0x12, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64,
+	0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x5f, 0x71, 0x75, 0x65, 0x75, 0x65, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x04, 0x52,
+	0x0c, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x3a, 0x0a,
+	0x0b, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73,
+	0x65, 0x45, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x0b, 0x65, 0x6c,
+	0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x12, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x57, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x12, 0x67, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x10,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75,
+	0x72, 0x73, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x1a, 0x48, 0x0a,
+	0x1a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc2, 0x04, 0x0a, 0x0f, 0x53, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x15, 0x65, 0x6e,
+	0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x65, 0x6e, 0x72, 0x6f, 0x6c,
+	0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2b,
+	0x0a, 0x11, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x72, 0x65, 0x6d, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
+	0x61, 0x78, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x6d, 0x61, 0x78, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x55, 0x6e,
+	0x69, 0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x03, 0x73, 0x65, 0x78, 0x12, 0x5c, 0x0a, 0x12, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x11, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61,
+	0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x6e, 0x74, 0x72,
+	0x79, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x59, 0x65, 0x61, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x73, 0x73, 0x65,
+	0x64, 0x5f, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x0d, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x12, 0x19,
+	0x0a, 0x08, 0x67, 0x70, 0x61, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x67, 0x70, 0x61, 0x42, 0x61, 0x6e, 0x64, 0x12, 0x2b, 0x0a, 0x08, 0x77, 0x69, 0x73,
+	0x68, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x08, 0x77, 0x69,
+	0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x1a, 0x44, 0x0a, 0x16, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x1c, 0x5a, 0x1a,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_snapshot_proto_rawDescOnce sync.Once
+	file_pkg_proto_snapshot_proto_rawDescData = file_pkg_proto_snapshot_proto_rawDesc
+)
+
+func file_pkg_proto_snapshot_proto_rawDescGZIP() []byte {
+	file_pkg_proto_snapshot_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_snapshot_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_snapshot_proto_rawDescData)
+	})
+	return file_pkg_proto_snapshot_proto_rawDescData
+}
+
+var file_pkg_proto_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_pkg_proto_snapshot_proto_goTypes = []interface{}{
+	(*EnrollmentSnapshot)(nil), // 0: proto.EnrollmentSnapshot
+	(*CourseRequisites)(nil),   // 1: proto.CourseRequisites
+	(*CourseSnapshot)(nil),     // 2: proto.CourseSnapshot
+	(*StudentSnapshot)(nil),    // 3: proto.StudentSnapshot
+	nil,                        // 4: proto.CourseSnapshot.ConfirmationDeadlinesEntry
+	nil,                        // 5: proto.StudentSnapshot.RegisteredCoursesEntry
+	(*EnrollmentSchedule)(nil), // 6: proto.EnrollmentSchedule
+	(*CourseEligibility)(nil),  // 7: proto.CourseEligibility
+	(*Wishlist)(nil),           // 8: proto.Wishlist
+}
+var file_pkg_proto_snapshot_proto_depIdxs = []int32{
+	2, // 0: proto.EnrollmentSnapshot.courses:type_name -> proto.CourseSnapshot
+	3, // 1: proto.EnrollmentSnapshot.students:type_name -> proto.StudentSnapshot
+	6, // 2: proto.EnrollmentSnapshot.schedule:type_name -> proto.EnrollmentSchedule
+	1, // 3: proto.EnrollmentSnapshot.requisites:type_name -> proto.CourseRequisites
+	7, // 4: proto.CourseSnapshot.eligibility:type_name -> proto.CourseEligibility
+	4, // 5: proto.CourseSnapshot.confirmation_deadlines:type_name -> proto.CourseSnapshot.ConfirmationDeadlinesEntry
+	5, // 6: proto.StudentSnapshot.registered_courses:type_name -> proto.StudentSnapshot.RegisteredCoursesEntry
+	8, // 7: proto.StudentSnapshot.wishlist:type_name -> proto.Wishlist
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_snapshot_proto_init() }
+func file_pkg_proto_snapshot_proto_init() {
+	if File_pkg_proto_snapshot_proto != nil {
+		return
+	}
+	file_pkg_proto_schedule_proto_init()
+	file_pkg_proto_eligibility_proto_init()
+	file_pkg_proto_lottery_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_snapshot_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnrollmentSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_snapshot_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseRequisites); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_snapshot_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_snapshot_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudentSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_snapshot_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_snapshot_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_snapshot_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_snapshot_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_snapshot_proto = out.File
+	file_pkg_proto_snapshot_proto_rawDesc = nil
+	file_pkg_proto_snapshot_proto_goTypes = nil
+	file_pkg_proto_snapshot_proto_depIdxs = nil
+}
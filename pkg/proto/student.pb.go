@@ -21,6 +21,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type StudentChange_Action int32
+
+const (
+	StudentChange_ENROLL       StudentChange_Action = 0
+	StudentChange_DISENROLL    StudentChange_Action = 1
+	StudentChange_CHANGE_GROUP StudentChange_Action = 2
+)
+
+// Enum value maps for StudentChange_Action.
+var (
+	StudentChange_Action_name = map[int32]string{
+		0: "ENROLL",
+		1: "DISENROLL",
+		2: "CHANGE_GROUP",
+	}
+	StudentChange_Action_value = map[string]int32{
+		"ENROLL":       0,
+		"DISENROLL":    1,
+		"CHANGE_GROUP": 2,
+	}
+)
+
+func (x StudentChange_Action) Enum() *StudentChange_Action {
+	p := new(StudentChange_Action)
+	*p = x
+	return p
+}
+
+func (x StudentChange_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StudentChange_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_student_proto_enumTypes[0].Descriptor()
+}
+
+func (StudentChange_Action) Type() protoreflect.EnumType {
+	return &file_pkg_proto_student_proto_enumTypes[0]
+}
+
+func (x StudentChange_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StudentChange_Action.Descriptor instead.
+func (StudentChange_Action) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{3, 0}
+}
+
 // The request to enroll a student in a course
 type StudentEnrollRequest struct {
 	state         protoimpl.MessageState
@@ -205,6 +254,129 @@ func (x *StudentChangeGroupRequest) GetNewGroupId() uint32 {
 	return 0
 }
 
+// A single action in StudentApplyChangesRequest
+type StudentChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action   StudentChange_Action `protobuf:"varint,1,opt,name=action,proto3,enum=proto.StudentChange_Action" json:"action,omitempty"`
+	CourseId int32                `protobuf:"varint,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// The group to enroll in or change to. It's ignored when disenrolling.
+	GroupId uint32 `protobuf:"varint,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+}
+
+func (x *StudentChange) Reset() {
+	*x = StudentChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentChange) ProtoMessage() {}
+
+func (x *StudentChange) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentChange.ProtoReflect.Descriptor instead.
+func (*StudentChange) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StudentChange) GetAction() StudentChange_Action {
+	if x != nil {
+		return x.Action
+	}
+	return StudentChange_ENROLL
+}
+
+func (x *StudentChange) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *StudentChange) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// The request to apply a list of changes of a student atomically. All changed courses must be in
+// departments of the same queue shard; the changes in other shards must be sent in other requests.
+type StudentApplyChangesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	// Each course can be only changed once
+	Changes []*StudentChange `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+}
+
+func (x *StudentApplyChangesRequest) Reset() {
+	*x = StudentApplyChangesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentApplyChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentApplyChangesRequest) ProtoMessage() {}
+
+func (x *StudentApplyChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentApplyChangesRequest.ProtoReflect.Descriptor instead.
+func (*StudentApplyChangesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StudentApplyChangesRequest) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *StudentApplyChangesRequest) GetChanges() []*StudentChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
 // This is the request to get a student courses.
 // It only contains the student ID
 type GetStudentCoursesRequest struct {
@@ -218,7 +390,7 @@ type GetStudentCoursesRequest struct {
 func (x *GetStudentCoursesRequest) Reset() {
 	*x = GetStudentCoursesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[3]
+		mi := &file_pkg_proto_student_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -231,7 +403,7 @@ func (x *GetStudentCoursesRequest) String() string {
 func (*GetStudentCoursesRequest) ProtoMessage() {}
 
 func (x *GetStudentCoursesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[3]
+	mi := &file_pkg_proto_student_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,7 +416,7 @@ func (x *GetStudentCoursesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStudentCoursesRequest.ProtoReflect.Descriptor instead.
 func (*GetStudentCoursesRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{3}
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetStudentCoursesRequest) GetStudentId() uint64 {
@@ -267,7 +439,7 @@ type GetDepartmentCoursesRequest struct {
 func (x *GetDepartmentCoursesRequest) Reset() {
 	*x = GetDepartmentCoursesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[4]
+		mi := &file_pkg_proto_student_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -280,7 +452,7 @@ func (x *GetDepartmentCoursesRequest) String() string {
 func (*GetDepartmentCoursesRequest) ProtoMessage() {}
 
 func (x *GetDepartmentCoursesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[4]
+	mi := &file_pkg_proto_student_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -293,7 +465,7 @@ func (x *GetDepartmentCoursesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDepartmentCoursesRequest.ProtoReflect.Descriptor instead.
 func (*GetDepartmentCoursesRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{4}
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetDepartmentCoursesRequest) GetDepartmentId() uint32 {
@@ -322,7 +494,7 @@ type CourseData struct {
 func (x *CourseData) Reset() {
 	*x = CourseData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[5]
+		mi := &file_pkg_proto_student_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -335,7 +507,7 @@ func (x *CourseData) String() string {
 func (*CourseData) ProtoMessage() {}
 
 func (x *CourseData) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[5]
+	mi := &file_pkg_proto_student_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,7 +520,7 @@ func (x *CourseData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CourseData.ProtoReflect.Descriptor instead.
 func (*CourseData) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{5}
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CourseData) GetCourseId() int32 {
@@ -407,35 +579,33 @@ func (x *CourseData) GetLecturer() string {
 	return ""
 }
 
-// StudentCourseData contains the course + if user is
-type StudentCourseData struct {
+// CourseGroup is a group of a course. Zero group ID means every group of the course.
+type CourseGroup struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Course *CourseData `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
-	// Zero on this field means that this user is registered.
-	// Zeroth place on queue is out of it right?
-	ReserveQueuePosition uint32 `protobuf:"varint,2,opt,name=reserve_queue_position,json=reserveQueuePosition,proto3" json:"reserve_queue_position,omitempty"`
+	CourseId int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId  uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
 }
 
-func (x *StudentCourseData) Reset() {
-	*x = StudentCourseData{}
+func (x *CourseGroup) Reset() {
+	*x = CourseGroup{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[6]
+		mi := &file_pkg_proto_student_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StudentCourseData) String() string {
+func (x *CourseGroup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StudentCourseData) ProtoMessage() {}
+func (*CourseGroup) ProtoMessage() {}
 
-func (x *StudentCourseData) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[6]
+func (x *CourseGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -446,51 +616,51 @@ func (x *StudentCourseData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StudentCourseData.ProtoReflect.Descriptor instead.
-func (*StudentCourseData) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use CourseGroup.ProtoReflect.Descriptor instead.
+func (*CourseGroup) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *StudentCourseData) GetCourse() *CourseData {
+func (x *CourseGroup) GetCourseId() int32 {
 	if x != nil {
-		return x.Course
+		return x.CourseId
 	}
-	return nil
+	return 0
 }
 
-func (x *StudentCourseData) GetReserveQueuePosition() uint32 {
+func (x *CourseGroup) GetGroupId() uint32 {
 	if x != nil {
-		return x.ReserveQueuePosition
+		return x.GroupId
 	}
 	return 0
 }
 
-// StudentCourseDataArray is an array of StudentCourseData
-type StudentCourseDataArray struct {
+// WatchCoursesRequest lists the course groups to watch
+type WatchCoursesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data []*StudentCourseData `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Groups []*CourseGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
 }
 
-func (x *StudentCourseDataArray) Reset() {
-	*x = StudentCourseDataArray{}
+func (x *WatchCoursesRequest) Reset() {
+	*x = WatchCoursesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[7]
+		mi := &file_pkg_proto_student_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StudentCourseDataArray) String() string {
+func (x *WatchCoursesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StudentCourseDataArray) ProtoMessage() {}
+func (*WatchCoursesRequest) ProtoMessage() {}
 
-func (x *StudentCourseDataArray) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[7]
+func (x *WatchCoursesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -501,44 +671,49 @@ func (x *StudentCourseDataArray) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StudentCourseDataArray.ProtoReflect.Descriptor instead.
-func (*StudentCourseDataArray) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use WatchCoursesRequest.ProtoReflect.Descriptor instead.
+func (*WatchCoursesRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *StudentCourseDataArray) GetData() []*StudentCourseData {
+func (x *WatchCoursesRequest) GetGroups() []*CourseGroup {
 	if x != nil {
-		return x.Data
+		return x.Groups
 	}
 	return nil
 }
 
-// DepartmentCourses is an array of courses
-type DepartmentCourses struct {
+// CourseSeats is the seat availability of a course group
+type CourseSeats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Courses []*CourseData `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+	CourseId           int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId            uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Capacity           int32  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	RegisteredCount    uint32 `protobuf:"varint,4,opt,name=registered_count,json=registeredCount,proto3" json:"registered_count,omitempty"`
+	ReserveCapacity    int32  `protobuf:"varint,5,opt,name=reserve_capacity,json=reserveCapacity,proto3" json:"reserve_capacity,omitempty"`
+	ReserveQueueLength uint32 `protobuf:"varint,6,opt,name=reserve_queue_length,json=reserveQueueLength,proto3" json:"reserve_queue_length,omitempty"`
 }
 
-func (x *DepartmentCourses) Reset() {
-	*x = DepartmentCourses{}
+func (x *CourseSeats) Reset() {
+	*x = CourseSeats{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[8]
+		mi := &file_pkg_proto_student_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DepartmentCourses) String() string {
+func (x *CourseSeats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DepartmentCourses) ProtoMessage() {}
+func (*CourseSeats) ProtoMessage() {}
 
-func (x *DepartmentCourses) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[8]
+func (x *CourseSeats) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -549,45 +724,80 @@ func (x *DepartmentCourses) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DepartmentCourses.ProtoReflect.Descriptor instead.
-func (*DepartmentCourses) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use CourseSeats.ProtoReflect.Descriptor instead.
+func (*CourseSeats) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *DepartmentCourses) GetCourses() []*CourseData {
+func (x *CourseSeats) GetCourseId() int32 {
 	if x != nil {
-		return x.Courses
+		return x.CourseId
 	}
-	return nil
+	return 0
 }
 
-// The request body to get students of a course
-type StudentsOfCourseRequest struct {
+func (x *CourseSeats) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *CourseSeats) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *CourseSeats) GetRegisteredCount() uint32 {
+	if x != nil {
+		return x.RegisteredCount
+	}
+	return 0
+}
+
+func (x *CourseSeats) GetReserveCapacity() int32 {
+	if x != nil {
+		return x.ReserveCapacity
+	}
+	return 0
+}
+
+func (x *CourseSeats) GetReserveQueueLength() uint32 {
+	if x != nil {
+		return x.ReserveQueueLength
+	}
+	return 0
+}
+
+// CourseSeatsUpdate contains the latest seats of the groups which have changed, ordered by
+// course and group IDs
+type CourseSeatsUpdate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CourseId int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
-	GroupId  uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Courses []*CourseSeats `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
 }
 
-func (x *StudentsOfCourseRequest) Reset() {
-	*x = StudentsOfCourseRequest{}
+func (x *CourseSeatsUpdate) Reset() {
+	*x = CourseSeatsUpdate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[9]
+		mi := &file_pkg_proto_student_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StudentsOfCourseRequest) String() string {
+func (x *CourseSeatsUpdate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StudentsOfCourseRequest) ProtoMessage() {}
+func (*CourseSeatsUpdate) ProtoMessage() {}
 
-func (x *StudentsOfCourseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[9]
+func (x *CourseSeatsUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -598,27 +808,411 @@ func (x *StudentsOfCourseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StudentsOfCourseRequest.ProtoReflect.Descriptor instead.
-func (*StudentsOfCourseRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *StudentsOfCourseRequest) GetCourseId() int32 {
-	if x != nil {
-		return x.CourseId
-	}
-	return 0
+// Deprecated: Use CourseSeatsUpdate.ProtoReflect.Descriptor instead.
+func (*CourseSeatsUpdate) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *StudentsOfCourseRequest) GetGroupId() uint32 {
+func (x *CourseSeatsUpdate) GetCourses() []*CourseSeats {
 	if x != nil {
-		return x.GroupId
+		return x.Courses
 	}
-	return 0
+	return nil
 }
 
-// The result of the request which contains list of students in a course.
-type StudentsOfCourseResponse struct {
+// StudentCourseData contains the course + if user is
+type StudentCourseData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Course *CourseData `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	// Zero on this field means that this user is registered.
+	// Zeroth place on queue is out of it right?
+	ReserveQueuePosition uint32 `protobuf:"varint,2,opt,name=reserve_queue_position,json=reserveQueuePosition,proto3" json:"reserve_queue_position,omitempty"`
+	// If not zero, the student is promoted from the reserve queue and must confirm their seat
+	// before this time in unix milliseconds.
+	ConfirmationDeadline int64 `protobuf:"varint,3,opt,name=confirmation_deadline,json=confirmationDeadline,proto3" json:"confirmation_deadline,omitempty"`
+}
+
+func (x *StudentCourseData) Reset() {
+	*x = StudentCourseData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentCourseData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentCourseData) ProtoMessage() {}
+
+func (x *StudentCourseData) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentCourseData.ProtoReflect.Descriptor instead.
+func (*StudentCourseData) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StudentCourseData) GetCourse() *CourseData {
+	if x != nil {
+		return x.Course
+	}
+	return nil
+}
+
+func (x *StudentCourseData) GetReserveQueuePosition() uint32 {
+	if x != nil {
+		return x.ReserveQueuePosition
+	}
+	return 0
+}
+
+func (x *StudentCourseData) GetConfirmationDeadline() int64 {
+	if x != nil {
+		return x.ConfirmationDeadline
+	}
+	return 0
+}
+
+// AddGroupChangeIntentResponse says if the group was changed immediately or the intent is pending
+type AddGroupChangeIntentResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Changed bool `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
+}
+
+func (x *AddGroupChangeIntentResponse) Reset() {
+	*x = AddGroupChangeIntentResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddGroupChangeIntentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddGroupChangeIntentResponse) ProtoMessage() {}
+
+func (x *AddGroupChangeIntentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddGroupChangeIntentResponse.ProtoReflect.Descriptor instead.
+func (*AddGroupChangeIntentResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AddGroupChangeIntentResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+// GroupChangeIntent is a pending intent of a student to change their group in a course
+type GroupChangeIntent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CourseId int32 `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// The current group of the student
+	SourceGroupId uint32 `protobuf:"varint,2,opt,name=source_group_id,json=sourceGroupId,proto3" json:"source_group_id,omitempty"`
+	// The group which the student wants to move to
+	DestinationGroupId uint32 `protobuf:"varint,3,opt,name=destination_group_id,json=destinationGroupId,proto3" json:"destination_group_id,omitempty"`
+	// The position of the student between the intents of the destination group (1-indexed)
+	Position uint32 `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+}
+
+func (x *GroupChangeIntent) Reset() {
+	*x = GroupChangeIntent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupChangeIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupChangeIntent) ProtoMessage() {}
+
+func (x *GroupChangeIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupChangeIntent.ProtoReflect.Descriptor instead.
+func (*GroupChangeIntent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GroupChangeIntent) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *GroupChangeIntent) GetSourceGroupId() uint32 {
+	if x != nil {
+		return x.SourceGroupId
+	}
+	return 0
+}
+
+func (x *GroupChangeIntent) GetDestinationGroupId() uint32 {
+	if x != nil {
+		return x.DestinationGroupId
+	}
+	return 0
+}
+
+func (x *GroupChangeIntent) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+// GroupChangeIntents is an array of GroupChangeIntent
+type GroupChangeIntents struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Intents []*GroupChangeIntent `protobuf:"bytes,1,rep,name=intents,proto3" json:"intents,omitempty"`
+}
+
+func (x *GroupChangeIntents) Reset() {
+	*x = GroupChangeIntents{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupChangeIntents) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupChangeIntents) ProtoMessage() {}
+
+func (x *GroupChangeIntents) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupChangeIntents.ProtoReflect.Descriptor instead.
+func (*GroupChangeIntents) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GroupChangeIntents) GetIntents() []*GroupChangeIntent {
+	if x != nil {
+		return x.Intents
+	}
+	return nil
+}
+
+// StudentCourseDataArray is an array of StudentCourseData
+type StudentCourseDataArray struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []*StudentCourseData `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *StudentCourseDataArray) Reset() {
+	*x = StudentCourseDataArray{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentCourseDataArray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentCourseDataArray) ProtoMessage() {}
+
+func (x *StudentCourseDataArray) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentCourseDataArray.ProtoReflect.Descriptor instead.
+func (*StudentCourseDataArray) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StudentCourseDataArray) GetData() []*StudentCourseData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// DepartmentCourses is an array of courses
+type DepartmentCourses struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Courses []*CourseData `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+}
+
+func (x *DepartmentCourses) Reset() {
+	*x = DepartmentCourses{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepartmentCourses) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepartmentCourses) ProtoMessage() {}
+
+func (x *DepartmentCourses) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepartmentCourses.ProtoReflect.Descriptor instead.
+func (*DepartmentCourses) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DepartmentCourses) GetCourses() []*CourseData {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
+// The request body to get students of a course
+type StudentsOfCourseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CourseId int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId  uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+}
+
+func (x *StudentsOfCourseRequest) Reset() {
+	*x = StudentsOfCourseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentsOfCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentsOfCourseRequest) ProtoMessage() {}
+
+func (x *StudentsOfCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentsOfCourseRequest.ProtoReflect.Descriptor instead.
+func (*StudentsOfCourseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StudentsOfCourseRequest) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *StudentsOfCourseRequest) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+// The result of the request which contains list of students in a course.
+type StudentsOfCourseResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -633,7 +1227,7 @@ type StudentsOfCourseResponse struct {
 func (x *StudentsOfCourseResponse) Reset() {
 	*x = StudentsOfCourseResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[10]
+		mi := &file_pkg_proto_student_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -646,7 +1240,7 @@ func (x *StudentsOfCourseResponse) String() string {
 func (*StudentsOfCourseResponse) ProtoMessage() {}
 
 func (x *StudentsOfCourseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[10]
+	mi := &file_pkg_proto_student_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -659,7 +1253,7 @@ func (x *StudentsOfCourseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StudentsOfCourseResponse.ProtoReflect.Descriptor instead.
 func (*StudentsOfCourseResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{10}
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *StudentsOfCourseResponse) GetRegisteredStudents() []uint64 {
@@ -673,37 +1267,407 @@ func (x *StudentsOfCourseResponse) GetReservedQueueStudents() []uint64 {
 	if x != nil {
 		return x.ReservedQueueStudents
 	}
-	return nil
+	return nil
+}
+
+// The request to enroll a student in a course
+type ChangeCourseCapacityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CourseId    int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId     uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	NewCapacity int32  `protobuf:"varint,3,opt,name=new_capacity,json=newCapacity,proto3" json:"new_capacity,omitempty"`
+}
+
+func (x *ChangeCourseCapacityRequest) Reset() {
+	*x = ChangeCourseCapacityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChangeCourseCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeCourseCapacityRequest) ProtoMessage() {}
+
+func (x *ChangeCourseCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeCourseCapacityRequest.ProtoReflect.Descriptor instead.
+func (*ChangeCourseCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ChangeCourseCapacityRequest) GetCourseId() int32 {
+	if x != nil {
+		return x.CourseId
+	}
+	return 0
+}
+
+func (x *ChangeCourseCapacityRequest) GetGroupId() uint32 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *ChangeCourseCapacityRequest) GetNewCapacity() int32 {
+	if x != nil {
+		return x.NewCapacity
+	}
+	return 0
+}
+
+// The request to create or update a student
+type PutStudentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	// The hash of the password. It can be empty for existing students to keep their password.
+	PasswordHash string `protobuf:"bytes,2,opt,name=password_hash,json=passwordHash,proto3" json:"password_hash,omitempty"`
+	// In unix milliseconds
+	EnrollmentStartTime int64  `protobuf:"varint,3,opt,name=enrollment_start_time,json=enrollmentStartTime,proto3" json:"enrollment_start_time,omitempty"`
+	MaxUnits            uint32 `protobuf:"varint,4,opt,name=max_units,json=maxUnits,proto3" json:"max_units,omitempty"`
+	RemainingActions    uint32 `protobuf:"varint,5,opt,name=remaining_actions,json=remainingActions,proto3" json:"remaining_actions,omitempty"`
+	DepartmentId        uint32 `protobuf:"varint,6,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	EntryYear           int32  `protobuf:"varint,7,opt,name=entry_year,json=entryYear,proto3" json:"entry_year,omitempty"`
+	Female              bool   `protobuf:"varint,8,opt,name=female,proto3" json:"female,omitempty"`
+	GpaBand             uint32 `protobuf:"varint,9,opt,name=gpa_band,json=gpaBand,proto3" json:"gpa_band,omitempty"`
+}
+
+func (x *PutStudentRequest) Reset() {
+	*x = PutStudentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PutStudentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutStudentRequest) ProtoMessage() {}
+
+func (x *PutStudentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutStudentRequest.ProtoReflect.Descriptor instead.
+func (*PutStudentRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PutStudentRequest) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *PutStudentRequest) GetPasswordHash() string {
+	if x != nil {
+		return x.PasswordHash
+	}
+	return ""
+}
+
+func (x *PutStudentRequest) GetEnrollmentStartTime() int64 {
+	if x != nil {
+		return x.EnrollmentStartTime
+	}
+	return 0
+}
+
+func (x *PutStudentRequest) GetMaxUnits() uint32 {
+	if x != nil {
+		return x.MaxUnits
+	}
+	return 0
+}
+
+func (x *PutStudentRequest) GetRemainingActions() uint32 {
+	if x != nil {
+		return x.RemainingActions
+	}
+	return 0
+}
+
+func (x *PutStudentRequest) GetDepartmentId() uint32 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *PutStudentRequest) GetEntryYear() int32 {
+	if x != nil {
+		return x.EntryYear
+	}
+	return 0
+}
+
+func (x *PutStudentRequest) GetFemale() bool {
+	if x != nil {
+		return x.Female
+	}
+	return false
+}
+
+func (x *PutStudentRequest) GetGpaBand() uint32 {
+	if x != nil {
+		return x.GpaBand
+	}
+	return 0
+}
+
+// The request to replace the wishlist of a student. The phase is the lottery phase which is open
+// for the student. Empty entries removes the wishlist.
+type PutWishlistRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId uint64 `protobuf:"varint,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	// Ranked from the most wanted group
+	Entries []*WishlistEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *PutWishlistRequest) Reset() {
+	*x = PutWishlistRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PutWishlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutWishlistRequest) ProtoMessage() {}
+
+func (x *PutWishlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutWishlistRequest.ProtoReflect.Descriptor instead.
+func (*PutWishlistRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PutWishlistRequest) GetStudentId() uint64 {
+	if x != nil {
+		return x.StudentId
+	}
+	return 0
+}
+
+func (x *PutWishlistRequest) GetEntries() []*WishlistEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// The request to allocate the seats of a lottery phase
+type RunLotteryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Phase string `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+}
+
+func (x *RunLotteryRequest) Reset() {
+	*x = RunLotteryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunLotteryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunLotteryRequest) ProtoMessage() {}
+
+func (x *RunLotteryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunLotteryRequest.ProtoReflect.Descriptor instead.
+func (*RunLotteryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RunLotteryRequest) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+// The result of allocating the seats of a lottery phase
+type RunLotteryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The students which had a wishlist, in the order which they were given seats
+	Order []uint64 `protobuf:"varint,1,rep,packed,name=order,proto3" json:"order,omitempty"`
+	// How many wishes got a seat
+	Enrolled uint32 `protobuf:"varint,2,opt,name=enrolled,proto3" json:"enrolled,omitempty"`
+	// How many wishes got a place in a reserve queue
+	Reserved uint32 `protobuf:"varint,3,opt,name=reserved,proto3" json:"reserved,omitempty"`
+}
+
+func (x *RunLotteryResponse) Reset() {
+	*x = RunLotteryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_student_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunLotteryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunLotteryResponse) ProtoMessage() {}
+
+func (x *RunLotteryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunLotteryResponse.ProtoReflect.Descriptor instead.
+func (*RunLotteryResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RunLotteryResponse) GetOrder() []uint64 {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *RunLotteryResponse) GetEnrolled() uint32 {
+	if x != nil {
+		return x.Enrolled
+	}
+	return 0
+}
+
+func (x *RunLotteryResponse) GetReserved() uint32 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
 }
 
-// The request to enroll a student in a course
-type ChangeCourseCapacityRequest struct {
+// The request to create or update a course group. The department of a course cannot be changed
+// and all of its groups must be in the same department.
+type PutCourseRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CourseId    int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
-	GroupId     uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
-	NewCapacity int32  `protobuf:"varint,3,opt,name=new_capacity,json=newCapacity,proto3" json:"new_capacity,omitempty"`
-}
-
-func (x *ChangeCourseCapacityRequest) Reset() {
-	*x = ChangeCourseCapacityRequest{}
+	CourseId        int32  `protobuf:"varint,1,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	GroupId         uint32 `protobuf:"varint,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	DepartmentId    uint32 `protobuf:"varint,3,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Name            string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Lecturer        string `protobuf:"bytes,5,opt,name=lecturer,proto3" json:"lecturer,omitempty"`
+	Units           uint32 `protobuf:"varint,6,opt,name=units,proto3" json:"units,omitempty"`
+	Capacity        int32  `protobuf:"varint,7,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	ReserveCapacity int32  `protobuf:"varint,8,opt,name=reserve_capacity,json=reserveCapacity,proto3" json:"reserve_capacity,omitempty"`
+	ExamTime        int64  `protobuf:"varint,9,opt,name=exam_time,json=examTime,proto3" json:"exam_time,omitempty"` // in unix epoch, zero means no exam
+	// The days which class is held on alongside its start and end minute from 00:00
+	ClassDays        []Weekday `protobuf:"varint,10,rep,packed,name=class_days,json=classDays,proto3,enum=proto.Weekday" json:"class_days,omitempty"`
+	ClassStartMinute uint32    `protobuf:"varint,11,opt,name=class_start_minute,json=classStartMinute,proto3" json:"class_start_minute,omitempty"`
+	ClassEndMinute   uint32    `protobuf:"varint,12,opt,name=class_end_minute,json=classEndMinute,proto3" json:"class_end_minute,omitempty"`
+	// Zero means no lock, 1 is male only and 2 is female only
+	SexLock uint32 `protobuf:"varint,13,opt,name=sex_lock,json=sexLock,proto3" json:"sex_lock,omitempty"`
+	Notes   string `protobuf:"bytes,14,opt,name=notes,proto3" json:"notes,omitempty"`
+	// Nil means that everyone can take the group
+	Eligibility *CourseEligibility `protobuf:"bytes,15,opt,name=eligibility,proto3" json:"eligibility,omitempty"`
+	// How many seconds the students which are promoted from the reserve queue have to confirm
+	// their seat. Zero means that no confirmation is needed.
+	ConfirmationWindow int64 `protobuf:"varint,16,opt,name=confirmation_window,json=confirmationWindow,proto3" json:"confirmation_window,omitempty"`
+}
+
+func (x *PutCourseRequest) Reset() {
+	*x = PutCourseRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_student_proto_msgTypes[11]
+		mi := &file_pkg_proto_student_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ChangeCourseCapacityRequest) String() string {
+func (x *PutCourseRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChangeCourseCapacityRequest) ProtoMessage() {}
+func (*PutCourseRequest) ProtoMessage() {}
 
-func (x *ChangeCourseCapacityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_student_proto_msgTypes[11]
+func (x *PutCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_student_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -714,28 +1678,119 @@ func (x *ChangeCourseCapacityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChangeCourseCapacityRequest.ProtoReflect.Descriptor instead.
-func (*ChangeCourseCapacityRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_student_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use PutCourseRequest.ProtoReflect.Descriptor instead.
+func (*PutCourseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_student_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *ChangeCourseCapacityRequest) GetCourseId() int32 {
+func (x *PutCourseRequest) GetCourseId() int32 {
 	if x != nil {
 		return x.CourseId
 	}
 	return 0
 }
 
-func (x *ChangeCourseCapacityRequest) GetGroupId() uint32 {
+func (x *PutCourseRequest) GetGroupId() uint32 {
 	if x != nil {
 		return x.GroupId
 	}
 	return 0
 }
 
-func (x *ChangeCourseCapacityRequest) GetNewCapacity() int32 {
+func (x *PutCourseRequest) GetDepartmentId() uint32 {
 	if x != nil {
-		return x.NewCapacity
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PutCourseRequest) GetLecturer() string {
+	if x != nil {
+		return x.Lecturer
+	}
+	return ""
+}
+
+func (x *PutCourseRequest) GetUnits() uint32 {
+	if x != nil {
+		return x.Units
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetReserveCapacity() int32 {
+	if x != nil {
+		return x.ReserveCapacity
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetExamTime() int64 {
+	if x != nil {
+		return x.ExamTime
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetClassDays() []Weekday {
+	if x != nil {
+		return x.ClassDays
+	}
+	return nil
+}
+
+func (x *PutCourseRequest) GetClassStartMinute() uint32 {
+	if x != nil {
+		return x.ClassStartMinute
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetClassEndMinute() uint32 {
+	if x != nil {
+		return x.ClassEndMinute
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetSexLock() uint32 {
+	if x != nil {
+		return x.SexLock
+	}
+	return 0
+}
+
+func (x *PutCourseRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *PutCourseRequest) GetEligibility() *CourseEligibility {
+	if x != nil {
+		return x.Eligibility
+	}
+	return nil
+}
+
+func (x *PutCourseRequest) GetConfirmationWindow() int64 {
+	if x != nil {
+		return x.ConfirmationWindow
 	}
 	return 0
 }
@@ -748,138 +1803,349 @@ var file_pkg_proto_student_proto_rawDesc = []byte{
 	0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
 	0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14, 0x70,
 	0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0x6d, 0x0a, 0x14, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x45, 0x6e,
-	0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
-	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f,
-	0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63,
-	0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x49, 0x64, 0x22, 0x55, 0x0a, 0x17, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73,
-	0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a,
-	0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09,
-	0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x22, 0x79, 0x0a, 0x19, 0x53, 0x74, 0x75,
-	0x64, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
+	0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x18,
+	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x6c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6d,
+	0x0a, 0x14, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
 	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64,
 	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f,
 	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
-	0x49, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x6e, 0x65, 0x77, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
-	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x49, 0x64, 0x22, 0x39, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65,
-	0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22,
-	0x42, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x44, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74,
-	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23,
-	0x0a, 0x0d, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e,
-	0x74, 0x49, 0x64, 0x22, 0x8b, 0x02, 0x0a, 0x0a, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61,
-	0x74, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12,
-	0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e,
-	0x69, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73,
-	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10,
-	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
-	0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x61, 0x6d, 0x5f,
-	0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d,
-	0x54, 0x69, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x74, 0x69,
-	0x6d, 0x65, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73,
-	0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65,
-	0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65,
-	0x72, 0x22, 0x74, 0x0a, 0x11, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72,
-	0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x29, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
-	0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x72, 0x73,
-	0x65, 0x12, 0x34, 0x0a, 0x16, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x71, 0x75, 0x65,
-	0x75, 0x65, 0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x14, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x51, 0x75, 0x65, 0x75, 0x65, 0x50,
-	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x46, 0x0a, 0x16, 0x53, 0x74, 0x75, 0x64, 0x65,
-	0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x41, 0x72, 0x72, 0x61,
-	0x79, 0x12, 0x2c, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43,
-	0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22,
-	0x40, 0x0a, 0x11, 0x44, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75,
-	0x72, 0x73, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f,
-	0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
-	0x73, 0x22, 0x51, 0x0a, 0x17, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x4f, 0x66, 0x43,
-	0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09,
-	0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x49, 0x64, 0x22, 0x83, 0x01, 0x0a, 0x18, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
-	0x73, 0x4f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x2f, 0x0a, 0x13, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x5f,
-	0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x12,
-	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e,
-	0x74, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x5f, 0x71,
-	0x75, 0x65, 0x75, 0x65, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x04, 0x52, 0x15, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65,
-	0x75, 0x65, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x78, 0x0a, 0x1b, 0x43, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69,
-	0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75,
+	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0x55, 0x0a,
+	0x17, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74,
+	0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x49, 0x64, 0x22, 0x79, 0x0a, 0x19, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x20, 0x0a,
+	0x0c, 0x6e, 0x65, 0x77, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22,
+	0xb3, 0x01, 0x0a, 0x0d, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x33, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73,
+	0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0x35,
+	0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x4e, 0x52, 0x4f,
+	0x4c, 0x4c, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x44, 0x49, 0x53, 0x45, 0x4e, 0x52, 0x4f, 0x4c,
+	0x4c, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x47, 0x52,
+	0x4f, 0x55, 0x50, 0x10, 0x02, 0x22, 0x6b, 0x0a, 0x1a, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
+	0x41, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x2e, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x73, 0x22, 0x39, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
+	0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x42, 0x0a,
+	0x1b, 0x47, 0x65, 0x74, 0x44, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d,
+	0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x49,
+	0x64, 0x22, 0x8b, 0x02, 0x0a, 0x0a, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a,
+	0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x61, 0x6d, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
+	0x6c, 0x61, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x22,
+	0x45, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1b,
+	0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0x41, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a,
+	0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x22, 0xe9, 0x01, 0x0a, 0x0b, 0x43, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x53, 0x65, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75,
 	0x72, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f,
 	0x75, 0x72, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
 	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49,
-	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x65, 0x77, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74,
-	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6e, 0x65, 0x77, 0x43, 0x61, 0x70, 0x61,
-	0x63, 0x69, 0x74, 0x79, 0x32, 0xea, 0x05, 0x0a, 0x1d, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45,
-	0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x0d, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e,
-	0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4a, 0x0a, 0x10,
-	0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c,
-	0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
-	0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4e, 0x0a, 0x12, 0x53, 0x74, 0x75, 0x64,
-	0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x20,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5b, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x53,
-	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x43, 0x6f,
-	0x75, 0x72, 0x73, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65,
-	0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53,
-	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61,
-	0x41, 0x72, 0x72, 0x61, 0x79, 0x12, 0x56, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x75, 0x72,
-	0x73, 0x65, 0x73, 0x4f, 0x66, 0x44, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12,
-	0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x70, 0x61, 0x72,
-	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x70, 0x61,
-	0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x12, 0x56, 0x0a,
-	0x13, 0x47, 0x65, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x49, 0x6e, 0x43, 0x6f,
-	0x75, 0x72, 0x73, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75,
-	0x64, 0x65, 0x6e, 0x74, 0x73, 0x4f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75,
-	0x64, 0x65, 0x6e, 0x74, 0x73, 0x4f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x45, 0x6e,
-	0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75,
-	0x64, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x48, 0x0a, 0x0e, 0x46, 0x6f, 0x72,
-	0x63, 0x65, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x1e, 0x2e, 0x70, 0x72,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a,
+	0x10, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63,
+	0x69, 0x74, 0x79, 0x12, 0x30, 0x0a, 0x14, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x71,
+	0x75, 0x65, 0x75, 0x65, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x12, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x51, 0x75, 0x65, 0x75, 0x65, 0x4c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0x41, 0x0a, 0x11, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x53,
+	0x65, 0x61, 0x74, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x63, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x53, 0x65, 0x61, 0x74, 0x73, 0x52,
+	0x07, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x22, 0xa9, 0x01, 0x0a, 0x11, 0x53, 0x74, 0x75,
+	0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x29,
+	0x0a, 0x06, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74,
+	0x61, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x72, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x5f, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x51, 0x75, 0x65, 0x75, 0x65, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x33, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x61, 0x64,
+	0x6c, 0x69, 0x6e, 0x65, 0x22, 0x38, 0x0a, 0x1c, 0x41, 0x64, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x22, 0xa6,
+	0x01, 0x0a, 0x11, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x49, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49,
+	0x64, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x48, 0x0a, 0x12, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x32, 0x0a,
+	0x07, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x73, 0x22, 0x46, 0x0a, 0x16, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x41, 0x72, 0x72, 0x61, 0x79, 0x12, 0x2c, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x40, 0x0a, 0x11, 0x44, 0x65, 0x70,
+	0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x12, 0x2b,
+	0x0a, 0x07, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61,
+	0x74, 0x61, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x17, 0x53,
+	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x4f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73,
+	0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x22, 0x83,
+	0x01, 0x0a, 0x18, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x4f, 0x66, 0x43, 0x6f, 0x75,
+	0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x13, 0x72,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x12, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x65, 0x64, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x17,
+	0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x5f, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x73,
+	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x04, 0x52, 0x15, 0x72,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x51, 0x75, 0x65, 0x75, 0x65, 0x53, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0x78, 0x0a, 0x1b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x43, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x49, 0x64,
+	0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6e,
+	0x65, 0x77, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0b, 0x6e, 0x65, 0x77, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0xcc,
+	0x02, 0x0a, 0x11, 0x50, 0x75, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x32, 0x0a, 0x15, 0x65, 0x6e, 0x72, 0x6f,
+	0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d,
+	0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x6d, 0x61, 0x78, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x6d, 0x61, 0x78, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x65, 0x6d,
+	0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74,
+	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64,
+	0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65,
+	0x6e, 0x74, 0x72, 0x79, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x59, 0x65, 0x61, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x65,
+	0x6d, 0x61, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x65, 0x6d, 0x61,
+	0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x70, 0x61, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x70, 0x61, 0x42, 0x61, 0x6e, 0x64, 0x22, 0x63, 0x0a,
+	0x12, 0x50, 0x75, 0x74, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x2e, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x69, 0x73, 0x68,
+	0x6c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x22, 0x29, 0x0a, 0x11, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x22, 0x62, 0x0a,
+	0x12, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x04, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x72,
+	0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65, 0x6e, 0x72,
+	0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x64, 0x22, 0xbe, 0x04, 0x0a, 0x10, 0x50, 0x75, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6f, 0x75, 0x72, 0x73,
+	0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x23,
+	0x0a, 0x0d, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75,
+	0x72, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x65, 0x63, 0x74, 0x75,
+	0x72, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x61, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x61, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2d, 0x0a,
+	0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28,
+	0x0e, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x65, 0x65, 0x6b, 0x64, 0x61,
+	0x79, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x44, 0x61, 0x79, 0x73, 0x12, 0x2c, 0x0a, 0x12,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x6d, 0x69, 0x6e, 0x75,
+	0x74, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x4d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x6d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x45, 0x6e, 0x64, 0x4d, 0x69,
+	0x6e, 0x75, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x65, 0x78, 0x5f, 0x6c, 0x6f, 0x63, 0x6b,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x73, 0x65, 0x78, 0x4c, 0x6f, 0x63, 0x6b, 0x12,
+	0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x3a, 0x0a, 0x0b, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x52, 0x0b, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x2f, 0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x32, 0xe6, 0x0d, 0x0a, 0x1d, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6e, 0x72,
+	0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x44, 0x0a, 0x0d, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x45,
+	0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74,
+	0x75, 0x64, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4a, 0x0a, 0x10, 0x53, 0x74,
+	0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x1e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x69,
+	0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4e, 0x0a, 0x12, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e,
+	0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x20, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5b, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x53, 0x74, 0x75,
+	0x64, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75,
+	0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x44, 0x61, 0x74, 0x61, 0x41, 0x72,
+	0x72, 0x61, 0x79, 0x12, 0x56, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65,
+	0x73, 0x4f, 0x66, 0x44, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x22, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x70, 0x61, 0x72, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x70, 0x61, 0x72, 0x74,
+	0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x12, 0x56, 0x0a, 0x13, 0x47,
+	0x65, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x49, 0x6e, 0x43, 0x6f, 0x75, 0x72,
+	0x73, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65,
+	0x6e, 0x74, 0x73, 0x4f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65,
+	0x6e, 0x74, 0x73, 0x4f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x45, 0x6e, 0x72, 0x6f,
+	0x6c, 0x6c, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65,
+	0x6e, 0x74, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x48, 0x0a, 0x0e, 0x46, 0x6f, 0x72, 0x63, 0x65,
+	0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f,
+	0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x12, 0x4c, 0x0a, 0x0e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63,
+	0x69, 0x74, 0x79, 0x12, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x3e, 0x0a, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x12, 0x17, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65,
+	0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3e, 0x0a, 0x0a, 0x50, 0x75, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x3c, 0x0a, 0x09, 0x50, 0x75, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x12, 0x17, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x74, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a,
+	0x0b, 0x47, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x72,
+	0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12,
+	0x40, 0x0a, 0x0b, 0x50, 0x75, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x19,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e,
+	0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x12, 0x5d, 0x0a, 0x14, 0x41, 0x64, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x51, 0x0a, 0x17, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x65, 0x6e,
 	0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x12, 0x4c, 0x0a, 0x0e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x43, 0x61, 0x70,
-	0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69,
-	0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x42, 0x1c, 0x5a, 0x1a, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c,
-	0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x74, 0x79, 0x12, 0x53, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43,
+	0x6f, 0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x49, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x50, 0x0a, 0x13, 0x53, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12,
+	0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x41,
+	0x70, 0x70, 0x6c, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x0b, 0x50, 0x75,
+	0x74, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x50, 0x75, 0x74, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3f, 0x0a, 0x0b,
+	0x47, 0x65, 0x74, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x1f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x43, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x69, 0x73, 0x68, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x41, 0x0a,
+	0x0a, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x12, 0x18, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75,
+	0x6e, 0x4c, 0x6f, 0x74, 0x74, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x46, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x73,
+	0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f,
+	0x75, 0x72, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x75, 0x72, 0x73, 0x65, 0x53, 0x65, 0x61, 0x74, 0x73,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x51, 0x0a, 0x17, 0x53, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x72, 0x6f, 0x6d, 0x6f, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x75, 0x64,
+	0x65, 0x6e, 0x74, 0x44, 0x69, 0x73, 0x65, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x1c, 0x5a, 0x1a, 0x43,
+	0x6f, 0x75, 0x72, 0x73, 0x65, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x6d, 0x65, 0x6e, 0x74, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -894,51 +2160,110 @@ func file_pkg_proto_student_proto_rawDescGZIP() []byte {
 	return file_pkg_proto_student_proto_rawDescData
 }
 
-var file_pkg_proto_student_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pkg_proto_student_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_proto_student_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_pkg_proto_student_proto_goTypes = []interface{}{
-	(*StudentEnrollRequest)(nil),        // 0: proto.StudentEnrollRequest
-	(*StudentDisenrollRequest)(nil),     // 1: proto.StudentDisenrollRequest
-	(*StudentChangeGroupRequest)(nil),   // 2: proto.StudentChangeGroupRequest
-	(*GetStudentCoursesRequest)(nil),    // 3: proto.GetStudentCoursesRequest
-	(*GetDepartmentCoursesRequest)(nil), // 4: proto.GetDepartmentCoursesRequest
-	(*CourseData)(nil),                  // 5: proto.CourseData
-	(*StudentCourseData)(nil),           // 6: proto.StudentCourseData
-	(*StudentCourseDataArray)(nil),      // 7: proto.StudentCourseDataArray
-	(*DepartmentCourses)(nil),           // 8: proto.DepartmentCourses
-	(*StudentsOfCourseRequest)(nil),     // 9: proto.StudentsOfCourseRequest
-	(*StudentsOfCourseResponse)(nil),    // 10: proto.StudentsOfCourseResponse
-	(*ChangeCourseCapacityRequest)(nil), // 11: proto.ChangeCourseCapacityRequest
-	(*ClassTime)(nil),                   // 12: proto.ClassTime
-	(*emptypb.Empty)(nil),               // 13: google.protobuf.Empty
+	(StudentChange_Action)(0),            // 0: proto.StudentChange.Action
+	(*StudentEnrollRequest)(nil),         // 1: proto.StudentEnrollRequest
+	(*StudentDisenrollRequest)(nil),      // 2: proto.StudentDisenrollRequest
+	(*StudentChangeGroupRequest)(nil),    // 3: proto.StudentChangeGroupRequest
+	(*StudentChange)(nil),                // 4: proto.StudentChange
+	(*StudentApplyChangesRequest)(nil),   // 5: proto.StudentApplyChangesRequest
+	(*GetStudentCoursesRequest)(nil),     // 6: proto.GetStudentCoursesRequest
+	(*GetDepartmentCoursesRequest)(nil),  // 7: proto.GetDepartmentCoursesRequest
+	(*CourseData)(nil),                   // 8: proto.CourseData
+	(*CourseGroup)(nil),                  // 9: proto.CourseGroup
+	(*WatchCoursesRequest)(nil),          // 10: proto.WatchCoursesRequest
+	(*CourseSeats)(nil),                  // 11: proto.CourseSeats
+	(*CourseSeatsUpdate)(nil),            // 12: proto.CourseSeatsUpdate
+	(*StudentCourseData)(nil),            // 13: proto.StudentCourseData
+	(*AddGroupChangeIntentResponse)(nil), // 14: proto.AddGroupChangeIntentResponse
+	(*GroupChangeIntent)(nil),            // 15: proto.GroupChangeIntent
+	(*GroupChangeIntents)(nil),           // 16: proto.GroupChangeIntents
+	(*StudentCourseDataArray)(nil),       // 17: proto.StudentCourseDataArray
+	(*DepartmentCourses)(nil),            // 18: proto.DepartmentCourses
+	(*StudentsOfCourseRequest)(nil),      // 19: proto.StudentsOfCourseRequest
+	(*StudentsOfCourseResponse)(nil),     // 20: proto.StudentsOfCourseResponse
+	(*ChangeCourseCapacityRequest)(nil),  // 21: proto.ChangeCourseCapacityRequest
+	(*PutStudentRequest)(nil),            // 22: proto.PutStudentRequest
+	(*PutWishlistRequest)(nil),           // 23: proto.PutWishlistRequest
+	(*RunLotteryRequest)(nil),            // 24: proto.RunLotteryRequest
+	(*RunLotteryResponse)(nil),           // 25: proto.RunLotteryResponse
+	(*PutCourseRequest)(nil),             // 26: proto.PutCourseRequest
+	(*ClassTime)(nil),                    // 27: proto.ClassTime
+	(*WishlistEntry)(nil),                // 28: proto.WishlistEntry
+	(Weekday)(0),                         // 29: proto.Weekday
+	(*CourseEligibility)(nil),            // 30: proto.CourseEligibility
+	(*ReconcileRequest)(nil),             // 31: proto.ReconcileRequest
+	(*emptypb.Empty)(nil),                // 32: google.protobuf.Empty
+	(*EnrollmentSchedule)(nil),           // 33: proto.EnrollmentSchedule
+	(*ReconcileResponse)(nil),            // 34: proto.ReconcileResponse
+	(*Wishlist)(nil),                     // 35: proto.Wishlist
 }
 var file_pkg_proto_student_proto_depIdxs = []int32{
-	12, // 0: proto.CourseData.class_time:type_name -> proto.ClassTime
-	5,  // 1: proto.StudentCourseData.course:type_name -> proto.CourseData
-	6,  // 2: proto.StudentCourseDataArray.data:type_name -> proto.StudentCourseData
-	5,  // 3: proto.DepartmentCourses.courses:type_name -> proto.CourseData
-	0,  // 4: proto.CourseEnrollmentServerService.StudentEnroll:input_type -> proto.StudentEnrollRequest
-	1,  // 5: proto.CourseEnrollmentServerService.StudentDisenroll:input_type -> proto.StudentDisenrollRequest
-	2,  // 6: proto.CourseEnrollmentServerService.StudentChangeGroup:input_type -> proto.StudentChangeGroupRequest
-	3,  // 7: proto.CourseEnrollmentServerService.GetStudentEnrolledCourses:input_type -> proto.GetStudentCoursesRequest
-	4,  // 8: proto.CourseEnrollmentServerService.GetCoursesOfDepartment:input_type -> proto.GetDepartmentCoursesRequest
-	9,  // 9: proto.CourseEnrollmentServerService.GetStudentsInCourse:input_type -> proto.StudentsOfCourseRequest
-	0,  // 10: proto.CourseEnrollmentServerService.ForceEnroll:input_type -> proto.StudentEnrollRequest
-	1,  // 11: proto.CourseEnrollmentServerService.ForceDisenroll:input_type -> proto.StudentDisenrollRequest
-	11, // 12: proto.CourseEnrollmentServerService.ChangeCapacity:input_type -> proto.ChangeCourseCapacityRequest
-	13, // 13: proto.CourseEnrollmentServerService.StudentEnroll:output_type -> google.protobuf.Empty
-	13, // 14: proto.CourseEnrollmentServerService.StudentDisenroll:output_type -> google.protobuf.Empty
-	13, // 15: proto.CourseEnrollmentServerService.StudentChangeGroup:output_type -> google.protobuf.Empty
-	7,  // 16: proto.CourseEnrollmentServerService.GetStudentEnrolledCourses:output_type -> proto.StudentCourseDataArray
-	8,  // 17: proto.CourseEnrollmentServerService.GetCoursesOfDepartment:output_type -> proto.DepartmentCourses
-	10, // 18: proto.CourseEnrollmentServerService.GetStudentsInCourse:output_type -> proto.StudentsOfCourseResponse
-	13, // 19: proto.CourseEnrollmentServerService.ForceEnroll:output_type -> google.protobuf.Empty
-	13, // 20: proto.CourseEnrollmentServerService.ForceDisenroll:output_type -> google.protobuf.Empty
-	13, // 21: proto.CourseEnrollmentServerService.ChangeCapacity:output_type -> google.protobuf.Empty
-	13, // [13:22] is the sub-list for method output_type
-	4,  // [4:13] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	0,  // 0: proto.StudentChange.action:type_name -> proto.StudentChange.Action
+	4,  // 1: proto.StudentApplyChangesRequest.changes:type_name -> proto.StudentChange
+	27, // 2: proto.CourseData.class_time:type_name -> proto.ClassTime
+	9,  // 3: proto.WatchCoursesRequest.groups:type_name -> proto.CourseGroup
+	11, // 4: proto.CourseSeatsUpdate.courses:type_name -> proto.CourseSeats
+	8,  // 5: proto.StudentCourseData.course:type_name -> proto.CourseData
+	15, // 6: proto.GroupChangeIntents.intents:type_name -> proto.GroupChangeIntent
+	13, // 7: proto.StudentCourseDataArray.data:type_name -> proto.StudentCourseData
+	8,  // 8: proto.DepartmentCourses.courses:type_name -> proto.CourseData
+	28, // 9: proto.PutWishlistRequest.entries:type_name -> proto.WishlistEntry
+	29, // 10: proto.PutCourseRequest.class_days:type_name -> proto.Weekday
+	30, // 11: proto.PutCourseRequest.eligibility:type_name -> proto.CourseEligibility
+	1,  // 12: proto.CourseEnrollmentServerService.StudentEnroll:input_type -> proto.StudentEnrollRequest
+	2,  // 13: proto.CourseEnrollmentServerService.StudentDisenroll:input_type -> proto.StudentDisenrollRequest
+	3,  // 14: proto.CourseEnrollmentServerService.StudentChangeGroup:input_type -> proto.StudentChangeGroupRequest
+	6,  // 15: proto.CourseEnrollmentServerService.GetStudentEnrolledCourses:input_type -> proto.GetStudentCoursesRequest
+	7,  // 16: proto.CourseEnrollmentServerService.GetCoursesOfDepartment:input_type -> proto.GetDepartmentCoursesRequest
+	19, // 17: proto.CourseEnrollmentServerService.GetStudentsInCourse:input_type -> proto.StudentsOfCourseRequest
+	1,  // 18: proto.CourseEnrollmentServerService.ForceEnroll:input_type -> proto.StudentEnrollRequest
+	2,  // 19: proto.CourseEnrollmentServerService.ForceDisenroll:input_type -> proto.StudentDisenrollRequest
+	21, // 20: proto.CourseEnrollmentServerService.ChangeCapacity:input_type -> proto.ChangeCourseCapacityRequest
+	31, // 21: proto.CourseEnrollmentServerService.Reconcile:input_type -> proto.ReconcileRequest
+	22, // 22: proto.CourseEnrollmentServerService.PutStudent:input_type -> proto.PutStudentRequest
+	26, // 23: proto.CourseEnrollmentServerService.PutCourse:input_type -> proto.PutCourseRequest
+	32, // 24: proto.CourseEnrollmentServerService.GetSchedule:input_type -> google.protobuf.Empty
+	33, // 25: proto.CourseEnrollmentServerService.PutSchedule:input_type -> proto.EnrollmentSchedule
+	3,  // 26: proto.CourseEnrollmentServerService.AddGroupChangeIntent:input_type -> proto.StudentChangeGroupRequest
+	2,  // 27: proto.CourseEnrollmentServerService.CancelGroupChangeIntent:input_type -> proto.StudentDisenrollRequest
+	6,  // 28: proto.CourseEnrollmentServerService.GetGroupChangeIntents:input_type -> proto.GetStudentCoursesRequest
+	5,  // 29: proto.CourseEnrollmentServerService.StudentApplyChanges:input_type -> proto.StudentApplyChangesRequest
+	23, // 30: proto.CourseEnrollmentServerService.PutWishlist:input_type -> proto.PutWishlistRequest
+	6,  // 31: proto.CourseEnrollmentServerService.GetWishlist:input_type -> proto.GetStudentCoursesRequest
+	24, // 32: proto.CourseEnrollmentServerService.RunLottery:input_type -> proto.RunLotteryRequest
+	10, // 33: proto.CourseEnrollmentServerService.WatchCourses:input_type -> proto.WatchCoursesRequest
+	2,  // 34: proto.CourseEnrollmentServerService.StudentConfirmPromotion:input_type -> proto.StudentDisenrollRequest
+	32, // 35: proto.CourseEnrollmentServerService.StudentEnroll:output_type -> google.protobuf.Empty
+	32, // 36: proto.CourseEnrollmentServerService.StudentDisenroll:output_type -> google.protobuf.Empty
+	32, // 37: proto.CourseEnrollmentServerService.StudentChangeGroup:output_type -> google.protobuf.Empty
+	17, // 38: proto.CourseEnrollmentServerService.GetStudentEnrolledCourses:output_type -> proto.StudentCourseDataArray
+	18, // 39: proto.CourseEnrollmentServerService.GetCoursesOfDepartment:output_type -> proto.DepartmentCourses
+	20, // 40: proto.CourseEnrollmentServerService.GetStudentsInCourse:output_type -> proto.StudentsOfCourseResponse
+	32, // 41: proto.CourseEnrollmentServerService.ForceEnroll:output_type -> google.protobuf.Empty
+	32, // 42: proto.CourseEnrollmentServerService.ForceDisenroll:output_type -> google.protobuf.Empty
+	32, // 43: proto.CourseEnrollmentServerService.ChangeCapacity:output_type -> google.protobuf.Empty
+	34, // 44: proto.CourseEnrollmentServerService.Reconcile:output_type -> proto.ReconcileResponse
+	32, // 45: proto.CourseEnrollmentServerService.PutStudent:output_type -> google.protobuf.Empty
+	32, // 46: proto.CourseEnrollmentServerService.PutCourse:output_type -> google.protobuf.Empty
+	33, // 47: proto.CourseEnrollmentServerService.GetSchedule:output_type -> proto.EnrollmentSchedule
+	32, // 48: proto.CourseEnrollmentServerService.PutSchedule:output_type -> google.protobuf.Empty
+	14, // 49: proto.CourseEnrollmentServerService.AddGroupChangeIntent:output_type -> proto.AddGroupChangeIntentResponse
+	32, // 50: proto.CourseEnrollmentServerService.CancelGroupChangeIntent:output_type -> google.protobuf.Empty
+	16, // 51: proto.CourseEnrollmentServerService.GetGroupChangeIntents:output_type -> proto.GroupChangeIntents
+	32, // 52: proto.CourseEnrollmentServerService.StudentApplyChanges:output_type -> google.protobuf.Empty
+	32, // 53: proto.CourseEnrollmentServerService.PutWishlist:output_type -> google.protobuf.Empty
+	35, // 54: proto.CourseEnrollmentServerService.GetWishlist:output_type -> proto.Wishlist
+	25, // 55: proto.CourseEnrollmentServerService.RunLottery:output_type -> proto.RunLotteryResponse
+	12, // 56: proto.CourseEnrollmentServerService.WatchCourses:output_type -> proto.CourseSeatsUpdate
+	32, // 57: proto.CourseEnrollmentServerService.StudentConfirmPromotion:output_type -> google.protobuf.Empty
+	35, // [35:58] is the sub-list for method output_type
+	12, // [12:35] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_student_proto_init() }
@@ -947,6 +2272,10 @@ func file_pkg_proto_student_proto_init() {
 		return
 	}
 	file_pkg_proto_time_proto_init()
+	file_pkg_proto_reconcile_proto_init()
+	file_pkg_proto_schedule_proto_init()
+	file_pkg_proto_eligibility_proto_init()
+	file_pkg_proto_lottery_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_pkg_proto_student_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StudentEnrollRequest); i {
@@ -985,7 +2314,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetStudentCoursesRequest); i {
+			switch v := v.(*StudentChange); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -997,7 +2326,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetDepartmentCoursesRequest); i {
+			switch v := v.(*StudentApplyChangesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1009,7 +2338,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CourseData); i {
+			switch v := v.(*GetStudentCoursesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1021,7 +2350,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StudentCourseData); i {
+			switch v := v.(*GetDepartmentCoursesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1033,7 +2362,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StudentCourseDataArray); i {
+			switch v := v.(*CourseData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1045,7 +2374,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DepartmentCourses); i {
+			switch v := v.(*CourseGroup); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1057,7 +2386,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StudentsOfCourseRequest); i {
+			switch v := v.(*WatchCoursesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1069,7 +2398,7 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StudentsOfCourseResponse); i {
+			switch v := v.(*CourseSeats); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1081,6 +2410,114 @@ func file_pkg_proto_student_proto_init() {
 			}
 		}
 		file_pkg_proto_student_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CourseSeatsUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudentCourseData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddGroupChangeIntentResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupChangeIntent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupChangeIntents); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudentCourseDataArray); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepartmentCourses); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudentsOfCourseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudentsOfCourseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ChangeCourseCapacityRequest); i {
 			case 0:
 				return &v.state
@@ -1092,19 +2529,80 @@ func file_pkg_proto_student_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_proto_student_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PutStudentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PutWishlistRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunLotteryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunLotteryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_student_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PutCourseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_student_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   12,
+			NumEnums:      1,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_proto_student_proto_goTypes,
 		DependencyIndexes: file_pkg_proto_student_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_student_proto_enumTypes,
 		MessageInfos:      file_pkg_proto_student_proto_msgTypes,
 	}.Build()
 	File_pkg_proto_student_proto = out.File
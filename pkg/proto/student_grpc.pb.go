@@ -42,6 +42,38 @@ type CourseEnrollmentServerServiceClient interface {
 	// This endpoint will change the capacity of a course if possible. It is not possible
 	// to change the capacity if the new capacity if less than the registered users.
 	ChangeCapacity(ctx context.Context, in *ChangeCourseCapacityRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This endpoint compares the registered students and reserve queue of every course with the
+	// database and optionally repairs the differences.
+	Reconcile(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileResponse, error)
+	// This endpoint creates a student or replaces the data of an existing one
+	PutStudent(ctx context.Context, in *PutStudentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This endpoint creates a course group or replaces the data of an existing one
+	PutCourse(ctx context.Context, in *PutCourseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This endpoint returns the enrollment schedule
+	GetSchedule(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EnrollmentSchedule, error)
+	// This endpoint replaces the enrollment schedule
+	PutSchedule(ctx context.Context, in *EnrollmentSchedule, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This method registers the intent of a student to change their group when the destination
+	// group has a free seat. If it has a free seat right now, the group is changed immediately.
+	AddGroupChangeIntent(ctx context.Context, in *StudentChangeGroupRequest, opts ...grpc.CallOption) (*AddGroupChangeIntentResponse, error)
+	// This method cancels the intent of a student to change their group in a course
+	CancelGroupChangeIntent(ctx context.Context, in *StudentDisenrollRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This method lists the pending intents of a student to change their groups
+	GetGroupChangeIntents(ctx context.Context, in *GetStudentCoursesRequest, opts ...grpc.CallOption) (*GroupChangeIntents, error)
+	// This method applies a list of enroll, disenroll and change group actions of a student
+	// atomically. Either all of them are applied or none of them.
+	StudentApplyChanges(ctx context.Context, in *StudentApplyChangesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This method replaces the wishlist of a student in the open lottery phase
+	PutWishlist(ctx context.Context, in *PutWishlistRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This method returns the wishlist of a student
+	GetWishlist(ctx context.Context, in *GetStudentCoursesRequest, opts ...grpc.CallOption) (*Wishlist, error)
+	// This endpoint allocates the seats of a closed lottery phase based on the wishlists
+	RunLottery(ctx context.Context, in *RunLotteryRequest, opts ...grpc.CallOption) (*RunLotteryResponse, error)
+	// This method streams the seats of some course groups. The current seats of every watched group
+	// are sent at first, and then the groups are sent again whenever their seats change.
+	WatchCourses(ctx context.Context, in *WatchCoursesRequest, opts ...grpc.CallOption) (CourseEnrollmentServerService_WatchCoursesClient, error)
+	// This method confirms the seat of a student which is promoted from the reserve queue of a course
+	StudentConfirmPromotion(ctx context.Context, in *StudentDisenrollRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type courseEnrollmentServerServiceClient struct {
@@ -133,6 +165,155 @@ func (c *courseEnrollmentServerServiceClient) ChangeCapacity(ctx context.Context
 	return out, nil
 }
 
+func (c *courseEnrollmentServerServiceClient) Reconcile(ctx context.Context, in *ReconcileRequest, opts ...grpc.CallOption) (*ReconcileResponse, error) {
+	out := new(ReconcileResponse)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/Reconcile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) PutStudent(ctx context.Context, in *PutStudentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/PutStudent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) PutCourse(ctx context.Context, in *PutCourseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/PutCourse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) GetSchedule(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EnrollmentSchedule, error) {
+	out := new(EnrollmentSchedule)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/GetSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) PutSchedule(ctx context.Context, in *EnrollmentSchedule, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/PutSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) AddGroupChangeIntent(ctx context.Context, in *StudentChangeGroupRequest, opts ...grpc.CallOption) (*AddGroupChangeIntentResponse, error) {
+	out := new(AddGroupChangeIntentResponse)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/AddGroupChangeIntent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) CancelGroupChangeIntent(ctx context.Context, in *StudentDisenrollRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/CancelGroupChangeIntent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) GetGroupChangeIntents(ctx context.Context, in *GetStudentCoursesRequest, opts ...grpc.CallOption) (*GroupChangeIntents, error) {
+	out := new(GroupChangeIntents)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/GetGroupChangeIntents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) StudentApplyChanges(ctx context.Context, in *StudentApplyChangesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/StudentApplyChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) PutWishlist(ctx context.Context, in *PutWishlistRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/PutWishlist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) GetWishlist(ctx context.Context, in *GetStudentCoursesRequest, opts ...grpc.CallOption) (*Wishlist, error) {
+	out := new(Wishlist)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/GetWishlist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) RunLottery(ctx context.Context, in *RunLotteryRequest, opts ...grpc.CallOption) (*RunLotteryResponse, error) {
+	out := new(RunLotteryResponse)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/RunLottery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) WatchCourses(ctx context.Context, in *WatchCoursesRequest, opts ...grpc.CallOption) (CourseEnrollmentServerService_WatchCoursesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CourseEnrollmentServerService_ServiceDesc.Streams[0], "/proto.CourseEnrollmentServerService/WatchCourses", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &courseEnrollmentServerServiceWatchCoursesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CourseEnrollmentServerService_WatchCoursesClient interface {
+	Recv() (*CourseSeatsUpdate, error)
+	grpc.ClientStream
+}
+
+type courseEnrollmentServerServiceWatchCoursesClient struct {
+	grpc.ClientStream
+}
+
+func (x *courseEnrollmentServerServiceWatchCoursesClient) Recv() (*CourseSeatsUpdate, error) {
+	m := new(CourseSeatsUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *courseEnrollmentServerServiceClient) StudentConfirmPromotion(ctx context.Context, in *StudentDisenrollRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.CourseEnrollmentServerService/StudentConfirmPromotion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CourseEnrollmentServerServiceServer is the server API for CourseEnrollmentServerService service.
 // All implementations must embed UnimplementedCourseEnrollmentServerServiceServer
 // for forward compatibility
@@ -156,6 +337,38 @@ type CourseEnrollmentServerServiceServer interface {
 	// This endpoint will change the capacity of a course if possible. It is not possible
 	// to change the capacity if the new capacity if less than the registered users.
 	ChangeCapacity(context.Context, *ChangeCourseCapacityRequest) (*emptypb.Empty, error)
+	// This endpoint compares the registered students and reserve queue of every course with the
+	// database and optionally repairs the differences.
+	Reconcile(context.Context, *ReconcileRequest) (*ReconcileResponse, error)
+	// This endpoint creates a student or replaces the data of an existing one
+	PutStudent(context.Context, *PutStudentRequest) (*emptypb.Empty, error)
+	// This endpoint creates a course group or replaces the data of an existing one
+	PutCourse(context.Context, *PutCourseRequest) (*emptypb.Empty, error)
+	// This endpoint returns the enrollment schedule
+	GetSchedule(context.Context, *emptypb.Empty) (*EnrollmentSchedule, error)
+	// This endpoint replaces the enrollment schedule
+	PutSchedule(context.Context, *EnrollmentSchedule) (*emptypb.Empty, error)
+	// This method registers the intent of a student to change their group when the destination
+	// group has a free seat. If it has a free seat right now, the group is changed immediately.
+	AddGroupChangeIntent(context.Context, *StudentChangeGroupRequest) (*AddGroupChangeIntentResponse, error)
+	// This method cancels the intent of a student to change their group in a course
+	CancelGroupChangeIntent(context.Context, *StudentDisenrollRequest) (*emptypb.Empty, error)
+	// This method lists the pending intents of a student to change their groups
+	GetGroupChangeIntents(context.Context, *GetStudentCoursesRequest) (*GroupChangeIntents, error)
+	// This method applies a list of enroll, disenroll and change group actions of a student
+	// atomically. Either all of them are applied or none of them.
+	StudentApplyChanges(context.Context, *StudentApplyChangesRequest) (*emptypb.Empty, error)
+	// This method replaces the wishlist of a student in the open lottery phase
+	PutWishlist(context.Context, *PutWishlistRequest) (*emptypb.Empty, error)
+	// This method returns the wishlist of a student
+	GetWishlist(context.Context, *GetStudentCoursesRequest) (*Wishlist, error)
+	// This endpoint allocates the seats of a closed lottery phase based on the wishlists
+	RunLottery(context.Context, *RunLotteryRequest) (*RunLotteryResponse, error)
+	// This method streams the seats of some course groups. The current seats of every watched group
+	// are sent at first, and then the groups are sent again whenever their seats change.
+	WatchCourses(*WatchCoursesRequest, CourseEnrollmentServerService_WatchCoursesServer) error
+	// This method confirms the seat of a student which is promoted from the reserve queue of a course
+	StudentConfirmPromotion(context.Context, *StudentDisenrollRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedCourseEnrollmentServerServiceServer()
 }
 
@@ -190,6 +403,48 @@ func (UnimplementedCourseEnrollmentServerServiceServer) ForceDisenroll(context.C
 func (UnimplementedCourseEnrollmentServerServiceServer) ChangeCapacity(context.Context, *ChangeCourseCapacityRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChangeCapacity not implemented")
 }
+func (UnimplementedCourseEnrollmentServerServiceServer) Reconcile(context.Context, *ReconcileRequest) (*ReconcileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reconcile not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) PutStudent(context.Context, *PutStudentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutStudent not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) PutCourse(context.Context, *PutCourseRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutCourse not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) GetSchedule(context.Context, *emptypb.Empty) (*EnrollmentSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchedule not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) PutSchedule(context.Context, *EnrollmentSchedule) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutSchedule not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) AddGroupChangeIntent(context.Context, *StudentChangeGroupRequest) (*AddGroupChangeIntentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddGroupChangeIntent not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) CancelGroupChangeIntent(context.Context, *StudentDisenrollRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelGroupChangeIntent not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) GetGroupChangeIntents(context.Context, *GetStudentCoursesRequest) (*GroupChangeIntents, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroupChangeIntents not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) StudentApplyChanges(context.Context, *StudentApplyChangesRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StudentApplyChanges not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) PutWishlist(context.Context, *PutWishlistRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutWishlist not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) GetWishlist(context.Context, *GetStudentCoursesRequest) (*Wishlist, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWishlist not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) RunLottery(context.Context, *RunLotteryRequest) (*RunLotteryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunLottery not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) WatchCourses(*WatchCoursesRequest, CourseEnrollmentServerService_WatchCoursesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCourses not implemented")
+}
+func (UnimplementedCourseEnrollmentServerServiceServer) StudentConfirmPromotion(context.Context, *StudentDisenrollRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StudentConfirmPromotion not implemented")
+}
 func (UnimplementedCourseEnrollmentServerServiceServer) mustEmbedUnimplementedCourseEnrollmentServerServiceServer() {
 }
 
@@ -366,6 +621,261 @@ func _CourseEnrollmentServerService_ChangeCapacity_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CourseEnrollmentServerService_Reconcile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).Reconcile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/Reconcile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).Reconcile(ctx, req.(*ReconcileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_PutStudent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutStudentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).PutStudent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/PutStudent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).PutStudent(ctx, req.(*PutStudentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_PutCourse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutCourseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).PutCourse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/PutCourse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).PutCourse(ctx, req.(*PutCourseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/GetSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).GetSchedule(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_PutSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollmentSchedule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).PutSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/PutSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).PutSchedule(ctx, req.(*EnrollmentSchedule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_AddGroupChangeIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StudentChangeGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).AddGroupChangeIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/AddGroupChangeIntent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).AddGroupChangeIntent(ctx, req.(*StudentChangeGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_CancelGroupChangeIntent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StudentDisenrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).CancelGroupChangeIntent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/CancelGroupChangeIntent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).CancelGroupChangeIntent(ctx, req.(*StudentDisenrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_GetGroupChangeIntents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStudentCoursesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).GetGroupChangeIntents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/GetGroupChangeIntents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).GetGroupChangeIntents(ctx, req.(*GetStudentCoursesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_StudentApplyChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StudentApplyChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).StudentApplyChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/StudentApplyChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).StudentApplyChanges(ctx, req.(*StudentApplyChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_PutWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutWishlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).PutWishlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/PutWishlist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).PutWishlist(ctx, req.(*PutWishlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_GetWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStudentCoursesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).GetWishlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/GetWishlist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).GetWishlist(ctx, req.(*GetStudentCoursesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_RunLottery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunLotteryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).RunLottery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/RunLottery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).RunLottery(ctx, req.(*RunLotteryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseEnrollmentServerService_WatchCourses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCoursesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CourseEnrollmentServerServiceServer).WatchCourses(m, &courseEnrollmentServerServiceWatchCoursesServer{stream})
+}
+
+type CourseEnrollmentServerService_WatchCoursesServer interface {
+	Send(*CourseSeatsUpdate) error
+	grpc.ServerStream
+}
+
+type courseEnrollmentServerServiceWatchCoursesServer struct {
+	grpc.ServerStream
+}
+
+func (x *courseEnrollmentServerServiceWatchCoursesServer) Send(m *CourseSeatsUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CourseEnrollmentServerService_StudentConfirmPromotion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StudentDisenrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseEnrollmentServerServiceServer).StudentConfirmPromotion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.CourseEnrollmentServerService/StudentConfirmPromotion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseEnrollmentServerServiceServer).StudentConfirmPromotion(ctx, req.(*StudentDisenrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CourseEnrollmentServerService_ServiceDesc is the grpc.ServiceDesc for CourseEnrollmentServerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -409,7 +919,65 @@ var CourseEnrollmentServerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ChangeCapacity",
 			Handler:    _CourseEnrollmentServerService_ChangeCapacity_Handler,
 		},
+		{
+			MethodName: "Reconcile",
+			Handler:    _CourseEnrollmentServerService_Reconcile_Handler,
+		},
+		{
+			MethodName: "PutStudent",
+			Handler:    _CourseEnrollmentServerService_PutStudent_Handler,
+		},
+		{
+			MethodName: "PutCourse",
+			Handler:    _CourseEnrollmentServerService_PutCourse_Handler,
+		},
+		{
+			MethodName: "GetSchedule",
+			Handler:    _CourseEnrollmentServerService_GetSchedule_Handler,
+		},
+		{
+			MethodName: "PutSchedule",
+			Handler:    _CourseEnrollmentServerService_PutSchedule_Handler,
+		},
+		{
+			MethodName: "AddGroupChangeIntent",
+			Handler:    _CourseEnrollmentServerService_AddGroupChangeIntent_Handler,
+		},
+		{
+			MethodName: "CancelGroupChangeIntent",
+			Handler:    _CourseEnrollmentServerService_CancelGroupChangeIntent_Handler,
+		},
+		{
+			MethodName: "GetGroupChangeIntents",
+			Handler:    _CourseEnrollmentServerService_GetGroupChangeIntents_Handler,
+		},
+		{
+			MethodName: "StudentApplyChanges",
+			Handler:    _CourseEnrollmentServerService_StudentApplyChanges_Handler,
+		},
+		{
+			MethodName: "PutWishlist",
+			Handler:    _CourseEnrollmentServerService_PutWishlist_Handler,
+		},
+		{
+			MethodName: "GetWishlist",
+			Handler:    _CourseEnrollmentServerService_GetWishlist_Handler,
+		},
+		{
+			MethodName: "RunLottery",
+			Handler:    _CourseEnrollmentServerService_RunLottery_Handler,
+		},
+		{
+			MethodName: "StudentConfirmPromotion",
+			Handler:    _CourseEnrollmentServerService_StudentConfirmPromotion_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCourses",
+			Handler:       _CourseEnrollmentServerService_WatchCourses_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/student.proto",
 }
@@ -0,0 +1,16 @@
+package util
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// NewOperationID generates a random (version 4) UUID which can be used as a unique
+// ID for operations
+func NewOperationID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	id[6] = (id[6] & 0x0f) | 0x40 // version 4
+	id[8] = (id[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
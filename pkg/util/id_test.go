@@ -0,0 +1,18 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestNewOperationID(t *testing.T) {
+	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[string]struct{})
+	for i := 0; i < 1000; i++ {
+		id := NewOperationID()
+		assert.Regexp(t, uuidRegex, id)
+		assert.NotContains(t, seen, id)
+		seen[id] = struct{}{}
+	}
+}
@@ -31,6 +31,15 @@ func (q Queue[T]) Dequeue() T {
 	return q.l.Remove(front).(T)
 }
 
+// Peek returns the first of queue without removing it
+func (q Queue[T]) Peek() T {
+	front := q.l.Front()
+	if front == nil {
+		panic("peek called on empty queue")
+	}
+	return front.Value.(T)
+}
+
 // Exists checks if an item is in the queue. If it exists returns the index of the element in queue.
 // Otherwise -1 is returned.
 //
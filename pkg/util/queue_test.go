@@ -41,6 +41,7 @@ func TestQueue(t *testing.T) {
 		}
 		assertion.Equal(expectedQueue, queue.CopyAsArray())
 	}
+	assertion.Equal(50, queue.Peek())
 	// Pop everything
 	for i := 0; i < 50; i++ {
 		assertion.Equal(50+i, queue.Dequeue())
@@ -50,6 +51,7 @@ func TestQueue(t *testing.T) {
 	}
 	assertion.Equal(0, queue.Len())
 	assertion.Panics(func() { queue.Dequeue() })
+	assertion.Panics(func() { queue.Peek() })
 }
 
 func TestQueueExists(t *testing.T) {